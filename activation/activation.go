@@ -42,7 +42,7 @@ func (provider *poetNumberOfTickProvider) NumOfTicks() uint64 {
 }
 
 type nipostBuilder interface {
-	updatePoETProver(PoetProvingServiceClient)
+	updatePoETProvers([]PoetProvingServiceClient)
 	BuildNIPost(ctx context.Context, challenge *types.Hash32, timeout chan struct{}) (*types.NIPost, error)
 }
 
@@ -103,14 +103,16 @@ type Config struct {
 	CoinbaseAccount types.Address
 	GoldenATXID     types.ATXID
 	LayersPerEpoch  uint32
+	// PoETServers are the urls of the PoET proving services that the nipost builder is created with.
+	PoETServers []string
 }
 
 // Builder struct is the struct that orchestrates the creation of activation transactions
 // it is responsible for initializing post, receiving poet proof and orchestrating nipst. after which it will
 // calculate total weight and providing relevant view as proof.
 type Builder struct {
-	pendingPoetClient atomic.UnsafePointer
-	started           atomic.Bool
+	pendingPoetClients atomic.UnsafePointer
+	started            atomic.Bool
 
 	signer
 	accountLock       sync.RWMutex
@@ -137,6 +139,8 @@ type Builder struct {
 	exited                chan struct{}
 	poetRetryInterval     time.Duration
 	poetClientInitializer PoETClientInitializer
+	poetMu                sync.Mutex
+	poetServers           []string
 }
 
 // BuilderOption ...
@@ -178,6 +182,7 @@ func NewBuilder(conf Config, nodeID types.NodeID, signer signer, db atxDBProvide
 		coinbaseAccount:   conf.CoinbaseAccount,
 		goldenATXID:       conf.GoldenATXID,
 		layersPerEpoch:    conf.LayersPerEpoch,
+		poetServers:       conf.PoETServers,
 		db:                db,
 		publisher:         publisher,
 		nipostBuilder:     nipostBuilder,
@@ -333,11 +338,11 @@ func (b *Builder) loop(ctx context.Context) {
 	}()
 	defer b.log.Info("atx builder is stopped")
 	for {
-		client := b.pendingPoetClient.Load()
-		if client != nil {
-			b.nipostBuilder.updatePoETProver(*(*PoetProvingServiceClient)(client))
+		clients := b.pendingPoetClients.Load()
+		if clients != nil {
+			b.nipostBuilder.updatePoETProvers(*(*[]PoetProvingServiceClient)(clients))
 			// CaS here will not lose concurrent update
-			b.pendingPoetClient.CAS(client, nil)
+			b.pendingPoetClients.CAS(clients, nil)
 		}
 
 		if err := b.PublishActivationTx(ctx); err != nil {
@@ -407,16 +412,44 @@ func (b *Builder) buildNIPostChallenge(ctx context.Context) error {
 	return nil
 }
 
-// UpdatePoETServer updates poet client. Context is used to verify that the target is responsive.
-func (b *Builder) UpdatePoETServer(ctx context.Context, target string) error {
-	client := b.poetClientInitializer(target)
-	// TODO(dshulyak) not enough information to verify that PoetServiceID matches with an expected one.
-	// Maybe it should be provided during update.
-	_, err := client.PoetServiceID(ctx)
-	if err != nil {
-		return fmt.Errorf("%w: %v", ErrPoetServiceUnstable, err)
+// UpdatePoETServers replaces poet clients. Context is used to verify that the targets are responsive.
+func (b *Builder) UpdatePoETServers(ctx context.Context, targets []string) error {
+	b.poetMu.Lock()
+	defer b.poetMu.Unlock()
+	return b.updatePoETServers(ctx, targets)
+}
+
+// AddPoETServer adds poet client to the used ones. Context is used to verify that the target is responsive.
+func (b *Builder) AddPoETServer(ctx context.Context, target string) error {
+	b.poetMu.Lock()
+	defer b.poetMu.Unlock()
+	targets := make([]string, 0, len(b.poetServers)+1)
+	for _, server := range b.poetServers {
+		if server == target {
+			return nil
+		}
+		targets = append(targets, server)
+	}
+	return b.updatePoETServers(ctx, append(targets, target))
+}
+
+func (b *Builder) updatePoETServers(ctx context.Context, targets []string) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: no poet servers", ErrPoetServiceUnstable)
+	}
+	clients := make([]PoetProvingServiceClient, 0, len(targets))
+	for _, target := range targets {
+		client := b.poetClientInitializer(target)
+		// TODO(dshulyak) not enough information to verify that PoetServiceID matches with an expected one.
+		// Maybe it should be provided during update.
+		_, err := client.PoetServiceID(ctx)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrPoetServiceUnstable, target, err)
+		}
+		clients = append(clients, client)
 	}
-	b.pendingPoetClient.Store(unsafe.Pointer(&client))
+	b.pendingPoetClients.Store(unsafe.Pointer(&clients))
+	b.poetServers = targets
 	return nil
 }
 
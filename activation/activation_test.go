@@ -13,10 +13,10 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
-	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/system/mocks"
 )
 
@@ -96,7 +96,7 @@ type NIPostBuilderMock struct {
 	SleepTime       int
 }
 
-func (np NIPostBuilderMock) updatePoETProver(PoetProvingServiceClient) {}
+func (np NIPostBuilderMock) updatePoETProvers([]PoetProvingServiceClient) {}
 
 func (np *NIPostBuilderMock) BuildNIPost(_ context.Context, challenge *types.Hash32, _ chan struct{}) (*types.NIPost, error) {
 	if np.buildNIPostFunc != nil {
@@ -107,7 +107,7 @@ func (np *NIPostBuilderMock) BuildNIPost(_ context.Context, challenge *types.Has
 
 type NIPostErrBuilderMock struct{}
 
-func (np *NIPostErrBuilderMock) updatePoETProver(PoetProvingServiceClient) {}
+func (np *NIPostErrBuilderMock) updatePoETProvers([]PoetProvingServiceClient) {}
 
 func (np *NIPostErrBuilderMock) BuildNIPost(context.Context, *types.Hash32, chan struct{}) (*types.NIPost, error) {
 	return nil, fmt.Errorf("NIPost builder error")
@@ -177,7 +177,7 @@ func (n *FaultyNetMock) Publish(_ context.Context, _ string, d []byte) error {
 // ========== Helper functions ==========
 
 func newActivationDb(tb testing.TB) *DB {
-	return NewDB(database.NewMemDatabase(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(tb).WithName("atxDB"))
+	return NewDB(sql.InMemory(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(tb).WithName("atxDB"))
 }
 
 func newChallenge(nodeID types.NodeID, sequence uint64, prevAtxID, posAtxID types.ATXID, pubLayerID types.LayerID) types.NIPostChallenge {
@@ -331,6 +331,35 @@ func TestBuilder_RestartSmeshing(t *testing.T) {
 	}
 }
 
+func TestBuilder_AddPoETServer(t *testing.T) {
+	activationDb := newActivationDb(t)
+	net.atxDb = activationDb
+	cfg := Config{
+		CoinbaseAccount: coinbase,
+		GoldenATXID:     goldenATXID,
+		LayersPerEpoch:  layersPerEpoch,
+		PoETServers:     []string{"first"},
+	}
+	var initialized []string
+	builder := NewBuilder(cfg, nodeID, &MockSigning{}, activationDb, net, nipostBuilderMock, &postSetupProviderMock{},
+		layerClockMock, &mockSyncer{}, NewMockDB(), logtest.New(t).WithName("atxBuilder"),
+		WithPoETClientInitializer(func(target string) PoetProvingServiceClient {
+			initialized = append(initialized, target)
+			poet, _ := newPoetServiceMock(t)
+			poet.EXPECT().PoetServiceID(gomock.Any()).Return([]byte(target), nil)
+			return poet
+		}),
+	)
+
+	require.NoError(t, builder.AddPoETServer(context.TODO(), "second"))
+	require.Equal(t, []string{"first", "second"}, initialized)
+	require.Len(t, *(*[]PoetProvingServiceClient)(builder.pendingPoetClients.Load()), 2)
+
+	initialized = nil
+	require.NoError(t, builder.AddPoETServer(context.TODO(), "first"))
+	require.Empty(t, initialized)
+}
+
 func TestBuilder_PublishActivationTx_HappyFlow(t *testing.T) {
 	types.SetLayersPerEpoch(layersPerEpoch)
 	r := require.New(t)
@@ -625,7 +654,7 @@ func TestBuilder_SignAtx(t *testing.T) {
 
 	ed := signing.NewEdSigner()
 	nodeID := types.NodeID{Key: ed.PublicKey().String(), VRFPublicKey: []byte("bbbbb")}
-	activationDb := NewDB(database.NewMemDatabase(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(t).WithName("atxDB1"))
+	activationDb := NewDB(sql.InMemory(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(t).WithName("atxDB1"))
 	b := NewBuilder(cfg, nodeID, ed, activationDb, net, nipostBuilderMock, &postSetupProviderMock{}, layerClockMock, &mockSyncer{}, NewMockDB(), logtest.New(t).WithName("atxBuilder"))
 
 	prevAtx := types.ATXID(types.HexToHash32("0x111"))
@@ -651,7 +680,7 @@ func TestBuilder_NIPostPublishRecovery(t *testing.T) {
 	layersPerEpoch := uint32(10)
 	db := NewMockDB()
 	sig := &MockSigning{}
-	activationDb := NewDB(database.NewMemDatabase(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(t).WithName("atxDB1"))
+	activationDb := NewDB(sql.InMemory(), nil, &MockIDStore{}, layersPerEpoch, goldenATXID, &ValidatorMock{}, logtest.New(t).WithName("atxDB1"))
 	net.atxDb = activationDb
 
 	cfg := Config{
@@ -807,7 +836,7 @@ func TestBuilder_InitialProofGeneratedOnce(t *testing.T) {
 }
 
 /*
-func TestBuilder_UpdatePoETProver(t *testing.T) {
+func TestBuilder_UpdatePoETProvers(t *testing.T) {
 	// we test that poet client is not replaced in between PoetServiceID and Submit calls.
 	// but after Submit call fails with error it is replaced and called
 
@@ -863,7 +892,7 @@ func TestBuilder_UpdatePoETProver(t *testing.T) {
 		require.FailNow(t, "timedout waiting for PoetServiceID to be called")
 	}
 	poetProver2.EXPECT().PoetServiceID(gomock.Any()).Return([]byte{}, nil)
-	require.NoError(t, b.UpdatePoETServer(context.TODO(), "update"))
+	require.NoError(t, b.UpdatePoETServers(context.TODO(), []string{"update"}))
 	poet2Called := make(chan struct{})
 	poetProver2.EXPECT().PoetServiceID(gomock.Any()).Do(func(context.Context) {
 		cancel()
@@ -887,7 +916,7 @@ func TestActivationDB_FetchAtxReferences(t *testing.T) {
 	defer ctrl.Finish()
 	mockFetch := mocks.NewMockFetcher(ctrl)
 
-	activationDb := NewDB(database.NewMemDatabase(), mockFetch, &MockIDStore{}, layersPerEpoch,
+	activationDb := NewDB(sql.InMemory(), mockFetch, &MockIDStore{}, layersPerEpoch,
 		goldenATXID, &ValidatorMock{}, logtest.New(t).WithName("atxDB"))
 	challenge := newChallenge(nodeID, 1, prevAtxID, prevAtxID, postGenesisEpochLayer)
 
@@ -3,7 +3,6 @@ package activation
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
@@ -19,17 +18,17 @@ import (
 	"github.com/spacemeshos/go-spacemesh/codec"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
-	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
 const layersPerEpochBig = 1000
 
 func getAtxDb(tb testing.TB, id string) *DB {
 	lg := logtest.New(tb).WithName(id)
-	return NewDB(database.NewMemDatabase(), nil, NewIdentityStore(database.NewMemDatabase()), layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg)
+	return NewDB(sql.InMemory(), nil, NewIdentityStore(sql.InMemory()), layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg)
 }
 
 func processAtxs(db *DB, atxs []*types.ActivationTx) error {
@@ -76,18 +75,12 @@ func Test_DBSanity(t *testing.T) {
 	coinbase3 := types.HexToAddress("cccc")
 
 	atx1 := newActivationTx(id1, 0, *types.EmptyATXID, *types.EmptyATXID, types.NewLayerID(1), 0, 100, coinbase1, 100, &types.NIPost{})
-	atx2 := newActivationTx(id1, 0, *types.EmptyATXID, *types.EmptyATXID, types.NewLayerID(1001), 0, 100, coinbase2, 100, &types.NIPost{})
+	atx2 := newActivationTx(id2, 0, *types.EmptyATXID, *types.EmptyATXID, types.NewLayerID(1001), 0, 100, coinbase2, 100, &types.NIPost{})
 	atx3 := newActivationTx(id1, 0, *types.EmptyATXID, *types.EmptyATXID, types.NewLayerID(2001), 0, 100, coinbase3, 100, &types.NIPost{})
 
 	err := atxdb.storeAtxUnlocked(atx1)
 	assert.NoError(t, err)
-	err = atxdb.storeAtxUnlocked(atx2)
-	assert.NoError(t, err)
-	err = atxdb.storeAtxUnlocked(atx3)
-	assert.NoError(t, err)
 
-	err = atxdb.addAtxToNodeID(id1, atx1)
-	assert.NoError(t, err)
 	id, err := atxdb.GetNodeLastAtxID(id1)
 	assert.NoError(t, err)
 	assert.Equal(t, atx1.ID(), id)
@@ -96,10 +89,9 @@ func Test_DBSanity(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, atx1.ID(), id)
 
-	err = atxdb.addAtxToNodeID(id2, atx2)
+	err = atxdb.storeAtxUnlocked(atx2)
 	assert.NoError(t, err)
-
-	err = atxdb.addAtxToNodeID(id1, atx3)
+	err = atxdb.storeAtxUnlocked(atx3)
 	assert.NoError(t, err)
 
 	id, err = atxdb.GetNodeLastAtxID(id2)
@@ -332,15 +324,9 @@ func TestActivationDB_ValidateAtxErrors(t *testing.T) {
 	err = atxdb.StoreAtx(context.TODO(), 1, atx)
 	assert.NoError(t, err)
 	atx = newActivationTx(idx1, 1, prevAtx.ID(), posAtx.ID(), types.NewLayerID(12), 0, 100, coinbase, 100, &types.NIPost{})
-	iter := atxdb.atxs.Find(getNodeAtxPrefix(atx.NodeID).Bytes())
-	defer iter.Release()
-	for iter.Next() {
-		err = atxdb.atxs.Delete(iter.Key())
-		assert.NoError(t, err)
-	}
 	err = SignAtx(signer, atx)
 	assert.NoError(t, err)
-	err = atxdb.ContextuallyValidateAtx(atx.ActivationTxHeader)
+	err = getAtxDb(t, "empty").ContextuallyValidateAtx(atx.ActivationTxHeader)
 	assert.EqualError(t, err,
 		fmt.Sprintf("could not fetch node last atx: find ATX in DB: atx for node %v does not exist", atx.NodeID.ShortString()))
 
@@ -555,12 +541,11 @@ func BenchmarkActivationDb_SyntacticallyValidateAtx(b *testing.B) {
 func BenchmarkNewActivationDb(b *testing.B) {
 	r := require.New(b)
 
-	const tmpPath = "../tmp/atx"
 	lg := logtest.New(b)
 
-	store, err := database.NewLDBDatabase(tmpPath, 0, 0, lg.WithName("atxLDB"))
+	db, err := sql.Open("file:" + filepath.Join(b.TempDir(), "state.sql"))
 	r.NoError(err)
-	atxdb := NewDB(store, nil, NewIdentityStore(store), layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
+	atxdb := NewDB(db, nil, NewIdentityStore(db), layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
 
 	const (
 		numOfMiners = 300
@@ -605,11 +590,6 @@ func BenchmarkNewActivationDb(b *testing.B) {
 		copy(pPrevAtxs, prevAtxs)
 	}
 	b.Logf("\n>>> Total time: %v\n\n", time.Since(start))
-	time.Sleep(1 * time.Second)
-
-	// cleanup
-	err = os.RemoveAll(tmpPath)
-	r.NoError(err)
 }
 
 func TestActivationDb_TopAtx(t *testing.T) {
@@ -654,8 +634,8 @@ func createAndValidateSignedATX(r *require.Assertions, atxdb *DB, ed *signing.Ed
 func TestActivationDb_ValidateSignedAtx(t *testing.T) {
 	r := require.New(t)
 	lg := logtest.New(t).WithName("sigValidation")
-	idStore := NewIdentityStore(database.NewMemDatabase())
-	atxdb := NewDB(database.NewMemDatabase(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
+	idStore := NewIdentityStore(sql.InMemory())
+	atxdb := NewDB(sql.InMemory(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
 
 	ed := signing.NewEdSigner()
 	nodeID := types.NodeID{Key: ed.PublicKey().String(), VRFPublicKey: []byte("bbbbb")}
@@ -698,8 +678,8 @@ func TestActivationDb_AwaitAtx(t *testing.T) {
 	r := require.New(t)
 
 	lg := logtest.New(t).WithName("sigValidation")
-	idStore := NewIdentityStore(database.NewMemDatabase())
-	atxdb := NewDB(database.NewMemDatabase(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
+	idStore := NewIdentityStore(sql.InMemory())
+	atxdb := NewDB(sql.InMemory(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
 	id := types.NodeID{Key: uuid.New().String(), VRFPublicKey: []byte("vrf")}
 	atx := newActivationTx(id, 0, *types.EmptyATXID, *types.EmptyATXID, types.NewLayerID(1), 0, 100, coinbase, 100, &types.NIPost{})
 
@@ -738,8 +718,8 @@ func TestActivationDb_ContextuallyValidateAtx(t *testing.T) {
 	r := require.New(t)
 
 	lg := logtest.New(t).WithName("sigValidation")
-	idStore := NewIdentityStore(database.NewMemDatabase())
-	atxdb := NewDB(database.NewMemDatabase(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
+	idStore := NewIdentityStore(sql.InMemory())
+	atxdb := NewDB(sql.InMemory(), nil, idStore, layersPerEpochBig, goldenATXID, &ValidatorMock{}, lg.WithName("atxDB"))
 
 	validAtx := types.NewActivationTx(newChallenge(nodeID, 0, *types.EmptyATXID, goldenATXID, types.LayerID{}), types.Address{}, nil, 0, nil)
 	err := atxdb.ContextuallyValidateAtx(validAtx.ActivationTxHeader)
@@ -775,12 +755,9 @@ func BenchmarkGetAtxHeaderWithConcurrentStoreAtx(b *testing.B) {
 	path := b.TempDir()
 
 	lg := logtest.New(b)
-	iddbstore, err := database.NewLDBDatabase(filepath.Join(path, "ids"), 0, 0, lg)
-	require.NoError(b, err)
-	idStore := NewIdentityStore(iddbstore)
-	atxdbstore, err := database.NewLDBDatabase(filepath.Join(path, "atx"), 0, 0, lg)
+	db, err := sql.Open("file:" + filepath.Join(path, "state.sql"))
 	require.NoError(b, err)
-	atxdb := NewDB(atxdbstore, nil, idStore, 288, types.ATXID{}, &Validator{}, lg)
+	atxdb := NewDB(db, nil, NewIdentityStore(db), 288, types.ATXID{}, &Validator{}, lg)
 
 	var (
 		stop uint64
@@ -806,7 +783,7 @@ func BenchmarkGetAtxHeaderWithConcurrentStoreAtx(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := atxdb.GetAtxHeader(types.ATXID{1, 1, 1})
-		require.ErrorIs(b, err, database.ErrNotFound)
+		require.ErrorIs(b, err, sql.ErrNotFound)
 	}
 	atomic.StoreUint64(&stop, 1)
 	wg.Wait()
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
@@ -28,43 +30,164 @@ type builderState struct {
 
 	NIPost *types.NIPost
 
-	// PoetRound is the round of the PoET proving service in which the PoET challenge was included in.
+	// PoetRequests are the rounds of the PoET proving services in which the PoET challenge was included in.
+	PoetRequests []PoetRequest
+
+	// PoetProofRef is the root of the proof received from the PoET service.
+	PoetProofRef []byte
+}
+
+// legacyBuilderState is the state persisted by the builder that submitted the challenge to a single
+// PoET proving service.
+type legacyBuilderState struct {
+	Challenge types.Hash32
+
+	NIPost *types.NIPost
+
 	PoetRound *types.PoetRound
 
-	// PoetServiceID returns the public key of the PoET proving service.
 	PoetServiceID []byte
 
-	// PoetProofRef is the root of the proof received from the PoET service.
 	PoetProofRef []byte
 }
 
+func (s *legacyBuilderState) upgrade() *builderState {
+	state := &builderState{
+		Challenge:    s.Challenge,
+		NIPost:       s.NIPost,
+		PoetProofRef: s.PoetProofRef,
+	}
+	if s.PoetRound != nil {
+		state.PoetRequests = []PoetRequest{{PoetRound: s.PoetRound, PoetServiceID: s.PoetServiceID}}
+	}
+	return state
+}
+
+// PoetRequest describes a challenge submitted to a PoET proving service.
+type PoetRequest struct {
+	// PoetRound is the round of the PoET proving service in which the PoET challenge was included in.
+	PoetRound *types.PoetRound
+
+	// PoetServiceID is the public key of the PoET proving service.
+	PoetServiceID []byte
+}
+
+const (
+	// poetServiceMaxFailures is the number of consecutive failures after which a PoET proving service
+	// is considered unreliable. Unreliable services are used only if none of the reliable ones accepted
+	// the challenge.
+	poetServiceMaxFailures = 3
+	// poetServiceTimeout bounds the time to get the id of a PoET proving service and to submit the challenge to it.
+	poetServiceTimeout = 30 * time.Second
+)
+
+// poetFailures is the number of consecutive rounds in which the PoET proving service failed to provide a proof.
+type poetFailures struct {
+	PoetServiceID []byte
+	Failures      uint32
+}
+
 func nipostBuildStateKey() []byte {
+	return []byte("nipstatev2")
+}
+
+func legacyNIPostBuildStateKey() []byte {
 	return []byte("nipstate")
 }
 
+func poetFailuresKey() []byte {
+	return []byte("poetfailures")
+}
+
 func (nb *NIPostBuilder) load(challenge types.Hash32) {
-	if bts, err := nb.store.Get(nipostBuildStateKey()); err != nil {
+	state, err := nb.loadState()
+	if err != nil {
 		nb.log.With().Warning("cannot load nipost state", log.Err(err))
 		return
-	} else if len(bts) > 0 {
+	}
+	if state == nil {
+		return
+	}
+	if state.Challenge == challenge {
+		nb.state = state
+	} else {
+		nb.state = &builderState{Challenge: challenge, NIPost: &types.NIPost{}}
+	}
+}
+
+// loadState returns the persisted state, or nil if it is empty. The state persisted in the legacy
+// format is upgraded and persisted in the current format.
+func (nb *NIPostBuilder) loadState() (*builderState, error) {
+	bts, err := nb.store.Get(nipostBuildStateKey())
+	if err == nil && len(bts) > 0 {
 		var state builderState
 		if err := types.BytesToInterface(bts, &state); err != nil {
-			nb.log.With().Error("cannot load nipost state", log.Err(err))
-		}
-		if state.Challenge == challenge {
-			nb.state = &state
-		} else {
-			nb.state = &builderState{Challenge: challenge, NIPost: &types.NIPost{}}
+			return nil, fmt.Errorf("decode nipost state: %w", err)
 		}
+		return &state, nil
+	}
+	legacy, legacyErr := nb.store.Get(legacyNIPostBuildStateKey())
+	if legacyErr != nil || len(legacy) == 0 {
+		return nil, err
 	}
+	var state legacyBuilderState
+	if err := types.BytesToInterface(legacy, &state); err != nil {
+		return nil, fmt.Errorf("decode legacy nipost state: %w", err)
+	}
+	upgraded := state.upgrade()
+	nb.log.With().Info("upgraded legacy nipost state",
+		log.String("poet_service_id", fmt.Sprintf("%x", state.PoetServiceID)))
+	if err := nb.persistState(upgraded); err != nil {
+		return nil, err
+	}
+	if err := nb.store.Put(legacyNIPostBuildStateKey(), nil); err != nil {
+		nb.log.With().Warning("cannot clear legacy nipost state", log.Err(err))
+	}
+	return upgraded, nil
 }
 
 func (nb *NIPostBuilder) persist() {
-	if bts, err := types.InterfaceToBytes(&nb.state); err != nil {
+	if err := nb.persistState(nb.state); err != nil {
 		nb.log.With().Warning("cannot store nipost state", log.Err(err))
+	}
+}
+
+func (nb *NIPostBuilder) persistState(state *builderState) error {
+	bts, err := types.InterfaceToBytes(&state)
+	if err != nil {
+		return fmt.Errorf("encode nipost state: %w", err)
+	}
+	if err := nb.store.Put(nipostBuildStateKey(), bts); err != nil {
+		return fmt.Errorf("store nipost state: %w", err)
+	}
+	return nil
+}
+
+// loadFailures restores the number of failures of PoET proving services.
+func (nb *NIPostBuilder) loadFailures() {
+	bts, err := nb.store.Get(poetFailuresKey())
+	if err != nil || len(bts) == 0 {
 		return
-	} else if err := nb.store.Put(nipostBuildStateKey(), bts); err != nil {
-		nb.log.With().Warning("cannot store nipost state", log.Err(err))
+	}
+	var failures []poetFailures
+	if err := types.BytesToInterface(bts, &failures); err != nil {
+		nb.log.With().Warning("cannot load poet services failures", log.Err(err))
+		return
+	}
+	for _, f := range failures {
+		nb.failures[string(f.PoetServiceID)] = int(f.Failures)
+	}
+}
+
+func (nb *NIPostBuilder) persistFailures() {
+	failures := make([]poetFailures, 0, len(nb.failures))
+	for id, count := range nb.failures {
+		failures = append(failures, poetFailures{PoetServiceID: []byte(id), Failures: uint32(count)})
+	}
+	if bts, err := types.InterfaceToBytes(&failures); err != nil {
+		nb.log.With().Warning("cannot store poet services failures", log.Err(err))
+	} else if err := nb.store.Put(poetFailuresKey(), bts); err != nil {
+		nb.log.With().Warning("cannot store poet services failures", log.Err(err))
 	}
 }
 
@@ -72,11 +195,15 @@ func (nb *NIPostBuilder) persist() {
 type NIPostBuilder struct {
 	minerID           []byte
 	postSetupProvider PostSetupProvider
-	poetProver        PoetProvingServiceClient
-	poetDB            poetDbAPI
-	state             *builderState
-	store             bytesStore
-	log               log.Log
+	poetProvers       []PoetProvingServiceClient
+	poetTimeout       time.Duration
+	// failures is the number of consecutive failures of the PoET proving services, keyed by their ids.
+	// It is persisted, so that the failures are attributed across restarts and updates of the services.
+	failures map[string]int
+	poetDB   poetDbAPI
+	state    *builderState
+	store    bytesStore
+	log      log.Log
 }
 
 type poetDbAPI interface {
@@ -89,29 +216,34 @@ type poetDbAPI interface {
 func NewNIPostBuilder(
 	minerID []byte,
 	postSetupProvider PostSetupProvider,
-	poetProver PoetProvingServiceClient,
+	poetProvers []PoetProvingServiceClient,
 	poetDB poetDbAPI,
 	store bytesStore,
 	log log.Log,
 ) *NIPostBuilder {
-	return &NIPostBuilder{
+	nb := &NIPostBuilder{
 		minerID:           minerID,
 		postSetupProvider: postSetupProvider,
-		poetProver:        poetProver,
+		poetProvers:       poetProvers,
+		poetTimeout:       poetServiceTimeout,
+		failures:          map[string]int{},
 		poetDB:            poetDB,
 		state:             &builderState{NIPost: &types.NIPost{}},
 		store:             store,
 		log:               log,
 	}
+	nb.loadFailures()
+	return nb
 }
 
-// updatePoETProver updates poetProver reference. It should not be executed concurently with BuildNIPST.
-func (nb *NIPostBuilder) updatePoETProver(poetProver PoetProvingServiceClient) {
+// updatePoETProvers replaces PoET proving services. It should not be executed concurently with BuildNIPST.
+// Failures of the services are kept.
+func (nb *NIPostBuilder) updatePoETProvers(poetProvers []PoetProvingServiceClient) {
 	// reset the state for safety to avoid accidental erroneous wait in Phase 1.
 	nb.state = &builderState{
 		NIPost: &types.NIPost{},
 	}
-	nb.poetProver = poetProver
+	nb.poetProvers = poetProvers
 }
 
 // BuildNIPost uses the given challenge to build a NIPost. "atxExpired" and "stop" are channels for early termination of
@@ -126,55 +258,23 @@ func (nb *NIPostBuilder) BuildNIPost(ctx context.Context, challenge *types.Hash3
 
 	nipost := nb.state.NIPost
 
-	// Phase 0: Submit challenge to PoET service.
-	if nb.state.PoetRound == nil {
-		poetServiceID, err := nb.poetProver.PoetServiceID(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("%w: failed to get PoET service ID: %v", ErrPoetServiceUnstable, err)
-		}
-		nb.state.PoetServiceID = poetServiceID
-
-		poetChallenge := challenge
+	// Phase 0: Submit challenge to PoET services.
+	if len(nb.state.PoetRequests) == 0 {
 		nb.state.Challenge = *challenge
-
-		nb.log.Debug("submitting challenge to poet proving service (poet id: %x, challenge: %x)",
-			nb.state.PoetServiceID, poetChallenge)
-
-		round, err := nb.poetProver.Submit(ctx, *poetChallenge)
+		requests, err := nb.submitPoetChallenge(ctx, *challenge)
 		if err != nil {
-			return nil, fmt.Errorf("%w: failed to submit challenge to poet service: %v", ErrPoetServiceUnstable, err)
+			return nil, err
 		}
-
-		nb.log.Info("challenge submitted to poet proving service (poet id: %x, round id: %v, challenge: %x)",
-			nb.state.PoetServiceID, round.ID, poetChallenge)
-
-		nipost.Challenge = poetChallenge
-		nb.state.PoetRound = round
+		nipost.Challenge = challenge
+		nb.state.PoetRequests = requests
 		nb.persist()
 	}
 
-	// Phase 1: receive proofs from PoET service
+	// Phase 1: receive the first valid proof from PoET services.
 	if nb.state.PoetProofRef == nil {
-		var poetProofRef []byte
-		select {
-		case poetProofRef = <-nb.poetDB.SubscribeToProofRef(nb.state.PoetServiceID, nb.state.PoetRound.ID):
-		case <-atxExpired:
-			nb.poetDB.UnsubscribeFromProofRef(nb.state.PoetServiceID, nb.state.PoetRound.ID)
-			return nil, fmt.Errorf("%w: while waiting for poet proof, target epoch ended", ErrATXChallengeExpired)
-		case <-ctx.Done():
-			return nil, ErrStopRequested
-		}
-
-		membership, err := nb.poetDB.GetMembershipMap(poetProofRef)
+		poetProofRef, err := nb.awaitPoetProof(ctx, *nipost.Challenge, atxExpired)
 		if err != nil {
-			nb.log.With().Panic("failed to fetch membership for poet proof",
-				log.String("challenge", fmt.Sprintf("%x", nb.state.PoetProofRef))) // TODO: handle inconsistent state
-		}
-		if !membership[*nipost.Challenge] {
-			round := nb.state.PoetRound
-			nb.state.PoetRound = nil // no point in waiting in Phase 1 since we are already received a proof
-			return nil, fmt.Errorf("%w not a member of this round (poetId: %x, roundId: %s, challenge: %x, num of members: %d)", ErrPoetServiceUnstable,
-				nb.state.PoetServiceID, round.ID, *nipost.Challenge, len(membership)) // TODO(noamnelke): handle this case!
+			return nil, err
 		}
 		nb.state.PoetProofRef = poetProofRef
 		nb.persist()
@@ -208,6 +308,205 @@ func (nb *NIPostBuilder) BuildNIPost(ctx context.Context, challenge *types.Hash3
 	return nipost, nil
 }
 
+// submitResult is the result of submitting the challenge to a PoET proving service.
+type submitResult struct {
+	client PoetProvingServiceClient
+	// id is nil if the service didn't respond with its id.
+	id      []byte
+	request *PoetRequest
+	// skipped is true if the challenge wasn't submitted because the service is unreliable.
+	skipped bool
+	err     error
+}
+
+// submitPoetChallenge submits the challenge to every reliable PoET proving service concurrently.
+// Unreliable services are used only if none of the reliable services accepted the challenge.
+// It fails only if none of the services accepted the challenge.
+func (nb *NIPostBuilder) submitPoetChallenge(ctx context.Context, challenge types.Hash32) ([]PoetRequest, error) {
+	var (
+		requests []PoetRequest
+		skipped  []PoetProvingServiceClient
+		errs     []string
+	)
+	collect := func(results []submitResult) {
+		for _, res := range results {
+			switch {
+			case res.skipped:
+				skipped = append(skipped, res.client)
+			case res.err != nil:
+				if res.id != nil {
+					nb.poetServiceFailed(res.id, res.err)
+				} else {
+					nb.log.With().Info("poet service failed", log.Err(res.err))
+				}
+				errs = append(errs, res.err.Error())
+			default:
+				requests = append(requests, *res.request)
+			}
+		}
+	}
+	results := nb.submitPoetServices(ctx, nb.poetProvers, challenge, true)
+	if ctx.Err() != nil {
+		return nil, ErrStopRequested
+	}
+	collect(results)
+	if len(requests) == 0 && len(skipped) > 0 {
+		results = nb.submitPoetServices(ctx, skipped, challenge, false)
+		if ctx.Err() != nil {
+			return nil, ErrStopRequested
+		}
+		collect(results)
+	}
+	nb.persistFailures()
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("%w: failed to submit challenge to any poet service: %s",
+			ErrPoetServiceUnstable, strings.Join(errs, "; "))
+	}
+	return requests, nil
+}
+
+// submitPoetServices submits the challenge to the PoET proving services concurrently. Every service
+// must respond within the poet timeout. Unreliable services are skipped if skipUnreliable is true.
+func (nb *NIPostBuilder) submitPoetServices(ctx context.Context, clients []PoetProvingServiceClient, challenge types.Hash32, skipUnreliable bool) []submitResult {
+	results := make([]submitResult, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		i, client := i, client
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, nb.poetTimeout)
+			defer cancel()
+			results[i] = nb.submitPoetService(ctx, client, challenge, skipUnreliable)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// submitPoetService submits the challenge to the PoET proving service. It doesn't modify the builder.
+func (nb *NIPostBuilder) submitPoetService(ctx context.Context, client PoetProvingServiceClient, challenge types.Hash32, skipUnreliable bool) submitResult {
+	poetServiceID, err := client.PoetServiceID(ctx)
+	if err != nil {
+		return submitResult{client: client, err: fmt.Errorf("failed to get PoET service ID: %v", err)}
+	}
+	if skipUnreliable && nb.unreliable(poetServiceID) {
+		return submitResult{client: client, id: poetServiceID, skipped: true}
+	}
+
+	nb.log.Debug("submitting challenge to poet proving service (poet id: %x, challenge: %x)",
+		poetServiceID, challenge)
+
+	round, err := client.Submit(ctx, challenge)
+	if err != nil {
+		return submitResult{
+			client: client,
+			id:     poetServiceID,
+			err:    fmt.Errorf("failed to submit challenge to poet service %x: %v", poetServiceID, err),
+		}
+	}
+
+	nb.log.Info("challenge submitted to poet proving service (poet id: %x, round id: %v, challenge: %x)",
+		poetServiceID, round.ID, challenge)
+	return submitResult{
+		client:  client,
+		id:      poetServiceID,
+		request: &PoetRequest{PoetRound: round, PoetServiceID: poetServiceID},
+	}
+}
+
+type poetProofResult struct {
+	request  PoetRequest
+	proofRef []byte
+}
+
+// awaitPoetProof waits for proofs, validated and stored by the PoET database, for every submitted challenge.
+// It returns the first proof that includes the challenge.
+func (nb *NIPostBuilder) awaitPoetProof(ctx context.Context, challenge types.Hash32, atxExpired chan struct{}) ([]byte, error) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan poetProofResult, len(nb.state.PoetRequests))
+	for _, request := range nb.state.PoetRequests {
+		request := request
+		proofs := nb.poetDB.SubscribeToProofRef(request.PoetServiceID, request.PoetRound.ID)
+		go func() {
+			select {
+			case proofRef := <-proofs:
+				results <- poetProofResult{request: request, proofRef: proofRef}
+			case <-waitCtx.Done():
+			}
+		}()
+	}
+
+	pending := make(map[string]PoetRequest, len(nb.state.PoetRequests))
+	for _, request := range nb.state.PoetRequests {
+		pending[string(request.PoetServiceID)] = request
+	}
+	unsubscribe := func() {
+		for _, request := range pending {
+			nb.poetDB.UnsubscribeFromProofRef(request.PoetServiceID, request.PoetRound.ID)
+		}
+	}
+	defer nb.persistFailures()
+	for len(pending) > 0 {
+		var result poetProofResult
+		select {
+		case result = <-results:
+		case <-atxExpired:
+			unsubscribe()
+			for _, request := range pending {
+				nb.poetServiceFailed(request.PoetServiceID,
+					fmt.Errorf("no proof for round %s before the target epoch ended", request.PoetRound.ID))
+			}
+			return nil, fmt.Errorf("%w: while waiting for poet proof, target epoch ended", ErrATXChallengeExpired)
+		case <-ctx.Done():
+			return nil, ErrStopRequested
+		}
+		delete(pending, string(result.request.PoetServiceID))
+
+		membership, err := nb.poetDB.GetMembershipMap(result.proofRef)
+		if err != nil {
+			nb.log.With().Error("failed to fetch membership for poet proof",
+				log.String("poet_service_id", fmt.Sprintf("%x", result.request.PoetServiceID)),
+				log.String("proof_ref", fmt.Sprintf("%x", result.proofRef)),
+				log.Err(err))
+			continue
+		}
+		if !membership[challenge] {
+			nb.poetServiceFailed(result.request.PoetServiceID, fmt.Errorf("not a member of round %s (challenge: %x, num of members: %d)",
+				result.request.PoetRound.ID, challenge, len(membership)))
+			continue
+		}
+		delete(nb.failures, string(result.request.PoetServiceID))
+		unsubscribe()
+		return result.proofRef, nil
+	}
+	// no point in waiting in Phase 1 since we are already received all proofs
+	nb.state.PoetRequests = nil
+	return nil, fmt.Errorf("%w: not a member of any poet round (challenge: %x)", ErrPoetServiceUnstable, challenge)
+}
+
+// unreliable returns true if the PoET proving service failed poetServiceMaxFailures times in a row.
+func (nb *NIPostBuilder) unreliable(id []byte) bool {
+	return nb.failures[string(id)] >= poetServiceMaxFailures
+}
+
+// poetServiceFailed records a failure of the PoET proving service.
+func (nb *NIPostBuilder) poetServiceFailed(id []byte, err error) {
+	nb.failures[string(id)]++
+	fields := []log.LoggableField{
+		log.String("poet_service_id", fmt.Sprintf("%x", id)),
+		log.Int("failures", nb.failures[string(id)]),
+		log.Err(err),
+	}
+	if nb.unreliable(id) {
+		nb.log.With().Warning("poet service is unreliable", fields...)
+	} else {
+		nb.log.With().Info("poet service failed", fields...)
+	}
+}
+
 // NewNIPostWithChallenge is a convenience method FOR TESTS ONLY. TODO: move this out of production code.
 func NewNIPostWithChallenge(challenge *types.Hash32, poetRef []byte) *types.NIPost {
 	return &types.NIPost{
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/spacemeshos/post/initialization"
@@ -36,6 +38,7 @@ type postSetupProviderMock struct {
 	sessionChan chan struct{}
 	called      int
 	setError    bool
+	challenge   []byte
 }
 
 // A compile time check to ensure that postSetupProviderMock fully implements the PostProvider interface.
@@ -71,6 +74,7 @@ func (p *postSetupProviderMock) StopSession(deleteFiles bool) error {
 
 func (p *postSetupProviderMock) GenerateProof(challenge []byte) (*types.Post, *types.PostMetadata, error) {
 	p.called++
+	p.challenge = challenge
 	if p.setError {
 		return nil, nil, fmt.Errorf("error")
 	}
@@ -139,7 +143,7 @@ func TestNIPostBuilderWithMocks(t *testing.T) {
 
 	poetDb := &poetDbMock{}
 
-	nb := NewNIPostBuilder(minerID, postProvider, poetProvider,
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 	hash := types.BytesToHash([]byte("anton"))
 	nipost, err := nb.BuildNIPost(context.TODO(), &hash, nil)
@@ -161,7 +165,7 @@ func TestPostSetup(t *testing.T) {
 
 	poetDb := &poetDbMock{}
 
-	nb := NewNIPostBuilder(minerID, postSetupProvider, poetProvider,
+	nb := NewNIPostBuilder(minerID, postSetupProvider, []PoetProvingServiceClient{poetProvider},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 
 	done, err := postSetupProvider.StartSession(postSetupOpts)
@@ -216,7 +220,7 @@ func buildNIPost(tb testing.TB, r *require.Assertions, postCfg PostConfig, nipos
 	r.NoError(err)
 	<-done
 
-	nb := NewNIPostBuilder(minerID, postProvider, poetProver,
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProver},
 		poetDb, database.NewMemDatabase(), logtest.New(tb))
 
 	nipost, err := nb.BuildNIPost(context.TODO(), &nipostChallenge, nil)
@@ -251,7 +255,7 @@ func TestNewNIPostBuilderNotInitialized(t *testing.T) {
 		r.NoError(err)
 	}()
 	poetDb := &poetDbMock{}
-	nb := NewNIPostBuilder(minerIDNotInitialized, postProvider, poetProver,
+	nb := NewNIPostBuilder(minerIDNotInitialized, postProvider, []PoetProvingServiceClient{poetProver},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 
 	nipost, err := nb.BuildNIPost(context.TODO(), &nipostChallenge, nil)
@@ -282,7 +286,7 @@ func TestNIPostBuilder_BuildNIPost(t *testing.T) {
 
 	poetDb := &poetDbMock{errOn: false}
 
-	nb := NewNIPostBuilder(minerID, postProvider, poetProvider,
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 	hash := types.BytesToHash([]byte("anton"))
 	nipost, err := nb.BuildNIPost(context.TODO(), &hash, nil)
@@ -292,20 +296,20 @@ func TestNIPostBuilder_BuildNIPost(t *testing.T) {
 	assert.Equal(builderState{NIPost: &types.NIPost{}}, *nb.state)
 
 	// fail after getting proof ref
-	nb = NewNIPostBuilder(minerID, postProvider, poetProvider, poetDb, db, logtest.New(t))
+	nb = NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider}, poetDb, db, logtest.New(t))
 	poetDb.errOn = true
 	nipost, err = nb.BuildNIPost(context.TODO(), &hash, nil)
 	assert.Nil(nipost)
 	assert.Error(err)
 
 	// check that proof ref is not called again
-	nb = NewNIPostBuilder(minerID, postProvider, poetProvider, poetDb, db, logtest.New(t))
+	nb = NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider}, poetDb, db, logtest.New(t))
 	nipost, err = nb.BuildNIPost(context.TODO(), &hash, nil)
 	assert.Nil(nipost)
 	assert.Error(err)
 
 	// fail post exec
-	nb = NewNIPostBuilder(minerID, postProvider, poetProvider, poetDb, db, logtest.New(t))
+	nb = NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider}, poetDb, db, logtest.New(t))
 	poetDb.errOn = false
 	postProvider.setError = true
 	// check that proof ref is not called again
@@ -314,7 +318,7 @@ func TestNIPostBuilder_BuildNIPost(t *testing.T) {
 	assert.Error(err)
 
 	// fail post exec
-	nb = NewNIPostBuilder(minerID, postProvider, poetProvider, poetDb, db, logtest.New(t))
+	nb = NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider}, poetDb, db, logtest.New(t))
 	poetDb.errOn = false
 	postProvider.setError = false
 	// check that proof ref is not called again
@@ -403,7 +407,7 @@ func TestNIPostBuilder_TimeoutUnsubscribe(t *testing.T) {
 
 	poetDb := &poetDbMock{}
 
-	nb := NewNIPostBuilder(minerID, postProvider, poetProvider,
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 	hash := types.BytesToHash([]byte("anton"))
 	poetDb.unsubscribed = false
@@ -422,7 +426,7 @@ func TestNIPostBuilder_Close(t *testing.T) {
 
 	poetDb := &poetDbMock{}
 
-	nb := NewNIPostBuilder(minerID, postProvider, poetProvider,
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 	hash := types.BytesToHash([]byte("anton"))
 	ctx, close := context.WithCancel(context.Background())
@@ -439,7 +443,7 @@ func TestNIPSTBuilder_PoetUnstable(t *testing.T) {
 
 	poetDb := &poetDbMock{}
 
-	nb := NewNIPostBuilder(minerID, postProver, poetProver,
+	nb := NewNIPostBuilder(minerID, postProver, []PoetProvingServiceClient{poetProver},
 		poetDb, database.NewMemDatabase(), logtest.New(t))
 
 	t.Run("PoetServiceID", func(t *testing.T) {
@@ -468,3 +472,238 @@ func TestNIPSTBuilder_PoetUnstable(t *testing.T) {
 		require.Nil(t, nipst)
 	})
 }
+
+// poetsDbMock serves a proof for every PoET service that has one, keyed by PoET service ID.
+type poetsDbMock struct {
+	proofs      map[string][]byte
+	memberships map[string]map[types.Hash32]bool
+	mu          sync.Mutex
+	unsubscribe []string
+}
+
+// A compile time check to ensure that poetsDbMock fully implements poetDbAPI.
+var _ poetDbAPI = (*poetsDbMock)(nil)
+
+func (p *poetsDbMock) SubscribeToProofRef(poetID []byte, roundID string) chan []byte {
+	ch := make(chan []byte, 1)
+	if ref, exists := p.proofs[string(poetID)]; exists {
+		ch <- ref
+	}
+	return ch
+}
+
+func (p *poetsDbMock) UnsubscribeFromProofRef(poetID []byte, roundID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unsubscribe = append(p.unsubscribe, string(poetID))
+}
+
+func (p *poetsDbMock) GetMembershipMap(proofRef []byte) (map[types.Hash32]bool, error) {
+	membership, exists := p.memberships[string(proofRef)]
+	if !exists {
+		return nil, errors.New("membership not found")
+	}
+	return membership, nil
+}
+
+func poetServiceWithID(tb testing.TB, id string, submitErr error) *MockPoetProvingServiceClient {
+	poetProver, _ := newPoetServiceMock(tb)
+	poetProver.EXPECT().PoetServiceID(gomock.Any()).AnyTimes().Return([]byte(id), nil)
+	if submitErr != nil {
+		poetProver.EXPECT().Submit(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, submitErr)
+	} else {
+		poetProver.EXPECT().Submit(gomock.Any(), gomock.Any()).AnyTimes().Return(&types.PoetRound{ID: id}, nil)
+	}
+	return poetProver
+}
+
+func TestNIPostBuilder_MultiplePoets(t *testing.T) {
+	challenge := types.BytesToHash([]byte("challenge"))
+	poetDb := &poetsDbMock{
+		proofs: map[string][]byte{"excluded": []byte("ref1"), "included": []byte("ref2"), "broken": []byte("ref3")},
+		memberships: map[string]map[types.Hash32]bool{
+			"ref1": {},
+			"ref2": {challenge: true},
+		},
+	}
+
+	t.Run("FirstValidProof", func(t *testing.T) {
+		postProvider := &postSetupProviderMock{}
+		nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{
+			poetServiceWithID(t, "failed", errors.New("test")),
+			poetServiceWithID(t, "excluded", nil),
+			poetServiceWithID(t, "silent", nil),
+			poetServiceWithID(t, "included", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+		require.NoError(t, err)
+		require.NotNil(t, nipost)
+		require.Equal(t, []byte("ref2"), postProvider.challenge)
+		require.Contains(t, poetDb.unsubscribe, "silent")
+
+		// proofs are received concurrently, the excluded one may not be checked
+		require.Equal(t, 1, nb.failures["failed"])
+		require.Equal(t, 0, nb.failures["silent"])
+		require.Equal(t, 0, nb.failures["included"])
+	})
+
+	t.Run("SkipUnreliable", func(t *testing.T) {
+		failing, _ := newPoetServiceMock(t)
+		failing.EXPECT().PoetServiceID(gomock.Any()).AnyTimes().Return([]byte("failing"), nil)
+		failing.EXPECT().Submit(gomock.Any(), gomock.Any()).Times(poetServiceMaxFailures).Return(nil, errors.New("test"))
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{
+			failing,
+			poetServiceWithID(t, "included", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		for i := 0; i < poetServiceMaxFailures+2; i++ {
+			nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+			require.NoError(t, err)
+			require.NotNil(t, nipost)
+		}
+		require.True(t, nb.unreliable([]byte("failing")))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		submitted := make(chan struct{})
+		waiting, _ := newPoetServiceMock(t)
+		waiting.EXPECT().PoetServiceID(gomock.Any()).Return([]byte("waiting"), nil)
+		waiting.EXPECT().Submit(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ types.Hash32) (*types.PoetRound, error) {
+				// returns only if the challenge is submitted to the other service concurrently
+				select {
+				case <-submitted:
+					return &types.PoetRound{ID: "waiting"}, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			})
+		included, _ := newPoetServiceMock(t)
+		included.EXPECT().PoetServiceID(gomock.Any()).Return([]byte("included"), nil)
+		included.EXPECT().Submit(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, types.Hash32) (*types.PoetRound, error) {
+				close(submitted)
+				return &types.PoetRound{ID: "included"}, nil
+			})
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{waiting, included},
+			poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+		require.NoError(t, err)
+		require.NotNil(t, nipost)
+		require.Zero(t, nb.failures["waiting"])
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		hanging, _ := newPoetServiceMock(t)
+		hanging.EXPECT().PoetServiceID(gomock.Any()).Return([]byte("hanging"), nil)
+		hanging.EXPECT().Submit(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ types.Hash32) (*types.PoetRound, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{
+			hanging,
+			poetServiceWithID(t, "included", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+		nb.poetTimeout = 10 * time.Millisecond
+
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+		require.NoError(t, err)
+		require.NotNil(t, nipost)
+		require.Equal(t, 1, nb.failures["hanging"])
+	})
+
+	t.Run("MembershipError", func(t *testing.T) {
+		postProvider := &postSetupProviderMock{}
+		nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{
+			poetServiceWithID(t, "broken", nil),
+			poetServiceWithID(t, "included", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+		require.NoError(t, err)
+		require.NotNil(t, nipost)
+		require.Equal(t, []byte("ref2"), postProvider.challenge)
+	})
+
+	t.Run("FailuresPersisted", func(t *testing.T) {
+		store := database.NewMemDatabase()
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{
+			poetServiceWithID(t, "failed", errors.New("test")),
+			poetServiceWithID(t, "included", nil),
+		}, poetDb, store, logtest.New(t))
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+		require.NoError(t, err)
+		require.NotNil(t, nipost)
+		require.Equal(t, 1, nb.failures["failed"])
+
+		nb.updatePoETProvers([]PoetProvingServiceClient{poetServiceWithID(t, "failed", errors.New("test"))})
+		require.Equal(t, 1, nb.failures["failed"])
+
+		restarted := NewNIPostBuilder(minerID, &postSetupProviderMock{}, nil, poetDb, store, logtest.New(t))
+		require.Equal(t, map[string]int{"failed": 1}, restarted.failures)
+	})
+
+	t.Run("AllUnreliable", func(t *testing.T) {
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{
+			poetServiceWithID(t, "failed", errors.New("test")),
+			poetServiceWithID(t, "excluded", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		for i := 0; i < poetServiceMaxFailures+1; i++ {
+			challenge := types.BytesToHash([]byte{byte(i)})
+			nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+			require.ErrorIs(t, err, ErrPoetServiceUnstable)
+			require.Nil(t, nipost)
+		}
+		// unreliable services are still used if there are no reliable ones
+		require.Equal(t, poetServiceMaxFailures+1, nb.failures["failed"])
+		require.Equal(t, poetServiceMaxFailures+1, nb.failures["excluded"])
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		nb := NewNIPostBuilder(minerID, &postSetupProviderMock{}, []PoetProvingServiceClient{
+			poetServiceWithID(t, "silent", nil),
+			poetServiceWithID(t, "another silent", nil),
+		}, poetDb, database.NewMemDatabase(), logtest.New(t))
+
+		nipost, err := nb.BuildNIPost(context.TODO(), &challenge, closedChan)
+		require.ErrorIs(t, err, ErrATXChallengeExpired)
+		require.Nil(t, nipost)
+		require.Equal(t, 1, nb.failures["silent"])
+		require.Equal(t, 1, nb.failures["another silent"])
+	})
+}
+
+func TestNIPostBuilder_LegacyState(t *testing.T) {
+	challenge := types.BytesToHash([]byte("challenge"))
+	poetDb := &poetsDbMock{
+		proofs:      map[string][]byte{"included": []byte("ref")},
+		memberships: map[string]map[types.Hash32]bool{"ref": {challenge: true}},
+	}
+	store := database.NewMemDatabase()
+	legacy := &legacyBuilderState{
+		Challenge:     challenge,
+		NIPost:        &types.NIPost{Challenge: &challenge},
+		PoetRound:     &types.PoetRound{ID: "round"},
+		PoetServiceID: []byte("included"),
+	}
+	bts, err := types.InterfaceToBytes(&legacy)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(legacyNIPostBuildStateKey(), bts))
+
+	// challenge is not submitted again
+	poetProvider, _ := newPoetServiceMock(t)
+	postProvider := &postSetupProviderMock{}
+	nb := NewNIPostBuilder(minerID, postProvider, []PoetProvingServiceClient{poetProvider}, poetDb, store, logtest.New(t))
+	nipost, err := nb.BuildNIPost(context.TODO(), &challenge, nil)
+	require.NoError(t, err)
+	require.NotNil(t, nipost)
+	require.Equal(t, []byte("ref"), postProvider.challenge)
+
+	bts, err = store.Get(legacyNIPostBuildStateKey())
+	require.NoError(t, err)
+	require.Empty(t, bts)
+}
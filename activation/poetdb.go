@@ -13,21 +13,37 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/poets"
 )
 
 type poetProofKey [sha256.Size]byte
 
 // PoetDb is a database for PoET proofs.
 type PoetDb struct {
-	store                     database.Database
+	db                        *sql.Database
 	poetProofRefSubscriptions map[poetProofKey][]chan []byte
 	log                       log.Log
 	mu                        sync.Mutex
 }
 
 // NewPoetDb returns a new PoET DB.
-func NewPoetDb(store database.Database, log log.Log) *PoetDb {
-	return &PoetDb{store: store, poetProofRefSubscriptions: make(map[poetProofKey][]chan []byte), log: log}
+func NewPoetDb(db *sql.Database, log log.Log) *PoetDb {
+	return &PoetDb{db: db, poetProofRefSubscriptions: make(map[poetProofKey][]chan []byte), log: log}
+}
+
+// Proofs exports the PoET proofs database for the fetcher.
+func (db *PoetDb) Proofs() database.Getter {
+	return &poetFetcherDB{db: db.db}
+}
+
+type poetFetcherDB struct {
+	db *sql.Database
+}
+
+// Get PoET proof message, by proof reference.
+func (db *poetFetcherDB) Get(ref []byte) ([]byte, error) {
+	return poets.Get(db.db, ref)
 }
 
 // HasProof returns true if the database contains a proof with the given reference, or false otherwise.
@@ -88,18 +104,8 @@ func (db *PoetDb) storeProof(proofMessage *types.PoetProofMessage) error {
 		return fmt.Errorf("could not marshal proof message: %v", err)
 	}
 
-	batch := db.store.NewBatch()
-	if err := batch.Put(ref, messageBytes); err != nil {
-		return fmt.Errorf("failed to store poet proof for poetId %x round %s: %v",
-			proofMessage.PoetServiceID[:5], proofMessage.RoundID, err)
-	}
-	key := makeKey(proofMessage.PoetServiceID, proofMessage.RoundID)
-	if err := batch.Put(key[:], ref); err != nil {
-		return fmt.Errorf("failed to store poet proof index entry for poetId %x round %s: %v",
-			proofMessage.PoetServiceID[:5], proofMessage.RoundID, err)
-	}
-	if err := batch.Write(); err != nil {
-		return fmt.Errorf("failed to store poet proof and index for poetId %x round %s: %v",
+	if err := poets.Add(db.db, ref, messageBytes, proofMessage.PoetServiceID, proofMessage.RoundID); err != nil {
+		return fmt.Errorf("failed to store poet proof for poetId %x round %s: %w",
 			proofMessage.PoetServiceID[:5], proofMessage.RoundID, err)
 	}
 	db.log.With().Info("stored poet proof",
@@ -107,7 +113,7 @@ func (db *PoetDb) storeProof(proofMessage *types.PoetProofMessage) error {
 		log.String("round_id", proofMessage.RoundID),
 		log.String("poet_service_id", fmt.Sprintf("%x", proofMessage.PoetServiceID[:5])),
 	)
-	db.publishProofRef(key, ref)
+	db.publishProofRef(makeKey(proofMessage.PoetServiceID, proofMessage.RoundID), ref)
 	return nil
 }
 
@@ -118,7 +124,7 @@ func (db *PoetDb) SubscribeToProofRef(poetID []byte, roundID string) chan []byte
 	ch := make(chan []byte, 1)
 	db.addSubscription(key, ch)
 
-	if poetProofRef, err := db.getProofRef(key); err == nil {
+	if poetProofRef, err := db.getProofRef(poetID, roundID); err == nil {
 		db.publishProofRef(key, poetProofRef)
 	}
 
@@ -139,10 +145,10 @@ func (db *PoetDb) UnsubscribeFromProofRef(poetID []byte, roundID string) {
 	delete(db.poetProofRefSubscriptions, makeKey(poetID, roundID))
 }
 
-func (db *PoetDb) getProofRef(key poetProofKey) ([]byte, error) {
-	proofRef, err := db.store.Get(key[:])
+func (db *PoetDb) getProofRef(poetID []byte, roundID string) ([]byte, error) {
+	proofRef, err := poets.GetRef(db.db, poetID, roundID)
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch poet proof for key %x: %v", key[:5], err)
+		return nil, fmt.Errorf("could not fetch poet proof for poet ID %x in round %v: %w", poetID, roundID, err)
 	}
 	return proofRef, nil
 }
@@ -159,7 +165,7 @@ func (db *PoetDb) publishProofRef(key poetProofKey, poetProofRef []byte) {
 
 // GetProofMessage returns the originally received PoET proof message.
 func (db *PoetDb) GetProofMessage(proofRef []byte) ([]byte, error) {
-	proof, err := db.store.Get(proofRef)
+	proof, err := poets.Get(db.db, proofRef)
 	if err != nil {
 		return proof, fmt.Errorf("get proof from store: %w", err)
 	}
@@ -171,7 +177,7 @@ func (db *PoetDb) GetProofMessage(proofRef []byte) ([]byte, error) {
 func (db *PoetDb) GetMembershipMap(proofRef []byte) (map[types.Hash32]bool, error) {
 	proofMessageBytes, err := db.GetProofMessage(proofRef)
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch poet proof for ref %x: %v", proofRef[:5], err)
+		return nil, fmt.Errorf("could not fetch poet proof for ref %x: %w", proofRef[:5], err)
 	}
 	var proofMessage types.PoetProofMessage
 	if err := types.BytesToInterface(proofMessageBytes, &proofMessage); err != nil {
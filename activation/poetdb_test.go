@@ -12,14 +12,14 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/codec"
 	"github.com/spacemeshos/go-spacemesh/common/types"
-	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
 func TestPoetDbHappyFlow(t *testing.T) {
 	r := require.New(t)
 
-	poetDb := NewPoetDb(database.NewMemDatabase(), logtest.New(t))
+	poetDb := NewPoetDb(sql.InMemory(), logtest.New(t))
 
 	file, err := os.Open(filepath.Join("test_resources", "poet.proof"))
 	r.NoError(err)
@@ -43,7 +43,7 @@ func TestPoetDbHappyFlow(t *testing.T) {
 	})
 	r.NoError(err)
 
-	ref, err := poetDb.getProofRef(makeKey(poetID, roundID))
+	ref, err := poetDb.getProofRef(poetID, roundID)
 	r.NoError(err)
 
 	proofBytes, err := types.InterfaceToBytes(poetProof)
@@ -60,7 +60,7 @@ func TestPoetDbHappyFlow(t *testing.T) {
 func TestPoetDbInvalidPoetProof(t *testing.T) {
 	r := require.New(t)
 
-	poetDb := NewPoetDb(database.NewMemDatabase(), logtest.New(t))
+	poetDb := NewPoetDb(sql.InMemory(), logtest.New(t))
 
 	file, err := os.Open(filepath.Join("test_resources", "poet.proof"))
 	r.NoError(err)
@@ -82,23 +82,22 @@ func TestPoetDbInvalidPoetProof(t *testing.T) {
 func TestPoetDbNonExistingKeys(t *testing.T) {
 	r := require.New(t)
 
-	poetDb := NewPoetDb(database.NewMemDatabase(), logtest.New(t))
+	poetDb := NewPoetDb(sql.InMemory(), logtest.New(t))
 
 	poetID := []byte("poet_id_123456")
 
-	key := makeKey(poetID, "0")
-	_, err := poetDb.getProofRef(key)
-	r.EqualError(err, fmt.Sprintf("could not fetch poet proof for key %x: get value: leveldb: not found", key[:5]))
+	_, err := poetDb.getProofRef(poetID, "0")
+	r.ErrorIs(err, sql.ErrNotFound)
 
 	ref := []byte("abcde")
 	_, err = poetDb.GetMembershipMap(ref)
-	r.EqualError(err, fmt.Sprintf("could not fetch poet proof for ref %x: get proof from store: get value: leveldb: not found", ref[:5]))
+	r.ErrorIs(err, sql.ErrNotFound)
 }
 
 func TestPoetDb_SubscribeToPoetProofRef(t *testing.T) {
 	r := require.New(t)
 
-	poetDb := NewPoetDb(database.NewMemDatabase(), logtest.New(t))
+	poetDb := NewPoetDb(sql.InMemory(), logtest.New(t))
 
 	poetID := []byte("poet_id_123456")
 
@@ -11,9 +11,11 @@ const (
 	defaultGRPCServerInterface     = ""
 	defaultStartJSONServer         = false
 	defaultJSONServerPort          = 9093
+	defaultStartBeaconService      = false
 	defaultStartDebugService       = false
 	defaultStartGatewayService     = false
 	defaultStartGlobalStateService = false
+	defaultStartHareService        = false
 	defaultStartMeshService        = false
 	defaultStartNodeService        = false
 	defaultStartSmesherService     = false
@@ -28,9 +30,11 @@ type Config struct {
 	StartJSONServer     bool     `mapstructure:"json-server"`
 	JSONServerPort      int      `mapstructure:"json-port"`
 	// no direct command line flags for these
+	StartBeaconService      bool
 	StartDebugService       bool
 	StartGatewayService     bool
 	StartGlobalStateService bool
+	StartHareService        bool
 	StartMeshService        bool
 	StartNodeService        bool
 	StartSmesherService     bool
@@ -50,9 +54,11 @@ func DefaultConfig() Config {
 		GrpcServerInterface:     defaultGRPCServerInterface,
 		StartJSONServer:         defaultStartJSONServer,
 		JSONServerPort:          defaultJSONServerPort,
+		StartBeaconService:      defaultStartBeaconService,
 		StartDebugService:       defaultStartDebugService,
 		StartGatewayService:     defaultStartGatewayService,
 		StartGlobalStateService: defaultStartGlobalStateService,
+		StartHareService:        defaultStartHareService,
 		StartMeshService:        defaultStartMeshService,
 		StartNodeService:        defaultStartNodeService,
 		StartSmesherService:     defaultStartSmesherService,
@@ -74,12 +80,16 @@ func (s *Config) ParseServicesList() error {
 	// Make sure all enabled GRPC services are known
 	for _, svc := range s.StartGrpcServices {
 		switch svc {
+		case "beacon":
+			s.StartBeaconService = true
 		case "debug":
 			s.StartDebugService = true
 		case "gateway":
 			s.StartGatewayService = true
 		case "globalstate":
 			s.StartGlobalStateService = true
+		case "hare":
+			s.StartHareService = true
 		case "mesh":
 			s.StartMeshService = true
 		case "node":
@@ -96,9 +106,11 @@ func (s *Config) ParseServicesList() error {
 	// If JSON gateway server is enabled, make sure at least one
 	// GRPC service is also enabled
 	if s.StartJSONServer &&
+		!s.StartBeaconService &&
 		!s.StartDebugService &&
 		!s.StartGatewayService &&
 		!s.StartGlobalStateService &&
+		!s.StartHareService &&
 		!s.StartMeshService &&
 		!s.StartNodeService &&
 		!s.StartSmesherService &&
@@ -0,0 +1,84 @@
+package grpcserver
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/api"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// BeaconService exposes the progress of the beacon protocol.
+type BeaconService struct {
+	beacon api.BeaconAPI
+}
+
+// RegisterService registers this service with a grpc server instance.
+func (s BeaconService) RegisterService(server *Server) {
+	nodepb.RegisterBeaconServiceServer(server.GrpcServer, s)
+}
+
+// NewBeaconService creates a new grpc service using config data.
+func NewBeaconService(beacon api.BeaconAPI) *BeaconService {
+	return &BeaconService{beacon: beacon}
+}
+
+// EpochProgress sends the current progress of the beacon protocol and streams the updates.
+func (s BeaconService) EpochProgress(_ *nodepb.EpochProgressRequest, stream nodepb.BeaconService_EpochProgressServer) error {
+	log.Info("GRPC BeaconService.EpochProgress")
+
+	var (
+		progressCh      <-chan interface{}
+		progressBufFull <-chan struct{}
+	)
+
+	if progressSubscription := events.SubscribeBeaconProgress(); progressSubscription != nil {
+		progressCh, progressBufFull = consumeEvents(stream.Context(), progressSubscription)
+	}
+
+	if err := stream.Send(convertBeaconProgress(s.beacon.Progress())); err != nil {
+		return fmt.Errorf("send to stream: %w", err)
+	}
+	for {
+		select {
+		case <-progressBufFull:
+			log.Info("beacon progress buffer is full, shutting down")
+			return status.Error(codes.Canceled, errBeaconBufferFull)
+		case progressEvent, ok := <-progressCh:
+			if !ok {
+				log.Info("EpochProgress closed, shutting down")
+				return nil
+			}
+			progress := progressEvent.(events.BeaconProgress)
+			if err := stream.Send(convertBeaconProgress(progress)); err != nil {
+				return fmt.Errorf("send to stream: %w", err)
+			}
+		case <-stream.Context().Done():
+			log.Info("EpochProgress closing stream, client disconnected")
+			return nil
+		}
+	}
+}
+
+func convertBeaconProgress(p events.BeaconProgress) *nodepb.EpochProgressResponse {
+	rst := &nodepb.EpochProgressResponse{
+		Epoch:                     uint32(p.Epoch),
+		Phase:                     p.Phase,
+		Round:                     uint32(p.Round),
+		ValidProposals:            uint32(p.ValidProposals),
+		PotentiallyValidProposals: uint32(p.PotentiallyValidProposals),
+		Votes:                     p.Votes,
+		OwnVotes:                  p.OwnVotes,
+		ProposalSent:              p.ProposalSent,
+		Proposal:                  p.Proposal,
+	}
+	if p.Beacon != types.EmptyBeacon {
+		rst.Beacon = p.Beacon.Bytes()
+	}
+	return rst
+}
@@ -2,6 +2,8 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
+	"math/big"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
@@ -9,26 +11,32 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/spacemeshos/go-spacemesh/api"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
+	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/tortoise"
 )
 
 // DebugService exposes global state data, output from the STF.
 type DebugService struct {
 	mesh     api.TxAPI
 	identity api.NetworkIdentity
+	tortoise api.TortoiseAPI
 }
 
 // RegisterService registers this service with a grpc server instance.
 func (d DebugService) RegisterService(server *Server) {
 	pb.RegisterDebugServiceServer(server.GrpcServer, d)
+	nodepb.RegisterDebugServiceServer(server.GrpcServer, d)
 }
 
 // NewDebugService creates a new grpc service using config data.
-func NewDebugService(tx api.TxAPI, host api.NetworkIdentity) *DebugService {
+func NewDebugService(tx api.TxAPI, host api.NetworkIdentity, trtl api.TortoiseAPI) *DebugService {
 	return &DebugService{
 		mesh:     tx,
 		identity: host,
+		tortoise: trtl,
 	}
 }
 
@@ -65,3 +73,52 @@ func (d DebugService) Accounts(_ context.Context, in *empty.Empty) (*pb.Accounts
 func (d DebugService) NetworkInfo(ctx context.Context, _ *empty.Empty) (*pb.NetworkInfoResponse, error) {
 	return &pb.NetworkInfoResponse{Id: d.identity.ID().String()}, nil
 }
+
+// ExplainBlock returns votes, thresholds and the tortoise mode that decided validity of the block.
+func (d DebugService) ExplainBlock(ctx context.Context, in *nodepb.ExplainBlockRequest) (*nodepb.ExplainBlockResponse, error) {
+	log.Info("GRPC DebugServices.ExplainBlock")
+
+	if len(in.BlockId) != types.BlockIDSize {
+		return nil, status.Errorf(codes.InvalidArgument, "block_id must be %d bytes", types.BlockIDSize)
+	}
+	var bid types.BlockID
+	copy(bid[:], in.BlockId)
+	rst, err := d.tortoise.Explain(ctx, bid)
+	if errors.Is(err, tortoise.ErrNotInWindow) {
+		return nil, status.Errorf(codes.NotFound, "block %s is not in the tortoise window", bid)
+	} else if err != nil {
+		log.Error("Failed to explain block %s: %s", bid, err)
+		return nil, status.Errorf(codes.Internal, "error explaining block")
+	}
+
+	resp := &nodepb.ExplainBlockResponse{
+		BlockId:         rst.Block.Bytes(),
+		Layer:           &pb.LayerNumber{Number: rst.Layer.Uint32()},
+		Decided:         rst.Decided,
+		Valid:           rst.Valid,
+		Mode:            rst.Mode,
+		LocalThreshold:  ratToFloat(rst.LocalThreshold),
+		GlobalThreshold: ratToFloat(rst.GlobalThreshold),
+		Vote:            rst.Vote,
+		VoteReason:      rst.Reason,
+		Weight:          ratToFloat(rst.Weight),
+	}
+	for _, votes := range rst.Votes {
+		resp.Votes = append(resp.Votes, &nodepb.LayerVotes{
+			Layer:   &pb.LayerNumber{Number: votes.Layer.Uint32()},
+			Support: ratToFloat(votes.Support),
+			Against: ratToFloat(votes.Against),
+			Abstain: ratToFloat(votes.Abstain),
+			Ballots: uint32(votes.Ballots),
+		})
+	}
+	return resp, nil
+}
+
+func ratToFloat(rat *big.Rat) float64 {
+	if rat == nil {
+		return 0
+	}
+	f, _ := rat.Float64()
+	return f
+}
@@ -20,6 +20,8 @@ var (
 	errActivationsBufferFull = "activations buffer is full"
 	errStatusBufferFull      = "status buffer is full"
 	errErrorsBufferFull      = "errors buffer is full"
+	errReorgsBufferFull      = "reorgs buffer is full"
+	errBeaconBufferFull      = "beacon progress buffer is full"
 )
 
 func consumeEvents(ctx context.Context, subscription event.Subscription) (out <-chan interface{}, bufFull <-chan struct{}) {
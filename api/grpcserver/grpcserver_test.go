@@ -35,19 +35,24 @@ import (
 	"github.com/spacemeshos/go-spacemesh/api"
 	"github.com/spacemeshos/go-spacemesh/api/config"
 	"github.com/spacemeshos/go-spacemesh/api/mocks"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
 	"github.com/spacemeshos/go-spacemesh/cmd"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/hare"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/mempool"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	pubsubmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
 	"github.com/spacemeshos/go-spacemesh/rand"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
 	"github.com/spacemeshos/go-spacemesh/svm"
 	"github.com/spacemeshos/go-spacemesh/svm/transaction"
+	"github.com/spacemeshos/go-spacemesh/tortoise"
 )
 
 const (
@@ -344,6 +349,10 @@ func (t *TxAPIMock) ProcessedLayer() types.LayerID {
 	return layerVerified
 }
 
+func (t *TxAPIMock) QueryTransactions(transactions.Query) ([]*types.MeshTransaction, *transactions.Cursor, error) {
+	return nil, nil, t.err
+}
+
 func NewTx(nonce uint64, recipient types.Address, signer *signing.EdSigner) *types.Transaction {
 	if nonce == 0 {
 		return transaction.GenerateSpawnTransaction(signer, recipient)
@@ -502,7 +511,7 @@ type ActivationAPIMock struct {
 	UpdatePoETErr error
 }
 
-func (a *ActivationAPIMock) UpdatePoETServer(context.Context, string) error {
+func (a *ActivationAPIMock) AddPoETServer(context.Context, string) error {
 	return a.UpdatePoETErr
 }
 
@@ -1771,6 +1780,199 @@ func TestMeshService(t *testing.T) {
 	}
 }
 
+type prunedTxAPIMock struct {
+	*TxAPIMock
+	pruned types.LayerID
+}
+
+func (t *prunedTxAPIMock) GetLayer(lid types.LayerID) (*types.Layer, error) {
+	if lid.Before(t.pruned) {
+		return nil, fmt.Errorf("layer ballots: %w", sql.ErrPruned)
+	}
+	return t.TxAPIMock.GetLayer(lid)
+}
+
+func TestMeshService_PrunedLayers(t *testing.T) {
+	logtest.SetupGlobal(t)
+	mesh := &prunedTxAPIMock{TxAPIMock: txAPI, pruned: layerVerified}
+	grpcService := NewMeshService(mesh, &genTime, layersPerEpoch, networkID, layerDurationSec, layerAvgSize, txsPerBlock)
+	shutDown := launchServer(t, grpcService)
+	defer shutDown()
+
+	conn, err := grpc.Dial("localhost:"+strconv.Itoa(cfg.GrpcServerPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	c := pb.NewMeshServiceClient(conn)
+
+	_, err = c.LayersQuery(context.Background(), &pb.LayersQueryRequest{
+		StartLayer: &pb.LayerNumber{Number: layerFirst.Uint32()},
+		EndLayer:   &pb.LayerNumber{Number: layerLatest.Uint32()},
+	})
+	require.Equal(t, codes.OutOfRange, status.Code(err), err)
+	require.Contains(t, err.Error(), "pruned")
+
+	res, err := c.LayersQuery(context.Background(), &pb.LayersQueryRequest{
+		StartLayer: &pb.LayerNumber{Number: layerVerified.Uint32()},
+		EndLayer:   &pb.LayerNumber{Number: layerLatest.Uint32()},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Layer, int(layerLatest.Difference(layerVerified))+1)
+}
+
+func TestMeshService_ReorgStream(t *testing.T) {
+	logtest.SetupGlobal(t)
+	events.CloseEventReporter()
+	require.NoError(t, events.InitializeEventReporterWithOptions(""))
+	defer events.CloseEventReporter()
+
+	grpcService := NewMeshService(txAPI, &genTime, layersPerEpoch, networkID, layerDurationSec, layerAvgSize, txsPerBlock)
+	shutDown := launchServer(t, grpcService)
+	defer shutDown()
+
+	conn, err := grpc.Dial("localhost:"+strconv.Itoa(cfg.GrpcServerPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	c := nodepb.NewMeshServiceClient(conn)
+
+	stream, err := c.ReorgStream(context.Background(), &nodepb.ReorgStreamRequest{})
+	require.NoError(t, err)
+	var res *nodepb.ReorgStreamResponse
+	received := make(chan error, 1)
+	go func() {
+		var err error
+		res, err = stream.Recv()
+		received <- err
+	}()
+
+	reorg := events.Reorg{
+		RevertTo:   layerFirst,
+		Invalid:    []types.BlockID{block1.ID()},
+		Valid:      []types.BlockID{block2.ID()},
+		Reinserted: []types.TransactionID{globalTx.ID()},
+	}
+	// report until the stream is subscribed and receives the event
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case <-ticker.C:
+			events.ReportReorg(reorg)
+		case err := <-received:
+			require.NoError(t, err)
+			done = true
+		case <-timeout:
+			require.FailNow(t, "reorg wasn't streamed")
+		}
+	}
+	require.Equal(t, layerFirst.Uint32(), res.Reorg.RevertTo.Number)
+	require.Equal(t, [][]byte{block1.ID().Bytes()}, res.Reorg.InvalidBlocks)
+	require.Equal(t, [][]byte{block2.ID().Bytes()}, res.Reorg.ValidBlocks)
+	require.Len(t, res.Reorg.ReinsertedTransactions, 1)
+	require.Equal(t, globalTx.ID().Bytes(), res.Reorg.ReinsertedTransactions[0].Id)
+}
+
+type queryTxAPIMock struct {
+	*TxAPIMock
+	db *sql.Database
+}
+
+func (t *queryTxAPIMock) QueryTransactions(q transactions.Query) ([]*types.MeshTransaction, *transactions.Cursor, error) {
+	return transactions.Find(t.db, q)
+}
+
+func TestTransactionService_TransactionsQuery(t *testing.T) {
+	logtest.SetupGlobal(t)
+	db := sql.InMemory()
+	signer := signing.NewEdSigner()
+	var all []*types.Transaction
+	for i := 0; i < 10; i++ {
+		tx, err := transaction.GenerateCallTransaction(signer, addr1, uint64(i), uint64(i+1), defaultGasLimit, defaultFee)
+		require.NoError(t, err)
+		require.NoError(t, transactions.Add(db, types.NewLayerID(uint32(i/2)+1), types.BlockID{1}, tx))
+		all = append(all, tx)
+	}
+	require.NoError(t, transactions.Applied(db, all[0].ID()))
+
+	grpcService := NewTransactionService(nil, &queryTxAPIMock{TxAPIMock: txAPI, db: db}, mempoolMock, &SyncerMock{})
+	shutDown := launchServer(t, grpcService)
+	defer shutDown()
+
+	conn, err := grpc.Dial("localhost:"+strconv.Itoa(cfg.GrpcServerPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	c := nodepb.NewTransactionServiceClient(conn)
+
+	t.Run("Pagination", func(t *testing.T) {
+		var (
+			cursor []byte
+			ids    []types.TransactionID
+		)
+		for {
+			res, err := c.TransactionsQuery(context.Background(), &nodepb.TransactionsQueryRequest{
+				StartLayer: &pb.LayerNumber{Number: 2},
+				EndLayer:   &pb.LayerNumber{Number: 4},
+				MaxResults: 4,
+				Cursor:     cursor,
+			})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(res.Transactions), 4)
+			for _, tx := range res.Transactions {
+				ids = append(ids, types.TransactionID(types.BytesToHash(tx.Transaction.Id.Id)))
+			}
+			if res.NextCursor == nil {
+				break
+			}
+			cursor = res.NextCursor
+		}
+		require.Len(t, ids, 6)
+		for _, tx := range all[2:8] {
+			require.Contains(t, ids, tx.ID())
+		}
+	})
+	t.Run("Filters", func(t *testing.T) {
+		res, err := c.TransactionsQuery(context.Background(), &nodepb.TransactionsQueryRequest{
+			Status: []nodepb.TransactionStatus{nodepb.TransactionStatus_TRANSACTION_STATUS_PENDING},
+			Amount: &nodepb.AmountRange{Min: 2, Max: 3},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Transactions, 2)
+		require.Nil(t, res.NextCursor)
+
+		res, err = c.TransactionsQuery(context.Background(), &nodepb.TransactionsQueryRequest{
+			Status: []nodepb.TransactionStatus{nodepb.TransactionStatus_TRANSACTION_STATUS_APPLIED},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Transactions, 1)
+		require.Equal(t, all[0].ID().Bytes(), res.Transactions[0].Transaction.Id.Id)
+		require.Equal(t, uint32(1), res.Transactions[0].LayerId.Number)
+
+		res, err = c.TransactionsQuery(context.Background(), &nodepb.TransactionsQueryRequest{
+			Text: addr1.Hex()[:8],
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Transactions, len(all))
+	})
+	t.Run("InvalidArguments", func(t *testing.T) {
+		for _, req := range []*nodepb.TransactionsQueryRequest{
+			{Status: []nodepb.TransactionStatus{nodepb.TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED}},
+			{BlockId: []byte{1, 2}},
+			{Cursor: []byte{1}},
+			{StartLayer: &pb.LayerNumber{Number: 2}, EndLayer: &pb.LayerNumber{Number: 1}},
+			{Text: "recipient"},
+		} {
+			_, err := c.TransactionsQuery(context.Background(), req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+		}
+	})
+}
+
 func TestTransactionServiceSubmitUnsync(t *testing.T) {
 	logtest.SetupGlobal(t)
 	req := require.New(t)
@@ -2924,7 +3126,8 @@ func TestDebugService(t *testing.T) {
 	logtest.SetupGlobal(t)
 	ctrl := gomock.NewController(t)
 	identity := mocks.NewMockNetworkIdentity(ctrl)
-	svc := NewDebugService(txAPI, identity)
+	trtl := mocks.NewMockTortoiseAPI(ctrl)
+	svc := NewDebugService(txAPI, identity, trtl)
 	shutDown := launchServer(t, svc)
 	defer shutDown()
 
@@ -2959,6 +3162,156 @@ func TestDebugService(t *testing.T) {
 		require.NotNil(t, response)
 		require.Equal(t, id.String(), response.Id)
 	})
+	t.Run("ExplainBlock", func(t *testing.T) {
+		nc := nodepb.NewDebugServiceClient(conn)
+		bid := types.BlockID{1, 2, 3}
+		explanation := &tortoise.Explanation{
+			Block:           bid,
+			Layer:           types.NewLayerID(10),
+			Decided:         true,
+			Valid:           true,
+			Mode:            "verifying",
+			LocalThreshold:  big.NewRat(1, 2),
+			GlobalThreshold: big.NewRat(9, 2),
+			Vote:            "support",
+			Reason:          "validity",
+			Votes: []tortoise.LayerVotes{{
+				Layer:   types.NewLayerID(11),
+				Support: big.NewRat(5, 1),
+				Against: big.NewRat(1, 4),
+				Abstain: new(big.Rat),
+				Ballots: 6,
+			}},
+		}
+		trtl.EXPECT().Explain(gomock.Any(), bid).Return(explanation, nil)
+
+		response, err := nc.ExplainBlock(context.Background(), &nodepb.ExplainBlockRequest{BlockId: bid.Bytes()})
+		require.NoError(t, err)
+		require.Equal(t, bid.Bytes(), response.BlockId)
+		require.Equal(t, uint32(10), response.Layer.Number)
+		require.True(t, response.Decided)
+		require.True(t, response.Valid)
+		require.Equal(t, "verifying", response.Mode)
+		require.Equal(t, 0.5, response.LocalThreshold)
+		require.Equal(t, 4.5, response.GlobalThreshold)
+		require.Equal(t, "support", response.Vote)
+		require.Equal(t, "validity", response.VoteReason)
+		require.Zero(t, response.Weight)
+		require.Len(t, response.Votes, 1)
+		require.Equal(t, uint32(11), response.Votes[0].Layer.Number)
+		require.Equal(t, 5.0, response.Votes[0].Support)
+		require.Equal(t, 0.25, response.Votes[0].Against)
+		require.Zero(t, response.Votes[0].Abstain)
+		require.Equal(t, uint32(6), response.Votes[0].Ballots)
+
+		trtl.EXPECT().Explain(gomock.Any(), bid).Return(nil, tortoise.ErrNotInWindow)
+		_, err = nc.ExplainBlock(context.Background(), &nodepb.ExplainBlockRequest{BlockId: bid.Bytes()})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = nc.ExplainBlock(context.Background(), &nodepb.ExplainBlockRequest{BlockId: []byte{1}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestHareService(t *testing.T) {
+	logtest.SetupGlobal(t)
+	ctrl := gomock.NewController(t)
+	hareAPI := mocks.NewMockHareAPI(ctrl)
+	svc := NewHareService(hareAPI)
+	shutDown := launchServer(t, svc)
+	defer shutDown()
+
+	addr := "localhost:" + strconv.Itoa(cfg.GrpcServerPort)
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+	c := nodepb.NewHareServiceClient(conn)
+
+	lid := types.NewLayerID(10)
+	pids := []types.ProposalID{{1}, {2}}
+	hareAPI.EXPECT().InstanceStatus(lid).Return(&hare.InstanceStatus{
+		Layer:       lid,
+		Round:       6,
+		Set:         pids,
+		ProposedSet: pids[:1],
+		CommitCount: 3,
+		NotifyCount: 1,
+	}, nil)
+	response, err := c.InstanceStatus(context.Background(), &nodepb.InstanceStatusRequest{Layer: &pb.LayerNumber{Number: lid.Uint32()}})
+	require.NoError(t, err)
+	require.Equal(t, lid.Uint32(), response.Layer.Number)
+	require.Equal(t, uint32(6), response.Round)
+	require.Equal(t, uint32(1), response.Iteration)
+	require.Equal(t, "commit", response.RoundType)
+	require.Equal(t, [][]byte{pids[0].Bytes(), pids[1].Bytes()}, response.Set)
+	require.Equal(t, [][]byte{pids[0].Bytes()}, response.ProposedSet)
+	require.Equal(t, uint32(3), response.CommitCount)
+	require.Equal(t, uint32(1), response.NotifyCount)
+	require.False(t, response.Terminated)
+
+	hareAPI.EXPECT().InstanceStatus(lid).Return(nil, hare.ErrNoInstance)
+	_, err = c.InstanceStatus(context.Background(), &nodepb.InstanceStatusRequest{Layer: &pb.LayerNumber{Number: lid.Uint32()}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = c.InstanceStatus(context.Background(), &nodepb.InstanceStatusRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBeaconService_EpochProgress(t *testing.T) {
+	logtest.SetupGlobal(t)
+	events.CloseEventReporter()
+	require.NoError(t, events.InitializeEventReporterWithOptions(""))
+	defer events.CloseEventReporter()
+
+	ctrl := gomock.NewController(t)
+	beaconAPI := mocks.NewMockBeaconAPI(ctrl)
+	svc := NewBeaconService(beaconAPI)
+	shutDown := launchServer(t, svc)
+	defer shutDown()
+
+	conn, err := grpc.Dial("localhost:"+strconv.Itoa(cfg.GrpcServerPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+	c := nodepb.NewBeaconServiceClient(conn)
+
+	beaconAPI.EXPECT().Progress().Return(events.BeaconProgress{
+		Epoch:    5,
+		Phase:    "idle",
+		Proposal: "node is not synced",
+	})
+	stream, err := c.EpochProgress(context.Background(), &nodepb.EpochProgressRequest{})
+	require.NoError(t, err)
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint32(5), res.Epoch)
+	require.Equal(t, "idle", res.Phase)
+	require.Equal(t, "node is not synced", res.Proposal)
+	require.Empty(t, res.Beacon)
+
+	// stream is subscribed before the current progress is sent
+	beacon := types.RandomBeacon()
+	events.ReportBeaconProgress(events.BeaconProgress{
+		Epoch:                     6,
+		Phase:                     "finished",
+		Round:                     2,
+		ValidProposals:            3,
+		PotentiallyValidProposals: 1,
+		Votes:                     []uint32{4, 3, 2},
+		OwnVotes:                  3,
+		ProposalSent:              true,
+		Beacon:                    beacon,
+	})
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint32(6), res.Epoch)
+	require.Equal(t, "finished", res.Phase)
+	require.Equal(t, uint32(2), res.Round)
+	require.Equal(t, uint32(3), res.ValidProposals)
+	require.Equal(t, uint32(1), res.PotentiallyValidProposals)
+	require.Equal(t, []uint32{4, 3, 2}, res.Votes)
+	require.Equal(t, uint32(3), res.OwnVotes)
+	require.True(t, res.ProposalSent)
+	require.Equal(t, beacon.Bytes(), res.Beacon)
 }
 
 func TestGatewayService(t *testing.T) {
@@ -2999,16 +3352,6 @@ func TestGatewayService(t *testing.T) {
 	require.NoError(t, err, "expected request to succeed")
 }
 
-type appliedTxsMock struct{}
-
-func (appliedTxsMock) Put(key []byte, value []byte) error { return nil }
-func (appliedTxsMock) Delete(key []byte) error            { panic("implement me") }
-func (appliedTxsMock) Get(key []byte) ([]byte, error)     { panic("implement me") }
-func (appliedTxsMock) Has(key []byte) (bool, error)       { panic("implement me") }
-func (appliedTxsMock) Close()                             { panic("implement me") }
-func (appliedTxsMock) NewBatch() database.Batch           { panic("implement me") }
-func (appliedTxsMock) Find(key []byte) database.Iterator  { panic("implement me") }
-
 type ProjectorMock struct {
 	nonceDiff   uint64
 	balanceDiff uint64
@@ -3120,7 +3463,7 @@ func TestEventsReceived(t *testing.T) {
 	pool.Put(globalTx.ID(), globalTx)
 
 	lg := logtest.New(t).WithName("svm")
-	svm := svm.New(database.NewMemDatabase(), appliedTxsMock{}, &ProjectorMock{}, mempool.NewTxMemPool(), lg)
+	svm := svm.New(database.NewMemDatabase(), sql.InMemory(), &ProjectorMock{}, mempool.NewTxMemPool(), lg)
 	time.Sleep(100 * time.Millisecond)
 
 	rewards := map[types.Address]uint64{
@@ -0,0 +1,69 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/api"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// HareService exposes the state of the hare consensus processes.
+type HareService struct {
+	hare api.HareAPI
+}
+
+// RegisterService registers this service with a grpc server instance.
+func (s HareService) RegisterService(server *Server) {
+	nodepb.RegisterHareServiceServer(server.GrpcServer, s)
+}
+
+// NewHareService creates a new grpc service using config data.
+func NewHareService(hare api.HareAPI) *HareService {
+	return &HareService{hare: hare}
+}
+
+// InstanceStatus returns the state of the consensus process for the layer.
+func (s HareService) InstanceStatus(_ context.Context, in *nodepb.InstanceStatusRequest) (*nodepb.InstanceStatusResponse, error) {
+	log.Info("GRPC HareService.InstanceStatus")
+
+	if in.Layer == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "layer must be provided")
+	}
+	lid := types.NewLayerID(in.Layer.Number)
+	rst, err := s.hare.InstanceStatus(lid)
+	if errors.Is(err, hare.ErrNoInstance) {
+		return nil, status.Errorf(codes.NotFound, "no consensus process for layer %s", lid)
+	} else if err != nil {
+		log.Error("Failed to get status of the consensus process for layer %s: %s", lid, err)
+		return nil, status.Errorf(codes.Internal, "error getting consensus process status")
+	}
+	return &nodepb.InstanceStatusResponse{
+		Layer:       &pb.LayerNumber{Number: rst.Layer.Uint32()},
+		Round:       rst.Round,
+		Iteration:   rst.Iteration(),
+		RoundType:   rst.RoundType(),
+		Set:         proposalIDsToBytes(rst.Set),
+		ProposedSet: proposalIDsToBytes(rst.ProposedSet),
+		CommitCount: uint32(rst.CommitCount),
+		NotifyCount: uint32(rst.NotifyCount),
+		Terminated:  rst.Terminated,
+		Completed:   rst.Completed,
+		Certified:   rst.Certified,
+	}, nil
+}
+
+func proposalIDsToBytes(pids []types.ProposalID) [][]byte {
+	rst := make([][]byte, 0, len(pids))
+	for _, pid := range pids {
+		rst = append(rst, pid.Bytes())
+	}
+	return rst
+}
@@ -2,6 +2,7 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
@@ -9,9 +10,11 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/spacemeshos/go-spacemesh/api"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
 // MeshService exposes mesh data such as accounts, blocks, and transactions.
@@ -28,6 +31,7 @@ type MeshService struct {
 // RegisterService registers this service with a grpc server instance.
 func (s MeshService) RegisterService(server *Server) {
 	pb.RegisterMeshServiceServer(server.GrpcServer, s)
+	nodepb.RegisterMeshServiceServer(server.GrpcServer, s)
 }
 
 // NewMeshService creates a new service using config data.
@@ -122,6 +126,9 @@ func (s MeshService) getFilteredActivations(ctx context.Context, startLayer type
 	var atxids []types.ATXID
 	for l := startLayer; !l.After(s.Mesh.LatestLayer()); l = l.Add(1) {
 		layer, err := s.Mesh.GetLayer(l)
+		if errors.Is(err, sql.ErrPruned) {
+			return nil, errLayerPruned(l)
+		}
 		if layer == nil || err != nil {
 			return nil, status.Errorf(codes.Internal, "error retrieving layer data")
 		}
@@ -286,6 +293,12 @@ func convertActivation(a *types.ActivationTx) (*pb.Activation, error) {
 	}, nil
 }
 
+// errLayerPruned is returned for layers which bodies were removed by the node according to its
+// retention policy. Such layers won't become available on retry.
+func errLayerPruned(lid types.LayerID) error {
+	return status.Errorf(codes.OutOfRange, "layer %d data was pruned", lid.Uint32())
+}
+
 func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layerStatus pb.Layer_LayerStatus) (*pb.Layer, error) {
 	// Load all block data
 	var blocks []*pb.Block
@@ -302,6 +315,9 @@ func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layer
 	// between these two is a gray area: do we define this as an
 	// internal or an input error? For now, all missing layers produce
 	// internal errors.
+	if errors.Is(err, sql.ErrPruned) {
+		return nil, errLayerPruned(layerID)
+	}
 	if err != nil {
 		log.With().Error("could not read layer from database", layerID, log.Err(err))
 		return nil, status.Errorf(codes.Internal, "error reading layer data")
@@ -408,6 +424,9 @@ func (s MeshService) LayersQuery(ctx context.Context, in *pb.LayersQueryRequest)
 		// between these two is a gray area: do we define this as an
 		// internal or an input error? For now, all missing layers produce
 		// internal errors.
+		if errors.Is(err, sql.ErrPruned) {
+			return nil, errLayerPruned(l)
+		}
 		if layer == nil || err != nil {
 			log.With().Error("error retrieving layer data", log.Err(err))
 			return nil, status.Errorf(codes.Internal, "error retrieving layer data")
@@ -557,6 +576,54 @@ func (s MeshService) LayerStream(_ *pb.LayerStreamRequest, stream pb.MeshService
 	}
 }
 
+// ReorgStream exposes a stream of state reversions.
+func (s MeshService) ReorgStream(_ *nodepb.ReorgStreamRequest, stream nodepb.MeshService_ReorgStreamServer) error {
+	log.Info("GRPC MeshService.ReorgStream")
+
+	var (
+		reorgCh       <-chan interface{}
+		reorgsBufFull <-chan struct{}
+	)
+
+	if reorgsSubscription := events.SubscribeReorgs(); reorgsSubscription != nil {
+		reorgCh, reorgsBufFull = consumeEvents(stream.Context(), reorgsSubscription)
+	}
+
+	for {
+		select {
+		case <-reorgsBufFull:
+			log.Info("reorgs buffer is full, shutting down")
+			return status.Error(codes.Canceled, errReorgsBufferFull)
+		case reorgEvent, ok := <-reorgCh:
+			if !ok {
+				log.Info("ReorgStream closed, shutting down")
+				return nil
+			}
+			reorg := reorgEvent.(events.Reorg)
+			if err := stream.Send(&nodepb.ReorgStreamResponse{Reorg: convertReorg(reorg)}); err != nil {
+				return fmt.Errorf("send to stream: %w", err)
+			}
+		case <-stream.Context().Done():
+			log.Info("ReorgStream closing stream, client disconnected")
+			return nil
+		}
+	}
+}
+
+func convertReorg(reorg events.Reorg) *nodepb.Reorg {
+	rst := &nodepb.Reorg{RevertTo: &pb.LayerNumber{Number: reorg.RevertTo.Uint32()}}
+	for _, bid := range reorg.Invalid {
+		rst.InvalidBlocks = append(rst.InvalidBlocks, bid.Bytes())
+	}
+	for _, bid := range reorg.Valid {
+		rst.ValidBlocks = append(rst.ValidBlocks, bid.Bytes())
+	}
+	for _, tid := range reorg.Reinserted {
+		rst.ReinsertedTransactions = append(rst.ReinsertedTransactions, &pb.TransactionId{Id: tid.Bytes()})
+	}
+	return rst
+}
+
 func convertLayerStatus(in int) pb.Layer_LayerStatus {
 	switch in {
 	case events.LayerStatusTypeApproved:
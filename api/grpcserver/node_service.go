@@ -125,9 +125,10 @@ func (s NodeService) Shutdown(context.Context, *pb.ShutdownRequest) (*pb.Shutdow
 	}, nil
 }
 
-// UpdatePoetServer update server that is used for generating PoETs.
+// UpdatePoetServer adds server to the servers that are used for generating PoETs. Servers that are
+// already used are kept, the challenge is submitted to all of them.
 func (s NodeService) UpdatePoetServer(ctx context.Context, req *pb.UpdatePoetServerRequest) (*pb.UpdatePoetServerResponse, error) {
-	err := s.AtxAPI.UpdatePoETServer(ctx, req.Url)
+	err := s.AtxAPI.AddPoETServer(ctx, req.Url)
 	if err == nil {
 		return &pb.UpdatePoetServerResponse{
 			Status: &rpcstatus.Status{Code: int32(code.Code_OK)},
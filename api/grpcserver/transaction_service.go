@@ -3,6 +3,7 @@ package grpcserver
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
@@ -12,12 +13,17 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/spacemeshos/go-spacemesh/api"
+	"github.com/spacemeshos/go-spacemesh/api/nodepb"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
 	"github.com/spacemeshos/go-spacemesh/svm"
 )
 
+// maxTransactionsQueryResults is a maximal number of transactions returned by a single TransactionsQuery.
+const maxTransactionsQueryResults = 1000
+
 // TransactionService exposes transaction data, and a submit tx endpoint.
 type TransactionService struct {
 	publisher api.Publisher // P2P Swarm
@@ -29,6 +35,7 @@ type TransactionService struct {
 // RegisterService registers this service with a grpc server instance.
 func (s TransactionService) RegisterService(server *Server) {
 	pb.RegisterTransactionServiceServer(server.GrpcServer, s)
+	nodepb.RegisterTransactionServiceServer(server.GrpcServer, s)
 }
 
 // NewTransactionService creates a new grpc service using config data.
@@ -167,6 +174,100 @@ func (s TransactionService) TransactionsState(_ context.Context, in *pb.Transact
 	return res, nil
 }
 
+// TransactionsQuery returns a page of transactions that match the request.
+func (s TransactionService) TransactionsQuery(_ context.Context, in *nodepb.TransactionsQueryRequest) (*nodepb.TransactionsQueryResponse, error) {
+	log.Info("GRPC TransactionService.TransactionsQuery")
+
+	query := transactions.Query{Limit: maxTransactionsQueryResults}
+	if in.StartLayer != nil {
+		query.From = types.NewLayerID(in.StartLayer.Number)
+	}
+	if in.EndLayer != nil {
+		query.To = types.NewLayerID(in.EndLayer.Number)
+		if query.To.Before(query.From) {
+			return nil, status.Error(codes.InvalidArgument, "`EndLayer` must not be before `StartLayer`")
+		}
+	}
+	for _, st := range in.Status {
+		switch st {
+		case nodepb.TransactionStatus_TRANSACTION_STATUS_PENDING:
+			query.Status = append(query.Status, transactions.StatusPending)
+		case nodepb.TransactionStatus_TRANSACTION_STATUS_APPLIED:
+			query.Status = append(query.Status, transactions.StatusApplied)
+		case nodepb.TransactionStatus_TRANSACTION_STATUS_DELETED:
+			query.Status = append(query.Status, transactions.StatusDeleted)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "`Status` %s is not supported", st)
+		}
+	}
+	if in.Amount != nil {
+		query.Amount = &transactions.Range{Min: in.Amount.Min, Max: in.Amount.Max}
+	}
+	if in.Fee != nil {
+		query.Fee = &transactions.Range{Min: in.Fee.Min, Max: in.Fee.Max}
+	}
+	if len(in.BlockId) > 0 {
+		if len(in.BlockId) != types.BlockIDSize {
+			return nil, status.Errorf(codes.InvalidArgument, "`BlockId` must be %d bytes long", types.BlockIDSize)
+		}
+		var bid types.BlockID
+		copy(bid[:], in.BlockId)
+		query.Block = &bid
+	}
+	if len(in.Text) > 0 {
+		if err := transactions.ValidateText(in.Text); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "`Text` is invalid: %v", err)
+		}
+		query.Text = in.Text
+	}
+	if in.MaxResults > 0 && in.MaxResults < maxTransactionsQueryResults {
+		query.Limit = int(in.MaxResults)
+	}
+	if len(in.Cursor) > 0 {
+		cursor, err := decodeTransactionsCursor(in.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		query.After = cursor
+	}
+
+	txs, next, err := s.Mesh.QueryTransactions(query)
+	if err != nil {
+		log.With().Error("failed to query transactions", log.Err(err))
+		return nil, status.Error(codes.Internal, "error querying transactions")
+	}
+	res := &nodepb.TransactionsQueryResponse{}
+	for _, tx := range txs {
+		res.Transactions = append(res.Transactions, &pb.MeshTransaction{
+			Transaction: convertTransaction(&tx.Transaction),
+			LayerId:     &pb.LayerNumber{Number: tx.LayerID.Uint32()},
+		})
+	}
+	if next != nil {
+		res.NextCursor = encodeTransactionsCursor(next)
+	}
+	return res, nil
+}
+
+// transactions cursor is an opaque for clients concatenation of the layer and transaction id.
+const transactionsCursorSize = 4 + types.TransactionIDSize
+
+func encodeTransactionsCursor(cursor *transactions.Cursor) []byte {
+	buf := make([]byte, transactionsCursorSize)
+	binary.BigEndian.PutUint32(buf, cursor.Layer.Uint32())
+	copy(buf[4:], cursor.ID[:])
+	return buf
+}
+
+func decodeTransactionsCursor(buf []byte) (*transactions.Cursor, error) {
+	if len(buf) != transactionsCursorSize {
+		return nil, fmt.Errorf("`Cursor` must be %d bytes long", transactionsCursorSize)
+	}
+	cursor := &transactions.Cursor{Layer: types.NewLayerID(binary.BigEndian.Uint32(buf))}
+	copy(cursor.ID[:], buf[4:])
+	return cursor, nil
+}
+
 // STREAMS
 
 // TransactionsStateStream exposes a stream of tx data.
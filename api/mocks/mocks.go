@@ -1,14 +1,19 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/spacemeshos/go-spacemesh/api (interfaces: NetworkIdentity)
+// Source: github.com/spacemeshos/go-spacemesh/api (interfaces: NetworkIdentity,TortoiseAPI,HareAPI,BeaconAPI)
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	types "github.com/spacemeshos/go-spacemesh/common/types"
+	events "github.com/spacemeshos/go-spacemesh/events"
+	hare "github.com/spacemeshos/go-spacemesh/hare"
+	tortoise "github.com/spacemeshos/go-spacemesh/tortoise"
 )
 
 // MockNetworkIdentity is a mock of NetworkIdentity interface.
@@ -47,3 +52,116 @@ func (mr *MockNetworkIdentityMockRecorder) ID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockNetworkIdentity)(nil).ID))
 }
+
+// MockTortoiseAPI is a mock of TortoiseAPI interface.
+type MockTortoiseAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockTortoiseAPIMockRecorder
+}
+
+// MockTortoiseAPIMockRecorder is the mock recorder for MockTortoiseAPI.
+type MockTortoiseAPIMockRecorder struct {
+	mock *MockTortoiseAPI
+}
+
+// NewMockTortoiseAPI creates a new mock instance.
+func NewMockTortoiseAPI(ctrl *gomock.Controller) *MockTortoiseAPI {
+	mock := &MockTortoiseAPI{ctrl: ctrl}
+	mock.recorder = &MockTortoiseAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTortoiseAPI) EXPECT() *MockTortoiseAPIMockRecorder {
+	return m.recorder
+}
+
+// Explain mocks base method.
+func (m *MockTortoiseAPI) Explain(arg0 context.Context, arg1 types.BlockID) (*tortoise.Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", arg0, arg1)
+	ret0, _ := ret[0].(*tortoise.Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockTortoiseAPIMockRecorder) Explain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockTortoiseAPI)(nil).Explain), arg0, arg1)
+}
+
+// MockHareAPI is a mock of HareAPI interface.
+type MockHareAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockHareAPIMockRecorder
+}
+
+// MockHareAPIMockRecorder is the mock recorder for MockHareAPI.
+type MockHareAPIMockRecorder struct {
+	mock *MockHareAPI
+}
+
+// NewMockHareAPI creates a new mock instance.
+func NewMockHareAPI(ctrl *gomock.Controller) *MockHareAPI {
+	mock := &MockHareAPI{ctrl: ctrl}
+	mock.recorder = &MockHareAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHareAPI) EXPECT() *MockHareAPIMockRecorder {
+	return m.recorder
+}
+
+// InstanceStatus mocks base method.
+func (m *MockHareAPI) InstanceStatus(arg0 types.LayerID) (*hare.InstanceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceStatus", arg0)
+	ret0, _ := ret[0].(*hare.InstanceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceStatus indicates an expected call of InstanceStatus.
+func (mr *MockHareAPIMockRecorder) InstanceStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceStatus", reflect.TypeOf((*MockHareAPI)(nil).InstanceStatus), arg0)
+}
+
+// MockBeaconAPI is a mock of BeaconAPI interface.
+type MockBeaconAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockBeaconAPIMockRecorder
+}
+
+// MockBeaconAPIMockRecorder is the mock recorder for MockBeaconAPI.
+type MockBeaconAPIMockRecorder struct {
+	mock *MockBeaconAPI
+}
+
+// NewMockBeaconAPI creates a new mock instance.
+func NewMockBeaconAPI(ctrl *gomock.Controller) *MockBeaconAPI {
+	mock := &MockBeaconAPI{ctrl: ctrl}
+	mock.recorder = &MockBeaconAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBeaconAPI) EXPECT() *MockBeaconAPIMockRecorder {
+	return m.recorder
+}
+
+// Progress mocks base method.
+func (m *MockBeaconAPI) Progress() events.BeaconProgress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Progress")
+	ret0, _ := ret[0].(events.BeaconProgress)
+	return ret0
+}
+
+// Progress indicates an expected call of Progress.
+func (mr *MockBeaconAPIMockRecorder) Progress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Progress", reflect.TypeOf((*MockBeaconAPI)(nil).Progress))
+}
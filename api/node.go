@@ -6,8 +6,12 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/activation"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/hare"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/tortoise"
 )
 
 // Publisher interface for publishing messages.
@@ -60,11 +64,12 @@ type TxAPI interface {
 	GetNonce(types.Address) uint64
 	GetAllAccounts() (*types.MultipleAccountsState, error)
 	GetRewardsBySmesherID(types.NodeID) ([]types.Reward, error)
+	QueryTransactions(transactions.Query) ([]*types.MeshTransaction, *transactions.Cursor, error)
 	// TODO: fix the discrepancy between SmesherID and NodeID (see https://github.com/spacemeshos/go-spacemesh/issues/2269)
 }
 
 // NOTE that mockgen doesn't use source-mode to avoid generating mocks for all interfaces in this file.
-//go:generate mockgen -package=mocks -destination=./mocks/mocks.go github.com/spacemeshos/go-spacemesh/api NetworkIdentity
+//go:generate mockgen -package=mocks -destination=./mocks/mocks.go github.com/spacemeshos/go-spacemesh/api NetworkIdentity,TortoiseAPI,HareAPI,BeaconAPI
 
 // NetworkIdentity interface.
 type NetworkIdentity interface {
@@ -85,5 +90,20 @@ type MempoolAPI interface {
 
 // ActivationAPI is an API for activation module.
 type ActivationAPI interface {
-	UpdatePoETServer(context.Context, string) error
+	AddPoETServer(context.Context, string) error
+}
+
+// TortoiseAPI is an API for inspecting tortoise decisions.
+type TortoiseAPI interface {
+	Explain(context.Context, types.BlockID) (*tortoise.Explanation, error)
+}
+
+// HareAPI is an API for inspecting hare consensus processes.
+type HareAPI interface {
+	InstanceStatus(types.LayerID) (*hare.InstanceStatus, error)
+}
+
+// BeaconAPI is an API for inspecting the progress of the beacon protocol.
+type BeaconAPI interface {
+	Progress() events.BeaconProgress
 }
@@ -0,0 +1,420 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: api/nodepb/beacon.proto
+
+package nodepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EpochProgressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *EpochProgressRequest) Reset() {
+	*x = EpochProgressRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_beacon_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EpochProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochProgressRequest) ProtoMessage() {}
+
+func (x *EpochProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_beacon_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochProgressRequest.ProtoReflect.Descriptor instead.
+func (*EpochProgressRequest) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_beacon_proto_rawDescGZIP(), []int{0}
+}
+
+type EpochProgressResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Epoch uint32 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// phase is one of: idle, proposal, first voting, following voting, weak coin or finished.
+	// idle is reported when the node doesn't participate in the protocol in the epoch.
+	Phase                     string `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Round                     uint32 `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"`
+	ValidProposals            uint32 `protobuf:"varint,4,opt,name=valid_proposals,json=validProposals,proto3" json:"valid_proposals,omitempty"`
+	PotentiallyValidProposals uint32 `protobuf:"varint,5,opt,name=potentially_valid_proposals,json=potentiallyValidProposals,proto3" json:"potentially_valid_proposals,omitempty"`
+	// votes is the number of votes received in each round.
+	Votes []uint32 `protobuf:"varint,6,rep,packed,name=votes,proto3" json:"votes,omitempty"`
+	// own_votes is the number of rounds in which the node sent its votes.
+	OwnVotes uint32 `protobuf:"varint,7,opt,name=own_votes,json=ownVotes,proto3" json:"own_votes,omitempty"`
+	// proposal_sent is true if the node was eligible and sent its proposal.
+	ProposalSent bool `protobuf:"varint,8,opt,name=proposal_sent,json=proposalSent,proto3" json:"proposal_sent,omitempty"`
+	// proposal explains why the node didn't send its proposal or doesn't participate in the protocol.
+	Proposal string `protobuf:"bytes,9,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	// beacon is set once the phase is finished.
+	Beacon []byte `protobuf:"bytes,10,opt,name=beacon,proto3" json:"beacon,omitempty"`
+}
+
+func (x *EpochProgressResponse) Reset() {
+	*x = EpochProgressResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_beacon_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EpochProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochProgressResponse) ProtoMessage() {}
+
+func (x *EpochProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_beacon_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochProgressResponse.ProtoReflect.Descriptor instead.
+func (*EpochProgressResponse) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_beacon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EpochProgressResponse) GetEpoch() uint32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *EpochProgressResponse) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *EpochProgressResponse) GetRound() uint32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *EpochProgressResponse) GetValidProposals() uint32 {
+	if x != nil {
+		return x.ValidProposals
+	}
+	return 0
+}
+
+func (x *EpochProgressResponse) GetPotentiallyValidProposals() uint32 {
+	if x != nil {
+		return x.PotentiallyValidProposals
+	}
+	return 0
+}
+
+func (x *EpochProgressResponse) GetVotes() []uint32 {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+func (x *EpochProgressResponse) GetOwnVotes() uint32 {
+	if x != nil {
+		return x.OwnVotes
+	}
+	return 0
+}
+
+func (x *EpochProgressResponse) GetProposalSent() bool {
+	if x != nil {
+		return x.ProposalSent
+	}
+	return false
+}
+
+func (x *EpochProgressResponse) GetProposal() string {
+	if x != nil {
+		return x.Proposal
+	}
+	return ""
+}
+
+func (x *EpochProgressResponse) GetBeacon() []byte {
+	if x != nil {
+		return x.Beacon
+	}
+	return nil
+}
+
+var File_api_nodepb_beacon_proto protoreflect.FileDescriptor
+
+var file_api_nodepb_beacon_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x2f, 0x62, 0x65, 0x61,
+	0x63, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x16, 0x0a, 0x14,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0xce, 0x02, 0x0a, 0x15, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x65,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x27, 0x0a, 0x0f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x61, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x12, 0x3e, 0x0a, 0x1b, 0x70, 0x6f, 0x74,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x70,
+	0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x19,
+	0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x74,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x6f, 0x77, 0x6e, 0x5f, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x6f, 0x77, 0x6e, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x53, 0x65, 0x6e,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x62, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x62,
+	0x65, 0x61, 0x63, 0x6f, 0x6e, 0x32, 0x75, 0x0a, 0x0d, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x64, 0x0a, 0x0d, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x27, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x70, 0x6f, 0x63,
+	0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x28, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x30, 0x5a, 0x2e,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x73, 0x68, 0x6f, 0x73, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x73, 0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_nodepb_beacon_proto_rawDescOnce sync.Once
+	file_api_nodepb_beacon_proto_rawDescData = file_api_nodepb_beacon_proto_rawDesc
+)
+
+func file_api_nodepb_beacon_proto_rawDescGZIP() []byte {
+	file_api_nodepb_beacon_proto_rawDescOnce.Do(func() {
+		file_api_nodepb_beacon_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_nodepb_beacon_proto_rawDescData)
+	})
+	return file_api_nodepb_beacon_proto_rawDescData
+}
+
+var file_api_nodepb_beacon_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_nodepb_beacon_proto_goTypes = []interface{}{
+	(*EpochProgressRequest)(nil),  // 0: spacemesh.node.v1.EpochProgressRequest
+	(*EpochProgressResponse)(nil), // 1: spacemesh.node.v1.EpochProgressResponse
+}
+var file_api_nodepb_beacon_proto_depIdxs = []int32{
+	0, // 0: spacemesh.node.v1.BeaconService.EpochProgress:input_type -> spacemesh.node.v1.EpochProgressRequest
+	1, // 1: spacemesh.node.v1.BeaconService.EpochProgress:output_type -> spacemesh.node.v1.EpochProgressResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_nodepb_beacon_proto_init() }
+func file_api_nodepb_beacon_proto_init() {
+	if File_api_nodepb_beacon_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_nodepb_beacon_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EpochProgressRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_beacon_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EpochProgressResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_nodepb_beacon_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_nodepb_beacon_proto_goTypes,
+		DependencyIndexes: file_api_nodepb_beacon_proto_depIdxs,
+		MessageInfos:      file_api_nodepb_beacon_proto_msgTypes,
+	}.Build()
+	File_api_nodepb_beacon_proto = out.File
+	file_api_nodepb_beacon_proto_rawDesc = nil
+	file_api_nodepb_beacon_proto_goTypes = nil
+	file_api_nodepb_beacon_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BeaconServiceClient is the client API for BeaconService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BeaconServiceClient interface {
+	// EpochProgress streams the progress of the beacon protocol. The current progress is sent first,
+	// then an update is sent on every change of the phase or the round and once the beacon is calculated.
+	EpochProgress(ctx context.Context, in *EpochProgressRequest, opts ...grpc.CallOption) (BeaconService_EpochProgressClient, error)
+}
+
+type beaconServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBeaconServiceClient(cc grpc.ClientConnInterface) BeaconServiceClient {
+	return &beaconServiceClient{cc}
+}
+
+func (c *beaconServiceClient) EpochProgress(ctx context.Context, in *EpochProgressRequest, opts ...grpc.CallOption) (BeaconService_EpochProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeaconService_serviceDesc.Streams[0], "/spacemesh.node.v1.BeaconService/EpochProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beaconServiceEpochProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeaconService_EpochProgressClient interface {
+	Recv() (*EpochProgressResponse, error)
+	grpc.ClientStream
+}
+
+type beaconServiceEpochProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *beaconServiceEpochProgressClient) Recv() (*EpochProgressResponse, error) {
+	m := new(EpochProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BeaconServiceServer is the server API for BeaconService service.
+type BeaconServiceServer interface {
+	// EpochProgress streams the progress of the beacon protocol. The current progress is sent first,
+	// then an update is sent on every change of the phase or the round and once the beacon is calculated.
+	EpochProgress(*EpochProgressRequest, BeaconService_EpochProgressServer) error
+}
+
+// UnimplementedBeaconServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBeaconServiceServer struct {
+}
+
+func (*UnimplementedBeaconServiceServer) EpochProgress(*EpochProgressRequest, BeaconService_EpochProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method EpochProgress not implemented")
+}
+
+func RegisterBeaconServiceServer(s *grpc.Server, srv BeaconServiceServer) {
+	s.RegisterService(&_BeaconService_serviceDesc, srv)
+}
+
+func _BeaconService_EpochProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeaconServiceServer).EpochProgress(m, &beaconServiceEpochProgressServer{stream})
+}
+
+type BeaconService_EpochProgressServer interface {
+	Send(*EpochProgressResponse) error
+	grpc.ServerStream
+}
+
+type beaconServiceEpochProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *beaconServiceEpochProgressServer) Send(m *EpochProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BeaconService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "spacemesh.node.v1.BeaconService",
+	HandlerType: (*BeaconServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EpochProgress",
+			Handler:       _BeaconService_EpochProgress_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/nodepb/beacon.proto",
+}
@@ -0,0 +1,527 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: api/nodepb/debug.proto
+
+package nodepb
+
+import (
+	context "context"
+	v1 "github.com/spacemeshos/api/release/go/spacemesh/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExplainBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockId []byte `protobuf:"bytes,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+}
+
+func (x *ExplainBlockRequest) Reset() {
+	*x = ExplainBlockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_debug_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExplainBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainBlockRequest) ProtoMessage() {}
+
+func (x *ExplainBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_debug_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainBlockRequest.ProtoReflect.Descriptor instead.
+func (*ExplainBlockRequest) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_debug_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExplainBlockRequest) GetBlockId() []byte {
+	if x != nil {
+		return x.BlockId
+	}
+	return nil
+}
+
+// LayerVotes is a weight of ballots from a single layer that voted on a block.
+type LayerVotes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Layer   *v1.LayerNumber `protobuf:"bytes,1,opt,name=layer,proto3" json:"layer,omitempty"`
+	Support float64         `protobuf:"fixed64,2,opt,name=support,proto3" json:"support,omitempty"`
+	Against float64         `protobuf:"fixed64,3,opt,name=against,proto3" json:"against,omitempty"`
+	Abstain float64         `protobuf:"fixed64,4,opt,name=abstain,proto3" json:"abstain,omitempty"`
+	// ballots is a number of ballots with non-zero weight in the layer.
+	Ballots uint32 `protobuf:"varint,5,opt,name=ballots,proto3" json:"ballots,omitempty"`
+}
+
+func (x *LayerVotes) Reset() {
+	*x = LayerVotes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_debug_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LayerVotes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayerVotes) ProtoMessage() {}
+
+func (x *LayerVotes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_debug_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayerVotes.ProtoReflect.Descriptor instead.
+func (*LayerVotes) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_debug_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LayerVotes) GetLayer() *v1.LayerNumber {
+	if x != nil {
+		return x.Layer
+	}
+	return nil
+}
+
+func (x *LayerVotes) GetSupport() float64 {
+	if x != nil {
+		return x.Support
+	}
+	return 0
+}
+
+func (x *LayerVotes) GetAgainst() float64 {
+	if x != nil {
+		return x.Against
+	}
+	return 0
+}
+
+func (x *LayerVotes) GetAbstain() float64 {
+	if x != nil {
+		return x.Abstain
+	}
+	return 0
+}
+
+func (x *LayerVotes) GetBallots() uint32 {
+	if x != nil {
+		return x.Ballots
+	}
+	return 0
+}
+
+type ExplainBlockResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockId []byte          `protobuf:"bytes,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	Layer   *v1.LayerNumber `protobuf:"bytes,2,opt,name=layer,proto3" json:"layer,omitempty"`
+	// decided is true if the layer of the block is verified.
+	Decided bool `protobuf:"varint,3,opt,name=decided,proto3" json:"decided,omitempty"`
+	Valid   bool `protobuf:"varint,4,opt,name=valid,proto3" json:"valid,omitempty"`
+	// mode of the tortoise that verified the layer: verifying or full, with rerun prefix
+	// if the layer was verified during rerun. empty if it is unknown or the layer is not verified.
+	Mode string `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	// local_threshold and global_threshold that were used when the layer was verified.
+	// current thresholds if the layer is not verified.
+	LocalThreshold  float64 `protobuf:"fixed64,6,opt,name=local_threshold,json=localThreshold,proto3" json:"local_threshold,omitempty"`
+	GlobalThreshold float64 `protobuf:"fixed64,7,opt,name=global_threshold,json=globalThreshold,proto3" json:"global_threshold,omitempty"`
+	// vote is a local opinion about the block: support, against or abstain.
+	Vote string `protobuf:"bytes,8,opt,name=vote,proto3" json:"vote,omitempty"`
+	// vote_reason is the data that the local opinion is based on: hare, validity, local_threshold or coinflip.
+	VoteReason string `protobuf:"bytes,9,opt,name=vote_reason,json=voteReason,proto3" json:"vote_reason,omitempty"`
+	// weight is a sum of votes counted by the full tortoise.
+	Weight float64 `protobuf:"fixed64,10,opt,name=weight,proto3" json:"weight,omitempty"`
+	// votes from ballots in layers after the layer of the block.
+	Votes []*LayerVotes `protobuf:"bytes,11,rep,name=votes,proto3" json:"votes,omitempty"`
+}
+
+func (x *ExplainBlockResponse) Reset() {
+	*x = ExplainBlockResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_debug_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExplainBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainBlockResponse) ProtoMessage() {}
+
+func (x *ExplainBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_debug_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainBlockResponse.ProtoReflect.Descriptor instead.
+func (*ExplainBlockResponse) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_debug_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExplainBlockResponse) GetBlockId() []byte {
+	if x != nil {
+		return x.BlockId
+	}
+	return nil
+}
+
+func (x *ExplainBlockResponse) GetLayer() *v1.LayerNumber {
+	if x != nil {
+		return x.Layer
+	}
+	return nil
+}
+
+func (x *ExplainBlockResponse) GetDecided() bool {
+	if x != nil {
+		return x.Decided
+	}
+	return false
+}
+
+func (x *ExplainBlockResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ExplainBlockResponse) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ExplainBlockResponse) GetLocalThreshold() float64 {
+	if x != nil {
+		return x.LocalThreshold
+	}
+	return 0
+}
+
+func (x *ExplainBlockResponse) GetGlobalThreshold() float64 {
+	if x != nil {
+		return x.GlobalThreshold
+	}
+	return 0
+}
+
+func (x *ExplainBlockResponse) GetVote() string {
+	if x != nil {
+		return x.Vote
+	}
+	return ""
+}
+
+func (x *ExplainBlockResponse) GetVoteReason() string {
+	if x != nil {
+		return x.VoteReason
+	}
+	return ""
+}
+
+func (x *ExplainBlockResponse) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ExplainBlockResponse) GetVotes() []*LayerVotes {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+var File_api_nodepb_debug_proto protoreflect.FileDescriptor
+
+var file_api_nodepb_debug_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x2f, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x18, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x30, 0x0a, 0x13, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x22, 0xa5, 0x01, 0x0a, 0x0a, 0x4c, 0x61, 0x79, 0x65,
+	0x72, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x52, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x70, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x07, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x62, 0x73, 0x74, 0x61, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x61, 0x62,
+	0x73, 0x74, 0x61, 0x69, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x6c, 0x6f, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x6c, 0x6f, 0x74, 0x73, 0x22,
+	0xfc, 0x02, 0x0a, 0x14, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x05, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x74, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x67, 0x6c, 0x6f,
+	0x62, 0x61, 0x6c, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x76, 0x6f, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x76, 0x6f, 0x74, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x6f, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x6f, 0x74,
+	0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x79,
+	0x65, 0x72, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x32, 0x6f,
+	0x0a, 0x0c, 0x44, 0x65, 0x62, 0x75, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5f,
+	0x0a, 0x0c, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x26,
+	0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61,
+	0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
+	0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x6f, 0x73, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_nodepb_debug_proto_rawDescOnce sync.Once
+	file_api_nodepb_debug_proto_rawDescData = file_api_nodepb_debug_proto_rawDesc
+)
+
+func file_api_nodepb_debug_proto_rawDescGZIP() []byte {
+	file_api_nodepb_debug_proto_rawDescOnce.Do(func() {
+		file_api_nodepb_debug_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_nodepb_debug_proto_rawDescData)
+	})
+	return file_api_nodepb_debug_proto_rawDescData
+}
+
+var file_api_nodepb_debug_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_nodepb_debug_proto_goTypes = []interface{}{
+	(*ExplainBlockRequest)(nil),  // 0: spacemesh.node.v1.ExplainBlockRequest
+	(*LayerVotes)(nil),           // 1: spacemesh.node.v1.LayerVotes
+	(*ExplainBlockResponse)(nil), // 2: spacemesh.node.v1.ExplainBlockResponse
+	(*v1.LayerNumber)(nil),       // 3: spacemesh.v1.LayerNumber
+}
+var file_api_nodepb_debug_proto_depIdxs = []int32{
+	3, // 0: spacemesh.node.v1.LayerVotes.layer:type_name -> spacemesh.v1.LayerNumber
+	3, // 1: spacemesh.node.v1.ExplainBlockResponse.layer:type_name -> spacemesh.v1.LayerNumber
+	1, // 2: spacemesh.node.v1.ExplainBlockResponse.votes:type_name -> spacemesh.node.v1.LayerVotes
+	0, // 3: spacemesh.node.v1.DebugService.ExplainBlock:input_type -> spacemesh.node.v1.ExplainBlockRequest
+	2, // 4: spacemesh.node.v1.DebugService.ExplainBlock:output_type -> spacemesh.node.v1.ExplainBlockResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_nodepb_debug_proto_init() }
+func file_api_nodepb_debug_proto_init() {
+	if File_api_nodepb_debug_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_nodepb_debug_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExplainBlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_debug_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LayerVotes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_debug_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExplainBlockResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_nodepb_debug_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_nodepb_debug_proto_goTypes,
+		DependencyIndexes: file_api_nodepb_debug_proto_depIdxs,
+		MessageInfos:      file_api_nodepb_debug_proto_msgTypes,
+	}.Build()
+	File_api_nodepb_debug_proto = out.File
+	file_api_nodepb_debug_proto_rawDesc = nil
+	file_api_nodepb_debug_proto_goTypes = nil
+	file_api_nodepb_debug_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// DebugServiceClient is the client API for DebugService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DebugServiceClient interface {
+	// ExplainBlock returns votes, thresholds and the tortoise mode that decided validity of the block.
+	// Only blocks within the tortoise sliding window can be explained.
+	ExplainBlock(ctx context.Context, in *ExplainBlockRequest, opts ...grpc.CallOption) (*ExplainBlockResponse, error)
+}
+
+type debugServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDebugServiceClient(cc grpc.ClientConnInterface) DebugServiceClient {
+	return &debugServiceClient{cc}
+}
+
+func (c *debugServiceClient) ExplainBlock(ctx context.Context, in *ExplainBlockRequest, opts ...grpc.CallOption) (*ExplainBlockResponse, error) {
+	out := new(ExplainBlockResponse)
+	err := c.cc.Invoke(ctx, "/spacemesh.node.v1.DebugService/ExplainBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DebugServiceServer is the server API for DebugService service.
+type DebugServiceServer interface {
+	// ExplainBlock returns votes, thresholds and the tortoise mode that decided validity of the block.
+	// Only blocks within the tortoise sliding window can be explained.
+	ExplainBlock(context.Context, *ExplainBlockRequest) (*ExplainBlockResponse, error)
+}
+
+// UnimplementedDebugServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedDebugServiceServer struct {
+}
+
+func (*UnimplementedDebugServiceServer) ExplainBlock(context.Context, *ExplainBlockRequest) (*ExplainBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainBlock not implemented")
+}
+
+func RegisterDebugServiceServer(s *grpc.Server, srv DebugServiceServer) {
+	s.RegisterService(&_DebugService_serviceDesc, srv)
+}
+
+func _DebugService_ExplainBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).ExplainBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spacemesh.node.v1.DebugService/ExplainBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).ExplainBlock(ctx, req.(*ExplainBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DebugService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "spacemesh.node.v1.DebugService",
+	HandlerType: (*DebugServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExplainBlock",
+			Handler:    _DebugService_ExplainBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/nodepb/debug.proto",
+}
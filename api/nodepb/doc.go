@@ -0,0 +1,5 @@
+// Package nodepb contains grpc services that are specific to go-spacemesh node
+// and are not yet a part of github.com/spacemeshos/api.
+package nodepb
+
+//go:generate protoc -I../.. -I${SPACEMESH_API_PROTO} --go_out=plugins=grpc,paths=source_relative:../.. api/nodepb/tx.proto api/nodepb/mesh.proto api/nodepb/debug.proto api/nodepb/hare.proto api/nodepb/beacon.proto
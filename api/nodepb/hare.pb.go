@@ -0,0 +1,422 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: api/nodepb/hare.proto
+
+package nodepb
+
+import (
+	context "context"
+	v1 "github.com/spacemeshos/api/release/go/spacemesh/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InstanceStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Layer *v1.LayerNumber `protobuf:"bytes,1,opt,name=layer,proto3" json:"layer,omitempty"`
+}
+
+func (x *InstanceStatusRequest) Reset() {
+	*x = InstanceStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_hare_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstanceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceStatusRequest) ProtoMessage() {}
+
+func (x *InstanceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_hare_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceStatusRequest.ProtoReflect.Descriptor instead.
+func (*InstanceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_hare_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InstanceStatusRequest) GetLayer() *v1.LayerNumber {
+	if x != nil {
+		return x.Layer
+	}
+	return nil
+}
+
+type InstanceStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Layer *v1.LayerNumber `protobuf:"bytes,1,opt,name=layer,proto3" json:"layer,omitempty"`
+	// round is the round counter of the consensus process.
+	Round uint32 `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	// iteration is the current iteration, starting from 0.
+	Iteration uint32 `protobuf:"varint,3,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	// round_type is one of: preround, status, proposal, commit, notify or certify.
+	RoundType string `protobuf:"bytes,4,opt,name=round_type,json=roundType,proto3" json:"round_type,omitempty"`
+	// set is the current set of proposal ids.
+	Set [][]byte `protobuf:"bytes,5,rep,name=set,proto3" json:"set,omitempty"`
+	// proposed_set is the set proposed by the leader in the current iteration.
+	// it is empty if there is no valid proposal.
+	ProposedSet [][]byte `protobuf:"bytes,6,rep,name=proposed_set,json=proposedSet,proto3" json:"proposed_set,omitempty"`
+	// commit_count is the eligibility count of the commit messages in the current iteration.
+	CommitCount uint32 `protobuf:"varint,7,opt,name=commit_count,json=commitCount,proto3" json:"commit_count,omitempty"`
+	// notify_count is the eligibility count of the notify messages for the current set.
+	NotifyCount uint32 `protobuf:"varint,8,opt,name=notify_count,json=notifyCount,proto3" json:"notify_count,omitempty"`
+	Terminated  bool   `protobuf:"varint,9,opt,name=terminated,proto3" json:"terminated,omitempty"`
+	Completed   bool   `protobuf:"varint,10,opt,name=completed,proto3" json:"completed,omitempty"`
+	Certified   bool   `protobuf:"varint,11,opt,name=certified,proto3" json:"certified,omitempty"`
+}
+
+func (x *InstanceStatusResponse) Reset() {
+	*x = InstanceStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_hare_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstanceStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceStatusResponse) ProtoMessage() {}
+
+func (x *InstanceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_hare_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceStatusResponse.ProtoReflect.Descriptor instead.
+func (*InstanceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_hare_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InstanceStatusResponse) GetLayer() *v1.LayerNumber {
+	if x != nil {
+		return x.Layer
+	}
+	return nil
+}
+
+func (x *InstanceStatusResponse) GetRound() uint32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *InstanceStatusResponse) GetIteration() uint32 {
+	if x != nil {
+		return x.Iteration
+	}
+	return 0
+}
+
+func (x *InstanceStatusResponse) GetRoundType() string {
+	if x != nil {
+		return x.RoundType
+	}
+	return ""
+}
+
+func (x *InstanceStatusResponse) GetSet() [][]byte {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *InstanceStatusResponse) GetProposedSet() [][]byte {
+	if x != nil {
+		return x.ProposedSet
+	}
+	return nil
+}
+
+func (x *InstanceStatusResponse) GetCommitCount() uint32 {
+	if x != nil {
+		return x.CommitCount
+	}
+	return 0
+}
+
+func (x *InstanceStatusResponse) GetNotifyCount() uint32 {
+	if x != nil {
+		return x.NotifyCount
+	}
+	return 0
+}
+
+func (x *InstanceStatusResponse) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
+
+func (x *InstanceStatusResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *InstanceStatusResponse) GetCertified() bool {
+	if x != nil {
+		return x.Certified
+	}
+	return false
+}
+
+var File_api_nodepb_hare_proto protoreflect.FileDescriptor
+
+var file_api_nodepb_hare_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x2f, 0x68, 0x61, 0x72,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x18, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x48, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a,
+	0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x79, 0x65,
+	0x72, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0xf3,
+	0x02, 0x0a, 0x16, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x52, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x74, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x64, 0x53,
+	0x65, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6e, 0x6f, 0x74,
+	0x69, 0x66, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x65,
+	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x64, 0x32, 0x74, 0x0a, 0x0b, 0x48, 0x61, 0x72, 0x65, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x65, 0x0a, 0x0e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73,
+	0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x29, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x73, 0x68, 0x6f, 0x73, 0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73,
+	0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_nodepb_hare_proto_rawDescOnce sync.Once
+	file_api_nodepb_hare_proto_rawDescData = file_api_nodepb_hare_proto_rawDesc
+)
+
+func file_api_nodepb_hare_proto_rawDescGZIP() []byte {
+	file_api_nodepb_hare_proto_rawDescOnce.Do(func() {
+		file_api_nodepb_hare_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_nodepb_hare_proto_rawDescData)
+	})
+	return file_api_nodepb_hare_proto_rawDescData
+}
+
+var file_api_nodepb_hare_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_nodepb_hare_proto_goTypes = []interface{}{
+	(*InstanceStatusRequest)(nil),  // 0: spacemesh.node.v1.InstanceStatusRequest
+	(*InstanceStatusResponse)(nil), // 1: spacemesh.node.v1.InstanceStatusResponse
+	(*v1.LayerNumber)(nil),         // 2: spacemesh.v1.LayerNumber
+}
+var file_api_nodepb_hare_proto_depIdxs = []int32{
+	2, // 0: spacemesh.node.v1.InstanceStatusRequest.layer:type_name -> spacemesh.v1.LayerNumber
+	2, // 1: spacemesh.node.v1.InstanceStatusResponse.layer:type_name -> spacemesh.v1.LayerNumber
+	0, // 2: spacemesh.node.v1.HareService.InstanceStatus:input_type -> spacemesh.node.v1.InstanceStatusRequest
+	1, // 3: spacemesh.node.v1.HareService.InstanceStatus:output_type -> spacemesh.node.v1.InstanceStatusResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_nodepb_hare_proto_init() }
+func file_api_nodepb_hare_proto_init() {
+	if File_api_nodepb_hare_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_nodepb_hare_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_hare_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstanceStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_nodepb_hare_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_nodepb_hare_proto_goTypes,
+		DependencyIndexes: file_api_nodepb_hare_proto_depIdxs,
+		MessageInfos:      file_api_nodepb_hare_proto_msgTypes,
+	}.Build()
+	File_api_nodepb_hare_proto = out.File
+	file_api_nodepb_hare_proto_rawDesc = nil
+	file_api_nodepb_hare_proto_goTypes = nil
+	file_api_nodepb_hare_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// HareServiceClient is the client API for HareService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HareServiceClient interface {
+	// InstanceStatus returns the state of the consensus process for the layer.
+	// Only the consensus processes for the recent layers are available.
+	InstanceStatus(ctx context.Context, in *InstanceStatusRequest, opts ...grpc.CallOption) (*InstanceStatusResponse, error)
+}
+
+type hareServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHareServiceClient(cc grpc.ClientConnInterface) HareServiceClient {
+	return &hareServiceClient{cc}
+}
+
+func (c *hareServiceClient) InstanceStatus(ctx context.Context, in *InstanceStatusRequest, opts ...grpc.CallOption) (*InstanceStatusResponse, error) {
+	out := new(InstanceStatusResponse)
+	err := c.cc.Invoke(ctx, "/spacemesh.node.v1.HareService/InstanceStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HareServiceServer is the server API for HareService service.
+type HareServiceServer interface {
+	// InstanceStatus returns the state of the consensus process for the layer.
+	// Only the consensus processes for the recent layers are available.
+	InstanceStatus(context.Context, *InstanceStatusRequest) (*InstanceStatusResponse, error)
+}
+
+// UnimplementedHareServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedHareServiceServer struct {
+}
+
+func (*UnimplementedHareServiceServer) InstanceStatus(context.Context, *InstanceStatusRequest) (*InstanceStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstanceStatus not implemented")
+}
+
+func RegisterHareServiceServer(s *grpc.Server, srv HareServiceServer) {
+	s.RegisterService(&_HareService_serviceDesc, srv)
+}
+
+func _HareService_InstanceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HareServiceServer).InstanceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spacemesh.node.v1.HareService/InstanceStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HareServiceServer).InstanceStatus(ctx, req.(*InstanceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HareService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "spacemesh.node.v1.HareService",
+	HandlerType: (*HareServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InstanceStatus",
+			Handler:    _HareService_InstanceStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/nodepb/hare.proto",
+}
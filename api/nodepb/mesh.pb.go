@@ -0,0 +1,434 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: api/nodepb/mesh.proto
+
+package nodepb
+
+import (
+	context "context"
+	v1 "github.com/spacemeshos/api/release/go/spacemesh/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReorgStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReorgStreamRequest) Reset() {
+	*x = ReorgStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_mesh_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReorgStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorgStreamRequest) ProtoMessage() {}
+
+func (x *ReorgStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_mesh_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorgStreamRequest.ProtoReflect.Descriptor instead.
+func (*ReorgStreamRequest) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_mesh_proto_rawDescGZIP(), []int{0}
+}
+
+// Reorg describes changes to the applied state. It is reported when the state is reverted, before reverted layers are applied again.
+type Reorg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// revert_to is the last layer that was not reverted.
+	RevertTo *v1.LayerNumber `protobuf:"bytes,1,opt,name=revert_to,json=revertTo,proto3" json:"revert_to,omitempty"`
+	// invalid_blocks were applied before the state was reverted and are not valid anymore.
+	InvalidBlocks [][]byte `protobuf:"bytes,2,rep,name=invalid_blocks,json=invalidBlocks,proto3" json:"invalid_blocks,omitempty"`
+	// valid_blocks were not applied before the state was reverted and will be applied instead.
+	ValidBlocks [][]byte `protobuf:"bytes,3,rep,name=valid_blocks,json=validBlocks,proto3" json:"valid_blocks,omitempty"`
+	// reinserted_transactions are included in invalid_blocks and not in valid_blocks, and are returned to the mempool.
+	ReinsertedTransactions []*v1.TransactionId `protobuf:"bytes,4,rep,name=reinserted_transactions,json=reinsertedTransactions,proto3" json:"reinserted_transactions,omitempty"`
+}
+
+func (x *Reorg) Reset() {
+	*x = Reorg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_mesh_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reorg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reorg) ProtoMessage() {}
+
+func (x *Reorg) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_mesh_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reorg.ProtoReflect.Descriptor instead.
+func (*Reorg) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_mesh_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Reorg) GetRevertTo() *v1.LayerNumber {
+	if x != nil {
+		return x.RevertTo
+	}
+	return nil
+}
+
+func (x *Reorg) GetInvalidBlocks() [][]byte {
+	if x != nil {
+		return x.InvalidBlocks
+	}
+	return nil
+}
+
+func (x *Reorg) GetValidBlocks() [][]byte {
+	if x != nil {
+		return x.ValidBlocks
+	}
+	return nil
+}
+
+func (x *Reorg) GetReinsertedTransactions() []*v1.TransactionId {
+	if x != nil {
+		return x.ReinsertedTransactions
+	}
+	return nil
+}
+
+type ReorgStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reorg *Reorg `protobuf:"bytes,1,opt,name=reorg,proto3" json:"reorg,omitempty"`
+}
+
+func (x *ReorgStreamResponse) Reset() {
+	*x = ReorgStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_nodepb_mesh_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReorgStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorgStreamResponse) ProtoMessage() {}
+
+func (x *ReorgStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_nodepb_mesh_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorgStreamResponse.ProtoReflect.Descriptor instead.
+func (*ReorgStreamResponse) Descriptor() ([]byte, []int) {
+	return file_api_nodepb_mesh_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReorgStreamResponse) GetReorg() *Reorg {
+	if x != nil {
+		return x.Reorg
+	}
+	return nil
+}
+
+var File_api_nodepb_mesh_proto protoreflect.FileDescriptor
+
+var file_api_nodepb_mesh_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x70, 0x62, 0x2f, 0x6d, 0x65, 0x73,
+	0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x18, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x65, 0x6f, 0x72, 0x67, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xdf, 0x01, 0x0a, 0x05, 0x52,
+	0x65, 0x6f, 0x72, 0x67, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x74,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x52, 0x08, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x12, 0x25, 0x0a, 0x0e,
+	0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x54, 0x0a, 0x17, 0x72, 0x65, 0x69, 0x6e, 0x73, 0x65,
+	0x72, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x52, 0x16, 0x72, 0x65, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x65, 0x64,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x45, 0x0a, 0x13,
+	0x52, 0x65, 0x6f, 0x72, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x65, 0x6f, 0x72, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6f, 0x72, 0x67, 0x52, 0x05, 0x72, 0x65,
+	0x6f, 0x72, 0x67, 0x32, 0x6d, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x5e, 0x0a, 0x0b, 0x52, 0x65, 0x6f, 0x72, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x25, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f,
+	0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6f, 0x72, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6f,
+	0x72, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x30, 0x01, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x6f, 0x73, 0x2f, 0x67, 0x6f, 0x2d,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x6f,
+	0x64, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_nodepb_mesh_proto_rawDescOnce sync.Once
+	file_api_nodepb_mesh_proto_rawDescData = file_api_nodepb_mesh_proto_rawDesc
+)
+
+func file_api_nodepb_mesh_proto_rawDescGZIP() []byte {
+	file_api_nodepb_mesh_proto_rawDescOnce.Do(func() {
+		file_api_nodepb_mesh_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_nodepb_mesh_proto_rawDescData)
+	})
+	return file_api_nodepb_mesh_proto_rawDescData
+}
+
+var file_api_nodepb_mesh_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_nodepb_mesh_proto_goTypes = []interface{}{
+	(*ReorgStreamRequest)(nil),  // 0: spacemesh.node.v1.ReorgStreamRequest
+	(*Reorg)(nil),               // 1: spacemesh.node.v1.Reorg
+	(*ReorgStreamResponse)(nil), // 2: spacemesh.node.v1.ReorgStreamResponse
+	(*v1.LayerNumber)(nil),      // 3: spacemesh.v1.LayerNumber
+	(*v1.TransactionId)(nil),    // 4: spacemesh.v1.TransactionId
+}
+var file_api_nodepb_mesh_proto_depIdxs = []int32{
+	3, // 0: spacemesh.node.v1.Reorg.revert_to:type_name -> spacemesh.v1.LayerNumber
+	4, // 1: spacemesh.node.v1.Reorg.reinserted_transactions:type_name -> spacemesh.v1.TransactionId
+	1, // 2: spacemesh.node.v1.ReorgStreamResponse.reorg:type_name -> spacemesh.node.v1.Reorg
+	0, // 3: spacemesh.node.v1.MeshService.ReorgStream:input_type -> spacemesh.node.v1.ReorgStreamRequest
+	2, // 4: spacemesh.node.v1.MeshService.ReorgStream:output_type -> spacemesh.node.v1.ReorgStreamResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_nodepb_mesh_proto_init() }
+func file_api_nodepb_mesh_proto_init() {
+	if File_api_nodepb_mesh_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_nodepb_mesh_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReorgStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_mesh_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reorg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_nodepb_mesh_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReorgStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_nodepb_mesh_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_nodepb_mesh_proto_goTypes,
+		DependencyIndexes: file_api_nodepb_mesh_proto_depIdxs,
+		MessageInfos:      file_api_nodepb_mesh_proto_msgTypes,
+	}.Build()
+	File_api_nodepb_mesh_proto = out.File
+	file_api_nodepb_mesh_proto_rawDesc = nil
+	file_api_nodepb_mesh_proto_goTypes = nil
+	file_api_nodepb_mesh_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// MeshServiceClient is the client API for MeshService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MeshServiceClient interface {
+	// ReorgStream streams reorgs: state reversions after tortoise changed validity of blocks
+	// in layers that were already applied.
+	ReorgStream(ctx context.Context, in *ReorgStreamRequest, opts ...grpc.CallOption) (MeshService_ReorgStreamClient, error)
+}
+
+type meshServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMeshServiceClient(cc grpc.ClientConnInterface) MeshServiceClient {
+	return &meshServiceClient{cc}
+}
+
+func (c *meshServiceClient) ReorgStream(ctx context.Context, in *ReorgStreamRequest, opts ...grpc.CallOption) (MeshService_ReorgStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MeshService_serviceDesc.Streams[0], "/spacemesh.node.v1.MeshService/ReorgStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &meshServiceReorgStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MeshService_ReorgStreamClient interface {
+	Recv() (*ReorgStreamResponse, error)
+	grpc.ClientStream
+}
+
+type meshServiceReorgStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *meshServiceReorgStreamClient) Recv() (*ReorgStreamResponse, error) {
+	m := new(ReorgStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MeshServiceServer is the server API for MeshService service.
+type MeshServiceServer interface {
+	// ReorgStream streams reorgs: state reversions after tortoise changed validity of blocks
+	// in layers that were already applied.
+	ReorgStream(*ReorgStreamRequest, MeshService_ReorgStreamServer) error
+}
+
+// UnimplementedMeshServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMeshServiceServer struct {
+}
+
+func (*UnimplementedMeshServiceServer) ReorgStream(*ReorgStreamRequest, MeshService_ReorgStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReorgStream not implemented")
+}
+
+func RegisterMeshServiceServer(s *grpc.Server, srv MeshServiceServer) {
+	s.RegisterService(&_MeshService_serviceDesc, srv)
+}
+
+func _MeshService_ReorgStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReorgStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MeshServiceServer).ReorgStream(m, &meshServiceReorgStreamServer{stream})
+}
+
+type MeshService_ReorgStreamServer interface {
+	Send(*ReorgStreamResponse) error
+	grpc.ServerStream
+}
+
+type meshServiceReorgStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *meshServiceReorgStreamServer) Send(m *ReorgStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _MeshService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "spacemesh.node.v1.MeshService",
+	HandlerType: (*MeshServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReorgStream",
+			Handler:       _MeshService_ReorgStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/nodepb/mesh.proto",
+}
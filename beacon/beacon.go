@@ -99,6 +99,7 @@ func New(
 type ProtocolDriver struct {
 	running    uint64
 	inProtocol uint64
+	syncing    uint64
 	eg         errgroup.Group
 	cancel     context.CancelFunc
 
@@ -108,6 +109,10 @@ type ProtocolDriver struct {
 	nodeID      types.NodeID
 	sync        system.SyncStateProvider
 	publisher   pubsub.Publisher
+	requestor   requestor
+	peers       peerProvider
+	fetcher     system.BallotFetcher
+	ballots     ballotProvider
 	atxDB       activationDB
 	edSigner    signing.Signer
 	edVerifier  signing.VerifyExtractor
@@ -124,6 +129,7 @@ type ProtocolDriver struct {
 	lastTickedEpoch types.EpochID
 	epochInProgress types.EpochID
 	roundInProgress types.RoundID
+	progress        progress
 
 	// beacons store calculated beacons as the result of the beacon protocol.
 	// the map key is the target epoch when beacon is used. if a beacon is calculated in epoch N, it will be used
@@ -436,6 +442,7 @@ func (pd *ProtocolDriver) handleLayer(ctx context.Context, layer types.LayerID)
 	logger := pd.logger.WithContext(ctx).WithFields(layer, epoch)
 
 	pd.setTickedEpoch(epoch)
+	pd.maybeSyncBeacon(ctx, epoch)
 	if !layer.FirstInEpoch() {
 		logger.Debug("not first layer in epoch, skipping")
 		return
@@ -459,6 +466,7 @@ func (pd *ProtocolDriver) handleEpoch(ctx context.Context, epoch types.EpochID)
 	}
 	if !pd.sync.IsSynced(ctx) {
 		logger.Info("beacon protocol is skipped while node is not synced")
+		pd.notParticipating(epoch, "node is not synced")
 		return
 	}
 
@@ -466,6 +474,7 @@ func (pd *ProtocolDriver) handleEpoch(ctx context.Context, epoch types.EpochID)
 	atxID, err := pd.atxDB.GetNodeAtxIDForEpoch(pd.nodeID, epoch-1)
 	if err != nil {
 		logger.With().Info("node has no ATX in last epoch, not participating in beacon protocol", log.Err(err))
+		pd.notParticipating(epoch, fmt.Sprintf("node has no ATX in epoch %s: %v", epoch-1, err))
 		return
 	}
 
@@ -474,10 +483,12 @@ func (pd *ProtocolDriver) handleEpoch(ctx context.Context, epoch types.EpochID)
 	epochWeight, atxs, err := pd.atxDB.GetEpochWeight(epoch)
 	if err != nil {
 		logger.With().Error("failed to get weight targeting epoch", log.Err(err))
+		pd.notParticipating(epoch, fmt.Sprintf("failed to get weight targeting epoch: %v", err))
 		return
 	}
 	if epochWeight == 0 {
 		logger.With().Error("zero weight targeting epoch", log.Err(errZeroEpochWeight))
+		pd.notParticipating(epoch, errZeroEpochWeight.Error())
 		return
 	}
 
@@ -496,6 +507,7 @@ func (pd *ProtocolDriver) handleEpoch(ctx context.Context, epoch types.EpochID)
 	pd.startWeakCoinEpoch(ctx, epoch, atxs)
 	defer pd.weakCoin.FinishEpoch(ctx, epoch)
 
+	pd.startProgress(epoch)
 	pd.runProposalPhase(ctx, epoch)
 	// close the proposal channel
 	close(ch)
@@ -510,10 +522,14 @@ func (pd *ProtocolDriver) handleEpoch(ctx context.Context, epoch types.EpochID)
 	beacon := calcBeacon(logger, lastRoundOwnVotes)
 	events.ReportCalculatedBeacon(targetEpoch, beacon.ShortString())
 
+	if err := pd.persistRecord(epoch, lastRoundOwnVotes, beacon); err != nil {
+		logger.With().Error("failed to persist beacon record", log.Err(err))
+	}
 	if err := pd.setBeacon(targetEpoch, beacon); err != nil {
 		logger.With().Error("failed to set beacon", log.Err(err))
 		return
 	}
+	pd.finishProgress(beacon)
 
 	logger.With().Info("beacon set for epoch", beacon)
 }
@@ -574,6 +590,7 @@ func (pd *ProtocolDriver) proposalPhaseImpl(ctx context.Context, epoch types.Epo
 		logger.With().Debug("own proposal doesn't pass threshold",
 			log.String("proposal", string(proposedSignature)))
 		// proposal is not sent
+		pd.setOwnProposal(false, explainIneligibleProposal(proposedSignature, pd.config.Kappa, pd.config.Q, pd.epochWeight()))
 		return nil
 	}
 
@@ -589,8 +606,10 @@ func (pd *ProtocolDriver) proposalPhaseImpl(ctx context.Context, epoch types.Epo
 
 	logger.With().Debug("sending proposal", log.String("message", m.String()))
 	if err := pd.sendToGossip(ctx, ProposalProtocol, m); err != nil {
+		pd.setOwnProposal(false, fmt.Sprintf("failed to broadcast proposal: %v", err))
 		return fmt.Errorf("broadcast proposal message: %w", err)
 	}
+	pd.setOwnProposal(true, "")
 
 	logger.With().Info("sent proposal", log.String("message", m.String()))
 	return nil
@@ -653,6 +672,11 @@ func (pd *ProtocolDriver) runConsensusPhase(ctx context.Context, epoch types.Epo
 	for round := types.FirstRound; round < pd.config.RoundsNumber; round++ {
 		round := round
 		pd.setRoundInProgress(round)
+		if round == types.FirstRound {
+			pd.setPhase(PhaseFirstVoting)
+		} else {
+			pd.setPhase(PhaseFollowingVoting)
+		}
 		rLogger := logger.WithFields(round)
 		votes := ownVotes
 		pd.eg.Go(func() error {
@@ -680,6 +704,7 @@ func (pd *ProtocolDriver) runConsensusPhase(ctx context.Context, epoch types.Epo
 		ownVotes, undecided = pd.calcVotesBeforeWeakCoin(rLogger)
 		if round != types.FirstRound {
 			timer.Reset(pd.config.WeakCoinRoundDuration)
+			pd.setPhase(PhaseWeakCoin)
 
 			pd.eg.Go(func() error {
 				if err := pd.weakCoin.StartRound(ctx, round); err != nil {
@@ -693,7 +718,9 @@ func (pd *ProtocolDriver) runConsensusPhase(ctx context.Context, epoch types.Epo
 				return allVotes{}, fmt.Errorf("context done: %w", ctx.Err())
 			}
 			pd.weakCoin.FinishRound(ctx)
-			tallyUndecided(&ownVotes, undecided, pd.weakCoin.Get(ctx, epoch, round))
+			coin := pd.weakCoin.Get(ctx, epoch, round)
+			pd.recordCoin(round, coin)
+			tallyUndecided(&ownVotes, undecided, coin)
 		}
 		timer.Reset(pd.config.VotingRoundDuration)
 	}
@@ -736,8 +763,9 @@ func (pd *ProtocolDriver) receivedBeforeProposalPhaseFinished(epoch types.EpochI
 }
 
 func (pd *ProtocolDriver) calcVotesBeforeWeakCoin(logger log.Log) (allVotes, []string) {
-	pd.mu.RLock()
-	defer pd.mu.RUnlock()
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	defer func() { pd.current.tallies++ }()
 	return calcVotes(logger, pd.theta, pd.current)
 }
 
@@ -768,6 +796,7 @@ func (pd *ProtocolDriver) sendFirstRoundVote(ctx context.Context, epoch types.Ep
 	if err := pd.sendToGossip(ctx, FirstVoteProtocol, m); err != nil {
 		return fmt.Errorf("sendToGossip: %w", err)
 	}
+	pd.recordOwnVote()
 
 	return nil
 }
@@ -806,6 +835,7 @@ func (pd *ProtocolDriver) sendFollowingVote(ctx context.Context, epoch types.Epo
 	if err := pd.sendToGossip(ctx, FollowingVotingProtocol, m); err != nil {
 		return fmt.Errorf("broadcast voting message: %w", err)
 	}
+	pd.recordOwnVote()
 
 	return nil
 }
@@ -935,6 +965,12 @@ func (pd *ProtocolDriver) sendToGossip(ctx context.Context, protocol string, dat
 	return nil
 }
 
+func (pd *ProtocolDriver) epochWeight() uint64 {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.current.epochWeight
+}
+
 func (pd *ProtocolDriver) getOwnWeight(epoch types.EpochID) uint64 {
 	atxID, err := pd.atxDB.GetNodeAtxIDForEpoch(pd.nodeID, epoch)
 	if err != nil {
@@ -117,6 +117,13 @@ func TestBeacon(t *testing.T) {
 
 	pd.Close()
 	clock.Close()
+
+	record, err := LoadRecord(pd.db, epoch-1)
+	require.NoError(t, err)
+	require.Equal(t, v, record.Beacon)
+	audit, err := Audit(logtest.New(t), record)
+	require.NoError(t, err)
+	require.Equal(t, v, audit.Beacon)
 }
 
 func TestBeaconZeroWeightEpoch(t *testing.T) {
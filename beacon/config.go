@@ -20,6 +20,8 @@ type Config struct {
 	Theta                    *big.Rat      `mapstructure:"beacon-theta"`                       // Ratio of votes for reaching consensus
 	VotesLimit               uint32        `mapstructure:"beacon-votes-limit"`                 // Maximum allowed number of votes to be sent
 	BeaconSyncNumBallots     uint32        `mapstructure:"beacon-sync-num-blocks"`             // Numbers of layers to wait before determining beacon values from ballots when the node didn't participate in previous epoch.
+	BeaconSyncNumPeers       int           `mapstructure:"beacon-sync-num-peers"`              // Number of peers to request the beacon from when the node didn't participate in previous epoch.
+	BeaconSyncWeightFraction *big.Rat      `mapstructure:"beacon-sync-weight-fraction"`        // Fraction of the ballot weight reported by peers that must agree on the beacon to accept it.
 }
 
 // DefaultConfig returns the default configuration for the beacon.
@@ -36,6 +38,8 @@ func DefaultConfig() Config {
 		Theta:                    big.NewRat(1, 4),
 		VotesLimit:               100,  // TODO: around 100, find the calculation in the forum
 		BeaconSyncNumBallots:     1600, // should be 2 clusters of 800 ballots
+		BeaconSyncNumPeers:       5,
+		BeaconSyncWeightFraction: big.NewRat(2, 3),
 	}
 }
 
@@ -53,6 +57,8 @@ func UnitTestConfig() Config {
 		Theta:                    big.NewRat(1, 25000),
 		VotesLimit:               100,
 		BeaconSyncNumBallots:     2,
+		BeaconSyncNumPeers:       3,
+		BeaconSyncWeightFraction: big.NewRat(2, 3),
 	}
 }
 
@@ -70,5 +76,7 @@ func NodeSimUnitTestConfig() Config {
 		Theta:                    big.NewRat(1, 25000),
 		VotesLimit:               100,
 		BeaconSyncNumBallots:     10,
+		BeaconSyncNumPeers:       3,
+		BeaconSyncWeightFraction: big.NewRat(2, 3),
 	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/libp2p/go-libp2p-core/peer"
 
+	"github.com/spacemeshos/go-spacemesh/beacon/metrics"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log"
@@ -373,6 +374,14 @@ func (pd *ProtocolDriver) storeFirstVotes(message FirstVotingMessage, minerPK *s
 	}
 
 	pd.current.setMinerFirstRoundVote(minerPK, voteList)
+	metrics.VotesReceived.WithLabelValues("first").Inc()
+	pd.current.recordVote(VoteRecord{
+		Round:   types.FirstRound,
+		Miner:   minerPK.Bytes(),
+		Weight:  voteWeight.Uint64(),
+		Support: message.ValidProposals,
+		Against: message.PotentiallyValidProposals,
+	})
 }
 
 // HandleSerializedFollowingVotingMessage defines method to handle following voting Messages from gossip.
@@ -490,14 +499,22 @@ func (pd *ProtocolDriver) storeFollowingVotes(message FollowingVotingMessage, mi
 	}
 
 	thisRoundVotes := decodeVotes(message.VotesBitVector, firstRoundVotes)
-	pd.addToVoteMargin(thisRoundVotes, voteWeight)
+	pd.addToVoteMargin(thisRoundVotes, voteWeight, VoteRecord{
+		Round:  message.RoundID,
+		Miner:  minerPK.Bytes(),
+		Weight: voteWeight.Uint64(),
+		Bits:   message.VotesBitVector,
+	})
 	return nil
 }
 
-func (pd *ProtocolDriver) addToVoteMargin(thisRoundVotes allVotes, voteWeight *big.Int) {
+func (pd *ProtocolDriver) addToVoteMargin(thisRoundVotes allVotes, voteWeight *big.Int, vote VoteRecord) {
 	pd.mu.Lock()
 	defer pd.mu.Unlock()
 
+	metrics.VotesReceived.WithLabelValues("following").Inc()
+	pd.current.recordVote(vote)
+
 	for proposal := range thisRoundVotes.support {
 		pd.current.addVote(proposal, up, voteWeight)
 	}
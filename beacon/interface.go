@@ -6,6 +6,7 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/beacon/weakcoin"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/p2p"
 )
 
 //go:generate mockgen -package=mocks -destination=./mocks/mocks.go -source=./interface.go
@@ -28,3 +29,15 @@ type coin interface {
 type eligibilityChecker interface {
 	IsProposalEligible([]byte) bool
 }
+
+type requestor interface {
+	Request(context.Context, p2p.Peer, []byte, func([]byte), func(error)) error
+}
+
+type peerProvider interface {
+	GetPeers() []p2p.Peer
+}
+
+type ballotProvider interface {
+	GetBallot(types.BallotID) (*types.Ballot, error)
+}
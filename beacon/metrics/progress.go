@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/spacemeshos/go-spacemesh/metrics"
+)
+
+const (
+	// PhaseLabel is the label name for the phase of the beacon protocol.
+	PhaseLabel = "phase"
+	// ValidityLabel is the label name for the validity of the received proposals.
+	ValidityLabel = "validity"
+	// RoundTypeLabel is the label name for the voting round: first or following.
+	RoundTypeLabel = "round_type"
+	// ResultLabel is the label name for the result of the own proposal.
+	ResultLabel = "result"
+)
+
+// Phase is set to 1 for the current phase of the beacon protocol and to 0 for other phases.
+var Phase = metrics.NewGauge(
+	"phase",
+	subsystem,
+	"current phase of the beacon protocol",
+	[]string{
+		PhaseLabel,
+	},
+)
+
+// Round is the current voting round of the beacon protocol.
+var Round = metrics.NewGauge(
+	"round",
+	subsystem,
+	"current voting round of the beacon protocol",
+	[]string{},
+)
+
+// Proposals is the number of proposals received in the current epoch.
+var Proposals = metrics.NewGauge(
+	"proposals",
+	subsystem,
+	"number of proposals received in the current epoch by validity",
+	[]string{
+		ValidityLabel,
+	},
+)
+
+// VotesReceived counts the voting messages received from other smeshers.
+var VotesReceived = metrics.NewCounter(
+	"votes_received",
+	subsystem,
+	"number of voting messages received by round type",
+	[]string{
+		RoundTypeLabel,
+	},
+)
+
+// OwnVotes counts the voting messages sent by the node.
+var OwnVotes = metrics.NewCounter(
+	"own_votes",
+	subsystem,
+	"number of voting messages sent by the node",
+	[]string{},
+)
+
+// OwnProposals counts the results of the own proposal in every epoch.
+var OwnProposals = metrics.NewCounter(
+	"own_proposals",
+	subsystem,
+	"results of the own proposal: sent, ineligible or not_participating",
+	[]string{
+		ResultLabel,
+	},
+)
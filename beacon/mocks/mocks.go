@@ -12,6 +12,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	weakcoin "github.com/spacemeshos/go-spacemesh/beacon/weakcoin"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	p2p "github.com/spacemeshos/go-spacemesh/p2p"
 )
 
 // MockactivationDB is a mock of activationDB interface.
@@ -221,3 +222,115 @@ func (mr *MockeligibilityCheckerMockRecorder) IsProposalEligible(arg0 interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProposalEligible", reflect.TypeOf((*MockeligibilityChecker)(nil).IsProposalEligible), arg0)
 }
+
+// Mockrequestor is a mock of requestor interface.
+type Mockrequestor struct {
+	ctrl     *gomock.Controller
+	recorder *MockrequestorMockRecorder
+}
+
+// MockrequestorMockRecorder is the mock recorder for Mockrequestor.
+type MockrequestorMockRecorder struct {
+	mock *Mockrequestor
+}
+
+// NewMockrequestor creates a new mock instance.
+func NewMockrequestor(ctrl *gomock.Controller) *Mockrequestor {
+	mock := &Mockrequestor{ctrl: ctrl}
+	mock.recorder = &MockrequestorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrequestor) EXPECT() *MockrequestorMockRecorder {
+	return m.recorder
+}
+
+// Request mocks base method.
+func (m *Mockrequestor) Request(arg0 context.Context, arg1 p2p.Peer, arg2 []byte, arg3 func([]byte), arg4 func(error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockrequestorMockRecorder) Request(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*Mockrequestor)(nil).Request), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockpeerProvider is a mock of peerProvider interface.
+type MockpeerProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockpeerProviderMockRecorder
+}
+
+// MockpeerProviderMockRecorder is the mock recorder for MockpeerProvider.
+type MockpeerProviderMockRecorder struct {
+	mock *MockpeerProvider
+}
+
+// NewMockpeerProvider creates a new mock instance.
+func NewMockpeerProvider(ctrl *gomock.Controller) *MockpeerProvider {
+	mock := &MockpeerProvider{ctrl: ctrl}
+	mock.recorder = &MockpeerProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpeerProvider) EXPECT() *MockpeerProviderMockRecorder {
+	return m.recorder
+}
+
+// GetPeers mocks base method.
+func (m *MockpeerProvider) GetPeers() []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeers")
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// GetPeers indicates an expected call of GetPeers.
+func (mr *MockpeerProviderMockRecorder) GetPeers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeers", reflect.TypeOf((*MockpeerProvider)(nil).GetPeers))
+}
+
+// MockballotProvider is a mock of ballotProvider interface.
+type MockballotProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockballotProviderMockRecorder
+}
+
+// MockballotProviderMockRecorder is the mock recorder for MockballotProvider.
+type MockballotProviderMockRecorder struct {
+	mock *MockballotProvider
+}
+
+// NewMockballotProvider creates a new mock instance.
+func NewMockballotProvider(ctrl *gomock.Controller) *MockballotProvider {
+	mock := &MockballotProvider{ctrl: ctrl}
+	mock.recorder = &MockballotProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockballotProvider) EXPECT() *MockballotProviderMockRecorder {
+	return m.recorder
+}
+
+// GetBallot mocks base method.
+func (m *MockballotProvider) GetBallot(arg0 types.BallotID) (*types.Ballot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBallot", arg0)
+	ret0, _ := ret[0].(*types.Ballot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBallot indicates an expected call of GetBallot.
+func (mr *MockballotProviderMockRecorder) GetBallot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBallot", reflect.TypeOf((*MockballotProvider)(nil).GetBallot), arg0)
+}
@@ -0,0 +1,175 @@
+package beacon
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/spacemeshos/go-spacemesh/beacon/metrics"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+)
+
+// Phase is the phase of the beacon protocol in the epoch.
+type Phase uint8
+
+const (
+	// PhaseIdle is reported when the node doesn't participate in the protocol in the epoch.
+	PhaseIdle Phase = iota
+	// PhaseProposal is the phase when the proposals are sent and received.
+	PhaseProposal
+	// PhaseFirstVoting is the first voting round, when the votes for every proposal are sent.
+	PhaseFirstVoting
+	// PhaseFollowingVoting are the following voting rounds, when the votes are sent as a bit vector.
+	PhaseFollowingVoting
+	// PhaseWeakCoin is the phase of the following voting round when the weak coin is tossed.
+	PhaseWeakCoin
+	// PhaseFinished is reported once the beacon is calculated.
+	PhaseFinished
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseIdle:
+		return "idle"
+	case PhaseProposal:
+		return "proposal"
+	case PhaseFirstVoting:
+		return "first voting"
+	case PhaseFollowingVoting:
+		return "following voting"
+	case PhaseWeakCoin:
+		return "weak coin"
+	case PhaseFinished:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+var allPhases = []Phase{PhaseIdle, PhaseProposal, PhaseFirstVoting, PhaseFollowingVoting, PhaseWeakCoin, PhaseFinished}
+
+// progress of the protocol in the epoch. counts of the proposals and votes are kept in the state.
+type progress struct {
+	epoch        types.EpochID
+	phase        Phase
+	proposalSent bool
+	// proposal explains why the own proposal wasn't sent.
+	proposal string
+	// finished is the progress at the end of the epoch. it is kept after the state for the epoch is dropped.
+	finished *events.BeaconProgress
+}
+
+// Progress returns the progress of the beacon protocol in the latest epoch.
+func (pd *ProtocolDriver) Progress() events.BeaconProgress {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.progressLocked()
+}
+
+func (pd *ProtocolDriver) progressLocked() events.BeaconProgress {
+	if pd.progress.finished != nil {
+		return *pd.progress.finished
+	}
+	p := events.BeaconProgress{
+		Epoch:        pd.progress.epoch,
+		Phase:        pd.progress.phase.String(),
+		ProposalSent: pd.progress.proposalSent,
+		Proposal:     pd.progress.proposal,
+	}
+	if pd.progress.phase == PhaseIdle {
+		return p
+	}
+	p.Round = pd.roundInProgress
+	p.ValidProposals = len(pd.current.incomingProposals.valid)
+	p.PotentiallyValidProposals = len(pd.current.incomingProposals.potentiallyValid)
+	p.Votes = make([]uint32, len(pd.current.votesReceived))
+	copy(p.Votes, pd.current.votesReceived)
+	p.OwnVotes = pd.current.ownVotes
+	return p
+}
+
+func (pd *ProtocolDriver) reportProgress() {
+	pd.mu.RLock()
+	p := pd.progressLocked()
+	phase := pd.progress.phase
+	pd.mu.RUnlock()
+
+	for _, other := range allPhases {
+		value := 0.0
+		if other == phase {
+			value = 1
+		}
+		metrics.Phase.WithLabelValues(other.String()).Set(value)
+	}
+	metrics.Round.WithLabelValues().Set(float64(p.Round))
+	metrics.Proposals.WithLabelValues("valid").Set(float64(p.ValidProposals))
+	metrics.Proposals.WithLabelValues("potentially_valid").Set(float64(p.PotentiallyValidProposals))
+	events.ReportBeaconProgress(p)
+}
+
+func (pd *ProtocolDriver) setPhase(phase Phase) {
+	pd.mu.Lock()
+	pd.progress.phase = phase
+	pd.mu.Unlock()
+	pd.reportProgress()
+}
+
+// startProgress resets the progress for the epoch in which the node participates in the protocol.
+func (pd *ProtocolDriver) startProgress(epoch types.EpochID) {
+	pd.mu.Lock()
+	pd.progress = progress{epoch: epoch, phase: PhaseProposal}
+	pd.mu.Unlock()
+	pd.reportProgress()
+}
+
+// notParticipating reports the reason why the node doesn't participate in the protocol in the epoch.
+func (pd *ProtocolDriver) notParticipating(epoch types.EpochID, reason string) {
+	pd.mu.Lock()
+	pd.progress = progress{epoch: epoch, phase: PhaseIdle, proposal: reason}
+	pd.mu.Unlock()
+	metrics.OwnProposals.WithLabelValues("not_participating").Inc()
+	pd.reportProgress()
+}
+
+func (pd *ProtocolDriver) setOwnProposal(sent bool, reason string) {
+	pd.mu.Lock()
+	pd.progress.proposalSent = sent
+	pd.progress.proposal = reason
+	pd.mu.Unlock()
+	if sent {
+		metrics.OwnProposals.WithLabelValues("sent").Inc()
+	} else {
+		metrics.OwnProposals.WithLabelValues("ineligible").Inc()
+	}
+	pd.reportProgress()
+}
+
+func (pd *ProtocolDriver) finishProgress(beacon types.Beacon) {
+	pd.mu.Lock()
+	pd.progress.phase = PhaseFinished
+	finished := pd.progressLocked()
+	finished.Beacon = beacon
+	pd.progress.finished = &finished
+	pd.mu.Unlock()
+	pd.reportProgress()
+}
+
+func (pd *ProtocolDriver) recordOwnVote() {
+	pd.mu.Lock()
+	pd.current.ownVotes++
+	pd.mu.Unlock()
+	metrics.OwnVotes.WithLabelValues().Inc()
+}
+
+// explainIneligibleProposal explains why the proposal doesn't pass the threshold of the proposal checker.
+// The proposal is eligible if its VRF signature, as a fraction of the largest possible signature,
+// is below the threshold fraction that depends only on the total weight of the epoch.
+func explainIneligibleProposal(proposal []byte, kappa uint64, q *big.Rat, epochWeight uint64) string {
+	const signatureLength = 64 * 8
+	max := new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), signatureLength))
+	value := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).SetBytes(proposal)), max)
+	threshold := atxThresholdFraction(kappa, q, epochWeight)
+	return fmt.Sprintf("proposal %s is %.4g of the signature range, not below the threshold %.4g for epoch weight %d (kappa %d, q %s). "+
+		"every smesher is eligible with the same probability regardless of its own weight",
+		types.BytesToHash(proposal).ShortString(), value, threshold, epochWeight, kappa, q.RatString())
+}
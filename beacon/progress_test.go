@@ -0,0 +1,104 @@
+package beacon
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func TestPhase_String(t *testing.T) {
+	for _, phase := range allPhases {
+		require.NotEqual(t, "unknown", phase.String())
+	}
+	require.Equal(t, "unknown", Phase(100).String())
+}
+
+func TestProgress(t *testing.T) {
+	events.CloseEventReporter()
+	require.NoError(t, events.InitializeEventReporter(""))
+	defer events.CloseEventReporter()
+	sub := events.SubscribeBeaconProgress()
+	defer sub.Close()
+
+	cfg := UnitTestConfig()
+	cfg.RoundsNumber = 3
+	pd := &ProtocolDriver{
+		config:  cfg,
+		current: newState(cfg),
+		logger:  logtest.New(t),
+	}
+	const epoch = types.EpochID(5)
+	pd.notParticipating(epoch, "node is not synced")
+	require.Equal(t, events.BeaconProgress{Epoch: epoch, Phase: "idle", Proposal: "node is not synced"}, pd.Progress())
+
+	pd.startProgress(epoch)
+	pd.current.incomingProposals.valid = proposalSet{"0x01": {}, "0x02": {}}
+	pd.current.incomingProposals.potentiallyValid = proposalSet{"0x03": {}}
+	pd.setOwnProposal(false, "not eligible")
+	pd.setRoundInProgress(types.FirstRound)
+	pd.setPhase(PhaseFirstVoting)
+	pd.current.recordVote(VoteRecord{Round: types.FirstRound})
+	pd.current.recordVote(VoteRecord{Round: types.FirstRound})
+	pd.recordOwnVote()
+	pd.setRoundInProgress(1)
+	pd.setPhase(PhaseFollowingVoting)
+	pd.current.recordVote(VoteRecord{Round: 1})
+	pd.recordOwnVote()
+	pd.setPhase(PhaseWeakCoin)
+
+	expected := events.BeaconProgress{
+		Epoch:                     epoch,
+		Phase:                     "weak coin",
+		Round:                     1,
+		ValidProposals:            2,
+		PotentiallyValidProposals: 1,
+		Votes:                     []uint32{2, 1, 0},
+		OwnVotes:                  2,
+		Proposal:                  "not eligible",
+	}
+	require.Equal(t, expected, pd.Progress())
+
+	beacon := types.RandomBeacon()
+	pd.finishProgress(beacon)
+	// the state for the epoch is dropped once the protocol finishes
+	pd.current = newState(cfg)
+	pd.setRoundInProgress(types.FirstRound)
+	expected.Phase = "finished"
+	expected.Beacon = beacon
+	require.Equal(t, expected, pd.Progress())
+
+	var reported []events.BeaconProgress
+	timeout := time.After(time.Second)
+	for len(reported) < 7 {
+		select {
+		case ev := <-sub.Out():
+			reported = append(reported, ev.(events.BeaconProgress))
+		case <-timeout:
+			require.FailNow(t, "progress wasn't reported", "reported %d", len(reported))
+		}
+	}
+	var phases []string
+	for _, p := range reported {
+		phases = append(phases, p.Phase)
+	}
+	require.Equal(t, []string{"idle", "proposal", "proposal", "first voting", "following voting", "weak coin", "finished"}, phases)
+	require.Equal(t, expected, reported[len(reported)-1])
+}
+
+func TestExplainIneligibleProposal(t *testing.T) {
+	cfg := DefaultConfig()
+	proposal := bytes.Repeat([]byte{0xff}, 64)
+	checker := createProposalChecker(logtest.New(t), cfg.Kappa, cfg.Q, 10000)
+	require.False(t, checker.IsProposalEligible(proposal))
+
+	explanation := explainIneligibleProposal(proposal, cfg.Kappa, cfg.Q, 10000)
+	require.Contains(t, explanation, "is 1 of the signature range")
+	require.Contains(t, explanation, "not below the threshold 0.00")
+	require.Contains(t, explanation, "epoch weight 10000 (kappa 40, q 1/3)")
+}
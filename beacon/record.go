@@ -0,0 +1,213 @@
+package beacon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+)
+
+var errInvalidRecord = errors.New("invalid beacon record")
+
+// EpochRecord is the record of the beacon protocol executed by the node in the epoch.
+// It contains everything that is needed to recompute the beacon, votes are kept in the order they were counted.
+type EpochRecord struct {
+	Epoch       types.EpochID
+	EpochWeight uint64
+	Theta       string
+	Rounds      types.RoundID
+	VotesLimit  uint32
+	// ValidProposals and PotentiallyValidProposals are the proposals received in the proposal phase.
+	ValidProposals            [][]byte
+	PotentiallyValidProposals [][]byte
+	Votes                     []VoteRecord
+	Coins                     []CoinRecord
+	// Support is the list of proposals that the beacon was calculated from.
+	Support [][]byte
+	Beacon  types.Beacon
+}
+
+// VoteRecord is the voting message from the miner counted by the node.
+type VoteRecord struct {
+	// Tally is the number of times the votes were tallied before this vote was counted.
+	Tally  uint32
+	Round  types.RoundID
+	Miner  []byte
+	Weight uint64
+	// Support and Against are the votes of the first round.
+	Support, Against [][]byte
+	// Bits are the votes of the following rounds, encoded relative to the votes of the first round.
+	Bits []byte
+}
+
+// CoinRecord is the weak coin value of the round.
+type CoinRecord struct {
+	Round types.RoundID
+	Value bool
+}
+
+// DecidedProposal is the vote of the node for the proposal at the end of the protocol.
+type DecidedProposal struct {
+	Proposal []byte
+	Support  bool
+	// Round is the first round since which the votes for the proposal crossed the threshold.
+	// If Coinflip is true the votes didn't cross the threshold in the last round, and the proposal
+	// was decided by the weak coin of the last round.
+	Round    types.RoundID
+	Coinflip bool
+}
+
+// AuditResult is the beacon recomputed from the record and the decisions about the proposals.
+type AuditResult struct {
+	Beacon    types.Beacon
+	Proposals []DecidedProposal
+}
+
+func (pd *ProtocolDriver) recordCoin(round types.RoundID, value bool) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.current.coins = append(pd.current.coins, CoinRecord{Round: round, Value: value})
+}
+
+func (pd *ProtocolDriver) persistRecord(epoch types.EpochID, lastRoundVotes allVotes, beacon types.Beacon) error {
+	pd.mu.RLock()
+	record := &EpochRecord{
+		Epoch:                     epoch,
+		EpochWeight:               pd.current.epochWeight,
+		Theta:                     pd.config.Theta.RatString(),
+		Rounds:                    pd.config.RoundsNumber,
+		VotesLimit:                pd.config.VotesLimit,
+		ValidProposals:            pd.current.incomingProposals.valid.sort(),
+		PotentiallyValidProposals: pd.current.incomingProposals.potentiallyValid.sort(),
+		Votes:                     pd.current.votes,
+		Coins:                     pd.current.coins,
+		Support:                   lastRoundVotes.support.sort(),
+		Beacon:                    beacon,
+	}
+	buf, err := codec.Encode(record)
+	pd.mu.RUnlock()
+	if err != nil {
+		pd.logger.With().Panic("failed to encode beacon record", log.Err(err))
+	}
+	if err := beacons.SetRecord(pd.db, epoch, buf); err != nil {
+		return fmt.Errorf("persist beacon record: %w", err)
+	}
+	return nil
+}
+
+// LoadRecord loads the record of the beacon protocol executed in the epoch.
+func LoadRecord(db sql.Executor, epoch types.EpochID) (*EpochRecord, error) {
+	buf, err := beacons.GetRecord(db, epoch)
+	if err != nil {
+		return nil, err
+	}
+	var record EpochRecord
+	if err := codec.Decode(buf, &record); err != nil {
+		return nil, fmt.Errorf("decode beacon record: %w", err)
+	}
+	return &record, nil
+}
+
+// Audit recomputes the beacon by replaying the votes from the record and finds the round
+// when each proposal was decided.
+func Audit(logger log.Log, record *EpochRecord) (*AuditResult, error) {
+	rat, ok := new(big.Rat).SetString(record.Theta)
+	if !ok {
+		return nil, fmt.Errorf("%w: theta %s", errInvalidRecord, record.Theta)
+	}
+	if record.EpochWeight == 0 {
+		return nil, fmt.Errorf("%w: %v", errInvalidRecord, errZeroEpochWeight)
+	}
+	theta := new(big.Float).SetRat(rat)
+	coins := make(map[types.RoundID]bool, len(record.Coins))
+	for _, coin := range record.Coins {
+		coins[coin.Round] = coin.Value
+	}
+
+	s := &state{
+		epochWeight:             record.EpochWeight,
+		firstRoundIncomingVotes: make(map[string]proposalList),
+		votesMargin:             map[string]*big.Int{},
+	}
+	decisions := map[string]*DecidedProposal{}
+	var (
+		ownVotes allVotes
+		next     int
+	)
+	for round := types.FirstRound; round < record.Rounds; round++ {
+		for ; next < len(record.Votes) && record.Votes[next].Tally <= uint32(round); next++ {
+			if err := replayVote(s, record.VotesLimit, &record.Votes[next]); err != nil {
+				return nil, err
+			}
+		}
+		var undecided []string
+		ownVotes, undecided = calcVotes(logger, theta, s)
+		if round != types.FirstRound {
+			coin, exist := coins[round]
+			if !exist {
+				return nil, fmt.Errorf("%w: missing weak coin for round %v", errInvalidRecord, round)
+			}
+			tallyUndecided(&ownVotes, undecided, coin)
+		}
+		flipped := make(map[string]struct{}, len(undecided))
+		for _, p := range undecided {
+			flipped[p] = struct{}{}
+		}
+		for support, proposals := range map[bool]proposalSet{true: ownVotes.support, false: ownVotes.against} {
+			for p := range proposals {
+				_, coinflip := flipped[p]
+				d := decisions[p]
+				if d == nil || d.Support != support || d.Coinflip || coinflip {
+					decisions[p] = &DecidedProposal{Proposal: []byte(p), Support: support, Round: round, Coinflip: coinflip}
+				}
+			}
+		}
+	}
+
+	result := &AuditResult{Beacon: calcBeacon(logger, ownVotes)}
+	for _, d := range decisions {
+		result.Proposals = append(result.Proposals, *d)
+	}
+	sort.Slice(result.Proposals, func(i, j int) bool {
+		return bytes.Compare(result.Proposals[i].Proposal, result.Proposals[j].Proposal) == -1
+	})
+	return result, nil
+}
+
+func replayVote(s *state, votesLimit uint32, vote *VoteRecord) error {
+	weight := new(big.Int).SetUint64(vote.Weight)
+	if vote.Round == types.FirstRound {
+		for _, p := range vote.Support {
+			s.addVote(string(p), up, weight)
+		}
+		for _, p := range vote.Against {
+			s.addVote(string(p), down, weight)
+		}
+		voteList := make(proposalList, 0, len(vote.Support)+len(vote.Against))
+		voteList = append(append(voteList, vote.Support...), vote.Against...)
+		if uint32(len(voteList)) > votesLimit {
+			voteList = voteList[:votesLimit]
+		}
+		s.firstRoundIncomingVotes[string(vote.Miner)] = voteList
+		return nil
+	}
+	firstRound, exist := s.firstRoundIncomingVotes[string(vote.Miner)]
+	if !exist {
+		return fmt.Errorf("%w: %v for round %v", errInvalidRecord, errFirstRoundVoteMissing, vote.Round)
+	}
+	votes := decodeVotes(vote.Bits, firstRound)
+	for p := range votes.support {
+		s.addVote(p, up, weight)
+	}
+	for p := range votes.against {
+		s.addVote(p, down, weight)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+)
+
+func TestAudit(t *testing.T) {
+	var (
+		p1, p2, p3, p4 = []byte("0x01"), []byte("0x02"), []byte("0x03"), []byte("0x04")
+		minerA, minerB = []byte("a"), []byte("b")
+		firstA         = [][]byte{p1, p2, p3}
+		firstB         = [][]byte{p3, p4, p2}
+	)
+	bits := func(first [][]byte, support ...[]byte) []byte {
+		votes := allVotes{support: proposalSet{}}
+		for _, p := range support {
+			votes.support[string(p)] = struct{}{}
+		}
+		return encodeVotes(votes, first)
+	}
+	// threshold is 25
+	record := &EpochRecord{
+		Epoch:       2,
+		EpochWeight: 100,
+		Theta:       "1/4",
+		Rounds:      3,
+		VotesLimit:  100,
+		Votes: []VoteRecord{
+			{Tally: 0, Round: 0, Miner: minerA, Weight: 30, Support: firstA[:2], Against: firstA[2:]},
+			{Tally: 0, Round: 0, Miner: minerB, Weight: 10, Support: firstB[:2], Against: firstB[2:]},
+			{Tally: 1, Round: 1, Miner: minerA, Weight: 30, Bits: bits(firstA, p1, p2)},
+			{Tally: 2, Round: 2, Miner: minerB, Weight: 10, Bits: bits(firstB, p3)},
+			// counted after the last tally
+			{Tally: 3, Round: 2, Miner: minerA, Weight: 30, Bits: bits(firstA, p4)},
+		},
+		Coins: []CoinRecord{{Round: 1, Value: true}, {Round: 2, Value: true}},
+	}
+	rst, err := Audit(logtest.New(t), record)
+	require.NoError(t, err)
+	expected := calcBeacon(logtest.New(t), allVotes{support: proposalSet{
+		string(p1): {}, string(p2): {}, string(p4): {},
+	}})
+	require.Equal(t, expected, rst.Beacon)
+	require.Equal(t, []DecidedProposal{
+		{Proposal: p1, Support: true, Round: 0},
+		{Proposal: p2, Support: true, Round: 1},
+		{Proposal: p3, Support: false, Round: 1},
+		{Proposal: p4, Support: true, Round: 2, Coinflip: true},
+	}, rst.Proposals)
+
+	t.Run("MissingCoin", func(t *testing.T) {
+		invalid := *record
+		invalid.Coins = record.Coins[:1]
+		_, err := Audit(logtest.New(t), &invalid)
+		require.ErrorIs(t, err, errInvalidRecord)
+	})
+	t.Run("MissingFirstRoundVotes", func(t *testing.T) {
+		invalid := *record
+		invalid.Votes = record.Votes[1:]
+		_, err := Audit(logtest.New(t), &invalid)
+		require.ErrorIs(t, err, errInvalidRecord)
+	})
+}
+
+func TestLoadRecord(t *testing.T) {
+	db := sql.InMemory()
+	_, err := LoadRecord(db, 2)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	pd := &ProtocolDriver{
+		config:  UnitTestConfig(),
+		db:      db,
+		current: newState(UnitTestConfig()),
+		logger:  logtest.New(t),
+	}
+	pd.current.epochWeight = 10
+	pd.current.incomingProposals.valid = proposalSet{"0x01": {}}
+	pd.current.recordVote(VoteRecord{Miner: []byte("a"), Weight: 10, Support: [][]byte{[]byte("0x01")}})
+	pd.current.tallies++
+	pd.recordCoin(1, true)
+	lastRound := allVotes{support: proposalSet{"0x01": {}}}
+	beacon := calcBeacon(pd.logger, lastRound)
+	require.NoError(t, pd.persistRecord(2, lastRound, beacon))
+
+	record, err := LoadRecord(db, 2)
+	require.NoError(t, err)
+	require.Equal(t, types.EpochID(2), record.Epoch)
+	require.Equal(t, uint64(10), record.EpochWeight)
+	require.Equal(t, pd.config.Theta.RatString(), record.Theta)
+	require.Equal(t, [][]byte{[]byte("0x01")}, record.ValidProposals)
+	require.Len(t, record.Votes, 1)
+	require.Zero(t, record.Votes[0].Tally)
+	require.Equal(t, []CoinRecord{{Round: 1, Value: true}}, record.Coins)
+	require.Equal(t, [][]byte{[]byte("0x01")}, record.Support)
+	require.Equal(t, beacon, record.Beacon)
+
+	require.NoError(t, beacons.SetRecord(db, 3, []byte{1}))
+	_, err = LoadRecord(db, 3)
+	require.Error(t, err)
+}
@@ -29,6 +29,14 @@ type state struct {
 	proposalPhaseFinishedTime time.Time
 	proposalChan              chan *proposalMessageWithReceiptData
 	proposalChecker           eligibilityChecker
+	// tallies is the number of times the votes were tallied in the epoch.
+	// counted votes and weak coin values are recorded to audit the beacon later.
+	tallies uint32
+	votes   []VoteRecord
+	coins   []CoinRecord
+	// votesReceived is the number of votes received in each round, ownVotes is the number of votes sent.
+	votesReceived []uint32
+	ownVotes      uint32
 }
 
 func newState(cfg Config) *state {
@@ -37,6 +45,7 @@ func newState(cfg Config) *state {
 		votesMargin:             map[string]*big.Int{},
 		hasProposed:             make(map[string]struct{}),
 		hasVoted:                make([]map[string]struct{}, cfg.RoundsNumber),
+		votesReceived:           make([]uint32, cfg.RoundsNumber),
 		proposalChan:            make(chan *proposalMessageWithReceiptData, proposalChanCapacity),
 	}
 }
@@ -69,6 +78,14 @@ func (s *state) addVote(proposal string, vote uint, voteWeight *big.Int) {
 	}
 }
 
+func (s *state) recordVote(vote VoteRecord) {
+	vote.Tally = s.tallies
+	s.votes = append(s.votes, vote)
+	if int(vote.Round) < len(s.votesReceived) {
+		s.votesReceived[vote.Round]++
+	}
+}
+
 func (s *state) registerProposed(logger log.Log, minerPK *signing.PublicKey) error {
 	minerID := string(minerPK.Bytes())
 	if _, ok := s.hasProposed[minerID]; ok {
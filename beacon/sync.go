@@ -0,0 +1,314 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/system"
+)
+
+// SyncProtocol is the protocol to request the beacon of the epoch from peers.
+const SyncProtocol = "/beacon/sync/1.0.0"
+
+// maxSyncBallots is the maximal number of ballots with the beacon that a peer serves.
+const maxSyncBallots = 1000
+
+var (
+	errNoSyncPeers     = errors.New("no peers to sync beacon from")
+	errNoSyncAgreement = errors.New("peers don't agree on beacon")
+)
+
+// SyncRequest is the request for the beacon used in the epoch.
+type SyncRequest struct {
+	Epoch types.EpochID
+}
+
+// SyncResponse is the beacon used in the epoch, with the ballots that the peer received with this
+// beacon in the epoch. The weight of the beacon is verified from the ballots by the requesting node.
+type SyncResponse struct {
+	Epoch   types.EpochID
+	Beacon  types.Beacon
+	Ballots []types.BallotID
+}
+
+// SetBeaconSync enables requesting the beacon from peers when the node didn't participate in the protocol.
+// Ballots reported by peers are fetched with the fetcher and loaded from the ballots provider.
+// Must be executed before Start.
+func (pd *ProtocolDriver) SetBeaconSync(requestor requestor, peers peerProvider, fetcher system.BallotFetcher, ballots ballotProvider) {
+	pd.requestor = requestor
+	pd.peers = peers
+	pd.fetcher = fetcher
+	pd.ballots = ballots
+}
+
+// HandleSyncRequest serves the beacon used in the requested epoch.
+func (pd *ProtocolDriver) HandleSyncRequest(ctx context.Context, req []byte) ([]byte, error) {
+	var request SyncRequest
+	if err := codec.Decode(req, &request); err != nil {
+		return nil, fmt.Errorf("decode beacon sync request: %w", err)
+	}
+	beacon, err := pd.GetBeacon(request.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	resp := SyncResponse{Epoch: request.Epoch, Beacon: beacon}
+	pd.mu.RLock()
+	if entry, exist := pd.beaconsFromBallots[request.Epoch][beacon]; exist {
+		for bid := range entry.ballots {
+			if len(resp.Ballots) == maxSyncBallots {
+				break
+			}
+			resp.Ballots = append(resp.Ballots, bid)
+		}
+	}
+	pd.mu.RUnlock()
+	buf, err := codec.Encode(&resp)
+	if err != nil {
+		pd.logger.WithContext(ctx).With().Panic("failed to encode beacon sync response", log.Err(err))
+	}
+	return buf, nil
+}
+
+// maybeSyncBeacon requests the beacon for the epoch from peers in the background,
+// if the beacon is not known and no other request is in progress.
+func (pd *ProtocolDriver) maybeSyncBeacon(ctx context.Context, epoch types.EpochID) {
+	if pd.requestor == nil || epoch <= types.EpochID(2) {
+		return
+	}
+	if pd.getBeacon(epoch) != types.EmptyBeacon {
+		return
+	}
+	if _, err := pd.getPersistedBeacon(epoch); err == nil {
+		return
+	}
+	if !atomic.CompareAndSwapUint64(&pd.syncing, 0, 1) {
+		return
+	}
+	pd.eg.Go(func() error {
+		defer atomic.StoreUint64(&pd.syncing, 0)
+		if _, err := pd.syncBeacon(ctx, epoch); err != nil {
+			pd.logger.WithContext(ctx).With().Info("failed to sync beacon from peers", epoch, log.Err(err))
+		}
+		return nil
+	})
+}
+
+// syncBeacon requests the beacon for the epoch from BeaconSyncNumPeers peers. Peers can't be trusted
+// with the weight of the beacon, therefore the node fetches the ballots reported by peers and sums
+// the weight of their ATXs. The beacon is accepted if its weight is at least BeaconSyncWeightFraction
+// of the verified weight for all beacons.
+func (pd *ProtocolDriver) syncBeacon(ctx context.Context, epoch types.EpochID) (types.Beacon, error) {
+	peers := pd.peers.GetPeers()
+	if len(peers) > pd.config.BeaconSyncNumPeers {
+		peers = peers[:pd.config.BeaconSyncNumPeers]
+	}
+	if len(peers) == 0 {
+		return types.EmptyBeacon, errNoSyncPeers
+	}
+	req, err := codec.Encode(&SyncRequest{Epoch: epoch})
+	if err != nil {
+		pd.logger.With().Panic("failed to encode beacon sync request", log.Err(err))
+	}
+	logger := pd.logger.WithContext(ctx).WithFields(epoch)
+
+	results := make(chan syncResult, len(peers))
+	sent := 0
+	for _, peer := range peers {
+		if err := pd.requestBeacon(ctx, peer, req, epoch, results); err != nil {
+			logger.With().Debug("failed to request beacon", log.String("peer", peer.String()), log.Err(err))
+			continue
+		}
+		sent++
+	}
+	reported := map[types.Beacon]map[types.BallotID]struct{}{}
+	for i := 0; i < sent; i++ {
+		var rst syncResult
+		select {
+		case rst = <-results:
+		case <-ctx.Done():
+			return types.EmptyBeacon, ctx.Err()
+		}
+		if rst.err != nil {
+			logger.With().Debug("failed to sync beacon", log.String("peer", rst.peer.String()), log.Err(rst.err))
+			continue
+		}
+		logger.With().Debug("received beacon from peer",
+			log.String("peer", rst.peer.String()),
+			rst.resp.Beacon,
+			log.Int("ballots", len(rst.resp.Ballots)))
+		if _, exist := reported[rst.resp.Beacon]; !exist {
+			reported[rst.resp.Beacon] = map[types.BallotID]struct{}{}
+		}
+		for _, bid := range rst.resp.Ballots {
+			reported[rst.resp.Beacon][bid] = struct{}{}
+		}
+	}
+
+	weights, total, err := pd.syncWeights(ctx, epoch, reported)
+	if err != nil {
+		return types.EmptyBeacon, err
+	}
+	var (
+		beacon types.Beacon
+		best   = new(big.Int)
+	)
+	for b, weight := range weights {
+		if weight.Cmp(best) > 0 {
+			beacon, best = b, weight
+		}
+	}
+	if total.Sign() == 0 {
+		return types.EmptyBeacon, fmt.Errorf("%w: no ballot weight verified from %d peers", errNoSyncAgreement, len(peers))
+	}
+	fraction := new(big.Rat).SetFrac(best, total)
+	if fraction.Cmp(pd.config.BeaconSyncWeightFraction) < 0 {
+		return types.EmptyBeacon, fmt.Errorf("%w: beacon %s has %s out of %s weight",
+			errNoSyncAgreement, beacon, best, total)
+	}
+	if existing := pd.getBeacon(epoch); existing != types.EmptyBeacon {
+		return existing, nil
+	}
+	if err := pd.setBeacon(epoch, beacon); err != nil {
+		return types.EmptyBeacon, err
+	}
+	logger.With().Info("beacon synced from peers",
+		beacon,
+		log.String("weight", best.String()),
+		log.String("total_weight", total.String()))
+	return beacon, nil
+}
+
+// syncWeights fetches the ballots reported with each beacon and sums the weight of the distinct ATXs
+// whose ref ballots in the epoch use the beacon. ATXs that used different beacons are not counted.
+// Ballots that can't be fetched or verified are ignored. Weights are capped at the epoch weight.
+func (pd *ProtocolDriver) syncWeights(ctx context.Context, epoch types.EpochID, reported map[types.Beacon]map[types.BallotID]struct{}) (map[types.Beacon]*big.Int, *big.Int, error) {
+	epochWeight, _, err := pd.atxDB.GetEpochWeight(epoch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get epoch weight: %w", err)
+	}
+	if epochWeight == 0 {
+		return nil, nil, errZeroEpochWeight
+	}
+	logger := pd.logger.WithContext(ctx).WithFields(epoch)
+
+	var ids []types.BallotID
+	for _, ballots := range reported {
+		for bid := range ballots {
+			ids = append(ids, bid)
+		}
+	}
+	if err := pd.fetcher.GetBallots(ctx, ids); err != nil {
+		logger.With().Debug("failed to fetch ballots reported with beacons", log.Err(err))
+	}
+
+	var (
+		beacons     = map[types.ATXID]types.Beacon{}
+		atxWeights  = map[types.ATXID]uint64{}
+		conflicting = map[types.ATXID]struct{}{}
+	)
+	for beacon, ballots := range reported {
+		for bid := range ballots {
+			atx, err := pd.verifySyncBallot(epoch, beacon, bid)
+			if err != nil {
+				logger.With().Debug("failed to verify ballot reported with beacon", beacon, bid, log.Err(err))
+				continue
+			}
+			if other, exist := beacons[atx.ID()]; exist && other != beacon {
+				conflicting[atx.ID()] = struct{}{}
+			}
+			beacons[atx.ID()] = beacon
+			atxWeights[atx.ID()] = atx.GetWeight()
+		}
+	}
+
+	var (
+		limit   = new(big.Int).SetUint64(epochWeight)
+		total   = new(big.Int)
+		weights = map[types.Beacon]*big.Int{}
+	)
+	for id, beacon := range beacons {
+		if _, exist := conflicting[id]; exist {
+			continue
+		}
+		weight := new(big.Int).SetUint64(atxWeights[id])
+		if _, exist := weights[beacon]; !exist {
+			weights[beacon] = new(big.Int)
+		}
+		weights[beacon].Add(weights[beacon], weight)
+		total.Add(total, weight)
+	}
+	for _, weight := range weights {
+		if weight.Cmp(limit) > 0 {
+			weight.Set(limit)
+		}
+	}
+	if total.Cmp(limit) > 0 {
+		total.Set(limit)
+	}
+	return weights, total, nil
+}
+
+// verifySyncBallot returns the header of the ATX that cast the ballot, if the ballot is in the epoch
+// and the ref ballot of the smesher uses the beacon.
+func (pd *ProtocolDriver) verifySyncBallot(epoch types.EpochID, beacon types.Beacon, bid types.BallotID) (*types.ActivationTxHeader, error) {
+	ballot, err := pd.ballots.GetBallot(bid)
+	if err != nil {
+		return nil, fmt.Errorf("get ballot: %w", err)
+	}
+	if ballot.LayerIndex.GetEpoch() != epoch {
+		return nil, fmt.Errorf("ballot in epoch %s", ballot.LayerIndex.GetEpoch())
+	}
+	ref := ballot
+	if ballot.EpochData == nil {
+		if ref, err = pd.ballots.GetBallot(ballot.RefBallot); err != nil {
+			return nil, fmt.Errorf("get ref ballot %s: %w", ballot.RefBallot, err)
+		}
+	}
+	if ref.EpochData == nil || ref.EpochData.Beacon != beacon {
+		return nil, fmt.Errorf("ref ballot %s doesn't use the beacon", ref.ID())
+	}
+	atx, err := pd.atxDB.GetAtxHeader(ballot.AtxID)
+	if err != nil {
+		return nil, fmt.Errorf("get atx %s: %w", ballot.AtxID, err)
+	}
+	if atx.TargetEpoch() != epoch {
+		return nil, fmt.Errorf("atx %s targets epoch %s", atx.ID(), atx.TargetEpoch())
+	}
+	return atx, nil
+}
+
+type syncResult struct {
+	peer p2p.Peer
+	resp *SyncResponse
+	err  error
+}
+
+// requestBeacon sends the request to the peer. The result is delivered to the results channel.
+func (pd *ProtocolDriver) requestBeacon(ctx context.Context, peer p2p.Peer, req []byte, epoch types.EpochID, results chan<- syncResult) error {
+	respFunc := func(data []byte) {
+		var resp SyncResponse
+		if err := codec.Decode(data, &resp); err != nil {
+			results <- syncResult{peer: peer, err: fmt.Errorf("decode beacon sync response: %w", err)}
+			return
+		}
+		if resp.Epoch != epoch {
+			results <- syncResult{peer: peer, err: fmt.Errorf("beacon for epoch %s instead of %s", resp.Epoch, epoch)}
+			return
+		}
+		results <- syncResult{peer: peer, resp: &resp}
+	}
+	errFunc := func(err error) {
+		results <- syncResult{peer: peer, err: err}
+	}
+	if err := pd.requestor.Request(ctx, peer, req, respFunc, errFunc); err != nil {
+		return fmt.Errorf("request beacon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/beacon/mocks"
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// syncNetwork serves beacon sync requests from the drivers of the peers, and keeps the valid
+// ballots and ATXs of the network.
+type syncNetwork struct {
+	servers map[p2p.Peer]*ProtocolDriver
+	order   []p2p.Peer
+	ballots map[types.BallotID]*types.Ballot
+	atxs    map[types.ATXID]*types.ActivationTxHeader
+}
+
+func newSyncNetwork() *syncNetwork {
+	return &syncNetwork{
+		servers: map[p2p.Peer]*ProtocolDriver{},
+		ballots: map[types.BallotID]*types.Ballot{},
+		atxs:    map[types.ATXID]*types.ActivationTxHeader{},
+	}
+}
+
+// addBallot adds the ref ballot of a new ATX with the weight, that uses the beacon in the epoch.
+// The ballot is not valid in the network if invalid is set.
+func (n *syncNetwork) addBallot(epoch types.EpochID, beacon types.Beacon, weight uint64, invalid bool) *types.Ballot {
+	atx := &types.ActivationTxHeader{
+		NIPostChallenge: types.NIPostChallenge{
+			PubLayerID: (epoch - 1).FirstLayer(),
+			EndTick:    1,
+		},
+		NumUnits: uint(weight),
+	}
+	atxID := types.RandomATXID()
+	atx.SetID(&atxID)
+	ballot := types.NewExistingBallot(types.RandomBallotID(), nil, nil, types.InnerBallot{
+		AtxID:      atxID,
+		EpochData:  &types.EpochData{Beacon: beacon},
+		LayerIndex: epoch.FirstLayer(),
+	})
+	if !invalid {
+		n.atxs[atxID] = atx
+		n.ballots[ballot.ID()] = &ballot
+	}
+	return &ballot
+}
+
+// addSecondBallot adds a ballot that is cast by the same ATX as the ref ballot.
+func (n *syncNetwork) addSecondBallot(ref *types.Ballot) *types.Ballot {
+	ballot := types.NewExistingBallot(types.RandomBallotID(), nil, nil, types.InnerBallot{
+		AtxID:      ref.AtxID,
+		RefBallot:  ref.ID(),
+		LayerIndex: ref.LayerIndex.Add(1),
+	})
+	n.ballots[ballot.ID()] = &ballot
+	return &ballot
+}
+
+func (n *syncNetwork) GetPeers() []p2p.Peer {
+	return n.order
+}
+
+func (n *syncNetwork) Request(ctx context.Context, peer p2p.Peer, req []byte, resp func([]byte), failure func(error)) error {
+	server, exist := n.servers[peer]
+	if !exist {
+		return errors.New("peer is not connected")
+	}
+	go func() {
+		data, err := server.HandleSyncRequest(ctx, req)
+		if err != nil {
+			failure(err)
+			return
+		}
+		resp(data)
+	}()
+	return nil
+}
+
+// syncBallots are the ballots fetched by the syncing node from the network.
+type syncBallots struct {
+	network *syncNetwork
+	local   map[types.BallotID]*types.Ballot
+}
+
+func (b *syncBallots) GetBallots(_ context.Context, ids []types.BallotID) error {
+	var err error
+	for _, id := range ids {
+		ballot, exist := b.network.ballots[id]
+		if !exist {
+			err = fmt.Errorf("invalid ballot %s", id)
+			continue
+		}
+		b.local[id] = ballot
+	}
+	return err
+}
+
+func (b *syncBallots) GetBallot(id types.BallotID) (*types.Ballot, error) {
+	ballot, exist := b.local[id]
+	if !exist {
+		return nil, errors.New("ballot not found")
+	}
+	return ballot, nil
+}
+
+func newSyncDriver(tb testing.TB) *ProtocolDriver {
+	return &ProtocolDriver{
+		logger:             logtest.New(tb),
+		config:             UnitTestConfig(),
+		db:                 sql.InMemory(),
+		beacons:            make(map[types.EpochID]types.Beacon),
+		beaconsFromBallots: make(map[types.EpochID]map[types.Beacon]*ballotWeight),
+	}
+}
+
+// newSyncPeer returns a driver that uses own as the beacon for the epoch if it is not empty,
+// and received the ballots with this beacon.
+func newSyncPeer(tb testing.TB, epoch types.EpochID, own types.Beacon, ballots ...*types.Ballot) *ProtocolDriver {
+	pd := newSyncDriver(tb)
+	if own != types.EmptyBeacon {
+		require.NoError(tb, pd.setBeacon(epoch, own))
+	}
+	for _, ballot := range ballots {
+		pd.recordBeacon(epoch, ballot.ID(), own, 0)
+	}
+	return pd
+}
+
+// newSyncNode returns a driver that syncs the beacon from the network.
+func newSyncNode(tb testing.TB, network *syncNetwork, epochWeight uint64) *ProtocolDriver {
+	mockDB := mocks.NewMockactivationDB(gomock.NewController(tb))
+	mockDB.EXPECT().GetEpochWeight(gomock.Any()).Return(epochWeight, nil, nil).AnyTimes()
+	mockDB.EXPECT().GetAtxHeader(gomock.Any()).DoAndReturn(
+		func(id types.ATXID) (*types.ActivationTxHeader, error) {
+			atx, exist := network.atxs[id]
+			if !exist {
+				return nil, errors.New("atx not found")
+			}
+			return atx, nil
+		}).AnyTimes()
+	pd := newSyncDriver(tb)
+	pd.atxDB = mockDB
+	ballots := &syncBallots{network: network, local: map[types.BallotID]*types.Ballot{}}
+	pd.SetBeaconSync(network, network, ballots, ballots)
+	return pd
+}
+
+func TestHandleSyncRequest(t *testing.T) {
+	const epoch = types.EpochID(5)
+	beacon := types.RandomBeacon()
+	pd := newSyncPeer(t, epoch, beacon)
+	ballots := []types.BallotID{types.RandomBallotID(), types.RandomBallotID()}
+	for _, bid := range ballots {
+		pd.recordBeacon(epoch, bid, beacon, 10)
+	}
+	pd.recordBeacon(epoch, types.RandomBallotID(), types.RandomBeacon(), 20)
+
+	req, err := codec.Encode(&SyncRequest{Epoch: epoch})
+	require.NoError(t, err)
+	data, err := pd.HandleSyncRequest(context.TODO(), req)
+	require.NoError(t, err)
+	var resp SyncResponse
+	require.NoError(t, codec.Decode(data, &resp))
+	require.Equal(t, epoch, resp.Epoch)
+	require.Equal(t, beacon, resp.Beacon)
+	require.ElementsMatch(t, ballots, resp.Ballots)
+
+	req, err = codec.Encode(&SyncRequest{Epoch: epoch + 1})
+	require.NoError(t, err)
+	_, err = pd.HandleSyncRequest(context.TODO(), req)
+	require.ErrorIs(t, err, errBeaconNotCalculated)
+
+	_, err = pd.HandleSyncRequest(context.TODO(), []byte{1})
+	require.Error(t, err)
+}
+
+func TestSyncBeacon(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	const epoch = types.EpochID(5)
+	good, bad := types.RandomBeacon(), types.RandomBeacon()
+	type ballot struct {
+		beacon  types.Beacon
+		weight  uint64
+		invalid bool
+	}
+	type peer struct {
+		own     types.Beacon
+		ballots []ballot
+	}
+	for _, tc := range []struct {
+		desc     string
+		peers    []peer
+		expected types.Beacon
+		err      error
+	}{
+		{
+			desc: "agreement",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 70}}},
+				{own: good, ballots: []ballot{{beacon: good, weight: 60}}},
+				{own: bad, ballots: []ballot{{beacon: bad, weight: 20}}},
+			},
+			expected: good,
+		},
+		{
+			desc: "no agreement",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 50}}},
+				{own: bad, ballots: []ballot{{beacon: bad, weight: 50}}},
+			},
+			err: errNoSyncAgreement,
+		},
+		{
+			desc:  "no ballots",
+			peers: []peer{{own: good}, {own: good}},
+			err:   errNoSyncAgreement,
+		},
+		{
+			desc: "peers without beacon",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{}, {},
+			},
+			expected: good,
+		},
+		{
+			desc: "sybil peers with invalid ballots",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{own: bad, ballots: []ballot{{beacon: bad, weight: 100, invalid: true}}},
+				{own: bad, ballots: []ballot{{beacon: bad, weight: 100, invalid: true}}},
+			},
+			expected: good,
+		},
+		{
+			desc: "ballots with other beacon",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{own: bad, ballots: []ballot{{beacon: good, weight: 100}}},
+			},
+			expected: good,
+		},
+		{
+			desc: "more peers than requested",
+			peers: []peer{
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{own: good, ballots: []ballot{{beacon: good, weight: 10}}},
+				{own: bad, ballots: []ballot{{beacon: bad, weight: 100}}},
+			},
+			expected: good,
+		},
+		{
+			desc: "no peers",
+			err:  errNoSyncPeers,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			network := newSyncNetwork()
+			for i, p := range tc.peers {
+				var ballots []*types.Ballot
+				for _, b := range p.ballots {
+					ballots = append(ballots, network.addBallot(epoch, b.beacon, b.weight, b.invalid))
+				}
+				peer := p2p.Peer(string(rune('a' + i)))
+				network.servers[peer] = newSyncPeer(t, epoch, p.own, ballots...)
+				network.order = append(network.order, peer)
+			}
+			if len(network.order) > 0 {
+				// peer that is not connected anymore
+				network.order = append([]p2p.Peer{"disconnected"}, network.order...)
+			}
+			pd := newSyncNode(t, network, 1000)
+			pd.config.BeaconSyncNumPeers = 4
+
+			beacon, err := pd.syncBeacon(context.TODO(), epoch)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				_, err := pd.GetBeacon(epoch)
+				require.ErrorIs(t, err, errBeaconNotCalculated)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, beacon)
+			got, err := pd.GetBeacon(epoch)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSyncWeights(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	const epoch = types.EpochID(5)
+	good, bad := types.RandomBeacon(), types.RandomBeacon()
+	network := newSyncNetwork()
+	first := network.addBallot(epoch, good, math.MaxUint64, false)
+	second := network.addBallot(epoch, good, math.MaxUint64, false)
+	// the ATX is counted once for all of its ballots
+	third := network.addSecondBallot(second)
+	// the ATX that used different beacons is not counted
+	conflicting := network.addBallot(epoch, good, 10, false)
+	equivocation := types.NewExistingBallot(types.RandomBallotID(), nil, nil, types.InnerBallot{
+		AtxID:      conflicting.AtxID,
+		EpochData:  &types.EpochData{Beacon: bad},
+		LayerIndex: conflicting.LayerIndex.Add(1),
+	})
+	network.ballots[equivocation.ID()] = &equivocation
+	// the ballot is from another epoch
+	previous := network.addBallot(epoch-1, bad, 10, false)
+
+	pd := newSyncNode(t, network, 30)
+	weights, total, err := pd.syncWeights(context.TODO(), epoch, map[types.Beacon]map[types.BallotID]struct{}{
+		good: {first.ID(): {}, second.ID(): {}, third.ID(): {}, conflicting.ID(): {}},
+		bad:  {equivocation.ID(): {}, previous.ID(): {}},
+	})
+	require.NoError(t, err)
+	require.Len(t, weights, 1)
+	require.Equal(t, "30", weights[good].String())
+	require.Equal(t, "30", total.String())
+
+	pd = newSyncNode(t, network, 0)
+	_, _, err = pd.syncWeights(context.TODO(), epoch, nil)
+	require.ErrorIs(t, err, errZeroEpochWeight)
+}
+
+func TestMaybeSyncBeacon(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	const epoch = types.EpochID(5)
+	beacon := types.RandomBeacon()
+	network := newSyncNetwork()
+	network.servers["a"] = newSyncPeer(t, epoch, beacon, network.addBallot(epoch, beacon, 10, false))
+	network.order = []p2p.Peer{"a"}
+	pd := newSyncNode(t, network, 1000)
+
+	pd.maybeSyncBeacon(context.TODO(), epoch)
+	require.NoError(t, pd.eg.Wait())
+	got, err := pd.GetBeacon(epoch)
+	require.NoError(t, err)
+	require.Equal(t, beacon, got)
+
+	// the beacon for the first epochs is not computed by the protocol
+	pd.maybeSyncBeacon(context.TODO(), 2)
+	require.NoError(t, pd.eg.Wait())
+	_, err = pd.getPersistedBeacon(2)
+	require.Error(t, err)
+}
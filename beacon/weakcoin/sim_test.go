@@ -0,0 +1,393 @@
+package weakcoin_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/beacon/weakcoin"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+// hashSigner produces deterministic signatures that are uniformly distributed and cheap to verify,
+// so that thousands of rounds can be simulated.
+type hashSigner struct {
+	pub []byte
+}
+
+func (s hashSigner) Sign(msg []byte) []byte {
+	return hashSignature(s.pub, msg)
+}
+
+func (s hashSigner) PublicKey() *signing.PublicKey {
+	return signing.NewPublicKey(s.pub)
+}
+
+func (s hashSigner) LittleEndian() bool {
+	return true
+}
+
+type hashVerifier struct{}
+
+func (hashVerifier) Verify(pub *signing.PublicKey, msg, sig []byte) bool {
+	return bytes.Equal(hashSignature(pub.Bytes(), msg), sig)
+}
+
+func hashSignature(pub, msg []byte) []byte {
+	h := sha512.New()
+	h.Write(pub)
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// simConfig describes the network of weak coin instances that is simulated in lockstep.
+type simConfig struct {
+	// honest and adversarial are the unit allowances of every honest and adversarial node.
+	honest, adversarial []uint64
+	threshold           []byte
+	bufferSize          int
+	rounds              types.RoundID
+	// reach is the fraction of honest nodes that receive the proposals of the adversarial nodes.
+	// the adversary withholds its proposals from the rest of the honest nodes.
+	reach float64
+	// early is the number of honest nodes that start the round before others. their proposals
+	// are delivered before the rest of the honest nodes start the round, and are buffered.
+	early int
+	// flood is the number of invalid proposals for the next round that the adversary sends
+	// to every honest node before the round starts.
+	flood int
+}
+
+func defaultSimConfig() simConfig {
+	return simConfig{
+		honest:     uniformAllowances(16, 1),
+		threshold:  thresholdFraction(1, 2),
+		bufferSize: 100,
+		rounds:     20,
+		reach:      1,
+	}
+}
+
+func uniformAllowances(n int, units uint64) []uint64 {
+	rst := make([]uint64, n)
+	for i := range rst {
+		rst[i] = units
+	}
+	return rst
+}
+
+func sumUnits(allowances []uint64) (total uint64) {
+	for _, units := range allowances {
+		total += units
+	}
+	return total
+}
+
+// thresholdFraction returns the threshold that accepts num/denom of the signatures.
+func thresholdFraction(num, denom int64) []byte {
+	threshold := new(big.Int).Lsh(big.NewInt(1), 256)
+	threshold.Mul(threshold, big.NewInt(num))
+	threshold.Div(threshold, big.NewInt(denom))
+	return threshold.FillBytes(make([]byte, 32))
+}
+
+func (cfg simConfig) thresholdProbability() float64 {
+	value, _ := new(big.Float).SetInt(new(big.Int).SetBytes(cfg.threshold)).Float64()
+	return value / math.Pow(2, 256)
+}
+
+// disagreementBound is the largest expected rate of rounds in which honest nodes disagree on the coin.
+// Honest nodes may disagree only if the smallest proposal below the threshold is not delivered to all
+// of them: it belongs either to the adversary or to the early nodes when their proposals don't fit into
+// the next round buffer. Proposals are uniformly distributed, therefore the smallest one belongs to these
+// nodes with probability proportional to their units, given that there is at least one proposal below
+// the threshold.
+func (cfg simConfig) disagreementBound() float64 {
+	var partial uint64
+	if cfg.reach > 0 && cfg.reach < 1 {
+		partial += sumUnits(cfg.adversarial)
+	}
+	if cfg.flood >= cfg.bufferSize {
+		partial += sumUnits(cfg.honest[:cfg.early])
+	}
+	total := sumUnits(cfg.honest) + sumUnits(cfg.adversarial)
+	anyBelow := 1 - math.Pow(1-cfg.thresholdProbability(), float64(total))
+	return float64(partial) / float64(total) * anyBelow
+}
+
+type simNode struct {
+	coin   *weakcoin.WeakCoin
+	signer hashSigner
+	honest bool
+}
+
+type simMessage struct {
+	from int
+	data []byte
+}
+
+// simNetwork is an in-memory pubsub. Published messages are queued until they are delivered,
+// so that the order of delivery relative to StartRound/FinishRound is controlled by the simulation.
+type simNetwork struct {
+	cfg     simConfig
+	rng     *rand.Rand
+	nodes   []*simNode
+	pending []simMessage
+}
+
+type simPublisher struct {
+	net  *simNetwork
+	from int
+}
+
+func (p simPublisher) Publish(_ context.Context, _ string, data []byte) error {
+	p.net.pending = append(p.net.pending, simMessage{from: p.from, data: data})
+	return nil
+}
+
+func newSimNetwork(cfg simConfig, seed int64) (*simNetwork, weakcoin.UnitAllowances) {
+	net := &simNetwork{cfg: cfg, rng: rand.New(rand.NewSource(seed))}
+	allowances := weakcoin.UnitAllowances{}
+	add := func(units uint64, honest bool) {
+		pub := make([]byte, 32)
+		net.rng.Read(pub)
+		node := &simNode{signer: hashSigner{pub: pub}, honest: honest}
+		node.coin = weakcoin.New(simPublisher{net: net, from: len(net.nodes)}, node.signer, hashVerifier{},
+			weakcoin.WithThreshold(cfg.threshold),
+			weakcoin.WithNextRoundBufferSize(cfg.bufferSize),
+			weakcoin.WithMaxRound(cfg.rounds),
+		)
+		allowances[string(pub)] = units
+		net.nodes = append(net.nodes, node)
+	}
+	for _, units := range cfg.honest {
+		add(units, true)
+	}
+	for _, units := range cfg.adversarial {
+		add(units, false)
+	}
+	return net, allowances
+}
+
+// deliver the pending messages. Messages from honest nodes are delivered to every other honest node,
+// messages from the adversary are delivered only to the reach fraction of the honest nodes.
+func (net *simNetwork) deliver() {
+	pending := net.pending
+	net.pending = nil
+	for _, msg := range pending {
+		receivers := net.honestNodes()
+		if !net.nodes[msg.from].honest {
+			net.rng.Shuffle(len(receivers), func(i, j int) { receivers[i], receivers[j] = receivers[j], receivers[i] })
+			receivers = receivers[:int(math.Round(net.cfg.reach*float64(len(receivers))))]
+		}
+		for _, i := range receivers {
+			if i != msg.from {
+				net.nodes[i].coin.HandleProposal(context.TODO(), "", msg.data)
+			}
+		}
+	}
+}
+
+func (net *simNetwork) honestNodes() []int {
+	var rst []int
+	for i, node := range net.nodes {
+		if node.honest {
+			rst = append(rst, i)
+		}
+	}
+	return rst
+}
+
+// flood sends invalid proposals for the round to every honest node. The signatures pass the threshold
+// check and are buffered, but fail verification when the round starts.
+func (net *simNetwork) flood(epoch types.EpochID, round types.RoundID) {
+	if net.cfg.flood == 0 || len(net.cfg.adversarial) == 0 {
+		return
+	}
+	adversary := net.nodes[len(net.cfg.honest)].signer.pub
+	for n := 0; n < net.cfg.flood; n++ {
+		sig := make([]byte, 64)
+		binary.BigEndian.PutUint64(sig[8:], uint64(n))
+		buf, err := types.InterfaceToBytes(&weakcoin.Message{
+			Epoch:     epoch,
+			Round:     round,
+			MinerPK:   adversary,
+			Signature: sig,
+		})
+		if err != nil {
+			panic(err)
+		}
+		for _, i := range net.honestNodes() {
+			net.nodes[i].coin.HandleProposal(context.TODO(), "", buf)
+		}
+	}
+}
+
+type simResult struct {
+	// coins are the coins of the first honest node in every round.
+	coins []bool
+	// disagreements is the number of rounds in which honest nodes got different coins.
+	disagreements int
+}
+
+func runSim(tb testing.TB, cfg simConfig, seed int64) simResult {
+	tb.Helper()
+	const epoch types.EpochID = 2
+	ctx := context.TODO()
+	net, allowances := newSimNetwork(cfg, seed)
+	for _, node := range net.nodes {
+		node.coin.StartEpoch(ctx, epoch, allowances)
+	}
+	var rst simResult
+	for round := types.RoundID(0); round < cfg.rounds; round++ {
+		net.flood(epoch, round)
+		for i, node := range net.nodes[:len(cfg.honest)] {
+			if i == cfg.early {
+				// proposals from the early nodes arrive before the rest of the nodes start the round
+				net.deliver()
+			}
+			require.NoError(tb, node.coin.StartRound(ctx, round))
+		}
+		net.deliver()
+		for _, node := range net.nodes[len(cfg.honest):] {
+			require.NoError(tb, node.coin.StartRound(ctx, round))
+		}
+		net.deliver()
+		for _, node := range net.nodes {
+			node.coin.FinishRound(ctx)
+		}
+
+		first := net.nodes[0].coin.Get(ctx, epoch, round)
+		rst.coins = append(rst.coins, first)
+		for _, node := range net.nodes[1:len(cfg.honest)] {
+			if node.coin.Get(ctx, epoch, round) != first {
+				rst.disagreements++
+				break
+			}
+		}
+	}
+	for _, node := range net.nodes {
+		node.coin.FinishEpoch(ctx, epoch)
+	}
+	return rst
+}
+
+func TestWeakCoinSimDeterministic(t *testing.T) {
+	cfg := defaultSimConfig()
+	cfg.adversarial = uniformAllowances(4, 1)
+	cfg.reach = 0.5
+	first := runSim(t, cfg, 101)
+	require.Equal(t, first, runSim(t, cfg, 101))
+	require.NotEqual(t, first.coins, runSim(t, cfg, 102).coins)
+}
+
+func TestWeakCoinSimDisagreement(t *testing.T) {
+	const seeds = 50
+	for _, tc := range []struct {
+		desc string
+		cfg  func(simConfig) simConfig
+		// agree is true if honest nodes must never disagree.
+		agree bool
+	}{
+		{
+			desc:  "honest",
+			cfg:   func(cfg simConfig) simConfig { return cfg },
+			agree: true,
+		},
+		{
+			desc: "withholding from all",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = uniformAllowances(4, 1)
+				cfg.reach = 0
+				return cfg
+			},
+			agree: true,
+		},
+		{
+			desc: "withholding from half",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = uniformAllowances(4, 1)
+				cfg.reach = 0.5
+				return cfg
+			},
+		},
+		{
+			desc: "withholding with skewed allowances",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = []uint64{16}
+				cfg.reach = 0.5
+				return cfg
+			},
+		},
+		{
+			desc: "withholding against skewed honest allowances",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.honest = append([]uint64{20, 10}, uniformAllowances(14, 1)...)
+				cfg.adversarial = uniformAllowances(4, 1)
+				cfg.reach = 0.5
+				return cfg
+			},
+		},
+		{
+			desc: "withholding with low threshold",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = uniformAllowances(4, 1)
+				cfg.reach = 0.5
+				cfg.threshold = thresholdFraction(1, 64)
+				return cfg
+			},
+		},
+		{
+			desc: "early proposals fit into buffer",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = uniformAllowances(1, 1)
+				cfg.bufferSize = 10
+				cfg.early = 4
+				cfg.flood = cfg.bufferSize - cfg.early
+				return cfg
+			},
+			agree: true,
+		},
+		{
+			desc: "early proposals past buffer",
+			cfg: func(cfg simConfig) simConfig {
+				cfg.adversarial = uniformAllowances(1, 1)
+				cfg.bufferSize = 10
+				cfg.early = 4
+				cfg.flood = cfg.bufferSize
+				return cfg
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			cfg := tc.cfg(defaultSimConfig())
+			disagreements := 0
+			for seed := int64(0); seed < seeds; seed++ {
+				disagreements += runSim(t, cfg, seed).disagreements
+			}
+			samples := float64(seeds * int(cfg.rounds))
+			rate := float64(disagreements) / samples
+			bound := cfg.disagreementBound()
+			t.Logf("disagreement rate %.4f, bound %.4f", rate, bound)
+			if tc.agree {
+				require.Zero(t, bound)
+				require.Zero(t, disagreements)
+				return
+			}
+			require.Positive(t, disagreements, "adversary is expected to cause disagreement")
+			// three standard deviations above the bound
+			slack := 3 * math.Sqrt(bound*(1-bound)/samples)
+			require.LessOrEqual(t, rate, bound+slack)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/timesync"
 )
 
@@ -241,6 +242,8 @@ func (app *HareApp) Start(cmd *cobra.Command, args []string) {
 		uint16(app.Config.LayersPerEpoch),
 		&mockIDProvider{},
 		&mockStateQuerier{},
+		nil,
+		sql.InMemory(),
 		mockClock,
 		logger)
 	log.Info("starting hare service")
@@ -813,7 +813,7 @@ func TestShutdown(t *testing.T) {
 	clock := timesync.NewClock(timesync.RealClock{}, 20*time.Second, genesisTime, logtest.New(t))
 	r.NoError(smApp.initServices(context.TODO(), nodeID, t.TempDir(), edSgn, false,
 		hareOracle, uint32(smApp.Config.LayerAvgSize),
-		poetHarness.HTTPPoetClient, vrfSigner, smApp.Config.LayersPerEpoch, clock))
+		[]activation.PoetProvingServiceClient{poetHarness.HTTPPoetClient}, vrfSigner, smApp.Config.LayersPerEpoch, clock))
 	r.NoError(smApp.startServices(context.TODO()))
 	ActivateGrpcServer(smApp)
 
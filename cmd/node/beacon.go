@@ -0,0 +1,118 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/spacemeshos/go-spacemesh/beacon"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/common/util"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+)
+
+// BeaconCmd groups tools for the beacon protocol.
+var BeaconCmd = &cobra.Command{
+	Use:   "beacon",
+	Short: "beacon tools",
+}
+
+// AuditCmd recomputes the beacon from the protocol record persisted by the node.
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "recompute the beacon from the recorded votes and report the round in which each proposal was decided",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		types.SetLayersPerEpoch(conf.LayersPerEpoch)
+
+		flags := cmd.Flags()
+		epoch, _ := flags.GetUint32("epoch")
+		path, _ := flags.GetString("db")
+		if len(path) == 0 {
+			path = dbPath(conf.DataDir())
+		}
+		logger := log.NewDefault("audit")
+		if err := auditBeacon(cmd.OutOrStdout(), path, types.EpochID(epoch), logger); err != nil {
+			log.With().Fatal("beacon audit failed", log.Err(err))
+		}
+	},
+}
+
+func init() {
+	AuditCmd.Flags().String("db", "", "path to the node database. defaults to the database in the node data directory")
+	AuditCmd.Flags().Uint32("epoch", 0, "epoch in which the beacon protocol was executed. the beacon is used in the next epoch")
+	_ = AuditCmd.MarkFlagRequired("epoch")
+	BeaconCmd.AddCommand(AuditCmd)
+	Cmd.AddCommand(BeaconCmd)
+}
+
+// auditBeacon loads the record of the beacon protocol executed in the epoch, recomputes the beacon
+// and compares it with the recorded beacon and with the beacon persisted for the next epoch.
+//
+// Database is not modified.
+func auditBeacon(w io.Writer, path string, epoch types.EpochID, logger log.Log) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("database %s: %w", path, err)
+	}
+	db, err := sql.Open("file:"+path, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := checkSchemaVersion(db); err != nil {
+		return err
+	}
+
+	record, err := beacon.LoadRecord(db, epoch)
+	if errors.Is(err, sql.ErrNotFound) {
+		return fmt.Errorf("beacon protocol wasn't recorded in epoch %s", epoch)
+	} else if err != nil {
+		return err
+	}
+	result, err := beacon.Audit(logger, record)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "epoch %s: weight %d, theta %s, %d rounds, %d valid and %d potentially valid proposals, %d votes\n",
+		epoch, record.EpochWeight, record.Theta, record.Rounds,
+		len(record.ValidProposals), len(record.PotentiallyValidProposals), len(record.Votes))
+	for _, p := range result.Proposals {
+		vote := "against"
+		if p.Support {
+			vote = "support"
+		}
+		if p.Coinflip {
+			fmt.Fprintf(w, "  proposal %s: %s by weak coin in round %d\n", util.Bytes2Hex(p.Proposal), vote, p.Round)
+		} else {
+			fmt.Fprintf(w, "  proposal %s: %s since round %d\n", util.Bytes2Hex(p.Proposal), vote, p.Round)
+		}
+	}
+	fmt.Fprintf(w, "recomputed beacon %s, recorded %s (%s)\n",
+		result.Beacon, record.Beacon, matchString(result.Beacon == record.Beacon))
+
+	persisted, err := beacons.Get(db, epoch+1)
+	if errors.Is(err, sql.ErrNotFound) {
+		fmt.Fprintf(w, "beacon for epoch %s is not persisted\n", epoch+1)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "beacon for epoch %s is %s (%s)\n", epoch+1, persisted, matchString(result.Beacon == persisted))
+	return nil
+}
+
+func matchString(match bool) string {
+	if match {
+		return "match"
+	}
+	return "mismatch"
+}
@@ -0,0 +1,55 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/beacon"
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/common/util"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+)
+
+func TestAuditBeacon(t *testing.T) {
+	const epoch = types.EpochID(3)
+	proposal := []byte("0x01")
+	record := &beacon.EpochRecord{
+		Epoch:          epoch,
+		EpochWeight:    10,
+		Theta:          "1/4",
+		Rounds:         2,
+		VotesLimit:     100,
+		ValidProposals: [][]byte{proposal},
+		Votes: []beacon.VoteRecord{
+			{Miner: []byte("a"), Weight: 10, Support: [][]byte{proposal}},
+		},
+		Coins:   []beacon.CoinRecord{{Round: 1, Value: false}},
+		Support: [][]byte{proposal},
+	}
+	rst, err := beacon.Audit(logtest.New(t), record)
+	require.NoError(t, err)
+	record.Beacon = rst.Beacon
+
+	path := dbPath(t.TempDir())
+	db, err := sql.Open("file:" + path)
+	require.NoError(t, err)
+	buf, err := codec.Encode(record)
+	require.NoError(t, err)
+	require.NoError(t, beacons.SetRecord(db, epoch, buf))
+	require.NoError(t, beacons.Add(db, epoch+1, rst.Beacon))
+	require.NoError(t, db.Close())
+
+	var out bytes.Buffer
+	require.NoError(t, auditBeacon(&out, path, epoch, logtest.New(t)))
+	require.Contains(t, out.String(), fmt.Sprintf("proposal %s: support since round 0", util.Bytes2Hex(proposal)))
+	require.Contains(t, out.String(), fmt.Sprintf("recomputed beacon %s, recorded %s (match)", rst.Beacon, rst.Beacon))
+	require.Contains(t, out.String(), fmt.Sprintf("beacon for epoch %s is %s (match)", epoch+1, rst.Beacon))
+
+	require.Error(t, auditBeacon(&bytes.Buffer{}, path, epoch+1, logtest.New(t)))
+}
@@ -0,0 +1,389 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cmdp "github.com/spacemeshos/go-spacemesh/cmd"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/rewards"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// DBCmd groups maintenance commands for the node database.
+var DBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "database maintenance",
+}
+
+// MigrateCmd reports schema migrations status or migrates database to the requested version.
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "show migrations status or migrate database schema to a specific version",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		status, _ := cmd.Flags().GetBool("status")
+		to, _ := cmd.Flags().GetInt("to")
+		if !status && !cmd.Flags().Changed("to") {
+			log.With().Fatal("either --status or --to must be provided")
+		}
+		if err := migrateDB(cmd.OutOrStdout(), dbPath(conf.DataDir()), status, to); err != nil {
+			log.With().Fatal("failed to migrate database", log.Err(err))
+		}
+	},
+}
+
+// BackupCmd writes a consistent snapshot of the node database. It is safe to run while node is running.
+var BackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "write a consistent snapshot of the database, node may keep running",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		to, _ := cmd.Flags().GetString("to")
+		if len(to) == 0 {
+			log.With().Fatal("--to must be provided")
+		}
+		if err := backupDB(cmdp.Ctx(), dbPath(conf.DataDir()), to); err != nil {
+			log.With().Fatal("failed to backup database", log.Err(err))
+		}
+	},
+}
+
+// RestoreCmd validates a snapshot and replaces the node database with it. Node must be stopped.
+var RestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "validate a snapshot and replace the database with it, node must be stopped",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		from, _ := cmd.Flags().GetString("from")
+		if len(from) == 0 {
+			log.With().Fatal("--from must be provided")
+		}
+		if err := restoreDB(cmdp.Ctx(), from, dbPath(conf.DataDir())); err != nil {
+			log.With().Fatal("failed to restore database", log.Err(err))
+		}
+	},
+}
+
+// VerifyCmd recomputes layer hashes, aggregated hashes and state roots and reports the first diverging layer.
+var VerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "recompute layers hashes and state roots and report the first diverging layer",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		types.SetLayersPerEpoch(conf.LayersPerEpoch)
+		logger := log.NewDefault("verify")
+		if err := verifyDB(cmd.OutOrStdout(), dbPath(conf.DataDir()), conf.Genesis, logger); err != nil {
+			log.With().Fatal("database verification failed", log.Err(err))
+		}
+	},
+}
+
+func init() {
+	MigrateCmd.Flags().Bool("status", false, "print applied and pending migrations")
+	MigrateCmd.Flags().Int("to", 0, "migrate database schema up or down to the version")
+	BackupCmd.Flags().String("to", "", "path to the snapshot")
+	RestoreCmd.Flags().String("from", "", "path to the snapshot")
+	DBCmd.AddCommand(MigrateCmd, BackupCmd, RestoreCmd, VerifyCmd)
+	Cmd.AddCommand(DBCmd)
+}
+
+func dbPath(dataDir string) string {
+	return filepath.Join(dataDir, "state.sql")
+}
+
+func migrateDB(w io.Writer, path string, status bool, to int) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("database %s: %w", path, err)
+	}
+	// migrations are applied explicitly below
+	db, err := sql.Open("file:"+path, sql.WithMigrations(nil))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if !status {
+		if err := migrateTo(db, to); err != nil {
+			return err
+		}
+	}
+	return printMigrationsStatus(w, db)
+}
+
+func migrateTo(db *sql.Database, to int) error {
+	tx, err := db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	if err := sql.MigrateTo(tx, to); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migrations: %w", err)
+	}
+	return nil
+}
+
+func printMigrationsStatus(w io.Writer, db sql.Executor) error {
+	version, err := sql.Version(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := sql.MigrationsStatus(db)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "schema version: %d\n", version)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, m := range migrations {
+		state, appliedAt := "pending", ""
+		if m.Applied {
+			state = "applied"
+			if !m.AppliedAt.IsZero() {
+				appliedAt = m.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			if m.Checksum != nil && !bytes.Equal(m.Checksum, m.Migration.Checksum()) {
+				state = "checksum mismatch"
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", m.Order, m.Name, state, appliedAt)
+	}
+	return tw.Flush()
+}
+
+func backupDB(ctx context.Context, path, to string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("database %s: %w", path, err)
+	}
+	db, err := sql.Open("file:"+path, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.Backup(ctx, to); err != nil {
+		return fmt.Errorf("backup to %s: %w", to, err)
+	}
+	return nil
+}
+
+// restoreDB copies snapshot next to the database at path, validates the copy and swaps it in place of the database.
+// Previous database is kept next to it with a .bak suffix.
+func restoreDB(ctx context.Context, from, path string) error {
+	if _, err := os.Stat(from); err != nil {
+		return fmt.Errorf("snapshot %s: %w", from, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	restored := path + ".restore"
+	removeDBFiles(restored)
+	if err := copySnapshot(ctx, from, restored); err != nil {
+		removeDBFiles(restored)
+		return err
+	}
+	if err := swapDB(restored, path); err != nil {
+		removeDBFiles(restored)
+		return err
+	}
+	return nil
+}
+
+var dbFileSuffixes = []string{"", "-wal", "-shm"}
+
+func removeDBFiles(path string) {
+	for _, suffix := range dbFileSuffixes {
+		_ = os.Remove(path + suffix)
+	}
+}
+
+// copySnapshot copies snapshot to the path and validates the copy.
+func copySnapshot(ctx context.Context, from, to string) error {
+	snapshot, err := sql.Open("file:"+from, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+	if err := snapshot.Backup(ctx, to); err != nil {
+		return fmt.Errorf("copy snapshot to %s: %w", to, err)
+	}
+	restored, err := sql.Open("file:"+to, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer restored.Close()
+	if err := validateSnapshot(restored); err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", from, err)
+	}
+	return nil
+}
+
+// swapDB moves the database at path aside and moves the restored database in its place.
+// If any step fails the previous database is moved back.
+func swapDB(restored, path string) error {
+	var moved []string
+	rollback := func() {
+		for _, suffix := range moved {
+			_ = os.Rename(path+".bak"+suffix, path+suffix)
+		}
+	}
+	removeDBFiles(path + ".bak")
+	for _, suffix := range dbFileSuffixes {
+		err := os.Rename(path+suffix, path+".bak"+suffix)
+		if err == nil {
+			moved = append(moved, suffix)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			rollback()
+			return fmt.Errorf("move %s aside: %w", path+suffix, err)
+		}
+	}
+	for _, suffix := range dbFileSuffixes {
+		if err := os.Rename(restored+suffix, path+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			removeDBFiles(path)
+			rollback()
+			return fmt.Errorf("move restored database to %s: %w", path+suffix, err)
+		}
+	}
+	return nil
+}
+
+// validateSnapshot checks that snapshot is not corrupted, that its schema is supported,
+// that mesh_status is consistent and that layers hashes don't have gaps and match valid blocks.
+func validateSnapshot(db sql.Executor) error {
+	var integrity []string
+	if _, err := db.Exec("PRAGMA integrity_check;", nil, func(stmt *sql.Statement) bool {
+		integrity = append(integrity, stmt.ColumnText(0))
+		return true
+	}); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if len(integrity) != 1 || integrity[0] != "ok" {
+		return fmt.Errorf("integrity check failed: %v", integrity)
+	}
+
+	version, err := sql.Version(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := sql.LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return errors.New("schema is empty")
+	}
+	if supported := migrations[len(migrations)-1].Order; version > supported {
+		return fmt.Errorf("%w: snapshot version %d, supported %d", sql.ErrSchemaTooNew, version, supported)
+	}
+
+	latest, err := layers.GetByStatus(db, layers.Latest)
+	if err != nil {
+		return err
+	}
+	for _, status := range []layers.Status{layers.Processed, layers.Applied} {
+		lid, err := layers.GetByStatus(db, status)
+		if err != nil {
+			return err
+		}
+		if lid.After(latest) {
+			return fmt.Errorf("%s layer %s is after latest layer %s", status, lid, latest)
+		}
+	}
+
+	from, to, complete, err := layers.GetAggregatedHashRange(db)
+	if errors.Is(err, sql.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if to.After(latest) {
+		return fmt.Errorf("layer %s with aggregated hash is after latest layer %s", to, latest)
+	}
+	if expected := int(to.Difference(from)) + 1; complete != expected {
+		return fmt.Errorf("layers hashes between %s and %s are incomplete: %d out of %d", from, to, complete, expected)
+	}
+	applied, err := layers.GetByStatus(db, layers.Applied)
+	if err != nil {
+		return err
+	}
+	v := &verifier{db: db, applied: applied, hashesFrom: from, hashesTo: to}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		valid, reason, err := v.validBlocks(lid)
+		if err == nil && len(reason) == 0 {
+			reason, err = v.verifyHashes(lid, valid)
+		}
+		if err != nil {
+			return fmt.Errorf("verify layer %s: %w", lid, err)
+		}
+		if len(reason) > 0 {
+			return fmt.Errorf("%w at layer %s: %s", errDiverged, lid, reason)
+		}
+	}
+	return nil
+}
+
+// fillTransactionsAmounts indexes amount and fee of transactions stored before they were indexed.
+func fillTransactionsAmounts(db *sql.Database, logger log.Log) error {
+	tx, err := db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	n, err := transactions.FillAmounts(tx)
+	if err != nil {
+		return fmt.Errorf("fill transactions amounts: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transactions amounts: %w", err)
+	}
+	if n > 0 {
+		logger.With().Info("filled amounts for stored transactions", log.Int("count", n))
+	}
+	return nil
+}
+
+// fillRewardsTotals computes epoch and coinbase totals for rewards stored before totals were maintained.
+func fillRewardsTotals(db *sql.Database, logger log.Log) error {
+	tx, err := db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	n, err := rewards.FillTotals(tx)
+	if err != nil {
+		return fmt.Errorf("fill rewards totals: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rewards totals: %w", err)
+	}
+	if n > 0 {
+		logger.With().Info("filled totals for stored rewards", log.Int("count", n))
+	}
+	return nil
+}
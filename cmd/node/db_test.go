@@ -0,0 +1,265 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/api/config"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/database"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/svm"
+	"github.com/spacemeshos/go-spacemesh/svm/transaction"
+)
+
+func TestMigrateDB(t *testing.T) {
+	path := dbPath(t.TempDir())
+	require.Error(t, migrateDB(&bytes.Buffer{}, path, true, 0))
+
+	db, err := sql.Open("file:" + path)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	var out bytes.Buffer
+	require.NoError(t, migrateDB(&out, path, true, 0))
+	require.Contains(t, out.String(), "applied")
+	require.NotContains(t, out.String(), "pending")
+
+	out.Reset()
+	require.NoError(t, migrateDB(&out, path, false, 1))
+	require.Contains(t, out.String(), "schema version: 1")
+	require.Contains(t, out.String(), "pending")
+
+	db, err = sql.Open("file:"+path, sql.WithMigrations(nil))
+	require.NoError(t, err)
+	version, err := sql.Version(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	require.NoError(t, db.Close())
+
+}
+
+func newSnapshot(tb testing.TB, path string, latest types.LayerID, hashed int) {
+	tb.Helper()
+	db, err := sql.Open("file:" + path)
+	require.NoError(tb, err)
+	defer db.Close()
+	require.NoError(tb, layers.SetStatus(db, latest, layers.Latest))
+	require.NoError(tb, layers.SetStatus(db, latest, layers.Processed))
+	aggHash := types.EmptyLayerHash
+	for i := 1; i <= hashed; i++ {
+		lid := types.NewLayerID(uint32(i))
+		aggHash = types.CalcBlocksHash32(nil, aggHash.Bytes())
+		require.NoError(tb, layers.SetHash(db, lid, types.EmptyLayerHash))
+		require.NoError(tb, layers.SetAggregatedHash(db, lid, aggHash))
+	}
+}
+
+func TestBackupRestoreDB(t *testing.T) {
+	dir := t.TempDir()
+	path := dbPath(filepath.Join(dir, "node"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	newSnapshot(t, path, types.NewLayerID(10), 0)
+
+	source := filepath.Join(dir, "source.sql")
+	newSnapshot(t, source, types.NewLayerID(20), 10)
+	snapshot := filepath.Join(dir, "snapshot.sql")
+	require.NoError(t, backupDB(context.Background(), source, snapshot))
+
+	require.NoError(t, restoreDB(context.Background(), snapshot, path))
+	require.FileExists(t, path+".bak")
+
+	db, err := sql.Open("file:" + path)
+	require.NoError(t, err)
+	defer db.Close()
+	latest, err := layers.GetByStatus(db, layers.Latest)
+	require.NoError(t, err)
+	require.Equal(t, types.NewLayerID(20), latest)
+}
+
+func TestRestoreDBInvalidSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := dbPath(filepath.Join(dir, "node"))
+
+	t.Run("hashes after latest", func(t *testing.T) {
+		snapshot := filepath.Join(t.TempDir(), "snapshot.sql")
+		newSnapshot(t, snapshot, types.NewLayerID(5), 10)
+		require.Error(t, restoreDB(context.Background(), snapshot, path))
+		require.NoFileExists(t, path)
+	})
+	t.Run("missing hashes", func(t *testing.T) {
+		snapshot := filepath.Join(t.TempDir(), "snapshot.sql")
+		newSnapshot(t, snapshot, types.NewLayerID(20), 10)
+		db, err := sql.Open("file:" + snapshot)
+		require.NoError(t, err)
+		_, err = db.Exec("update layers set hash = null where id = 5;", nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		require.Error(t, restoreDB(context.Background(), snapshot, path))
+		require.NoFileExists(t, path)
+	})
+	t.Run("hash mismatch", func(t *testing.T) {
+		snapshot := filepath.Join(t.TempDir(), "snapshot.sql")
+		newVerifiableDB(t, snapshot, config.DefaultGenesisConfig(), 6)
+		db, err := sql.Open("file:" + snapshot)
+		require.NoError(t, err)
+		_, err = db.Exec("update blocks set validity = -1 where layer = 4;", nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		require.ErrorIs(t, restoreDB(context.Background(), snapshot, path), errDiverged)
+		require.NoFileExists(t, path)
+	})
+	t.Run("processed after latest", func(t *testing.T) {
+		snapshot := filepath.Join(t.TempDir(), "snapshot.sql")
+		newSnapshot(t, snapshot, types.NewLayerID(20), 0)
+		db, err := sql.Open("file:" + snapshot)
+		require.NoError(t, err)
+		require.NoError(t, layers.SetStatus(db, types.NewLayerID(21), layers.Processed))
+		require.NoError(t, db.Close())
+		require.Error(t, restoreDB(context.Background(), snapshot, path))
+		require.NoFileExists(t, path)
+	})
+	t.Run("missing snapshot", func(t *testing.T) {
+		require.Error(t, restoreDB(context.Background(), filepath.Join(t.TempDir(), "missing.sql"), path))
+	})
+}
+
+func TestRestoreDBKeepsDatabaseOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := dbPath(filepath.Join(dir, "node"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	newSnapshot(t, path, types.NewLayerID(10), 0)
+
+	snapshot := filepath.Join(dir, "snapshot.sql")
+	newSnapshot(t, snapshot, types.NewLayerID(5), 10)
+	require.Error(t, restoreDB(context.Background(), snapshot, path))
+	require.NoFileExists(t, path+".bak")
+	require.NoFileExists(t, path+".restore")
+
+	db, err := sql.Open("file:" + path)
+	require.NoError(t, err)
+	defer db.Close()
+	latest, err := layers.GetByStatus(db, layers.Latest)
+	require.NoError(t, err)
+	require.Equal(t, types.NewLayerID(10), latest)
+}
+
+// newVerifiableDB writes layers with a single valid block in the same way as mesh applies them.
+func newVerifiableDB(tb testing.TB, path string, genesis *config.GenesisConfig, n int) {
+	tb.Helper()
+	db, err := sql.Open("file:" + path)
+	require.NoError(tb, err)
+	defer db.Close()
+
+	state := svm.New(database.NewMemDatabase(), sql.InMemory(), nil, nil, logtest.New(tb))
+	require.NoError(tb, state.SetupGenesis(genesis))
+	signer := signing.NewEdSigner()
+	aggHash := types.EmptyLayerHash
+	for i := 1; i <= n; i++ {
+		lid := types.NewLayerID(uint32(i))
+		tx, err := transaction.GenerateCallTransaction(signer, types.Address{1}, uint64(i), 10, 1, 1)
+		require.NoError(tb, err)
+		require.NoError(tb, transactions.Add(db, lid, types.EmptyBlockID, tx))
+		block := types.NewExistingBlock(types.BlockID{byte(i)}, types.InnerBlock{
+			LayerIndex: lid,
+			TxIDs:      []types.TransactionID{tx.ID()},
+			Rewards:    []types.AnyReward{{Address: types.Address{2}, Amount: 100, LayerReward: 90}},
+		})
+		require.NoError(tb, blocks.Add(db, block))
+		require.NoError(tb, blocks.SetValid(db, block.ID()))
+		require.NoError(tb, layers.SetHareOutput(db, lid, block.ID()))
+
+		valid := []types.BlockID{block.ID()}
+		require.NoError(tb, layers.SetHash(db, lid, types.CalcBlocksHash32(valid, nil)))
+		aggHash = types.CalcBlocksHash32(valid, aggHash.Bytes())
+		require.NoError(tb, layers.SetAggregatedHash(db, lid, aggHash))
+
+		_, err = state.ApplyLayer(lid, []*types.Transaction{tx}, map[types.Address]uint64{{2}: 100})
+		require.NoError(tb, err)
+		root, err := state.GetLayerStateRoot(lid)
+		require.NoError(tb, err)
+		require.NoError(tb, layers.SetStateHash(db, lid, root))
+		require.NoError(tb, layers.SetApplied(db, lid, block.ID()))
+	}
+	for _, status := range []layers.Status{layers.Latest, layers.Processed, layers.Applied} {
+		require.NoError(tb, layers.SetStatus(db, types.NewLayerID(uint32(n)), status))
+	}
+}
+
+func TestVerifyDB(t *testing.T) {
+	genesis := config.DefaultGenesisConfig()
+	for _, tc := range []struct {
+		desc     string
+		tamper   string
+		diverged string
+	}{
+		{desc: "consistent"},
+		{
+			desc:     "aggregated hash",
+			tamper:   "update layers set aggregated_hash = x'01' where id = 3;",
+			diverged: "layer 3 diverged: aggregated hash",
+		},
+		{
+			desc:     "validity",
+			tamper:   "update blocks set validity = -1 where layer = 4;",
+			diverged: "layer 4 diverged: layer hash",
+		},
+		{
+			desc:     "hare output",
+			tamper:   "update layers set hare_output = x'ff' where id = 2;",
+			diverged: "layer 2 diverged: hare output",
+		},
+		{
+			desc:     "state root",
+			tamper:   "update layers set state_hash = x'01' where id = 5;",
+			diverged: "layer 5 diverged: state root",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			path := dbPath(t.TempDir())
+			newVerifiableDB(t, path, genesis, 6)
+			if len(tc.tamper) > 0 {
+				db, err := sql.Open("file:" + path)
+				require.NoError(t, err)
+				_, err = db.Exec(tc.tamper, nil, nil)
+				require.NoError(t, err)
+				require.NoError(t, db.Close())
+			}
+
+			var out bytes.Buffer
+			err := verifyDB(&out, path, genesis, logtest.New(t))
+			if len(tc.diverged) == 0 {
+				require.NoError(t, err)
+				require.Contains(t, out.String(), "verified layers from 1 to 6")
+				return
+			}
+			require.ErrorIs(t, err, errDiverged)
+			require.Contains(t, out.String(), tc.diverged)
+		})
+	}
+	t.Run("pruned", func(t *testing.T) {
+		path := dbPath(t.TempDir())
+		newVerifiableDB(t, path, genesis, 6)
+		db, err := sql.Open("file:" + path)
+		require.NoError(t, err)
+		_, err = blocks.Prune(db, types.NewLayerID(3))
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		var out bytes.Buffer
+		require.NoError(t, verifyDB(&out, path, genesis, logtest.New(t)))
+		require.Contains(t, out.String(), "verified layers from 1 to 6")
+		require.Contains(t, out.String(), "state roots verified up to layer 0")
+	})
+}
@@ -247,7 +247,7 @@ func InitSingleInstance(lg log.Log, cfg config.Config, i int, genesisTime string
 	hareOracle.Register(true, pub.String())
 
 	err = smApp.initServices(context.TODO(), nodeID, dbStorepath, edSgn, false, hareOracle,
-		uint32(smApp.Config.LayerAvgSize), poetClient, vrfSigner, smApp.Config.LayersPerEpoch, clock)
+		uint32(smApp.Config.LayerAvgSize), []activation.PoetProvingServiceClient{poetClient}, vrfSigner, smApp.Config.LayersPerEpoch, clock)
 	if err != nil {
 		return nil, err
 	}
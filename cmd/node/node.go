@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/spacemeshos/go-spacemesh/activation"
+	"github.com/spacemeshos/go-spacemesh/api"
 	"github.com/spacemeshos/go-spacemesh/api/grpcserver"
 	"github.com/spacemeshos/go-spacemesh/beacon"
 	"github.com/spacemeshos/go-spacemesh/beacon/weakcoin"
@@ -42,16 +43,19 @@ import (
 	"github.com/spacemeshos/go-spacemesh/layerpatrol"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/log/errcode"
+	"github.com/spacemeshos/go-spacemesh/malfeasance"
 	"github.com/spacemeshos/go-spacemesh/mempool"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/metrics"
 	"github.com/spacemeshos/go-spacemesh/miner"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/p2p/server"
 	"github.com/spacemeshos/go-spacemesh/pendingtxs"
 	"github.com/spacemeshos/go-spacemesh/proposals"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/ldbimport"
 	"github.com/spacemeshos/go-spacemesh/svm"
 	"github.com/spacemeshos/go-spacemesh/syncer"
 	"github.com/spacemeshos/go-spacemesh/system"
@@ -89,6 +93,7 @@ const (
 	ProposalListenerLogger = "proposalListener"
 	ProposalDBLogger       = "proposalStore"
 	PoetListenerLogger     = "poetListener"
+	MalfeasanceLogger      = "malfeasance"
 	NipostBuilderLogger    = "nipostBuilder"
 	LayerFetcher           = "layerFetcher"
 	TimeSyncLogger         = "timesync"
@@ -465,7 +470,7 @@ func (app *App) initServices(ctx context.Context,
 	isFixedOracle bool,
 	rolacle hare.Rolacle,
 	layerSize uint32,
-	poetClient activation.PoetProvingServiceClient,
+	poetClients []activation.PoetProvingServiceClient,
 	vrfSigner *signing.VRFSigner,
 	layersPerEpoch uint32, clock TickProvider) error {
 	app.nodeID = nodeID
@@ -481,42 +486,33 @@ func (app *App) initServices(ctx context.Context,
 	}
 	app.closers = append(app.closers, stateDBStore)
 
-	atxDBStore, err := database.NewLDBDatabase(filepath.Join(dbStorepath, "atx"), 0, 0, app.addLogger(AtxDbStoreLogger, lg))
-	if err != nil {
-		return fmt.Errorf("create ATX DB: %w", err)
-	}
-	app.closers = append(app.closers, atxDBStore)
-
-	poetDBStore, err := database.NewLDBDatabase(filepath.Join(dbStorepath, "poet"), 0, 0, app.addLogger(PoetDbStoreLogger, lg))
-	if err != nil {
-		return fmt.Errorf("create PoET DB: %w", err)
-	}
-	app.closers = append(app.closers, poetDBStore)
-
-	idDBStore, err := database.NewLDBDatabase(filepath.Join(dbStorepath, "ids"), 0, 0, app.addLogger(StateDbLogger, lg))
-	if err != nil {
-		return fmt.Errorf("create IDs DB: %w", err)
-	}
-	app.closers = append(app.closers, idDBStore)
-
 	store, err := database.NewLDBDatabase(filepath.Join(dbStorepath, "store"), 0, 0, app.addLogger(StoreLogger, lg))
 	if err != nil {
 		return fmt.Errorf("create store DB: %w", err)
 	}
 	app.closers = append(app.closers, store)
 
-	idStore := activation.NewIdentityStore(idDBStore)
-	poetDb := activation.NewPoetDb(poetDBStore, app.addLogger(PoetDbLogger, lg))
-	validator := activation.NewValidator(poetDb, app.Config.POST)
-
 	if err := os.MkdirAll(dbStorepath, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create %s: %w", dbStorepath, err)
 	}
 
-	sqlDB, err := sql.Open("file:" + filepath.Join(dbStorepath, "state.sql"))
+	sqlDB, err := sql.Open("file:" + dbPath(dbStorepath))
 	if err != nil {
 		return fmt.Errorf("open sqlite db %w", err)
 	}
+	if err := ldbimport.Import(dbStorepath, sqlDB, app.addLogger(StateDbLogger, lg)); err != nil {
+		return fmt.Errorf("import legacy stores: %w", err)
+	}
+	if err := fillTransactionsAmounts(sqlDB, lg); err != nil {
+		return err
+	}
+	if err := fillRewardsTotals(sqlDB, lg); err != nil {
+		return err
+	}
+
+	idStore := activation.NewIdentityStore(sqlDB)
+	poetDb := activation.NewPoetDb(sqlDB, app.addLogger(PoetDbLogger, lg))
+	validator := activation.NewValidator(poetDb, app.Config.POST)
 
 	mdb, err := mesh.NewPersistentMeshDB(sqlDB, app.Config.BlockCacheSize, app.addLogger(MeshDBLogger, lg))
 	if err != nil {
@@ -526,12 +522,7 @@ func (app *App) initServices(ctx context.Context,
 	app.txPool = mempool.NewTxMemPool()
 	meshAndPoolProjector := pendingtxs.NewMeshAndPoolProjector(mdb, app.txPool)
 
-	appliedTxs, err := database.NewLDBDatabase(filepath.Join(dbStorepath, "appliedTxs"), 0, 0, lg.WithName("appliedTxs"))
-	if err != nil {
-		return fmt.Errorf("create applied txs DB: %w", err)
-	}
-	app.closers = append(app.closers, appliedTxs)
-	state := svm.New(stateDBStore, appliedTxs, meshAndPoolProjector, app.txPool, app.addLogger(SVMLogger, lg))
+	state := svm.New(stateDBStore, sqlDB, meshAndPoolProjector, app.txPool, app.addLogger(SVMLogger, lg))
 
 	goldenATXID := types.ATXID(types.HexToHash32(app.Config.GoldenATXID))
 	if goldenATXID == *types.EmptyATXID {
@@ -539,7 +530,7 @@ func (app *App) initServices(ctx context.Context,
 	}
 
 	fetcherWrapped := &layerFetcher{}
-	atxDB := activation.NewDB(atxDBStore, fetcherWrapped, idStore, layersPerEpoch, goldenATXID, validator, app.addLogger(AtxDbLogger, lg))
+	atxDB := activation.NewDB(sqlDB, fetcherWrapped, idStore, layersPerEpoch, goldenATXID, validator, app.addLogger(AtxDbLogger, lg))
 
 	edVerifier := signing.NewEDVerifier()
 	vrfVerifier := signing.VRFVerifier{}
@@ -563,6 +554,9 @@ func (app *App) initServices(ctx context.Context,
 		sqlDB,
 		clock,
 		app.addLogger(BeaconLogger, lg))
+	beaconSync := server.New(app.host, beacon.SyncProtocol, beaconProtocol.HandleSyncRequest,
+		server.WithLog(app.addLogger(BeaconLogger, lg)))
+	beaconProtocol.SetBeaconSync(beaconSync, app.host, fetcherWrapped, mdb)
 
 	var msh *mesh.Mesh
 	var trtl *tortoise.Tortoise
@@ -586,13 +580,23 @@ func (app *App) initServices(ctx context.Context,
 		tortoise.WithContext(ctx),
 		tortoise.WithLogger(app.addLogger(TrtlLogger, lg)),
 		tortoise.WithConfig(trtlCfg),
+		tortoise.WithDatabase(sqlDB),
 	)
 
+	if retention := app.Config.PruneRetentionEpochs * app.Config.LayersPerEpoch; retention > 0 && retention <= trtlCfg.WindowSize {
+		return fmt.Errorf("prune retention (%d layers) must be larger than tortoise window (%d layers)", retention, trtlCfg.WindowSize)
+	}
+	malfeasanceHandler := malfeasance.NewHandler(sqlDB, app.host.ID(), app.host, trtl,
+		malfeasance.WithLogger(app.addLogger(MalfeasanceLogger, lg)))
+	meshOpts := []mesh.Opt{
+		mesh.WithPruneRetention(app.Config.PruneRetentionEpochs),
+		mesh.WithMalfeasancePublisher(malfeasanceHandler),
+	}
 	if mdb.PersistentData() {
-		msh = mesh.NewRecoveredMesh(mdb, atxDB, trtl, app.txPool, state, app.addLogger(MeshLogger, lg))
+		msh = mesh.NewRecoveredMesh(mdb, atxDB, trtl, app.txPool, state, app.addLogger(MeshLogger, lg), meshOpts...)
 		go msh.CacheWarmUp(app.Config.LayerAvgSize)
 	} else {
-		msh = mesh.NewMesh(mdb, atxDB, trtl, app.txPool, state, app.addLogger(MeshLogger, lg))
+		msh = mesh.NewMesh(mdb, atxDB, trtl, app.txPool, state, app.addLogger(MeshLogger, lg), meshOpts...)
 		if err := state.SetupGenesis(app.Config.Genesis); err != nil {
 			return fmt.Errorf("setup genesis: %w", err)
 		}
@@ -611,6 +615,13 @@ func (app *App) initServices(ctx context.Context,
 			app.Config.HareEligibility.EpochOffset, app.Config.BaseConfig.LayersPerEpoch)
 	}
 
+	if hareCfg := app.Config.HARE; hareCfg.AdaptiveTiming &&
+		(hareCfg.TimingRoundDuration <= 0 || hareCfg.TimingRoundDuration < hareCfg.MinRoundDuration ||
+			hareCfg.TimingRoundDuration > hareCfg.MaxRoundDuration || hareCfg.TimingWakeupDelta < 0) {
+		return fmt.Errorf("agreed hare timing is out of bounds. round_duration: %d (min %d, max %d). wakeup_delta: %d",
+			hareCfg.TimingRoundDuration, hareCfg.MinRoundDuration, hareCfg.MaxRoundDuration, hareCfg.TimingWakeupDelta)
+	}
+
 	proposalListener := proposals.NewHandler(fetcherWrapped, beaconProtocol, atxDB, msh, proposalDB,
 		proposals.WithLogger(app.addLogger(ProposalListenerLogger, lg)),
 		proposals.WithLayerPerEpoch(layersPerEpoch),
@@ -625,9 +636,9 @@ func (app *App) initServices(ctx context.Context,
 		fetch.BallotDB:   mdb.Ballots(),
 		fetch.BlockDB:    mdb.Blocks(),
 		fetch.ProposalDB: proposalDB,
-		fetch.ATXDB:      atxDBStore,
+		fetch.ATXDB:      atxDB.Atxs(),
 		fetch.TXDB:       mdb.Transactions(),
-		fetch.POETDB:     poetDBStore,
+		fetch.POETDB:     poetDb.Proofs(),
 	}
 	dataHanders := layerfetcher.DataHandlers{
 		ATX:      atxDB,
@@ -660,7 +671,7 @@ func (app *App) initServices(ctx context.Context,
 	}
 
 	blockGen := blocks.NewGenerator(atxDB, msh, blocks.WithConfig(app.Config.REWARD), blocks.WithGeneratorLogger(app.addLogger(BlockGenLogger, lg)))
-	rabbit := app.HareFactory(ctx, sgn, blockGen, nodeID, patrol, newSyncer, msh, proposalDB, beaconProtocol, fetcherWrapped, hOracle, idStore, clock, lg)
+	rabbit := app.HareFactory(ctx, sgn, blockGen, nodeID, patrol, newSyncer, msh, proposalDB, beaconProtocol, fetcherWrapped, layerFetch, hOracle, idStore, malfeasanceHandler, sqlDB, clock, lg)
 
 	stateAndMeshProjector := pendingtxs.NewStateAndMeshProjector(state, msh)
 	proposalBuilder := miner.NewProposalBuilder(
@@ -690,7 +701,7 @@ func (app *App) initServices(ctx context.Context,
 		app.log.Panic("failed to create post setup manager: %v", err)
 	}
 
-	nipostBuilder := activation.NewNIPostBuilder(util.Hex2Bytes(nodeID.Key), postSetupMgr, poetClient, poetDb, store, app.addLogger(NipostBuilderLogger, lg))
+	nipostBuilder := activation.NewNIPostBuilder(util.Hex2Bytes(nodeID.Key), postSetupMgr, poetClients, poetDb, store, app.addLogger(NipostBuilderLogger, lg))
 
 	coinbaseAddr := types.HexToAddress(app.Config.SMESHING.CoinbaseAccount)
 	if app.Config.SMESHING.Start {
@@ -703,9 +714,13 @@ func (app *App) initServices(ctx context.Context,
 		CoinbaseAccount: coinbaseAddr,
 		GoldenATXID:     goldenATXID,
 		LayersPerEpoch:  layersPerEpoch,
+		PoETServers:     app.Config.PoETServers,
 	}
 	atxBuilder := activation.NewBuilder(builderConfig, nodeID, sgn, atxDB, app.host, nipostBuilder,
 		postSetupMgr, clock, newSyncer, store, app.addLogger("atxBuilder", lg), activation.WithContext(ctx),
+		activation.WithPoETClientInitializer(func(target string) activation.PoetProvingServiceClient {
+			return activation.NewHTTPPoetClient(target)
+		}),
 	)
 
 	syncHandler := func(_ context.Context, _ p2p.Peer, _ []byte) pubsub.ValidationResult {
@@ -723,6 +738,7 @@ func (app *App) initServices(ctx context.Context,
 	app.host.Register(beacon.FollowingVotingProtocol,
 		pubsub.ChainGossipHandler(syncHandler, beaconProtocol.HandleSerializedFollowingVotingMessage))
 	app.host.Register(proposals.NewProposalProtocol, pubsub.ChainGossipHandler(syncHandler, proposalListener.HandleProposal))
+	app.host.Register(malfeasance.Protocol, pubsub.ChainGossipHandler(syncHandler, malfeasanceHandler.HandleMalfeasanceProof))
 	app.host.Register(activation.AtxProtocol, pubsub.ChainGossipHandler(syncHandler, atxDB.HandleGossipAtx))
 	app.host.Register(svm.IncomingTxProtocol, pubsub.ChainGossipHandler(syncHandler, state.HandleGossipTransaction))
 	app.host.Register(activation.PoetProofProtocol, poetListener.HandlePoetProofMessage)
@@ -748,6 +764,9 @@ func (app *App) initServices(ctx context.Context,
 			peersync.WithLog(app.addLogger(TimeSyncLogger, lg)),
 			peersync.WithConfig(app.Config.TIME.Peersync),
 		)
+		if ha, ok := rabbit.(*hare.Hare); ok {
+			ha.SetClockOffset(app.ptimesync)
+		}
 	}
 
 	return nil
@@ -788,8 +807,11 @@ func (app *App) HareFactory(
 	proposalDB *proposals.DB,
 	beacons system.BeaconGetter,
 	pFetcher system.ProposalFetcher,
+	layerFetch *layerfetcher.Logic,
 	hOracle hare.Rolacle,
 	idStore *activation.IdentityStore,
+	malfeasanceHandler *malfeasance.Handler,
+	db *sql.Database,
 	clock TickProvider,
 	lg log.Log,
 ) HareService {
@@ -815,8 +837,12 @@ func (app *App) HareFactory(
 		uint16(app.Config.LayersPerEpoch),
 		idStore,
 		hOracle,
+		malfeasanceHandler,
+		db,
 		clock,
 		app.addLogger(HareLogger, lg))
+	ha.SetOutputRequestor(server.New(app.host, hare.OutputProtocol, ha.HandleOutputRequest, server.WithLog(app.addLogger(HareLogger, lg))))
+	layerFetch.SetHareOutputVerifier(ha)
 	return ha
 }
 
@@ -874,8 +900,11 @@ func (app *App) startAPIServices(ctx context.Context) {
 	}
 
 	// Register the requested services one by one
+	if apiConf.StartBeaconService {
+		registerService(grpcserver.NewBeaconService(app.beaconProtocol))
+	}
 	if apiConf.StartDebugService {
-		registerService(grpcserver.NewDebugService(app.mesh, app.host))
+		registerService(grpcserver.NewDebugService(app.mesh, app.host, app.tortoise))
 	}
 	if apiConf.StartGatewayService {
 		registerService(grpcserver.NewGatewayService(app.host))
@@ -883,6 +912,13 @@ func (app *App) startAPIServices(ctx context.Context) {
 	if apiConf.StartGlobalStateService {
 		registerService(grpcserver.NewGlobalStateService(app.mesh, app.txPool))
 	}
+	if apiConf.StartHareService {
+		if hareAPI, ok := app.hare.(api.HareAPI); ok {
+			registerService(grpcserver.NewHareService(hareAPI))
+		} else {
+			app.log.Warning("hare service is not available with the configured hare implementation")
+		}
+	}
 	if apiConf.StartMeshService {
 		registerService(grpcserver.NewMeshService(app.mesh, app.clock, app.Config.LayersPerEpoch, app.Config.P2P.NetworkID, layerDuration, app.Config.LayerAvgSize, app.Config.TxsPerBlock))
 	}
@@ -1139,7 +1175,10 @@ func (app *App) Start() error {
 		return fmt.Errorf("could not retrieve identity: %w", err)
 	}
 
-	poetClient := activation.NewHTTPPoetClient(app.Config.PoETServer)
+	poetClients := make([]activation.PoetProvingServiceClient, 0, len(app.Config.PoETServers))
+	for _, target := range app.Config.PoETServers {
+		poetClients = append(poetClients, activation.NewHTTPPoetClient(target))
+	}
 
 	edPubkey := app.edSgn.PublicKey()
 	vrfSigner, vrfPub, err := signing.NewVRFSigner(app.edSgn.Sign(edPubkey.Bytes()))
@@ -1182,7 +1221,7 @@ func (app *App) Start() error {
 		false,
 		nil,
 		uint32(app.Config.LayerAvgSize),
-		poetClient,
+		poetClients,
 		vrfSigner,
 		app.Config.LayersPerEpoch,
 		clock); err != nil {
@@ -0,0 +1,352 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/spacemeshos/go-spacemesh/activation"
+	cmdp "github.com/spacemeshos/go-spacemesh/cmd"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/mesh"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/tortoise"
+)
+
+// TortoiseCmd groups tools for the tortoise protocol.
+var TortoiseCmd = &cobra.Command{
+	Use:   "tortoise",
+	Short: "tortoise tools",
+}
+
+// ReplayCmd replays ballots and blocks from the node database through a new tortoise instance.
+var ReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay recorded ballots and blocks through the tortoise with alternative parameters",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := loadConfig(Cmd)
+		if err != nil {
+			log.With().Fatal("failed to initialize config", log.Err(err))
+		}
+		types.SetLayersPerEpoch(conf.LayersPerEpoch)
+
+		rcfg := replayConfig{
+			goldenATX:      types.ATXID(types.HexToHash32(conf.GoldenATXID)),
+			layersPerEpoch: conf.LayersPerEpoch,
+			blockCacheSize: conf.BlockCacheSize,
+			tortoise:       conf.Tortoise,
+		}
+		rcfg.tortoise.LayerSize = uint32(conf.LayerAvgSize)
+		rcfg.tortoise.BadBeaconVoteDelayLayers = conf.LayersPerEpoch
+		flags := cmd.Flags()
+		if flags.Changed("from") {
+			from, _ := flags.GetUint32("from")
+			rcfg.from = types.NewLayerID(from)
+		}
+		if flags.Changed("to") {
+			to, _ := flags.GetUint32("to")
+			rcfg.to = types.NewLayerID(to)
+		}
+		if flags.Changed("hdist") {
+			rcfg.tortoise.Hdist, _ = flags.GetUint32("hdist")
+		}
+		if flags.Changed("zdist") {
+			rcfg.tortoise.Zdist, _ = flags.GetUint32("zdist")
+		}
+		if flags.Changed("window") {
+			rcfg.tortoise.WindowSize, _ = flags.GetUint32("window")
+		}
+		for name, threshold := range map[string]**big.Rat{
+			"global-threshold": &rcfg.tortoise.GlobalThreshold,
+			"local-threshold":  &rcfg.tortoise.LocalThreshold,
+		} {
+			if !flags.Changed(name) {
+				continue
+			}
+			value, _ := flags.GetString(name)
+			rat, ok := new(big.Rat).SetString(value)
+			if !ok {
+				log.With().Fatal("invalid threshold", log.String("flag", name), log.String("value", value))
+			}
+			*threshold = rat
+		}
+		path, _ := flags.GetString("db")
+		if len(path) == 0 {
+			path = dbPath(conf.DataDir())
+		}
+		logger := log.NewDefault("replay")
+		if err := replayTortoise(cmdp.Ctx(), cmd.OutOrStdout(), path, rcfg, logger); err != nil {
+			log.With().Fatal("tortoise replay failed", log.Err(err))
+		}
+	},
+}
+
+func init() {
+	ReplayCmd.Flags().String("db", "", "path to the node database. defaults to the database in the node data directory")
+	ReplayCmd.Flags().Uint32("from", 0, "first layer to report. defaults to the first layer after genesis")
+	ReplayCmd.Flags().Uint32("to", 0, "last layer to replay. defaults to the latest layer in the database")
+	ReplayCmd.Flags().Uint32("hdist", 0, "overwrite hdist")
+	ReplayCmd.Flags().Uint32("zdist", 0, "overwrite zdist")
+	ReplayCmd.Flags().Uint32("window", 0, "overwrite size of the tortoise window")
+	ReplayCmd.Flags().String("global-threshold", "", "overwrite global threshold. a rational number, e.g. 0.6 or 3/5")
+	ReplayCmd.Flags().String("local-threshold", "", "overwrite local threshold. a rational number, e.g. 0.2 or 1/5")
+	TortoiseCmd.AddCommand(ReplayCmd)
+	Cmd.AddCommand(TortoiseCmd)
+}
+
+type replayConfig struct {
+	// from is the first layer that is reported. Layers before it are replayed silently to build the state.
+	from, to       types.LayerID
+	goldenATX      types.ATXID
+	layersPerEpoch uint32
+	blockCacheSize int
+	tortoise       tortoise.Config
+}
+
+// replayTortoise feeds blocks and ballots layer by layer, in the order they were added to the database,
+// into a tortoise started from genesis. It reports verified layer after every layer between from and to,
+// blocks that changed validity during replay, and blocks with validity that differs from the recorded one.
+//
+// Database is not modified. Replay fails if bodies of the replayed layers were pruned. Weak coin is not persisted, therefore replayed tortoise never uses it.
+func replayTortoise(ctx context.Context, w io.Writer, path string, cfg replayConfig, logger log.Log) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("database %s: %w", path, err)
+	}
+	db, err := sql.Open("file:"+path, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := checkSchemaVersion(db); err != nil {
+		return err
+	}
+
+	genesis := types.GetEffectiveGenesis()
+	if cfg.from == (types.LayerID{}) {
+		cfg.from = genesis.Add(1)
+	}
+	if cfg.to == (types.LayerID{}) {
+		if cfg.to, err = layers.GetByStatus(db, layers.Latest); err != nil {
+			return err
+		}
+	}
+	if !cfg.from.After(genesis) {
+		return fmt.Errorf("from layer %s must be after genesis %s", cfg.from, genesis)
+	}
+	if cfg.to.Before(cfg.from) {
+		return fmt.Errorf("to layer %s is before from layer %s", cfg.to, cfg.from)
+	}
+	if cfg.tortoise.Hdist < cfg.tortoise.Zdist {
+		return fmt.Errorf("hdist %d must be >= zdist %d", cfg.tortoise.Hdist, cfg.tortoise.Zdist)
+	}
+
+	if err := checkPruned(w, db, cfg.to); err != nil {
+		return err
+	}
+
+	mdb, err := mesh.NewPersistentMeshDB(db, cfg.blockCacheSize, logger.WithName("meshdb"))
+	if err != nil {
+		return fmt.Errorf("create mesh DB: %w", err)
+	}
+	rmesh := newReplayMesh(mdb)
+	atxdb := activation.NewDB(db, nil, nil, cfg.layersPerEpoch, cfg.goldenATX, nil, logger.WithName("atxdb"))
+
+	tcfg := cfg.tortoise
+	tcfg.RerunInterval = 0
+	tcfg.CheckpointInterval = 0
+	tcfg.MeshProcessed = types.LayerID{}
+	tcfg.MeshVerified = types.LayerID{}
+	trtl := tortoise.New(rmesh, atxdb, replayBeacons{db: db},
+		tortoise.WithContext(ctx),
+		tortoise.WithLogger(logger.WithName("tortoise")),
+		tortoise.WithConfig(tcfg),
+	)
+	defer trtl.Stop()
+
+	var (
+		verified types.LayerID
+		flips    int
+	)
+	for lid := genesis.Add(1); !lid.After(cfg.to); lid = lid.Add(1) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := replayLayer(trtl, db, mdb, lid); err != nil {
+			return err
+		}
+		_, current, _ := trtl.HandleIncomingLayer(ctx, lid)
+		if lid.Before(cfg.from) {
+			verified = current
+			rmesh.flips = nil
+			continue
+		}
+		if current != verified {
+			fmt.Fprintf(w, "layer %s: verified %s\n", lid, current)
+		} else {
+			fmt.Fprintf(w, "layer %s: verified %s (no progress)\n", lid, current)
+		}
+		verified = current
+		for _, flip := range rmesh.flips {
+			fmt.Fprintf(w, "  block %s in layer %s flipped from %s to %s\n",
+				flip.block, flip.layer, validityString(!flip.valid), validityString(flip.valid))
+		}
+		flips += len(rmesh.flips)
+		rmesh.flips = nil
+	}
+
+	diverged := 0
+	for lid := cfg.from; !lid.After(verified); lid = lid.Add(1) {
+		ids, err := blocks.IDsInLayer(db, lid)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			replayed, exist := rmesh.validity[id]
+			if !exist {
+				continue
+			}
+			recorded, err := blocks.IsValid(db, id)
+			if errors.Is(err, sql.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if recorded != replayed {
+				diverged++
+				fmt.Fprintf(w, "block %s in layer %s is %s, recorded as %s\n",
+					id, lid, validityString(replayed), validityString(recorded))
+			}
+		}
+	}
+	fmt.Fprintf(w, "replayed layers from %s to %s: verified %s, %d validity flips, %d blocks differ from recorded validity\n",
+		cfg.from, cfg.to, verified, flips, diverged)
+	return nil
+}
+
+// checkPruned reports layers up to the last replayed layer where ballots or blocks were pruned
+// by the retention policy. Replay starts from genesis and requires bodies of every layer after it.
+func checkPruned(w io.Writer, db sql.Executor, last types.LayerID) error {
+	genesis := types.GetEffectiveGenesis()
+	pruned := false
+	for _, object := range []struct {
+		name   string
+		ranged func(sql.Executor) (types.LayerID, types.LayerID, error)
+	}{
+		{name: "ballots", ranged: ballots.PrunedRange},
+		{name: "blocks", ranged: blocks.PrunedRange},
+	} {
+		from, to, err := object.ranged(db)
+		if errors.Is(err, sql.ErrNotFound) || (err == nil && (from.After(last) || !to.After(genesis))) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !from.After(genesis) {
+			from = genesis.Add(1)
+		}
+		if to.After(last) {
+			to = last
+		}
+		fmt.Fprintf(w, "%s in layers from %s to %s are pruned\n", object.name, from, to)
+		pruned = true
+	}
+	if pruned {
+		return fmt.Errorf("%w: replay requires a database from a node with prune-retention-epochs 0", sql.ErrPruned)
+	}
+	return nil
+}
+
+// replayLayer feeds blocks and then ballots from the layer in the order they were added to the database.
+func replayLayer(trtl *tortoise.Tortoise, db sql.Executor, mdb *mesh.DB, lid types.LayerID) error {
+	bids, err := blocks.IDsInLayerByArrival(db, lid)
+	if err != nil {
+		return err
+	}
+	for _, id := range bids {
+		block, err := mdb.GetBlock(id)
+		if err != nil {
+			return fmt.Errorf("get block %s: %w", id, err)
+		}
+		trtl.OnBlock(block)
+	}
+	ids, err := ballots.IDsInLayerByArrival(db, lid)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		ballot, err := mdb.GetBallot(id)
+		if err != nil {
+			return fmt.Errorf("get ballot %s: %w", id, err)
+		}
+		trtl.OnBallot(ballot)
+	}
+	return nil
+}
+
+func validityString(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+type validityFlip struct {
+	block types.BlockID
+	layer types.LayerID
+	valid bool
+}
+
+// replayMesh keeps validity decided by the replayed tortoise in memory, so that recorded validity
+// is not modified, and tracks blocks that changed validity.
+type replayMesh struct {
+	*mesh.DB
+
+	validity map[types.BlockID]bool
+	flips    []validityFlip
+}
+
+func newReplayMesh(mdb *mesh.DB) *replayMesh {
+	return &replayMesh{DB: mdb, validity: map[types.BlockID]bool{}}
+}
+
+// SaveContextualValidity overrides the method in the embedded type to keep validity in memory.
+func (r *replayMesh) SaveContextualValidity(id types.BlockID, lid types.LayerID, valid bool) error {
+	if prev, exist := r.validity[id]; exist && prev != valid {
+		r.flips = append(r.flips, validityFlip{block: id, layer: lid, valid: valid})
+	}
+	r.validity[id] = valid
+	return nil
+}
+
+// ContextualValidity overrides the method in the embedded type to read validity decided during replay.
+func (r *replayMesh) ContextualValidity(id types.BlockID) (bool, error) {
+	valid, exist := r.validity[id]
+	if !exist {
+		return false, fmt.Errorf("%w block %s is undecided", sql.ErrNotFound, id)
+	}
+	return valid, nil
+}
+
+// replayBeacons reads beacons that were persisted by the node. Beacon for the first epochs
+// is not computed by the protocol and is never persisted.
+type replayBeacons struct {
+	db sql.Executor
+}
+
+func (b replayBeacons) GetBeacon(epoch types.EpochID) (types.Beacon, error) {
+	beacon, err := beacons.Get(b.db, epoch)
+	if errors.Is(err, sql.ErrNotFound) && epoch <= types.EpochID(2) {
+		return types.HexToBeacon(types.BootstrapBeacon), nil
+	}
+	return beacon, err
+}
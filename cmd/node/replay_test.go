@@ -0,0 +1,190 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/activation"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/mesh"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/beacons"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/tortoise"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+// newReplayDB copies atxs, beacons, ballots, blocks and hare outputs generated by the simulator
+// into a single database, in the same way as they are stored by the node.
+func newReplayDB(tb testing.TB, path string, state sim.State, last types.LayerID) {
+	tb.Helper()
+	db, err := sql.Open("file:" + path)
+	require.NoError(tb, err)
+	defer db.Close()
+
+	logger := logtest.New(tb)
+	atxdb := activation.NewDB(db, nil, nil, types.GetLayersPerEpoch(), types.ATXID{}, nil, logger)
+	mdb, err := mesh.NewPersistentMeshDB(db, 20, logger)
+	require.NoError(tb, err)
+	for epoch := types.EpochID(0); epoch <= last.GetEpoch(); epoch++ {
+		ids, err := state.AtxDB.GetEpochAtxs(epoch)
+		require.NoError(tb, err)
+		for _, id := range ids {
+			atx, err := state.AtxDB.GetFullAtx(id)
+			require.NoError(tb, err)
+			require.NoError(tb, atxdb.StoreAtx(context.TODO(), epoch, atx))
+		}
+		if beacon, err := state.Beacons.GetBeacon(epoch); err == nil {
+			require.NoError(tb, beacons.Add(db, epoch, beacon))
+		}
+	}
+	for lid := types.GetEffectiveGenesis().Add(1); !lid.After(last); lid = lid.Add(1) {
+		bids, err := state.MeshDB.LayerBlockIds(lid)
+		require.NoError(tb, err)
+		for _, id := range bids {
+			block, err := state.MeshDB.GetBlock(id)
+			require.NoError(tb, err)
+			require.NoError(tb, mdb.AddBlock(block))
+		}
+		ballots, err := state.MeshDB.LayerBallots(lid)
+		require.NoError(tb, err)
+		for _, ballot := range ballots {
+			require.NoError(tb, mdb.AddBallot(ballot))
+		}
+		output, err := state.MeshDB.GetHareConsensusOutput(lid)
+		require.NoError(tb, err)
+		require.NoError(tb, mdb.SaveHareConsensusOutput(context.TODO(), lid, output))
+	}
+	require.NoError(tb, layers.SetStatus(db, last, layers.Latest))
+}
+
+func TestReplayTortoise(t *testing.T) {
+	const size = 10
+	types.SetLayersPerEpoch(4)
+	// genesis layer is initialized once and may use the number of layers per epoch from other tests
+	types.InitGenesisData()
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+	var last types.LayerID
+	for _, last = range sim.GenLayers(s, sim.WithSequence(8)) {
+	}
+	path := dbPath(t.TempDir())
+	newReplayDB(t, path, s.GetState(0), last)
+
+	cfg := func() replayConfig {
+		trtl := tortoise.DefaultConfig()
+		trtl.LayerSize = size
+		trtl.Hdist = 4
+		trtl.Zdist = 2
+		trtl.WindowSize = 20
+		trtl.BadBeaconVoteDelayLayers = types.GetLayersPerEpoch()
+		return replayConfig{
+			layersPerEpoch: types.GetLayersPerEpoch(),
+			blockCacheSize: 20,
+			tortoise:       trtl,
+		}
+	}
+
+	t.Run("recorded", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, replayTortoise(context.TODO(), &out, path, cfg(), logtest.New(t)))
+		for lid := types.GetEffectiveGenesis().Add(1); !lid.After(last); lid = lid.Add(1) {
+			require.Contains(t, out.String(), fmt.Sprintf("layer %s: verified %s\n", lid, lid.Sub(1)))
+		}
+		require.Contains(t, out.String(), fmt.Sprintf(
+			"replayed layers from %s to %s: verified %s, 0 validity flips, 0 blocks differ from recorded validity",
+			types.GetEffectiveGenesis().Add(1), last, last.Sub(1)))
+	})
+	t.Run("range", func(t *testing.T) {
+		rcfg := cfg()
+		rcfg.from = last.Sub(2)
+		rcfg.to = last.Sub(1)
+		var out bytes.Buffer
+		require.NoError(t, replayTortoise(context.TODO(), &out, path, rcfg, logtest.New(t)))
+		require.NotContains(t, out.String(), fmt.Sprintf("layer %s:", last.Sub(3)))
+		require.Contains(t, out.String(), fmt.Sprintf("layer %s: verified %s\n", last.Sub(2), last.Sub(3)))
+		require.Contains(t, out.String(), fmt.Sprintf("layer %s: verified %s\n", last.Sub(1), last.Sub(2)))
+		require.NotContains(t, out.String(), fmt.Sprintf("layer %s:", last))
+	})
+	t.Run("threshold", func(t *testing.T) {
+		rcfg := cfg()
+		// threshold can't be crossed even if all ballots vote the same way
+		rcfg.tortoise.GlobalThreshold = big.NewRat(2, 1)
+		var out bytes.Buffer
+		require.NoError(t, replayTortoise(context.TODO(), &out, path, rcfg, logtest.New(t)))
+		require.Contains(t, out.String(), fmt.Sprintf("layer %s: verified %s (no progress)\n",
+			last, types.GetEffectiveGenesis()))
+	})
+	t.Run("differs from recorded", func(t *testing.T) {
+		target := last.Sub(2)
+		db, err := sql.Open("file:" + path)
+		require.NoError(t, err)
+		output, err := layers.GetHareOutput(db, target)
+		require.NoError(t, err)
+		require.NoError(t, blocks.SetInvalid(db, output))
+		require.NoError(t, db.Close())
+
+		var out bytes.Buffer
+		require.NoError(t, replayTortoise(context.TODO(), &out, path, cfg(), logtest.New(t)))
+		require.Contains(t, out.String(),
+			fmt.Sprintf("block %s in layer %s is valid, recorded as invalid", output, target))
+		require.Contains(t, out.String(), "1 blocks differ from recorded validity")
+
+		// replay doesn't modify the database
+		db, err = sql.Open("file:" + path)
+		require.NoError(t, err)
+		defer db.Close()
+		valid, err := blocks.IsValid(db, output)
+		require.NoError(t, err)
+		require.False(t, valid)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		rcfg := cfg()
+		rcfg.from = last
+		rcfg.to = last.Sub(1)
+		require.Error(t, replayTortoise(context.TODO(), &bytes.Buffer{}, path, rcfg, logtest.New(t)))
+	})
+	t.Run("pruned", func(t *testing.T) {
+		db, err := sql.Open("file:" + path)
+		require.NoError(t, err)
+		mdb, err := mesh.NewPersistentMeshDB(db, 20, logtest.New(t))
+		require.NoError(t, err)
+		require.NoError(t, mdb.Prune(last.Sub(2)))
+		require.NoError(t, db.Close())
+
+		var out bytes.Buffer
+		require.ErrorIs(t, replayTortoise(context.TODO(), &out, path, cfg(), logtest.New(t)), sql.ErrPruned)
+		first := types.GetEffectiveGenesis().Add(1)
+		require.Contains(t, out.String(),
+			fmt.Sprintf("ballots in layers from %s to %s are pruned\n", first, last.Sub(3)))
+		require.Contains(t, out.String(),
+			fmt.Sprintf("blocks in layers from %s to %s are pruned\n", first, last.Sub(3)))
+		require.NotContains(t, out.String(), "verified")
+	})
+}
+
+func TestReplayMeshFlips(t *testing.T) {
+	rmesh := newReplayMesh(nil)
+	lid := types.NewLayerID(10)
+	block := types.BlockID{1}
+
+	_, err := rmesh.ContextualValidity(block)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, rmesh.SaveContextualValidity(block, lid, true))
+	require.NoError(t, rmesh.SaveContextualValidity(block, lid, true))
+	require.Empty(t, rmesh.flips)
+
+	require.NoError(t, rmesh.SaveContextualValidity(block, lid, false))
+	require.Equal(t, []validityFlip{{block: block, layer: lid, valid: false}}, rmesh.flips)
+	valid, err := rmesh.ContextualValidity(block)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
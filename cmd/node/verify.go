@@ -0,0 +1,267 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spacemeshos/go-spacemesh/api/config"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/database"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/svm"
+)
+
+// errDiverged is returned if data recomputed from blocks and transactions doesn't match the database.
+var errDiverged = errors.New("mesh data diverged")
+
+// verifyDB walks every layer in the database, recomputes layer and aggregated hashes from valid blocks,
+// checks that hare output is known, and replays applied blocks to compare state roots.
+// Verification stops at the first diverging layer.
+func verifyDB(w io.Writer, path string, genesis *config.GenesisConfig, logger log.Log) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("database %s: %w", path, err)
+	}
+	db, err := sql.Open("file:"+path, sql.WithMigrations(nil), sql.WithConnections(1))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := checkSchemaVersion(db); err != nil {
+		return err
+	}
+
+	state := svm.New(database.NewMemDatabase(), sql.InMemory(), nil, nil, logger)
+	if err := state.SetupGenesis(genesis); err != nil {
+		return fmt.Errorf("setup genesis: %w", err)
+	}
+	v := &verifier{db: db, state: state, replay: true, logger: logger}
+	last, err := layers.GetByStatus(db, layers.Latest)
+	if err != nil {
+		return err
+	}
+	if v.applied, err = layers.GetByStatus(db, layers.Applied); err != nil {
+		return err
+	}
+	if v.hashesFrom, v.hashesTo, _, err = layers.GetAggregatedHashRange(db); err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return err
+	}
+	for lid := types.NewLayerID(1); !lid.After(last); lid = lid.Add(1) {
+		reason, err := v.verifyLayer(lid)
+		if err != nil {
+			return fmt.Errorf("verify layer %s: %w", lid, err)
+		}
+		if len(reason) > 0 {
+			fmt.Fprintf(w, "layer %s diverged: %s\n", lid, reason)
+			return fmt.Errorf("%w at layer %s", errDiverged, lid)
+		}
+	}
+	fmt.Fprintf(w, "verified layers from 1 to %s\n", last)
+	if !v.replay {
+		fmt.Fprintf(w, "state roots verified up to layer %s: %s\n", v.replayed, v.replayStopped)
+	}
+	return nil
+}
+
+func checkSchemaVersion(db sql.Executor) error {
+	version, err := sql.Version(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := sql.LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if latest := migrations[len(migrations)-1].Order; version != latest {
+		return fmt.Errorf("schema version %d is not the latest %d, run `node db migrate`", version, latest)
+	}
+	return nil
+}
+
+// verifier recomputes data the same way mesh computes it when layers are applied.
+type verifier struct {
+	db     sql.Executor
+	state  *svm.SVM
+	logger log.Log
+
+	// applied is the last layer applied to the state.
+	applied types.LayerID
+	// hashesFrom and hashesTo are the first and the last layers with persisted aggregated hash.
+	hashesFrom, hashesTo types.LayerID
+	// prevAggHash is the aggregated hash of the previous layer, nil if it wasn't verified.
+	prevAggHash *types.Hash32
+
+	// replay is false once state can't be replayed, for example if applied block was pruned.
+	replay        bool
+	replayed      types.LayerID
+	replayStopped string
+}
+
+// verifyLayer returns the reason of divergence, or an empty string if the layer is consistent.
+func (v *verifier) verifyLayer(lid types.LayerID) (string, error) {
+	if reason, err := v.verifyHareOutput(lid); err != nil || len(reason) > 0 {
+		return reason, err
+	}
+	valid, reason, err := v.validBlocks(lid)
+	if err != nil || len(reason) > 0 {
+		return reason, err
+	}
+	if !lid.Before(v.hashesFrom) && !lid.After(v.hashesTo) {
+		if reason, err := v.verifyHashes(lid, valid); err != nil || len(reason) > 0 {
+			return reason, err
+		}
+	}
+	if v.replay && !lid.After(v.applied) {
+		return v.verifyState(lid, valid)
+	}
+	return "", nil
+}
+
+func (v *verifier) verifyHareOutput(lid types.LayerID) (string, error) {
+	output, err := layers.GetHareOutput(v.db, lid)
+	if errors.Is(err, sql.ErrNotFound) || output == types.EmptyBlockID {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	blid, err := blocks.GetLayer(v.db, output)
+	if errors.Is(err, sql.ErrNotFound) {
+		return fmt.Sprintf("hare output %s is not in the blocks table", output), nil
+	} else if err != nil {
+		return "", err
+	}
+	if blid != lid {
+		return fmt.Sprintf("hare output %s is a block from layer %s", output, blid), nil
+	}
+	return "", nil
+}
+
+// validBlocks returns valid blocks in the layer. Layers that are hashed or applied must not have undecided blocks.
+func (v *verifier) validBlocks(lid types.LayerID) ([]types.BlockID, string, error) {
+	ids, err := blocks.IDsInLayer(v.db, lid)
+	if err != nil {
+		return nil, "", err
+	}
+	var valid []types.BlockID
+	for _, id := range ids {
+		isValid, err := blocks.IsValid(v.db, id)
+		if errors.Is(err, sql.ErrNotFound) {
+			if !lid.After(v.hashesTo) || !lid.After(v.applied) {
+				return nil, fmt.Sprintf("block %s is undecided in the verified layer", id), nil
+			}
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		if isValid {
+			valid = append(valid, id)
+		}
+	}
+	return valid, "", nil
+}
+
+// verifyHashes recomputes hashes in the same way as Mesh.persistLayerHashes.
+func (v *verifier) verifyHashes(lid types.LayerID, valid []types.BlockID) (string, error) {
+	hash := types.EmptyLayerHash
+	if len(valid) > 0 {
+		hash = types.CalcBlocksHash32(valid, nil)
+	}
+	stored, err := layers.GetHash(v.db, lid)
+	if err != nil {
+		return "", err
+	}
+	if stored != hash {
+		return fmt.Sprintf("layer hash %s, recomputed %s", stored.ShortString(), hash.ShortString()), nil
+	}
+
+	prev := types.EmptyLayerHash
+	if v.prevAggHash != nil {
+		prev = *v.prevAggHash
+	} else if lid.After(types.NewLayerID(1)) {
+		if prev, err = layers.GetAggregatedHash(v.db, lid.Sub(1)); err != nil {
+			return "", err
+		}
+	}
+	aggHash := types.CalcBlocksHash32(valid, prev.Bytes())
+	storedAgg, err := layers.GetAggregatedHash(v.db, lid)
+	if err != nil {
+		return "", err
+	}
+	if storedAgg != aggHash {
+		return fmt.Sprintf("aggregated hash %s, recomputed %s", storedAgg.ShortString(), aggHash.ShortString()), nil
+	}
+	v.prevAggHash = &aggHash
+	return "", nil
+}
+
+// verifyState applies the block that mesh would apply for the layer and compares the state root.
+func (v *verifier) verifyState(lid types.LayerID, valid []types.BlockID) (string, error) {
+	expected := types.EmptyBlockID
+	if len(valid) > 0 {
+		// mesh applies block with the lowest id if there are multiple valid blocks
+		expected = types.SortBlockIDs(append([]types.BlockID{}, valid...))[0]
+	}
+	if applied, err := layers.GetApplied(v.db, lid); err == nil && applied != expected {
+		return fmt.Sprintf("applied block %s, expected %s", applied, expected), nil
+	} else if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return "", err
+	}
+	if expected == types.EmptyBlockID {
+		// state is not modified by layers without blocks
+		return "", nil
+	}
+
+	block, err := blocks.Get(v.db, expected)
+	if errors.Is(err, sql.ErrPruned) {
+		v.stopReplay(lid, fmt.Sprintf("block %s in layer %s was pruned", expected, lid))
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	txs := make([]*types.Transaction, 0, len(block.TxIDs))
+	for _, id := range block.TxIDs {
+		tx, err := transactions.Get(v.db, id)
+		if errors.Is(err, sql.ErrPruned) {
+			v.stopReplay(lid, fmt.Sprintf("transaction %s in layer %s was pruned", id, lid))
+			return "", nil
+		} else if errors.Is(err, sql.ErrNotFound) {
+			return fmt.Sprintf("transaction %s from applied block %s is missing", id, expected), nil
+		} else if err != nil {
+			return "", err
+		}
+		txs = append(txs, &tx.Transaction)
+	}
+	rewardByMiner := map[types.Address]uint64{}
+	for _, r := range block.Rewards {
+		rewardByMiner[r.Address] += r.Amount
+	}
+	if _, err := v.state.ApplyLayer(lid, txs, rewardByMiner); err != nil {
+		return "", err
+	}
+	root, err := v.state.GetLayerStateRoot(lid)
+	if err != nil {
+		return "", err
+	}
+	stored, err := layers.GetStateHash(v.db, lid)
+	if errors.Is(err, sql.ErrNotFound) {
+		return fmt.Sprintf("state root is missing, replayed %s", root.ShortString()), nil
+	} else if err != nil {
+		return "", err
+	}
+	if stored != root {
+		return fmt.Sprintf("state root %s, replayed %s", stored.ShortString(), root.ShortString()), nil
+	}
+	v.replayed = lid
+	return "", nil
+}
+
+func (v *verifier) stopReplay(lid types.LayerID, reason string) {
+	v.logger.With().Info("state replay stopped", lid, log.String("reason", reason))
+	v.replay = false
+	v.replayStopped = reason
+}
@@ -38,8 +38,8 @@ func AddCommands(cmd *cobra.Command) {
 		config.OracleServer, "The oracle server url. (temporary) ")
 	cmd.PersistentFlags().IntVar(&config.OracleServerWorldID, "oracle_server_worldid",
 		config.OracleServerWorldID, "The worldid to use with the oracle server (temporary) ")
-	cmd.PersistentFlags().StringVar(&config.PoETServer, "poet-server",
-		config.PoETServer, "The poet server url. (temporary) ")
+	cmd.PersistentFlags().StringSliceVar(&config.PoETServers, "poet-server",
+		config.PoETServers, "The poet server urls. The challenge is submitted to all of them. (temporary) ")
 	cmd.PersistentFlags().StringVar(&config.GenesisTime, "genesis-time",
 		config.GenesisTime, "Time of the genesis layer in 2019-13-02T17:02:00+00:00 format")
 	cmd.PersistentFlags().IntVar(&config.LayerDurationSec, "layer-duration-sec",
@@ -52,6 +52,8 @@ func AddCommands(cmd *cobra.Command) {
 		config.GoldenATXID, "golden ATX hash")
 	cmd.PersistentFlags().IntVar(&config.BlockCacheSize, "block-cache-size",
 		config.BlockCacheSize, "size in layers of meshdb block cache")
+	cmd.PersistentFlags().Uint32Var(&config.PruneRetentionEpochs, "prune-retention-epochs",
+		config.PruneRetentionEpochs, "number of epochs to keep ballots, blocks and transactions for; 0 keeps everything")
 	cmd.PersistentFlags().StringVar(&config.PublishEventsURL, "events-url",
 		config.PublishEventsURL, "publish events to this url; if no url specified no events will be published")
 	cmd.PersistentFlags().StringVar(&config.ProfilerURL, "profiler-url",
@@ -153,6 +155,20 @@ func AddCommands(cmd *cobra.Command) {
 		config.HARE.LimitIterations, "The limit of the number of iteration per consensus process")
 	cmd.PersistentFlags().IntVar(&config.HARE.LimitConcurrent, "hare-limit-concurrent",
 		config.HARE.LimitConcurrent, "The number of consensus processes running concurrently")
+	cmd.PersistentFlags().StringVar(&config.HARE.TranscriptDir, "hare-transcript-dir",
+		config.HARE.TranscriptDir, "Directory for transcripts of consensus processes. Transcripts are not recorded if empty")
+	cmd.PersistentFlags().IntVar(&config.HARE.MinRoundDuration, "hare-min-round-duration-sec",
+		config.HARE.MinRoundDuration, "The lower bound of the suggested and applied hare round duration")
+	cmd.PersistentFlags().IntVar(&config.HARE.MaxRoundDuration, "hare-max-round-duration-sec",
+		config.HARE.MaxRoundDuration, "The upper bound of the suggested and applied hare round duration")
+	cmd.PersistentFlags().BoolVar(&config.HARE.AdaptiveTiming, "hare-adaptive-timing",
+		config.HARE.AdaptiveTiming, "Switch hare rounds to the timing agreed by the network at the start of the timing epoch")
+	cmd.PersistentFlags().Uint32Var(&config.HARE.TimingEpoch, "hare-timing-epoch",
+		config.HARE.TimingEpoch, "The epoch that starts using the agreed hare timing")
+	cmd.PersistentFlags().IntVar(&config.HARE.TimingRoundDuration, "hare-timing-round-duration-sec",
+		config.HARE.TimingRoundDuration, "The agreed hare round duration")
+	cmd.PersistentFlags().IntVar(&config.HARE.TimingWakeupDelta, "hare-timing-wakeup-delta",
+		config.HARE.TimingWakeupDelta, "The agreed hare wakeup delta")
 
 	/**======================== Hare Eligibility Oracle Flags ========================== **/
 
@@ -185,12 +201,20 @@ func AddCommands(cmd *cobra.Command) {
 		config.Beacon.VotesLimit, "Maximum allowed number of votes to be sent")
 	cmd.PersistentFlags().Uint32Var(&config.Beacon.BeaconSyncNumBallots, "beacon-sync-num-blocks",
 		config.Beacon.BeaconSyncNumBallots, "Numbers of blocks to wait before determining beacon values from them.")
+	cmd.PersistentFlags().IntVar(&config.Beacon.BeaconSyncNumPeers, "beacon-sync-num-peers",
+		config.Beacon.BeaconSyncNumPeers, "Number of peers to request the beacon from when the node missed the beacon protocol.")
+	cmd.PersistentFlags().Var((*types.RatVar)(config.Beacon.BeaconSyncWeightFraction), "beacon-sync-weight-fraction",
+		"Fraction of the ballot weight reported by peers that must agree on the beacon to accept it.")
 
 	/**======================== Tortoise Flags ========================== **/
 	cmd.PersistentFlags().Uint32Var(&config.Tortoise.Hdist, "tortoise-hdist",
 		config.Tortoise.Hdist, "hdist")
 	cmd.PersistentFlags().DurationVar(&config.Tortoise.RerunInterval, "tortoise-rerun-interval",
 		config.Tortoise.RerunInterval, "Tortoise will verify layers from scratch every interval.")
+	cmd.PersistentFlags().Uint32Var(&config.Tortoise.CheckpointInterval, "tortoise-checkpoint-interval",
+		config.Tortoise.CheckpointInterval, "Number of processed layers between checkpoints of the tortoise state. Zero disables checkpoints.")
+	cmd.PersistentFlags().StringVar(&config.Tortoise.VoteCounter, "tortoise-vote-counter",
+		config.Tortoise.VoteCounter, "Backend for counting votes by full tortoise: recount or incremental.")
 
 	// TODO(moshababo): add usage desc
 
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+const (
+	// MultipleBallots is a type of the proof with two different ballots signed
+	// by the same identity for the same layer.
+	MultipleBallots uint8 = iota + 1
+	// HareEquivocation is a type of the proof with two different hare messages signed
+	// by the same identity for the same layer and round.
+	HareEquivocation
+)
+
+// ErrInvalidProof is returned if malfeasance proof doesn't prove that identity is malicious.
+var ErrInvalidProof = errors.New("invalid malfeasance proof")
+
+// MalfeasanceProof proves that the identity signed conflicting messages.
+type MalfeasanceProof struct {
+	// Layer of the conflicting messages. Identity is treated as malicious starting from this layer.
+	Layer LayerID
+	Type  uint8
+	// Ballots are two signed ballots from the same layer. Set for MultipleBallots proof.
+	Ballots []Ballot
+	// Messages are two signed hare messages from the same layer and round. Set for HareEquivocation proof.
+	Messages []HareProofMsg
+}
+
+// HareMetadata is the part of the hare message that is signed by the sender.
+// It commits to the content of the message with the hash.
+type HareMetadata struct {
+	Layer   LayerID
+	Round   uint32
+	MsgHash Hash32
+}
+
+// Bytes returns the encoded metadata.
+func (m *HareMetadata) Bytes() []byte {
+	buf, err := codec.Encode(m)
+	if err != nil {
+		log.With().Panic("failed to encode hare metadata", log.Err(err))
+	}
+	return buf
+}
+
+// HareProofMsg is the signed metadata of the hare message.
+type HareProofMsg struct {
+	InnerMsg  HareMetadata
+	Signature []byte
+}
+
+// SignedBy extracts the public key of the identity that signed the message.
+func (m *HareProofMsg) SignedBy() (*signing.PublicKey, error) {
+	pub, err := signing.NewEDVerifier().Extract(m.InnerMsg.Bytes(), m.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	return pub, nil
+}
+
+// Verify checks that conflicting messages are signed by the same identity and returns that identity.
+// Ballots in the proof are initialized as a side effect.
+func (p *MalfeasanceProof) Verify() (*signing.PublicKey, error) {
+	switch p.Type {
+	case MultipleBallots:
+		return p.verifyBallots()
+	case HareEquivocation:
+		return p.verifyHareMessages()
+	default:
+		return nil, fmt.Errorf("%w: unknown type %d", ErrInvalidProof, p.Type)
+	}
+}
+
+func (p *MalfeasanceProof) verifyBallots() (*signing.PublicKey, error) {
+	if len(p.Ballots) != 2 {
+		return nil, fmt.Errorf("%w: expected 2 ballots, got %d", ErrInvalidProof, len(p.Ballots))
+	}
+	for i := range p.Ballots {
+		ballot := &p.Ballots[i]
+		if ballot.LayerIndex != p.Layer {
+			return nil, fmt.Errorf("%w: ballot layer %s doesn't match proof layer %s",
+				ErrInvalidProof, ballot.LayerIndex, p.Layer)
+		}
+		if ballot.ID() == EmptyBallotID {
+			if err := ballot.Initialize(); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+			}
+		}
+	}
+	first, second := &p.Ballots[0], &p.Ballots[1]
+	if first.ID() == second.ID() {
+		return nil, fmt.Errorf("%w: same ballot %s", ErrInvalidProof, first.ID())
+	}
+	if !first.SmesherID().Equals(second.SmesherID()) {
+		return nil, fmt.Errorf("%w: ballots signed by different identities", ErrInvalidProof)
+	}
+	return first.SmesherID(), nil
+}
+
+func (p *MalfeasanceProof) verifyHareMessages() (*signing.PublicKey, error) {
+	if len(p.Messages) != 2 {
+		return nil, fmt.Errorf("%w: expected 2 hare messages, got %d", ErrInvalidProof, len(p.Messages))
+	}
+	first, second := &p.Messages[0], &p.Messages[1]
+	for _, msg := range p.Messages {
+		if msg.InnerMsg.Layer != p.Layer {
+			return nil, fmt.Errorf("%w: hare message layer %s doesn't match proof layer %s",
+				ErrInvalidProof, msg.InnerMsg.Layer, p.Layer)
+		}
+	}
+	if first.InnerMsg.Round != second.InnerMsg.Round {
+		return nil, fmt.Errorf("%w: hare messages from different rounds %d and %d",
+			ErrInvalidProof, first.InnerMsg.Round, second.InnerMsg.Round)
+	}
+	if first.InnerMsg.MsgHash == second.InnerMsg.MsgHash {
+		return nil, fmt.Errorf("%w: same hare message %s", ErrInvalidProof, first.InnerMsg.MsgHash.ShortString())
+	}
+	pub, err := first.SignedBy()
+	if err != nil {
+		return nil, err
+	}
+	other, err := second.SignedBy()
+	if err != nil {
+		return nil, err
+	}
+	if !pub.Equals(other) {
+		return nil, fmt.Errorf("%w: hare messages signed by different identities", ErrInvalidProof)
+	}
+	return pub, nil
+}
+
+// MarshalLogObject implements logging encoder for MalfeasanceProof.
+func (p *MalfeasanceProof) MarshalLogObject(encoder log.ObjectEncoder) error {
+	encoder.AddUint32("layer_id", p.Layer.Value)
+	encoder.AddUint8("type", p.Type)
+	for i := range p.Ballots {
+		encoder.AddString(fmt.Sprintf("ballot_%d", i), p.Ballots[i].ID().String())
+	}
+	if len(p.Messages) > 0 {
+		encoder.AddUint32("round", p.Messages[0].InnerMsg.Round)
+	}
+	for i := range p.Messages {
+		encoder.AddString(fmt.Sprintf("hare_msg_%d", i), p.Messages[i].InnerMsg.MsgHash.ShortString())
+	}
+	return nil
+}
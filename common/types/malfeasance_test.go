@@ -0,0 +1,178 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+func signedBallot(signer *signing.EdSigner, lid LayerID) Ballot {
+	b := Ballot{
+		InnerBallot: InnerBallot{
+			AtxID:      RandomATXID(),
+			Votes:      Votes{Base: RandomBallotID()},
+			RefBallot:  RandomBallotID(),
+			LayerIndex: lid,
+		},
+	}
+	b.Signature = signer.Sign(b.Bytes())
+	return b
+}
+
+func TestMalfeasanceProof_Verify(t *testing.T) {
+	lid := NewLayerID(10)
+	signer := signing.NewEdSigner()
+	for _, tc := range []struct {
+		desc  string
+		proof MalfeasanceProof
+		err   error
+	}{
+		{
+			desc: "valid",
+			proof: MalfeasanceProof{
+				Layer:   lid,
+				Type:    MultipleBallots,
+				Ballots: []Ballot{signedBallot(signer, lid), signedBallot(signer, lid)},
+			},
+		},
+		{
+			desc: "unknown type",
+			proof: MalfeasanceProof{
+				Layer:   lid,
+				Ballots: []Ballot{signedBallot(signer, lid), signedBallot(signer, lid)},
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "single ballot",
+			proof: MalfeasanceProof{
+				Layer:   lid,
+				Type:    MultipleBallots,
+				Ballots: []Ballot{signedBallot(signer, lid)},
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "different layers",
+			proof: MalfeasanceProof{
+				Layer:   lid,
+				Type:    MultipleBallots,
+				Ballots: []Ballot{signedBallot(signer, lid), signedBallot(signer, lid.Add(1))},
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "different identities",
+			proof: MalfeasanceProof{
+				Layer:   lid,
+				Type:    MultipleBallots,
+				Ballots: []Ballot{signedBallot(signer, lid), signedBallot(signing.NewEdSigner(), lid)},
+			},
+			err: ErrInvalidProof,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			buf, err := codec.Encode(&tc.proof)
+			require.NoError(t, err)
+			var decoded MalfeasanceProof
+			require.NoError(t, codec.Decode(buf, &decoded))
+
+			smesher, err := decoded.Verify()
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, signer.PublicKey(), smesher)
+		})
+	}
+
+	t.Run("same ballot", func(t *testing.T) {
+		ballot := signedBallot(signer, lid)
+		proof := MalfeasanceProof{Layer: lid, Type: MultipleBallots, Ballots: []Ballot{ballot, ballot}}
+		_, err := proof.Verify()
+		require.ErrorIs(t, err, ErrInvalidProof)
+	})
+}
+
+func signedHareMsg(signer *signing.EdSigner, lid LayerID, round uint32, content []byte) HareProofMsg {
+	msg := HareProofMsg{InnerMsg: HareMetadata{Layer: lid, Round: round, MsgHash: CalcHash32(content)}}
+	msg.Signature = signer.Sign(msg.InnerMsg.Bytes())
+	return msg
+}
+
+func TestMalfeasanceProof_VerifyHareEquivocation(t *testing.T) {
+	lid := NewLayerID(10)
+	signer := signing.NewEdSigner()
+	for _, tc := range []struct {
+		desc     string
+		messages []HareProofMsg
+		err      error
+	}{
+		{
+			desc: "valid",
+			messages: []HareProofMsg{
+				signedHareMsg(signer, lid, 3, []byte{1}),
+				signedHareMsg(signer, lid, 3, []byte{2}),
+			},
+		},
+		{
+			desc:     "single message",
+			messages: []HareProofMsg{signedHareMsg(signer, lid, 3, []byte{1})},
+			err:      ErrInvalidProof,
+		},
+		{
+			desc: "same message",
+			messages: []HareProofMsg{
+				signedHareMsg(signer, lid, 3, []byte{1}),
+				signedHareMsg(signer, lid, 3, []byte{1}),
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "different rounds",
+			messages: []HareProofMsg{
+				signedHareMsg(signer, lid, 3, []byte{1}),
+				signedHareMsg(signer, lid, 4, []byte{2}),
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "different layers",
+			messages: []HareProofMsg{
+				signedHareMsg(signer, lid, 3, []byte{1}),
+				signedHareMsg(signer, lid.Add(1), 3, []byte{2}),
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			desc: "different identities",
+			messages: []HareProofMsg{
+				signedHareMsg(signer, lid, 3, []byte{1}),
+				signedHareMsg(signing.NewEdSigner(), lid, 3, []byte{2}),
+			},
+			err: ErrInvalidProof,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			proof := MalfeasanceProof{Layer: lid, Type: HareEquivocation, Messages: tc.messages}
+			buf, err := codec.Encode(&proof)
+			require.NoError(t, err)
+			var decoded MalfeasanceProof
+			require.NoError(t, codec.Decode(buf, &decoded))
+
+			smesher, err := decoded.Verify()
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, signer.PublicKey(), smesher)
+		})
+	}
+}
@@ -189,6 +189,7 @@ type Reward struct {
 	Layer               LayerID
 	TotalReward         uint64
 	LayerRewardEstimate uint64
+	FeeReward           uint64
 	SmesherID           NodeID
 	Coinbase            Address
 }
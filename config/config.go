@@ -80,7 +80,7 @@ type BaseConfig struct {
 	LayerAvgSize     int    `mapstructure:"layer-average-size"`
 	LayersPerEpoch   uint32 `mapstructure:"layers-per-epoch"`
 
-	PoETServer string `mapstructure:"poet-server"`
+	PoETServers []string `mapstructure:"poet-server"`
 
 	PprofHTTPServer bool `mapstructure:"pprof-server"`
 
@@ -98,6 +98,11 @@ type BaseConfig struct {
 
 	BlockCacheSize int `mapstructure:"block-cache-size"`
 
+	// PruneRetentionEpochs is a number of epochs for which ballots, blocks and transactions
+	// bodies are kept behind the layer applied to the state, or the start of the tortoise window
+	// if it is older. Zero disables pruning.
+	PruneRetentionEpochs uint32 `mapstructure:"prune-retention-epochs"`
+
 	AlwaysListen bool `mapstructure:"always-listen"` // force gossip to always be on (for testing)
 }
 
@@ -153,7 +158,7 @@ func defaultBaseConfig() BaseConfig {
 		GenesisTime:         time.Now().Format(time.RFC3339),
 		LayerDurationSec:    30,
 		LayersPerEpoch:      3,
-		PoETServer:          "127.0.0.1",
+		PoETServers:         []string{"127.0.0.1"},
 		GoldenATXID:         "0x5678", // TODO: Change the value
 		BlockCacheSize:      20,
 		SyncRequestTimeout:  2000,
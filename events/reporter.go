@@ -253,6 +253,35 @@ func ReportAccountUpdate(a types.Address) {
 	}
 }
 
+// ReportReorg reports blocks and transactions affected by the state reversion.
+func ReportReorg(r Reorg) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		if err := reporter.reorgEmitter.Emit(r); err != nil {
+			// TODO(nkryuchkov): consider returning an error and log outside the function
+			log.With().Error("Failed to emit reorg", r, log.Err(err))
+		} else {
+			log.With().Debug("reported reorg", r)
+		}
+	}
+}
+
+// ReportBeaconProgress reports the progress of the beacon protocol.
+func ReportBeaconProgress(p BeaconProgress) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		if err := reporter.beaconEmitter.Emit(p); err != nil {
+			log.With().Error("Failed to emit beacon progress", p, log.Err(err))
+		} else {
+			log.With().Debug("reported beacon progress", p)
+		}
+	}
+}
+
 // SubscribeTxs subscribes to new transactions.
 func SubscribeTxs() event.Subscription {
 	mu.RLock()
@@ -381,6 +410,38 @@ func SubscribeReceipts() event.Subscription {
 	return nil
 }
 
+// SubscribeReorgs subscribes to state reversions.
+func SubscribeReorgs() event.Subscription {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		sub, err := reporter.bus.Subscribe(new(Reorg))
+		if err != nil {
+			log.With().Panic("Failed to subscribe to reorgs")
+		}
+
+		return sub
+	}
+	return nil
+}
+
+// SubscribeBeaconProgress subscribes to the progress of the beacon protocol.
+func SubscribeBeaconProgress() event.Subscription {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		sub, err := reporter.bus.Subscribe(new(BeaconProgress))
+		if err != nil {
+			log.With().Panic("Failed to subscribe to beacon progress")
+		}
+
+		return sub
+	}
+	return nil
+}
+
 // InitializeEventReporter initializes the event reporting interface.
 func InitializeEventReporter(url string) error {
 	// By default use zero-buffer channels and non-blocking.
@@ -478,6 +539,53 @@ type Reward struct {
 	Smesher types.NodeID
 }
 
+// Reorg describes changes to the applied state after tortoise changed validity of blocks
+// in layers that were already applied. It is reported when the state is reverted.
+type Reorg struct {
+	// RevertTo is the last layer that was not reverted.
+	RevertTo types.LayerID
+	// Invalid blocks were applied before the state was reverted and are not valid anymore.
+	Invalid []types.BlockID
+	// Valid blocks were not applied before the state was reverted and will be applied instead.
+	Valid []types.BlockID
+	// Reinserted transactions are included in invalid blocks and not in valid blocks,
+	// and are returned to the mempool.
+	Reinserted []types.TransactionID
+}
+
+// Field returns a log field. Implements the LoggableField interface.
+func (r Reorg) Field() log.Field {
+	return log.String("reorg", fmt.Sprintf("revert to: %d, invalid: %d, valid: %d, reinserted: %d",
+		r.RevertTo, len(r.Invalid), len(r.Valid), len(r.Reinserted)))
+}
+
+// BeaconProgress describes the progress of the beacon protocol in the epoch.
+type BeaconProgress struct {
+	Epoch types.EpochID
+	// Phase is one of: idle, proposal, first voting, following voting, weak coin or finished.
+	Phase string
+	Round types.RoundID
+	// ValidProposals and PotentiallyValidProposals are the number of proposals received in the epoch.
+	ValidProposals            int
+	PotentiallyValidProposals int
+	// Votes is the number of votes received in each round.
+	Votes []uint32
+	// OwnVotes is the number of rounds in which the node sent its votes.
+	OwnVotes uint32
+	// ProposalSent is true if the node was eligible to send the proposal, Proposal explains
+	// why the node didn't send it otherwise.
+	ProposalSent bool
+	Proposal     string
+	// Beacon is the beacon calculated in the epoch. It is set when the phase is finished.
+	Beacon types.Beacon
+}
+
+// Field returns a log field. Implements the LoggableField interface.
+func (p BeaconProgress) Field() log.Field {
+	return log.String("beacon_progress", fmt.Sprintf("epoch: %d, phase: %s, round: %d, proposals: %d/%d, own votes: %d",
+		p.Epoch, p.Phase, p.Round, p.ValidProposals, p.PotentiallyValidProposals, p.OwnVotes))
+}
+
 // Transaction wraps a tx with its layer ID and validity info.
 type Transaction struct {
 	Transaction *types.Transaction
@@ -509,6 +617,8 @@ type EventReporter struct {
 	accountEmitter     event.Emitter
 	rewardEmitter      event.Emitter
 	receiptEmitter     event.Emitter
+	reorgEmitter       event.Emitter
+	beaconEmitter      event.Emitter
 	channelReward      chan Reward
 	stopChan           chan struct{}
 }
@@ -551,6 +661,16 @@ func newEventReporter() *EventReporter {
 		log.With().Panic("failed to create receipt emitter", log.Err(err))
 	}
 
+	reorgEmitter, err := bus.Emitter(new(Reorg))
+	if err != nil {
+		log.With().Panic("failed to create reorg emitter", log.Err(err))
+	}
+
+	beaconEmitter, err := bus.Emitter(new(BeaconProgress))
+	if err != nil {
+		log.With().Panic("failed to create beacon emitter", log.Err(err))
+	}
+
 	errorEmitter, err := bus.Emitter(new(NodeError))
 	if err != nil {
 		log.With().Panic("failed to create error emitter", log.Err(err))
@@ -565,6 +685,8 @@ func newEventReporter() *EventReporter {
 		accountEmitter:     accountEmitter,
 		rewardEmitter:      rewardEmitter,
 		receiptEmitter:     receiptEmitter,
+		reorgEmitter:       reorgEmitter,
+		beaconEmitter:      beaconEmitter,
 		errorEmitter:       errorEmitter,
 		stopChan:           make(chan struct{}),
 	}
@@ -599,6 +721,12 @@ func CloseEventReporter() {
 		if err := reporter.receiptEmitter.Close(); err != nil {
 			log.With().Panic("failed to close receiptEmitter: " + err.Error())
 		}
+		if err := reporter.reorgEmitter.Close(); err != nil {
+			log.With().Panic("failed to close reorgEmitter: " + err.Error())
+		}
+		if err := reporter.beaconEmitter.Close(); err != nil {
+			log.With().Panic("failed to close beaconEmitter: " + err.Error())
+		}
 		close(reporter.stopChan)
 		reporter = nil
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/hare/metrics"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/signing"
@@ -23,7 +25,9 @@ import (
 const (
 	// RoundsPerIteration is the number of rounds per iteration in the hare protocol.
 	RoundsPerIteration = 4
-	protoName          = "HARE_PROTOCOL"
+	// protoName is versioned since messages are signed over HareMetadata, so that nodes that sign
+	// the inner message don't exchange messages with nodes that sign the metadata.
+	protoName = "HARE_PROTOCOL/2"
 )
 
 type role byte
@@ -47,10 +51,11 @@ const (
 
 // procReport is the termination report of the CP.
 type procReport struct {
-	id        types.LayerID // layer id
-	set       *Set          // agreed-upon set
-	coinflip  bool          // weak coin value
-	completed bool          // whether the CP completed
+	id            types.LayerID // layer id
+	set           *Set          // agreed-upon set
+	coinflip      bool          // weak coin value
+	completed     bool          // whether the CP completed
+	notifications []*Message    // notify messages that justify the agreed-upon set
 }
 
 func (cpo procReport) ID() types.LayerID {
@@ -69,12 +74,49 @@ func (cpo procReport) Completed() bool {
 	return cpo.completed
 }
 
+func (cpo procReport) Notifications() []*Message {
+	return cpo.notifications
+}
+
 func (proc *consensusProcess) report(completed bool) {
-	proc.terminationReport <- procReport{proc.instanceID, proc.s, proc.preRoundTracker.coinflip, completed}
+	proc.transcript.output(proc.getK(), completed, proc.preRoundTracker.coinflip, proc.s)
+	metrics.IterationsToTermination.WithLabelValues(strconv.FormatBool(completed)).Observe(float64(proc.iterations()))
+	var notifications []*Message
+	if completed {
+		notifications = copyMessages(proc.notifyTracker.BuildCertificate(proc.s).AggMsgs.Messages)
+	}
+	proc.terminationReport <- procReport{proc.instanceID, proc.s, proc.preRoundTracker.coinflip, completed, notifications}
 }
 
 var _ TerminationOutput = (*procReport)(nil)
 
+// certReport is the certification report of the CP.
+type certReport struct {
+	id             types.LayerID // layer id
+	certifications []*Message    // certify messages that certify the agreed-upon set
+}
+
+func (cr certReport) ID() types.LayerID {
+	return cr.id
+}
+
+func (cr certReport) Certifications() []*Message {
+	return cr.certifications
+}
+
+var _ CertificationOutput = (*certReport)(nil)
+
+// copyMessages copies messages and their inner messages, so that the copies can be passed to another
+// goroutine while the consensus process keeps modifying the originals.
+func copyMessages(msgs []*Message) []*Message {
+	copied := make([]*Message, 0, len(msgs))
+	for _, m := range msgs {
+		inner := *m.InnerMsg
+		copied = append(copied, &Message{Sig: m.Sig, InnerMsg: &inner})
+	}
+	return copied
+}
+
 // State holds the current state of the consensus process (aka the participant).
 type State struct {
 	k           uint32       // the round counter (k%4 is the round number); it should be first in struct for alignment because atomics are used
@@ -112,7 +154,7 @@ func newMsg(ctx context.Context, logger log.Log, hareMsg *Message, querier state
 	logger = logger.WithContext(ctx)
 
 	// extract pub key
-	pubKey, err := ed25519.ExtractPublicKey(hareMsg.InnerMsg.Bytes(), hareMsg.Sig)
+	pubKey, err := ed25519.ExtractPublicKey(hareMsg.InnerMsg.signedBytes(), hareMsg.Sig)
 	if err != nil {
 		logger.With().Error("newmsg construction failed: could not extract public key",
 			log.Err(err),
@@ -170,7 +212,7 @@ type consensusProcess struct {
 	isStarted           bool
 	inbox               chan *Msg
 	terminationReport   chan TerminationOutput
-	certificationReport chan types.LayerID
+	certificationReport chan CertificationOutput
 	validator           messageValidator
 	preRoundTracker     *preRoundTracker
 	statusesTracker     *statusTracker
@@ -179,21 +221,25 @@ type consensusProcess struct {
 	notifyTracker       *notifyTracker
 	certifyTracker      *certifyTracker
 	cfg                 config.Config
-	pending             map[string]*Msg // buffer for early messages that are pending process
-	notifySent          bool            // flag to set in case a notification had already been sent by this instance
-	mTracker            *msgsTracker    // tracks valid messages
+	pending             map[string]*Msg      // buffer for early messages that are pending process
+	notifySent          bool                 // flag to set in case a notification had already been sent by this instance
+	mTracker            *msgsTracker         // tracks valid messages
+	eTracker            *equivocationTracker // detects senders that signed conflicting messages
+	malfeasance         malfeasanceReporter  // reports senders that equivocated if not nil
 	terminating         bool
 	certified           bool
 	completed           bool
 	eligibilityCount    uint16
 	clock               RoundClock
+	transcript          *transcript    // records messages, decisions and outputs if not nil
+	lastStatus          InstanceStatus // snapshot of the state, protected by mu
 }
 
 // newConsensusProcess creates a new consensus process instance.
 func newConsensusProcess(cfg config.Config, instanceID types.LayerID, s *Set, oracle Rolacle, stateQuerier stateQuerier,
 	layersPerEpoch uint16, signing Signer, nid types.NodeID, p2p pubsub.Publisher,
 	terminationReport chan TerminationOutput,
-	certificationReport chan types.LayerID,
+	certificationReport chan CertificationOutput,
 	ev roleValidator, clock RoundClock, logger log.Log) *consensusProcess {
 	msgsTracker := newMsgsTracker()
 	proc := &consensusProcess{
@@ -213,6 +259,7 @@ func newConsensusProcess(cfg config.Config, instanceID types.LayerID, s *Set, or
 		pending:             make(map[string]*Msg, cfg.N),
 		Log:                 logger,
 		mTracker:            msgsTracker,
+		eTracker:            newEquivocationTracker(instanceID, cfg.N),
 		clock:               clock,
 	}
 	proc.validator = newSyntaxContextValidator(signing, cfg.F+1, proc.statusValidator(), stateQuerier, layersPerEpoch, ev, msgsTracker, logger)
@@ -274,6 +321,9 @@ func (proc *consensusProcess) eventLoop(ctx context.Context) {
 		log.Int("exp_leaders", proc.cfg.ExpectedLeaders),
 		log.String("current_set", proc.s.String()),
 		log.Int("set_size", proc.s.Size()))
+	defer proc.transcript.close()
+	proc.updateStatus(false)
+	defer proc.updateStatus(true)
 
 	// check participation and send message
 	go func() {
@@ -301,8 +351,11 @@ PreRound:
 		select {
 		// listen to pre-round Messages
 		case msg := <-proc.inbox:
+			proc.transcript.message(proc.getK(), msg)
 			proc.handleMessage(ctx, msg)
+			proc.updateStatus(false)
 		case <-endOfRound:
+			proc.transcript.roundEnd(proc.getK())
 			break PreRound
 		case <-proc.CloseChannel():
 			logger.With().Info("terminating during preround: received termination signal",
@@ -330,6 +383,7 @@ PreRound:
 
 	// start first iteration
 	proc.onRoundBegin(ctx)
+	proc.updateStatus(false)
 	endOfRound = proc.clock.AwaitEndOfRound(proc.getK())
 
 	defer func() {
@@ -341,8 +395,9 @@ PreRound:
 	for {
 		select {
 		case msg := <-proc.inbox: // msg event
-
+			proc.transcript.message(proc.getK(), msg)
 			proc.handleMessage(ctx, msg)
+			proc.updateStatus(false)
 
 			if proc.terminating || (proc.completed && proc.certified) {
 				// TODO: possible error close closed channel
@@ -351,7 +406,7 @@ PreRound:
 			}
 
 		case <-endOfRound: // next round event
-
+			proc.transcript.roundEnd(proc.getK())
 			proc.onRoundEnd(ctx)
 
 			if proc.terminating || (proc.completed && proc.certified) || proc.getK() == certifyRound {
@@ -374,6 +429,7 @@ PreRound:
 			}
 
 			proc.onRoundBegin(ctx)
+			proc.updateStatus(false)
 			endOfRound = proc.clock.AwaitEndOfRound(k)
 
 		case <-proc.CloseChannel(): // close event
@@ -445,6 +501,9 @@ func (proc *consensusProcess) handleMessage(ctx context.Context, m *Msg) {
 				logger.Warning("early message failed syntactic validation, discarding")
 				return
 			}
+			if !proc.checkEquivocation(ctx, m) {
+				return
+			}
 
 			proc.onEarlyMessage(ctx, m)
 			return
@@ -452,6 +511,9 @@ func (proc *consensusProcess) handleMessage(ctx context.Context, m *Msg) {
 
 		// not an early message but also contextually invalid
 		logger.With().Warning("late message failed contextual validation, discarding", log.Err(err))
+		if m.InnerMsg.K < proc.getK() {
+			metrics.LateMessages.WithLabelValues(m.InnerMsg.Type.String()).Inc()
+		}
 		return
 	}
 
@@ -461,15 +523,37 @@ func (proc *consensusProcess) handleMessage(ctx context.Context, m *Msg) {
 		return
 	}
 
+	if !proc.checkEquivocation(ctx, m) {
+		return
+	}
+
 	// warn on late pre-round msgs
 	if m.InnerMsg.Type == pre && proc.getK() != preRound {
 		logger.Warning("encountered late preround message")
+		metrics.LateMessages.WithLabelValues(m.InnerMsg.Type.String()).Inc()
 	}
 
 	// valid, continue to process msg by type
+	metrics.MessageTypeCounter.WithLabelValues(m.InnerMsg.Type.String()).Inc()
+	metrics.EligibilityCount.WithLabelValues(m.InnerMsg.Type.String()).Observe(float64(m.InnerMsg.EligibilityCount))
 	proc.processMsg(ctx, m)
 }
 
+// checkEquivocation reports the sender if it signed a different message in the same round.
+// It returns false if the sender is proven to be malicious and the message should be discarded.
+func (proc *consensusProcess) checkEquivocation(ctx context.Context, m *Msg) bool {
+	if proof := proc.eTracker.Track(m); proof != nil {
+		logger := proc.WithContext(ctx).WithFields(log.FieldNamed("sender_id", m.PubKey), log.Inline(proof))
+		logger.Warning("sender signed conflicting messages in the same round")
+		if proc.malfeasance != nil {
+			if err := proc.malfeasance.Report(ctx, proof); err != nil {
+				logger.With().Error("failed to report malfeasance proof", log.Err(err))
+			}
+		}
+	}
+	return !proc.eTracker.IsProven(m)
+}
+
 // process the message by its type.
 func (proc *consensusProcess) processMsg(ctx context.Context, m *Msg) {
 	proc.WithContext(ctx).With().Debug("processing message",
@@ -520,6 +604,7 @@ func (proc *consensusProcess) sendMessage(ctx context.Context, msg *Msg) bool {
 		logger.With().Error("could not broadcast round message", log.Err(err))
 		return false
 	}
+	proc.transcript.publish(proc.getK(), msg)
 
 	logger.Info("should participate: message sent")
 	return true
@@ -726,6 +811,7 @@ func (proc *consensusProcess) onRoundBegin(ctx context.Context) {
 	// handle pending messages
 	pendingProcess := proc.pending
 	proc.pending = make(map[string]*Msg, proc.cfg.N)
+	proc.transcript.setPending(pendingProcess)
 	go proc.handlePending(pendingProcess)
 }
 
@@ -852,7 +938,8 @@ func (proc *consensusProcess) processCertificationMsg(ctx context.Context, msg *
 		proc.instanceID)
 	if !proc.certified && proc.certifyTracker.OnCertify(msg) {
 		proc.certified = true
-		proc.certificationReport <- proc.instanceID
+		proc.transcript.certified(proc.getK())
+		proc.certificationReport <- certReport{proc.instanceID, copyMessages(proc.certifyTracker.messages)}
 		proc.WithContext(ctx).Event().Info("hare certification completed", proc.instanceID)
 		// TODO: we can really terminate HARE right now even if it's not successful
 	}
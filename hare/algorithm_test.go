@@ -288,6 +288,41 @@ func TestConsensusProcess_handleMessage(t *testing.T) {
 	r.Equal(1, len(proc.pending))
 }
 
+func TestConsensusProcess_handleEquivocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	proc := generateConsensusProcess(t)
+	proc.validator = &mockMessageValidator{syntaxValid: true}
+	reporter := mocks.NewMockmalfeasanceReporter(ctrl)
+	proc.malfeasance = reporter
+
+	signer := signing.NewEdSigner()
+	proc.handleMessage(context.TODO(), BuildPreRoundMsg(signer, NewSetFromValues(value1), nil))
+	reporter.EXPECT().Report(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, proof *types.MalfeasanceProof) error {
+			smesher, err := proof.Verify()
+			require.NoError(t, err)
+			require.Equal(t, signer.PublicKey(), smesher)
+			require.Equal(t, proc.instanceID, proof.Layer)
+			return nil
+		})
+	proc.handleMessage(context.TODO(), BuildPreRoundMsg(signer, NewSetFromValues(value2), nil))
+	require.True(t, proc.preRoundTracker.preRound[signer.PublicKey().String()].Equals(NewSetFromValues(value1)),
+		"conflicting message is discarded")
+
+	// messages from the proven sender are discarded in other rounds too
+	proc.statusesTracker = newStatusTracker(proc.cfg.F+1, proc.cfg.N)
+	proc.handleMessage(context.TODO(), BuildStatusMsg(signer, NewSetFromValues(value1)))
+	require.Empty(t, proc.statusesTracker.statuses)
+
+	// conflicting early messages are detected as well
+	other := signing.NewEdSigner()
+	proc.validator = &mockMessageValidator{syntaxValid: true, contextValid: errEarlyMsg}
+	proc.handleMessage(context.TODO(), BuildStatusMsg(other, NewSetFromValues(value1)))
+	reporter.EXPECT().Report(gomock.Any(), gomock.Any()).Return(nil)
+	proc.handleMessage(context.TODO(), BuildStatusMsg(other, NewSetFromValues(value2)))
+	require.Len(t, proc.pending, 1)
+}
+
 func TestConsensusProcess_nextRound(t *testing.T) {
 	broker := buildBroker(t, t.Name())
 	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
@@ -568,13 +603,13 @@ func TestConsensusProcess_onEarlyMessage(t *testing.T) {
 }
 
 func TestProcOutput_Id(t *testing.T) {
-	po := procReport{instanceID1, nil, false, false}
+	po := procReport{instanceID1, nil, false, false, nil}
 	assert.Equal(t, po.ID(), instanceID1)
 }
 
 func TestProcOutput_Set(t *testing.T) {
 	es := NewDefaultEmptySet()
-	po := procReport{instanceID1, es, false, false}
+	po := procReport{instanceID1, es, false, false, nil}
 	assert.True(t, es.Equals(po.Set()))
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 
@@ -29,6 +30,8 @@ type msgRPC struct {
 	Ctx   context.Context
 	Data  []byte
 	Error chan error
+	// Received is the time when the message was received, it is zero for the messages from this node.
+	Received time.Time
 }
 
 // Broker is the dispatcher of incoming Hare messages.
@@ -56,6 +59,7 @@ type Broker struct {
 	stop           context.CancelFunc
 	eventLoopWg    sync.WaitGroup
 	queueMessageWg sync.WaitGroup
+	timing         *roundTiming // records delays of the messages, optional
 }
 
 func newBroker(pid peer.ID, eValidator validator, stateQuerier stateQuerier, syncState system.SyncStateProvider, layersPerEpoch uint16, limit int, closer util.Closer, log log.Log) *Broker {
@@ -181,6 +185,9 @@ func (b *Broker) queueMessage(ctx context.Context, pid p2p.Peer, msg []byte) (*m
 	logger.With().Debug("assigned message priority, writing to priority queue",
 		log.Int("priority", int(priority)))
 	m := &msgRPC{Data: msg, Error: make(chan error, 1), Ctx: ctx}
+	if pid != b.pid {
+		m.Received = time.Now()
+	}
 	if err := b.queue.Write(priority, m); err != nil {
 		logger.With().Error("error writing inbound message to priority queue, dropping", log.Err(err))
 		return nil, fmt.Errorf("write inbound message to priority queue: %w", err)
@@ -278,6 +285,9 @@ func (b *Broker) eventLoop(ctx context.Context) {
 			// validation passed, report
 			msg.Error <- nil
 			msgLogger.With().Debug("broker reported hare message as valid", hareMsg)
+			if b.timing != nil && !msg.Received.IsZero() {
+				b.timing.observe(hareMsg, msg.Received)
+			}
 
 			if isEarly {
 				b.mu.Lock()
@@ -75,6 +75,26 @@ func (im *innerMessage) Bytes() []byte {
 	return buf
 }
 
+// metadata returns the metadata of the message that is signed by the sender.
+func (im *innerMessage) metadata() types.HareMetadata {
+	round := im.K
+	if im.Type == certify {
+		// certify message is sent with the round counter of the notify round, it doesn't conflict with notify message
+		round = certifyRound
+	}
+	return types.HareMetadata{
+		Layer:   im.InstanceID,
+		Round:   round,
+		MsgHash: types.CalcHash32(im.Bytes()),
+	}
+}
+
+// signedBytes returns the bytes that are signed by the sender of the message.
+func (im *innerMessage) signedBytes() []byte {
+	md := im.metadata()
+	return md.Bytes()
+}
+
 func (im *innerMessage) String() string {
 	return fmt.Sprintf("Type: %v InstanceID: %v K: %v Ki: %v", im.Type, im.InstanceID, im.K, im.Ki)
 }
@@ -107,7 +127,7 @@ func (builder *messageBuilder) SetCertificate(certificate *certificate) *message
 
 // Sign calls the provided signer to calculate the signature and then set it accordingly.
 func (builder *messageBuilder) Sign(signing Signer) *messageBuilder {
-	builder.msg.Sig = signing.Sign(builder.inner.Bytes())
+	builder.msg.Sig = signing.Sign(builder.inner.signedBytes())
 
 	return builder
 }
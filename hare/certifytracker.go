@@ -4,6 +4,7 @@ package hare
 // It also provides the number of certifications tracked for a layer.
 type certifyTracker struct {
 	certifiers       map[string]struct{} // tracks PubKey->Certifier weight
+	messages         []*Message          // certify messages of the tracked certifiers
 	minEligibleCount uint32
 	totalCount       uint32
 }
@@ -21,6 +22,7 @@ func (ct *certifyTracker) OnCertify(msg *Msg) bool {
 	pub := msg.PubKey
 	if _, exist := ct.certifiers[pub.String()]; !exist { // already seenSenders
 		ct.certifiers[pub.String()] = struct{}{}
+		ct.messages = append(ct.messages, msg.Message)
 		ct.totalCount += uint32(msg.InnerMsg.EligibilityCount)
 	}
 	return ct.IsCertified()
@@ -10,17 +10,34 @@ type Config struct {
 	SuperHare       bool
 	LimitIterations int `mapstructure:"hare-limit-iterations"` // limit on number of iterations
 	LimitConcurrent int `mapstructure:"hare-limit-concurrent"` // limit number of concurrent CPs
+	// TranscriptDir is a directory where every consensus process writes received messages,
+	// oracle decisions and outputs. Transcripts are not recorded if empty.
+	TranscriptDir string `mapstructure:"hare-transcript-dir"`
+	// MinRoundDuration and MaxRoundDuration bound the round duration that is suggested at the epoch
+	// boundaries according to the delays of the hare messages observed in the previous epoch,
+	// and the round duration that is applied with AdaptiveTiming.
+	MinRoundDuration int `mapstructure:"hare-min-round-duration-sec"`
+	MaxRoundDuration int `mapstructure:"hare-max-round-duration-sec"`
+	// AdaptiveTiming enables switching the rounds to TimingRoundDuration and TimingWakeupDelta
+	// at the start of TimingEpoch. The suggested timing is local to the node, the applied values
+	// must be agreed by the network, otherwise honest nodes disagree on the round clocks.
+	AdaptiveTiming      bool   `mapstructure:"hare-adaptive-timing"`
+	TimingEpoch         uint32 `mapstructure:"hare-timing-epoch"`
+	TimingRoundDuration int    `mapstructure:"hare-timing-round-duration-sec"`
+	TimingWakeupDelta   int    `mapstructure:"hare-timing-wakeup-delta"`
 }
 
 // DefaultConfig returns the default configuration for the hare.
 func DefaultConfig() Config {
 	return Config{
-		N:               10,
-		F:               5,
-		RoundDuration:   10,
-		WakeupDelta:     10,
-		ExpectedLeaders: 5,
-		LimitIterations: 5,
-		LimitConcurrent: 5,
+		N:                10,
+		F:                5,
+		RoundDuration:    10,
+		WakeupDelta:      10,
+		ExpectedLeaders:  5,
+		LimitIterations:  5,
+		LimitConcurrent:  5,
+		MinRoundDuration: 5,
+		MaxRoundDuration: 30,
 	}
 }
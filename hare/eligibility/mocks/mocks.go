@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	signing "github.com/spacemeshos/go-spacemesh/signing"
 )
 
 // Mockcache is a mock of cache interface.
@@ -139,6 +140,21 @@ func (m *MockmeshProvider) EXPECT() *MockmeshProviderMockRecorder {
 	return m.recorder
 }
 
+// IsMaliciousAt mocks base method.
+func (m *MockmeshProvider) IsMaliciousAt(arg0 *signing.PublicKey, arg1 types.LayerID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMaliciousAt", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMaliciousAt indicates an expected call of IsMaliciousAt.
+func (mr *MockmeshProviderMockRecorder) IsMaliciousAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMaliciousAt", reflect.TypeOf((*MockmeshProvider)(nil).IsMaliciousAt), arg0, arg1)
+}
+
 // LayerBallots mocks base method.
 func (m *MockmeshProvider) LayerBallots(arg0 types.LayerID) ([]*types.Ballot, error) {
 	m.ctrl.T.Helper()
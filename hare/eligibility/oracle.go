@@ -12,6 +12,7 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/codec"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/hare/eligibility/config"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/signing"
@@ -46,6 +47,8 @@ type atxProvider interface {
 
 type meshProvider interface {
 	LayerBallots(types.LayerID) ([]*types.Ballot, error)
+	// IsMaliciousAt returns true if identity is proven to be malicious in the layer.
+	IsMaliciousAt(*signing.PublicKey, types.LayerID) (bool, error)
 }
 
 // a function to verify the message with the signature and its public key.
@@ -280,6 +283,16 @@ func (o *Oracle) prepareEligibilityCheck(ctx context.Context, layer types.LayerI
 		return 0, fixed.Fixed{}, fixed.Fixed{}, true, err
 	}
 
+	// identities that are proven to be malicious are excluded from the committees
+	malicious, err := o.meshdb.IsMaliciousAt(signing.NewPublicKey(util.Hex2Bytes(id.Key)), layer)
+	if err != nil {
+		return 0, fixed.Fixed{}, fixed.Fixed{}, true, fmt.Errorf("check malicious identity: %w", err)
+	}
+	if malicious {
+		logger.Info("eligibility: identity is proven to be malicious")
+		return 0, fixed.Fixed{}, fixed.Fixed{}, true, nil
+	}
+
 	logger.With().Debug("preparing eligibility check",
 		log.Uint64("miner_weight", minerWeight),
 		log.Uint64("total_weight", totalWeight),
@@ -34,6 +34,8 @@ type testOracle struct {
 	mBeacon *smocks.MockBeaconGetter
 	mAtxDB  *mocks.MockatxProvider
 	mMesh   *mocks.MockmeshProvider
+	// malicious identities, keyed by the public key
+	malicious map[string]types.LayerID
 }
 
 func defaultOracle(t testing.TB) *testOracle {
@@ -48,7 +50,14 @@ func defaultOracle(t testing.TB) *testOracle {
 		mBeacon: mb,
 		mAtxDB:  ma,
 		mMesh:   mm,
+
+		malicious: map[string]types.LayerID{},
 	}
+	mm.EXPECT().IsMaliciousAt(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(pub *signing.PublicKey, lid types.LayerID) (bool, error) {
+			from, exist := to.malicious[pub.String()]
+			return exist && !lid.Before(from), nil
+		}).AnyTimes()
 	return to
 }
 
@@ -205,6 +214,38 @@ func TestCalcEligibility_EligibleFromHareActiveSet(t *testing.T) {
 	}
 }
 
+func TestCalcEligibility_MaliciousIdentity(t *testing.T) {
+	o := defaultOracle(t)
+	defer o.ctrl.Finish()
+
+	layer := types.NewLayerID(50)
+	beacon := beaconWithValOne()
+	mockLayerBallots(t, o, layer, beacon, 5)
+	o.mBeacon.EXPECT().GetBeacon(layer.GetEpoch()).Return(beacon, nil).AnyTimes()
+	start, _ := safeLayerRange(layer, confidenceParam, defLayersPerEpoch, epochOffset)
+	o.mBeacon.EXPECT().GetBeacon(start.GetEpoch()).Return(beacon, nil).AnyTimes()
+
+	// eligible with count 1 before the identity is proven to be malicious
+	sig := util.Hex2Bytes("0516a574aef37257d6811ea53ef55d4cbb0e14674900a0d5165bd6742513840d02442d979fdabc7059645d1e8f8a0f44d0db2aa90f23374dd74a3636d4ecdab7")
+	nid := types.NodeID{Key: "0"}
+	res, err := o.CalcEligibility(context.TODO(), layer, 1, 10, nid, sig)
+	require.NoError(t, err)
+	require.Equal(t, 1, int(res))
+
+	o.malicious[signing.NewPublicKey(util.Hex2Bytes(nid.Key)).String()] = layer.Add(1)
+	res, err = o.CalcEligibility(context.TODO(), layer, 1, 10, nid, sig)
+	require.NoError(t, err)
+	require.Equal(t, 1, int(res), "identity is honest before the layer of the proof")
+
+	o.malicious[signing.NewPublicKey(util.Hex2Bytes(nid.Key)).String()] = layer
+	res, err = o.CalcEligibility(context.TODO(), layer, 1, 10, nid, sig)
+	require.NoError(t, err)
+	require.Zero(t, res)
+	valid, err := o.Validate(context.TODO(), layer, 1, 10, nid, sig, 1)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
+
 func TestCalcEligibility_EligibleFromTortoiseActiveSet(t *testing.T) {
 	o := defaultOracle(t)
 	defer o.ctrl.Finish()
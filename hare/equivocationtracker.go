@@ -0,0 +1,56 @@
+package hare
+
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+type equivocationKey struct {
+	pub   string
+	round uint32
+}
+
+// equivocationTracker tracks the first message from every sender in every round.
+// Sender that signed different messages in the same round is proven to be malicious.
+type equivocationTracker struct {
+	layer  types.LayerID
+	first  map[equivocationKey]types.HareProofMsg
+	proven map[string]struct{} // senders that were already proven to be malicious
+}
+
+func newEquivocationTracker(layer types.LayerID, expectedSize int) *equivocationTracker {
+	return &equivocationTracker{
+		layer:  layer,
+		first:  make(map[equivocationKey]types.HareProofMsg, expectedSize),
+		proven: make(map[string]struct{}),
+	}
+}
+
+// Track records the message and returns the proof of malfeasance if the sender already signed
+// a different message in the same round. The proof is returned only once for every sender.
+func (et *equivocationTracker) Track(m *Msg) *types.MalfeasanceProof {
+	msg := types.HareProofMsg{InnerMsg: m.InnerMsg.metadata(), Signature: m.Sig}
+	key := equivocationKey{pub: m.PubKey.String(), round: msg.InnerMsg.Round}
+	first, exist := et.first[key]
+	if !exist {
+		et.first[key] = msg
+		return nil
+	}
+	if first.InnerMsg.MsgHash == msg.InnerMsg.MsgHash {
+		return nil
+	}
+	if _, exist := et.proven[key.pub]; exist {
+		return nil
+	}
+	et.proven[key.pub] = struct{}{}
+	return &types.MalfeasanceProof{
+		Layer:    et.layer,
+		Type:     types.HareEquivocation,
+		Messages: []types.HareProofMsg{first, msg},
+	}
+}
+
+// IsProven returns true if the sender signed different messages in one of the rounds.
+func (et *equivocationTracker) IsProven(m *Msg) bool {
+	_, exist := et.proven[m.PubKey.String()]
+	return exist
+}
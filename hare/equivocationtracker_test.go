@@ -0,0 +1,40 @@
+package hare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+func TestEquivocationTracker_Track(t *testing.T) {
+	tracker := newEquivocationTracker(instanceID1, lowDefaultSize)
+	signer := signing.NewEdSigner()
+	first := BuildPreRoundMsg(signer, NewSetFromValues(value1), nil)
+	require.Nil(t, tracker.Track(first))
+	require.Nil(t, tracker.Track(first), "same message is not an equivocation")
+	require.False(t, tracker.IsProven(first))
+
+	// the same content from other senders or in other rounds is not an equivocation
+	require.Nil(t, tracker.Track(BuildPreRoundMsg(signing.NewEdSigner(), NewSetFromValues(value2), nil)))
+	require.Nil(t, tracker.Track(BuildStatusMsg(signer, NewSetFromValues(value2))))
+	// certify message is sent with the round counter of the notify message
+	require.Nil(t, tracker.Track(BuildNotifyMsg(signer, NewSetFromValues(value1))))
+	require.Nil(t, tracker.Track(BuildCertifyMsg(signer, NewSetFromValues(value1))))
+
+	second := BuildPreRoundMsg(signer, NewSetFromValues(value2), nil)
+	proof := tracker.Track(second)
+	require.NotNil(t, proof)
+	require.True(t, tracker.IsProven(second))
+	require.Equal(t, instanceID1, proof.Layer)
+	require.Equal(t, types.HareEquivocation, proof.Type)
+	smesher, err := proof.Verify()
+	require.NoError(t, err)
+	require.Equal(t, signer.PublicKey(), smesher)
+
+	// proof is created only once for the sender
+	require.Nil(t, tracker.Track(BuildPreRoundMsg(signer, NewSetFromValues(value3), nil)))
+	require.True(t, tracker.IsProven(first))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
 
@@ -205,7 +206,7 @@ func createTestHare(t testing.TB, tcfg config.Config, clock *mockClock, pid p2p.
 	mockFetcher := smocks.NewMockProposalFetcher(ctrl)
 
 	hare := New(tcfg, pid, p2p, ed, nodeID, mockBlockGen, mockSyncS, mockMeshDB, mockProposalDB, mockBeacons, mockFetcher, mockRoracle, patrol, 10,
-		mockIDProvider, mockStateQ, clock, logtest.New(t).WithName(name+"_"+ed.PublicKey().ShortString()))
+		mockIDProvider, mockStateQ, nil, sql.InMemory(), clock, logtest.New(t).WithName(name+"_"+ed.PublicKey().ShortString()))
 
 	return &hareWithMocks{
 		Hare:           hare,
@@ -16,6 +16,8 @@ import (
 	"github.com/spacemeshos/go-spacemesh/hare/config"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/p2p/server"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/system"
 )
 
@@ -31,6 +33,7 @@ type Consensus interface {
 	CloseChannel() chan struct{}
 	Start(ctx context.Context) error
 	SetInbox(chan *Msg)
+	Status() InstanceStatus
 }
 
 // TerminationOutput represents an output of a consensus process.
@@ -39,10 +42,16 @@ type TerminationOutput interface {
 	Set() *Set
 	Coinflip() bool
 	Completed() bool
+	// Notifications returns the notify messages that justify the set. Inner messages don't have Values.
+	Notifications() []*Message
 }
 
 // CertificationOutput represents an certification output of a consensus process.
-type CertificationOutput = types.LayerID
+type CertificationOutput interface {
+	ID() types.LayerID
+	// Certifications returns the certify messages that certify the set.
+	Certifications() []*Message
+}
 
 // RoundClock is a timer interface.
 type RoundClock interface {
@@ -62,32 +71,39 @@ type LayerClock interface {
 type Hare struct {
 	util.Closer
 	log.Log
-	config        config.Config
-	publisher     pubsub.Publisher
-	layerClock    LayerClock
-	broker        *Broker
-	sign          Signer
-	blockGen      blockGenerator
-	mesh          meshProvider
-	pdb           proposalProvider
-	beacons       system.BeaconGetter
-	fetcher       system.ProposalFetcher
-	rolacle       Rolacle
-	patrol        layerPatrol
-	factory       consensusFactory
-	newRoundClock func(LayerID types.LayerID) RoundClock
-	networkDelta  time.Duration
-	nid           types.NodeID
-	totalCPs      int32
-	bufferSize    uint32
-	outputChan    chan TerminationOutput
-	mu            sync.RWMutex
-	outputs       map[types.LayerID][]types.ProposalID
-	certChan      chan types.LayerID
-	certified     map[types.LayerID]struct{}
-	layerLock     sync.RWMutex
-	lastLayer     types.LayerID
-	wg            sync.WaitGroup
+	config         config.Config
+	publisher      pubsub.Publisher
+	layerClock     LayerClock
+	broker         *Broker
+	sign           Signer
+	blockGen       blockGenerator
+	mesh           meshProvider
+	pdb            proposalProvider
+	beacons        system.BeaconGetter
+	fetcher        system.ProposalFetcher
+	rolacle        Rolacle
+	patrol         layerPatrol
+	db             sql.Executor
+	stateQ         stateQuerier
+	roleValidator  roleValidator
+	layersPerEpoch uint16
+	outputsrv      server.Requestor
+	factory        consensusFactory
+	newRoundClock  func(LayerID types.LayerID) RoundClock
+	timing         *roundTiming
+	networkDelta   time.Duration
+	nid            types.NodeID
+	totalCPs       int32
+	bufferSize     uint32
+	outputChan     chan TerminationOutput
+	mu             sync.RWMutex
+	outputs        map[types.LayerID][]types.ProposalID
+	certChan       chan CertificationOutput
+	certified      map[types.LayerID]struct{}
+	instances      map[types.LayerID]Consensus
+	layerLock      sync.RWMutex
+	lastLayer      types.LayerID
+	wg             sync.WaitGroup
 }
 
 // New returns a new Hare struct.
@@ -108,6 +124,8 @@ func New(
 	layersPerEpoch uint16,
 	idProvider identityProvider,
 	stateQ stateQuerier,
+	malfeasance malfeasanceReporter,
+	db sql.Executor,
 	layerClock LayerClock,
 	logger log.Log,
 ) *Hare {
@@ -119,19 +137,20 @@ func New(
 	h.config = conf
 	h.publisher = publisher
 	h.layerClock = layerClock
+	h.timing = newRoundTiming(conf, layerClock, logger)
 	h.newRoundClock = func(layerID types.LayerID) RoundClock {
 		layerTime := layerClock.LayerToTime(layerID)
-		wakeupDelta := time.Duration(conf.WakeupDelta) * time.Second
-		roundDuration := time.Duration(h.config.RoundDuration) * time.Second
+		timing := h.timing.forEpoch(layerID.GetEpoch())
 		h.With().Info("creating hare round clock", layerID,
 			log.String("layer_time", layerTime.String()),
-			log.Duration("wakeup_delta", wakeupDelta),
-			log.Duration("round_duration", roundDuration))
-		return NewSimpleRoundClock(layerTime, wakeupDelta, roundDuration)
+			log.Duration("wakeup_delta", timing.WakeupDelta),
+			log.Duration("round_duration", timing.RoundDuration))
+		return NewSimpleRoundClock(layerTime, timing.WakeupDelta, timing.RoundDuration)
 	}
 
 	ev := newEligibilityValidator(rolacle, layersPerEpoch, idProvider, conf.N, conf.ExpectedLeaders, logger)
 	h.broker = newBroker(pid, ev, stateQ, syncState, layersPerEpoch, conf.LimitConcurrent, h.Closer, logger)
+	h.broker.timing = h.timing
 	publisher.Register(protoName, h.broker.HandleMessage)
 	h.sign = sign
 	h.blockGen = blockGen
@@ -142,6 +161,10 @@ func New(
 	h.fetcher = fetch
 	h.rolacle = rolacle
 	h.patrol = patrol
+	h.db = db
+	h.stateQ = stateQ
+	h.roleValidator = ev
+	h.layersPerEpoch = layersPerEpoch
 
 	h.networkDelta = time.Duration(conf.WakeupDelta) * time.Second
 	// todo: this should be loaded from global config
@@ -150,14 +173,34 @@ func New(
 	h.certChan = make(chan CertificationOutput, h.bufferSize)
 	h.outputs = make(map[types.LayerID][]types.ProposalID, h.bufferSize) // we keep results about LayerBuffer past layers
 	h.certified = make(map[types.LayerID]struct{}, h.bufferSize)
+	h.instances = make(map[types.LayerID]Consensus, h.bufferSize)
 	h.factory = func(conf config.Config, instanceId types.LayerID, s *Set, oracle Rolacle, signing Signer, p2p pubsub.Publisher, clock RoundClock, terminationReport chan TerminationOutput, certificationReport chan CertificationOutput) Consensus {
-		return newConsensusProcess(conf, instanceId, s, oracle, stateQ, layersPerEpoch, signing, nid, p2p, terminationReport, certificationReport, ev, clock, logger)
+		var proc *consensusProcess
+		if len(conf.TranscriptDir) > 0 {
+			proc = newRecordedConsensusProcess(conf.TranscriptDir, conf, instanceId, s, oracle, stateQ, layersPerEpoch, signing, nid, p2p, terminationReport, certificationReport, ev, clock, logger)
+		} else {
+			proc = newConsensusProcess(conf, instanceId, s, oracle, stateQ, layersPerEpoch, signing, nid, p2p, terminationReport, certificationReport, ev, clock, logger)
+		}
+		proc.malfeasance = malfeasance
+		return proc
 	}
 	h.nid = nid
 
 	return h
 }
 
+// SetClockOffset sets the provider of the local clock offset that is accounted for
+// when the round timing is adjusted. It must be called before Start.
+func (h *Hare) SetClockOffset(offset ClockOffset) {
+	h.timing.offset = offset
+}
+
+// SetOutputRequestor sets the requestor for OutputProtocol that is used to request outputs
+// from peers in VerifyOutput. It must be called before layer sync starts.
+func (h *Hare) SetOutputRequestor(requestor server.Requestor) {
+	h.outputsrv = requestor
+}
+
 func (h *Hare) getLastLayer() types.LayerID {
 	h.layerLock.RLock()
 	defer h.layerLock.RUnlock()
@@ -217,6 +260,9 @@ func (h *Hare) collectOutput(ctx context.Context, output TerminationOutput) erro
 	} else {
 		h.WithContext(ctx).With().Info("hare terminated with failure", layerID)
 	}
+	if err := saveOutput(h.db, newLayerOutput(output, pids)); err != nil {
+		h.WithContext(ctx).With().Error("failed to persist hare output", layerID, log.Err(err))
+	}
 
 	hareOutput := types.EmptyBlockID
 	if len(pids) > 0 {
@@ -257,7 +303,11 @@ func (h *Hare) collectOutput(ctx context.Context, output TerminationOutput) erro
 	return nil
 }
 
-func (h *Hare) certify(ctx context.Context, id types.LayerID) {
+func (h *Hare) certify(ctx context.Context, out CertificationOutput) {
+	id := out.ID()
+	if err := saveCertification(h.db, out); err != nil {
+		h.WithContext(ctx).With().Error("failed to persist hare certification", id, log.Err(err))
+	}
 	if h.outOfBufferRange(id) {
 		// ignore
 		return
@@ -299,7 +349,7 @@ func (h *Hare) onTick(ctx context.Context, id types.LayerID) (bool, error) {
 		// it must not return without starting consensus process or mark result as fail
 		// except if it's genesis layer
 		if err != nil {
-			h.outputChan <- procReport{id, &Set{}, false, notCompleted}
+			h.outputChan <- procReport{id, &Set{}, false, notCompleted, nil}
 		}
 	}()
 
@@ -348,6 +398,7 @@ func (h *Hare) onTick(ctx context.Context, id types.LayerID) (bool, error) {
 		return false, fmt.Errorf("start consensus: %w", err)
 	}
 	h.patrol.SetHareInCharge(instID)
+	h.addInstance(cp)
 	logger.With().Info("number of consensus processes (after register)",
 		log.Int32("count", atomic.AddInt32(&h.totalCPs, 1)))
 	return true, nil
@@ -470,7 +521,7 @@ func (h *Hare) tickLoop(ctx context.Context) {
 	for layer := h.layerClock.GetCurrentLayer(); ; layer = layer.Add(1) {
 		select {
 		case <-h.layerClock.AwaitLayer(layer):
-			if h.layerClock.LayerToTime(layer).Sub(time.Now()) > (time.Duration(h.config.WakeupDelta) * time.Second) {
+			if h.layerClock.LayerToTime(layer).Sub(time.Now()) > h.timing.forEpoch(layer.GetEpoch()).WakeupDelta {
 				h.With().Warning("missed hare window, skipping layer", layer)
 				continue
 			}
@@ -498,6 +549,9 @@ func (h *Hare) Start(ctx context.Context) error {
 	ctxOutputLoop := log.WithNewSessionID(ctx, log.String("protocol", protoName+"_outputloop"))
 	ctxCertLoop := log.WithNewSessionID(ctx, log.String("protocol", protoName+"_certloop"))
 
+	if err := h.loadOutputs(); err != nil {
+		return fmt.Errorf("load outputs: %w", err)
+	}
 	if err := h.broker.Start(ctxBroker); err != nil {
 		return fmt.Errorf("start broker: %w", err)
 	}
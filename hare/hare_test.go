@@ -20,6 +20,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	pubsubmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
 
@@ -46,6 +47,10 @@ func (m mockReport) Coinflip() bool {
 	return m.coinflip
 }
 
+func (m mockReport) Notifications() []*Message {
+	return nil
+}
+
 type mockConsensusProcess struct {
 	util.Closer
 	t    chan TerminationOutput
@@ -70,6 +75,10 @@ func (mcp *mockConsensusProcess) ID() types.LayerID {
 func (mcp *mockConsensusProcess) SetInbox(chan *Msg) {
 }
 
+func (mcp *mockConsensusProcess) Status() InstanceStatus {
+	return InstanceStatus{Layer: mcp.id, Terminated: mcp.IsClosed()}
+}
+
 var _ Consensus = (*mockConsensusProcess)(nil)
 
 func newMockConsensusProcess(_ config.Config, instanceID types.LayerID, s *Set, _ Rolacle, _ Signer, _ pubsub.Publisher, outputChan chan TerminationOutput) *mockConsensusProcess {
@@ -115,7 +124,7 @@ func TestHare_New(t *testing.T) {
 	logger := logtest.New(t).WithName(t.Name())
 	h := New(cfg, "", noopPubSub(t), signing.NewEdSigner(), types.NodeID{}, mocks.NewMockblockGenerator(ctrl), smocks.NewMockSyncStateProvider(ctrl),
 		mocks.NewMockmeshProvider(ctrl), mocks.NewMockproposalProvider(ctrl), smocks.NewMockBeaconGetter(ctrl), smocks.NewMockProposalFetcher(ctrl),
-		eligibility.New(logger), mocks.NewMocklayerPatrol(ctrl), 10, mocks.NewMockidentityProvider(ctrl), mocks.NewMockstateQuerier(ctrl), newMockClock(), logger)
+		eligibility.New(logger), mocks.NewMocklayerPatrol(ctrl), 10, mocks.NewMockidentityProvider(ctrl), mocks.NewMockstateQuerier(ctrl), nil, sql.InMemory(), newMockClock(), logger)
 	assert.NotNil(t, h)
 }
 
@@ -47,3 +47,8 @@ type identityProvider interface {
 type stateQuerier interface {
 	IsIdentityActiveOnConsensusView(context.Context, string, types.LayerID) (bool, error)
 }
+
+// malfeasanceReporter persists and gossips proofs that identities signed conflicting messages.
+type malfeasanceReporter interface {
+	Report(context.Context, *types.MalfeasanceProof) error
+}
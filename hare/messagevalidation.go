@@ -443,20 +443,17 @@ func (v *syntaxContextValidator) validateCertificate(ctx context.Context, cert *
 		return false
 	}
 
-	// refill Values
 	for _, commit := range cert.AggMsgs.Messages {
 		if commit.InnerMsg == nil {
 			logger.Warning("certificate validation failed: inner commit message is nil")
 			return false
 		}
-
-		commit.InnerMsg.Values = cert.Values
 	}
 
 	// Note: no need to validate notify.Values=commits.Values because we refill the InnerMsg with notify.Values
 	validateSameK := func(m *Msg) bool { return m.InnerMsg.K == cert.AggMsgs.Messages[0].InnerMsg.K }
 	validators := []func(m *Msg) bool{validateCommitType, validateSameK}
-	if err := v.validateAggregatedMessage(ctx, cert.AggMsgs, validators); err != nil {
+	if err := v.validateAggregatedMessage(ctx, refillValues(cert.AggMsgs, cert.Values), validators); err != nil {
 		logger.With().Warning("Certificate validation failed: aggregated messages validation failed", log.Err(err))
 		return false
 	}
@@ -464,6 +461,22 @@ func (v *syntaxContextValidator) validateCertificate(ctx context.Context, cert *
 	return true
 }
 
+// refillValues returns copies of the aggregated messages with the values refilled.
+// The messages in the certificate are not modified, they must stay as they were signed
+// since the certificate is a part of the message that carries it.
+func refillValues(aggMsgs *aggregatedMessages, values []types.ProposalID) *aggregatedMessages {
+	if aggMsgs.Messages == nil {
+		return aggMsgs
+	}
+	refilled := &aggregatedMessages{Messages: make([]*Message, 0, len(aggMsgs.Messages))}
+	for _, m := range aggMsgs.Messages {
+		inner := *m.InnerMsg
+		inner.Values = values
+		refilled.Messages = append(refilled.Messages, &Message{Sig: m.Sig, InnerMsg: &inner})
+	}
+	return refilled
+}
+
 func validateCommitType(m *Msg) bool {
 	return messageType(m.InnerMsg.Type) == commit
 }
@@ -527,7 +540,6 @@ func (v *syntaxContextValidator) validateTerminationCertificate(ctx context.Cont
 		return false
 	}
 
-	// refill Values
 	for _, notify := range cert.AggMsgs.Messages {
 		if notify.InnerMsg == nil {
 			logger.Warning("certificate validation failed: inner commit message is nil")
@@ -536,13 +548,12 @@ func (v *syntaxContextValidator) validateTerminationCertificate(ctx context.Cont
 		if notify.InnerMsg.Cert == nil {
 			logger.Warning("certificate validation failed: notify message does not have an associated certificate")
 		}
-		notify.InnerMsg.Values = cert.Values
 	}
 
 	// Note: no need to validate notify.Values=commits.Values because we refill the InnerMsg with notify.Values
 	// validateSameK := func(m *Msg) bool { return m.InnerMsg.K == cert.AggMsgs.Messages[0].InnerMsg.K }
 	// validators := []func(m *Msg) bool{validateCommitType, validateSameK}
-	if err := v.validateAggregatedTerminationMessage(ctx, cert.AggMsgs); err != nil {
+	if err := v.validateAggregatedTerminationMessage(ctx, refillValues(cert.AggMsgs, cert.Values)); err != nil {
 		logger.With().Warning("Certificate validation failed: aggregated messages validation failed", log.Err(err))
 		return false
 	}
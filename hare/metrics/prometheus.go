@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/spacemeshos/go-spacemesh/metrics"
+)
+
+const (
+	subsystem = "hare"
+
+	// MessageTypeLabel is the label name for the type of the hare message.
+	MessageTypeLabel = "msg_type"
+	// CompletedLabel is the label name for whether the consensus process completed.
+	CompletedLabel = "completed"
+)
+
+// IterationsToTermination records the number of iterations the consensus process ran until termination.
+var IterationsToTermination = metrics.NewHistogramWithBuckets(
+	"iterations_to_termination",
+	subsystem,
+	"number of iterations until the consensus process terminated",
+	[]string{
+		CompletedLabel,
+	},
+	prometheus.LinearBuckets(1, 1, 8),
+)
+
+// MessageTypeCounter counts valid messages processed by the consensus process per message type.
+var MessageTypeCounter = metrics.NewCounter(
+	"messages",
+	subsystem,
+	"number of valid messages processed by type",
+	[]string{
+		MessageTypeLabel,
+	},
+)
+
+// EligibilityCount records the eligibility count claimed by the valid messages per message type.
+var EligibilityCount = metrics.NewHistogramWithBuckets(
+	"eligibility_count",
+	subsystem,
+	"eligibility count of valid messages by type",
+	[]string{
+		MessageTypeLabel,
+	},
+	prometheus.ExponentialBuckets(1, 2, 8),
+)
+
+// LateMessages counts messages that arrived after their round.
+var LateMessages = metrics.NewCounter(
+	"late_messages",
+	subsystem,
+	"number of messages that arrived after their round",
+	[]string{
+		MessageTypeLabel,
+	},
+)
+
+// TimingLabel is the label name for whether the round timing was suggested or applied.
+const TimingLabel = "timing"
+
+// MessageDelay records the delay of the messages from other nodes relative to the start of their round.
+var MessageDelay = metrics.NewHistogramWithBuckets(
+	"message_delay_seconds",
+	subsystem,
+	"delay of the received messages relative to the start of their round",
+	[]string{
+		MessageTypeLabel,
+	},
+	prometheus.ExponentialBuckets(0.05, 2, 10),
+)
+
+// RoundDuration is the round duration of the current epoch in seconds.
+var RoundDuration = metrics.NewGauge(
+	"round_duration_seconds",
+	subsystem,
+	"round duration of the current epoch",
+	[]string{
+		TimingLabel,
+	},
+)
+
+// WakeupDelta is the wakeup delta of the current epoch in seconds.
+var WakeupDelta = metrics.NewGauge(
+	"wakeup_delta_seconds",
+	subsystem,
+	"wakeup delta of the current epoch",
+	[]string{
+		TimingLabel,
+	},
+)
@@ -386,3 +386,40 @@ func (mr *MockstateQuerierMockRecorder) IsIdentityActiveOnConsensusView(arg0, ar
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIdentityActiveOnConsensusView", reflect.TypeOf((*MockstateQuerier)(nil).IsIdentityActiveOnConsensusView), arg0, arg1, arg2)
 }
+
+// MockmalfeasanceReporter is a mock of malfeasanceReporter interface.
+type MockmalfeasanceReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockmalfeasanceReporterMockRecorder
+}
+
+// MockmalfeasanceReporterMockRecorder is the mock recorder for MockmalfeasanceReporter.
+type MockmalfeasanceReporterMockRecorder struct {
+	mock *MockmalfeasanceReporter
+}
+
+// NewMockmalfeasanceReporter creates a new mock instance.
+func NewMockmalfeasanceReporter(ctrl *gomock.Controller) *MockmalfeasanceReporter {
+	mock := &MockmalfeasanceReporter{ctrl: ctrl}
+	mock.recorder = &MockmalfeasanceReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmalfeasanceReporter) EXPECT() *MockmalfeasanceReporterMockRecorder {
+	return m.recorder
+}
+
+// Report mocks base method.
+func (m *MockmalfeasanceReporter) Report(arg0 context.Context, arg1 *types.MalfeasanceProof) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockmalfeasanceReporterMockRecorder) Report(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockmalfeasanceReporter)(nil).Report), arg0, arg1)
+}
@@ -0,0 +1,271 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/server"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/hareoutputs"
+)
+
+// OutputProtocol is the protocol to request the output of the hare for a layer from peers.
+// Layer sync requests the output from the peer that reported the hare output for the layer
+// and adopts it only if the output is verified with Hare.VerifyOutput.
+const OutputProtocol = "/hare/output/1.0.0"
+
+var (
+	errNotCompleted   = errors.New("consensus process didn't complete")
+	errNoRequestor    = errors.New("requestor for hare outputs is not set")
+	errOutputMismatch = errors.New("hare output doesn't match the block")
+)
+
+// LayerOutput is the output of the consensus process for the layer with the messages that justify it.
+type LayerOutput struct {
+	Layer     types.LayerID
+	Completed bool
+	Coinflip  bool
+	Proposals []types.ProposalID
+	// Notify are the notify messages for Proposals. Every notify message carries the certificate
+	// of the commit messages. Values of the inner messages are omitted and refilled from Proposals.
+	Notify []*Message
+	// Certify are the certify messages for Proposals. It is empty if the output wasn't certified.
+	Certify []*Message
+}
+
+// certification is the encoding of the certify messages in the database.
+type certification struct {
+	Messages []*Message
+}
+
+func newLayerOutput(output TerminationOutput, pids []types.ProposalID) *LayerOutput {
+	return &LayerOutput{
+		Layer:     output.ID(),
+		Completed: output.Completed(),
+		Coinflip:  output.Coinflip(),
+		Proposals: pids,
+		Notify:    output.Notifications(),
+	}
+}
+
+func saveOutput(db sql.Executor, out *LayerOutput) error {
+	buf, err := codec.Encode(out)
+	if err != nil {
+		return fmt.Errorf("encode hare output: %w", err)
+	}
+	if err := hareoutputs.Add(db, out.Layer, buf); err != nil {
+		return fmt.Errorf("save hare output: %w", err)
+	}
+	return nil
+}
+
+func saveCertification(db sql.Executor, out CertificationOutput) error {
+	buf, err := codec.Encode(&certification{Messages: out.Certifications()})
+	if err != nil {
+		return fmt.Errorf("encode hare certification: %w", err)
+	}
+	if err := hareoutputs.SetCertificate(db, out.ID(), buf); err != nil {
+		return fmt.Errorf("save hare certification: %w", err)
+	}
+	return nil
+}
+
+func decodeOutput(output, certificate []byte) (*LayerOutput, error) {
+	var out LayerOutput
+	if err := codec.Decode(output, &out); err != nil {
+		return nil, fmt.Errorf("decode hare output: %w", err)
+	}
+	if len(certificate) > 0 {
+		var cert certification
+		if err := codec.Decode(certificate, &cert); err != nil {
+			return nil, fmt.Errorf("decode hare certification: %w", err)
+		}
+		out.Certify = cert.Messages
+	}
+	return &out, nil
+}
+
+// loadOutput returns the persisted output of the consensus process for the layer.
+func loadOutput(db sql.Executor, lid types.LayerID) (*LayerOutput, error) {
+	output, certificate, err := hareoutputs.Get(db, lid)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOutput(output, certificate)
+}
+
+// loadOutputs restores results of the layers in the buffer range from the database.
+func (h *Hare) loadOutputs() error {
+	from := types.NewLayerID(0)
+	if current := h.layerClock.GetCurrentLayer(); current.After(types.NewLayerID(h.bufferSize)) {
+		from = current.Sub(h.bufferSize)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return hareoutputs.IterateFrom(h.db, from, func(lid types.LayerID, output, certificate []byte) bool {
+		out, err := decodeOutput(output, certificate)
+		if err != nil {
+			h.With().Error("failed to load hare output", lid, log.Err(err))
+			return true
+		}
+		if uint32(len(h.outputs)) >= h.bufferSize {
+			delete(h.outputs, h.oldestResultInBuffer())
+		}
+		h.outputs[lid] = out.Proposals
+		if len(out.Certify) > 0 {
+			h.certified[lid] = struct{}{}
+		}
+		return true
+	})
+}
+
+// HandleOutputRequest serves the persisted output of the consensus process for the requested layer.
+func (h *Hare) HandleOutputRequest(ctx context.Context, req []byte) ([]byte, error) {
+	lid := types.BytesToLayerID(req)
+	out, err := loadOutput(h.db, lid)
+	if errors.Is(err, sql.ErrNotFound) {
+		return nil, errNoResult
+	} else if err != nil {
+		h.WithContext(ctx).With().Error("failed to load hare output", lid, log.Err(err))
+		return nil, err
+	}
+	buf, err := codec.Encode(out)
+	if err != nil {
+		h.WithContext(ctx).With().Panic("failed to encode hare output", lid, log.Err(err))
+	}
+	return buf, nil
+}
+
+// RequestOutput requests the output of the consensus process for the layer from the peer.
+// The output must be validated with Hare.ValidateOutput before it is used.
+func RequestOutput(ctx context.Context, requestor server.Requestor, peer p2p.Peer, lid types.LayerID) (*LayerOutput, error) {
+	type result struct {
+		out *LayerOutput
+		err error
+	}
+	resCh := make(chan result, 1)
+	respFunc := func(data []byte) {
+		var out LayerOutput
+		if err := codec.Decode(data, &out); err != nil {
+			resCh <- result{err: fmt.Errorf("decode hare output: %w", err)}
+			return
+		}
+		if out.Layer != lid {
+			resCh <- result{err: fmt.Errorf("hare output for layer %s instead of %s", out.Layer, lid)}
+			return
+		}
+		resCh <- result{out: &out}
+	}
+	errFunc := func(err error) {
+		resCh <- result{err: err}
+	}
+	if err := requestor.Request(ctx, peer, lid.Bytes(), respFunc, errFunc); err != nil {
+		return nil, fmt.Errorf("request hare output: %w", err)
+	}
+	select {
+	case res := <-resCh:
+		return res.out, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ValidateOutput checks that the notify messages, and the certify messages if present, are valid
+// and that they were sent by enough eligible identities to justify the set of proposals.
+func (h *Hare) ValidateOutput(ctx context.Context, out *LayerOutput) error {
+	if !out.Completed {
+		return errNotCompleted
+	}
+	// status messages are not part of the output
+	noStatus := func(*Msg) bool { return false }
+	v := newSyntaxContextValidator(h.sign, h.config.F+1, noStatus, h.stateQ, h.layersPerEpoch, h.roleValidator, newMsgsTracker(), h.Log)
+	if err := v.validateOutputMessages(ctx, out.Layer, notify, out.Proposals, out.Notify); err != nil {
+		return fmt.Errorf("validate notify messages for %s: %w", out.Layer, err)
+	}
+	if len(out.Certify) > 0 {
+		if err := v.validateOutputMessages(ctx, out.Layer, certify, out.Proposals, out.Certify); err != nil {
+			return fmt.Errorf("validate certify messages for %s: %w", out.Layer, err)
+		}
+	}
+	return nil
+}
+
+// VerifyOutput requests the output of the consensus process for the layer from the peer, validates it
+// and checks that the block the peer reports as the hare output was generated from the proposals in the output.
+func (h *Hare) VerifyOutput(ctx context.Context, peer p2p.Peer, lid types.LayerID, bid types.BlockID) error {
+	if h.outputsrv == nil {
+		return errNoRequestor
+	}
+	out, err := RequestOutput(ctx, h.outputsrv, peer, lid)
+	if err != nil {
+		return err
+	}
+	if err := h.ValidateOutput(ctx, out); err != nil {
+		return err
+	}
+	expected := types.EmptyBlockID
+	if len(out.Proposals) > 0 {
+		if err := h.fetcher.GetProposals(ctx, out.Proposals); err != nil {
+			return fmt.Errorf("fetch proposals for %s: %w", lid, err)
+		}
+		proposals, err := h.pdb.GetProposals(out.Proposals)
+		if err != nil {
+			return fmt.Errorf("get proposals for %s: %w", lid, err)
+		}
+		block, err := h.blockGen.GenerateBlock(ctx, lid, proposals)
+		if err != nil {
+			return fmt.Errorf("generate block for %s: %w", lid, err)
+		}
+		expected = block.ID()
+	}
+	if expected != bid {
+		return fmt.Errorf("%w: expected %s, reported %s", errOutputMismatch, expected, bid)
+	}
+	return nil
+}
+
+// validateOutputMessages checks that messages of the type are valid for the layer and values
+// and that their total eligibility count crosses the threshold.
+func (v *syntaxContextValidator) validateOutputMessages(ctx context.Context, lid types.LayerID, mtype messageType, values []types.ProposalID, msgs []*Message) error {
+	var (
+		count   int
+		senders = make(map[string]struct{}, len(msgs))
+	)
+	for _, m := range msgs {
+		if m == nil || m.InnerMsg == nil {
+			return errNilInner
+		}
+		if m.InnerMsg.Type != mtype {
+			return fmt.Errorf("%w: %s", errUnexpectedType, m.InnerMsg.Type)
+		}
+		if m.InnerMsg.InstanceID != lid {
+			return fmt.Errorf("message for layer %s", m.InnerMsg.InstanceID)
+		}
+		// values are not part of the output, signature doesn't match if the message was signed for other values
+		m.InnerMsg.Values = values
+		msg, err := newMsg(ctx, v.Log, m, v.stateQuerier)
+		if err != nil {
+			return fmt.Errorf("new message: %w", err)
+		}
+		if _, exist := senders[msg.PubKey.String()]; exist {
+			return errDupSender
+		}
+		senders[msg.PubKey.String()] = struct{}{}
+		if !v.SyntacticallyValidateMessage(ctx, msg) {
+			return errInnerSyntax
+		}
+		if !v.roleValidator.Validate(ctx, msg) {
+			return errInnerEligibility
+		}
+		count += int(msg.InnerMsg.EligibilityCount)
+	}
+	if count < v.threshold {
+		return fmt.Errorf("%w: expected %d, actual %d", errMsgsCountMismatch, v.threshold, count)
+	}
+	return nil
+}
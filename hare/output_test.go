@@ -0,0 +1,248 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/eligibility"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	smocks "github.com/spacemeshos/go-spacemesh/p2p/server/mocks"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// knownSenders accepts only messages from the registered identities.
+type knownSenders map[string]struct{}
+
+func (ks knownSenders) Validate(_ context.Context, m *Msg) bool {
+	_, exist := ks[m.PubKey.String()]
+	return exist
+}
+
+func TestHare_OutputPersisted(t *testing.T) {
+	h := createTestHare(t, config.DefaultConfig(), newMockClock(), "test", noopPubSub(t), t.Name())
+	lid := instanceID1
+	set := NewSetFromValues(value1, value2)
+	signer := signing.NewEdSigner()
+	notifications := []*Message{BuildNotifyMsg(signer, set).Message}
+	certifications := []*Message{BuildCertifyMsg(signer, set).Message}
+
+	h.mockFetcher.EXPECT().GetProposals(gomock.Any(), gomock.Any()).Return(errors.New("not available"))
+	require.Error(t, h.collectOutput(context.TODO(), procReport{lid, set, true, completed, notifications}))
+	h.certify(context.TODO(), certReport{lid, certifications})
+
+	restarted := createTestHare(t, config.DefaultConfig(), newMockClock(), "test", noopPubSub(t), t.Name())
+	restarted.db = h.db
+	require.NoError(t, restarted.loadOutputs())
+	res, err := restarted.getResult(lid)
+	require.NoError(t, err)
+	require.ElementsMatch(t, set.ToSlice(), res)
+	require.Contains(t, restarted.certified, lid)
+
+	buf, err := restarted.HandleOutputRequest(context.TODO(), lid.Bytes())
+	require.NoError(t, err)
+	var out LayerOutput
+	require.NoError(t, codec.Decode(buf, &out))
+	require.Equal(t, lid, out.Layer)
+	require.True(t, out.Completed)
+	require.True(t, out.Coinflip)
+	require.ElementsMatch(t, set.ToSlice(), out.Proposals)
+	require.Equal(t, notifications, out.Notify)
+	require.Equal(t, certifications, out.Certify)
+
+	_, err = restarted.HandleOutputRequest(context.TODO(), lid.Add(1).Bytes())
+	require.ErrorIs(t, err, errNoResult)
+}
+
+func TestRequestOutput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	requestor := smocks.NewMockRequestor(ctrl)
+	lid := instanceID1
+	out := &LayerOutput{Layer: lid, Completed: true, Proposals: []types.ProposalID{value1}}
+	buf, err := codec.Encode(out)
+	require.NoError(t, err)
+	peer := p2p.Peer("peer")
+
+	requestor.EXPECT().Request(gomock.Any(), peer, lid.Bytes(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, resp func([]byte), _ func(error)) error {
+			resp(buf)
+			return nil
+		})
+	received, err := RequestOutput(context.TODO(), requestor, peer, lid)
+	require.NoError(t, err)
+	require.Equal(t, out, received)
+
+	requestor.EXPECT().Request(gomock.Any(), peer, lid.Add(1).Bytes(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, resp func([]byte), _ func(error)) error {
+			resp(buf)
+			return nil
+		})
+	_, err = RequestOutput(context.TODO(), requestor, peer, lid.Add(1))
+	require.Error(t, err)
+
+	requestor.EXPECT().Request(gomock.Any(), peer, lid.Bytes(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, _ func([]byte), fail func(error)) error {
+			fail(errNoResult)
+			return nil
+		})
+	_, err = RequestOutput(context.TODO(), requestor, peer, lid)
+	require.ErrorIs(t, err, errNoResult)
+}
+
+// startConsensus starts consensus processes that agree on value1 in the layer. It returns channels
+// with the termination and certification outputs of every process and the identities of the processes.
+func startConsensus(ctx context.Context, t *testing.T, cfg config.Config, layer types.LayerID) ([]chan TerminationOutput, []chan CertificationOutput, knownSenders) {
+	totalNodes := cfg.N
+	mesh, err := mocknet.FullMeshLinked(ctx, totalNodes)
+	require.NoError(t, err)
+
+	oracle := eligibility.New(logtest.New(t))
+	senders := knownSenders{}
+	outputs := make([]chan TerminationOutput, totalNodes)
+	certs := make([]chan CertificationOutput, totalNodes)
+	procs := make([]*consensusProcess, totalNodes)
+	pss := make([]*pubsub.PubSub, totalNodes)
+	for i := range procs {
+		ps, err := pubsub.New(ctx, logtest.New(t), mesh.Hosts()[i], pubsub.DefaultConfig())
+		require.NoError(t, err)
+		pss[i] = ps
+		broker := buildBroker(t, t.Name())
+		broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
+		broker.mockStateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+		broker.Start(ctx)
+		t.Cleanup(broker.Close)
+		ps.Register(protoName, broker.HandleMessage)
+
+		signer := signing.NewEdSigner()
+		oracle.Register(true, signer.PublicKey().String())
+		senders[signer.PublicKey().String()] = struct{}{}
+		outputs[i] = make(chan TerminationOutput, 1)
+		certs[i] = make(chan CertificationOutput, 1)
+		procs[i] = newConsensusProcess(cfg, layer, NewSetFromValues(value1), oracle, broker.mockStateQ, 10, signer,
+			types.NodeID{Key: signer.PublicKey().String(), VRFPublicKey: []byte{}}, ps, outputs[i], certs[i], truer{},
+			nil, logtest.New(t).WithName(signer.PublicKey().ShortString()))
+		c, err := broker.Register(ctx, procs[i].ID())
+		require.NoError(t, err)
+		procs[i].SetInbox(c)
+	}
+	require.NoError(t, mesh.ConnectAllButSelf())
+	// preround messages are sent on start, they are lost if the peers didn't join the topic yet
+	require.Eventually(t, func() bool {
+		for _, ps := range pss {
+			if len(ps.ProtocolPeers(protoName)) < totalNodes-1 {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+	clock := NewSimpleRoundClock(time.Now(), 0, 500*time.Millisecond)
+	for _, proc := range procs {
+		proc.clock = clock
+		require.NoError(t, proc.Start(ctx))
+	}
+	return outputs, certs, senders
+}
+
+// awaitOutput waits for the outputs of the process and persists them.
+func awaitOutput(t *testing.T, db sql.Executor, outputs chan TerminationOutput, certs chan CertificationOutput) {
+	var termination TerminationOutput
+	select {
+	case termination = <-outputs:
+	case <-time.After(30 * time.Second):
+		require.FailNow(t, "timed out waiting for termination")
+	}
+	require.True(t, termination.Completed())
+	require.NoError(t, saveOutput(db, newLayerOutput(termination, termination.Set().ToSlice())))
+	select {
+	case certification := <-certs:
+		require.NoError(t, saveCertification(db, certification))
+	case <-time.After(30 * time.Second):
+		require.FailNow(t, "timed out waiting for certification")
+	}
+}
+
+func TestHare_ValidateOutput(t *testing.T) {
+	const totalNodes = 6
+	cfg := config.Config{N: totalNodes, F: totalNodes / 2, ExpectedLeaders: 5, LimitIterations: 4}
+	layer := types.GetEffectiveGenesis().Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outputs, certs, senders := startConsensus(ctx, t, cfg, layer)
+
+	h := createTestHare(t, cfg, newMockClock(), "test", noopPubSub(t), t.Name())
+	h.roleValidator = senders
+	for i := range outputs {
+		awaitOutput(t, h.db, outputs[i], certs[i])
+
+		out, err := loadOutput(h.db, layer)
+		require.NoError(t, err)
+		require.NotEmpty(t, out.Certify)
+		require.NoError(t, h.ValidateOutput(ctx, out))
+
+		out, err = loadOutput(h.db, layer)
+		require.NoError(t, err)
+		out.Proposals = append(out.Proposals, value2)
+		require.ErrorIs(t, h.ValidateOutput(ctx, out), errInnerEligibility)
+
+		out, err = loadOutput(h.db, layer)
+		require.NoError(t, err)
+		out.Notify = out.Notify[:len(out.Notify)-1]
+		require.ErrorIs(t, h.ValidateOutput(ctx, out), errMsgsCountMismatch)
+
+		out, err = loadOutput(h.db, layer)
+		require.NoError(t, err)
+		out.Completed = false
+		require.ErrorIs(t, h.ValidateOutput(ctx, out), errNotCompleted)
+	}
+}
+
+func TestHare_VerifyOutput(t *testing.T) {
+	const totalNodes = 6
+	cfg := config.Config{N: totalNodes, F: totalNodes / 2, ExpectedLeaders: 5, LimitIterations: 4}
+	layer := types.GetEffectiveGenesis().Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outputs, certs, senders := startConsensus(ctx, t, cfg, layer)
+
+	db := sql.InMemory()
+	awaitOutput(t, db, outputs[0], certs[0])
+	out, err := loadOutput(db, layer)
+	require.NoError(t, err)
+	buf, err := codec.Encode(out)
+	require.NoError(t, err)
+
+	h := createTestHare(t, cfg, newMockClock(), "test", noopPubSub(t), t.Name())
+	h.roleValidator = senders
+	peer := p2p.Peer("peer")
+	proposals := []*types.Proposal{types.GenLayerProposal(layer, nil)}
+	block := types.GenLayerBlock(layer, nil)
+	require.ErrorIs(t, h.VerifyOutput(ctx, peer, layer, block.ID()), errNoRequestor)
+
+	requestor := smocks.NewMockRequestor(h.ctrl)
+	requestor.EXPECT().Request(gomock.Any(), peer, layer.Bytes(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, resp func([]byte), _ func(error)) error {
+			resp(buf)
+			return nil
+		}).AnyTimes()
+	h.SetOutputRequestor(requestor)
+	h.mockFetcher.EXPECT().GetProposals(gomock.Any(), out.Proposals).Return(nil).AnyTimes()
+	h.mockProposalDB.EXPECT().GetProposals(out.Proposals).Return(proposals, nil).AnyTimes()
+	h.mockBlockGen.EXPECT().GenerateBlock(gomock.Any(), layer, proposals).Return(block, nil).AnyTimes()
+
+	require.NoError(t, h.VerifyOutput(ctx, peer, layer, block.ID()))
+	require.ErrorIs(t, h.VerifyOutput(ctx, peer, layer, types.RandomBlockID()), errOutputMismatch)
+	require.ErrorIs(t, h.VerifyOutput(ctx, peer, layer, types.EmptyBlockID), errOutputMismatch)
+}
@@ -0,0 +1,334 @@
+package hare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+// replayTimeout is the time the replay waits for the consensus process to handle messages
+// that were recorded before the end of the round, and to react on the end of the round.
+const replayTimeout = time.Second
+
+var errNotRecorded = errors.New("decision is not recorded")
+
+// TranscriptResult summarizes how the consensus process ended.
+type TranscriptResult struct {
+	Layer types.LayerID
+	// Terminated is true if the consensus process reported an output.
+	Terminated bool
+	// Completed is true if the consensus process reached agreement on the Set.
+	Completed bool
+	Coinflip  bool
+	Certified bool
+	// Round is the round counter when the output was reported, or the last round that ended.
+	Round uint32
+	Set   []types.ProposalID
+}
+
+func transcriptResult(events []transcriptEvent) *TranscriptResult {
+	rst := &TranscriptResult{Layer: events[0].Header.Layer}
+	for _, ev := range events {
+		switch ev.Type {
+		case transcriptRoundEnd:
+			if !rst.Terminated {
+				rst.Round = ev.Round
+			}
+		case transcriptOutput:
+			rst.Terminated = true
+			rst.Round = ev.Round
+			rst.Completed = ev.Output.Completed
+			rst.Coinflip = ev.Output.Coinflip
+			rst.Set = ev.Output.Set
+		case transcriptCertified:
+			rst.Certified = true
+		}
+	}
+	return rst
+}
+
+// ReplayTranscript reads the transcript of the consensus process and drives a new consensus process
+// with the recorded messages and oracle decisions. Ends of the rounds are signaled by the fake clock
+// in the same order relative to the messages as they were recorded.
+// It returns the result from the transcript and the result of the replay.
+func ReplayTranscript(ctx context.Context, r io.Reader, logger log.Log) (recorded, replayed *TranscriptResult, err error) {
+	events, err := readTranscript(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(events) == 0 || events[0].Type != transcriptStart || events[0].Header == nil {
+		return nil, nil, errors.New("transcript doesn't start with a header")
+	}
+	header := events[0].Header
+	decisions := newReplayDecisions(events, logger)
+	cfg := config.Config{
+		N:               int(header.N),
+		F:               int(header.F),
+		ExpectedLeaders: int(header.ExpectedLeaders),
+		LimitIterations: int(header.LimitIterations),
+	}
+	var (
+		buf          bytes.Buffer
+		clock        = newReplayClock()
+		terminations = make(chan TerminationOutput, 1)
+		certificates = make(chan CertificationOutput, 1)
+		inbox        = make(chan *Msg)
+	)
+	proc := newConsensusProcess(cfg, header.Layer, NewSet(header.Set), decisions, decisions, header.LayersPerEpoch,
+		replaySigner{pub: signing.NewPublicKey(header.PubKey)}, header.NodeID, replayPublisher{},
+		terminations, certificates, replayRoleValidator{decisions}, clock, logger)
+	proc.transcript = newTranscript(&buf, nil, logger)
+	proc.SetInbox(inbox)
+	if err := proc.Start(ctx); err != nil {
+		return nil, nil, err
+	}
+	// consensus process closes itself when it terminates. it is idle once all events are replayed.
+	stop := func() {
+		if !proc.IsClosed() {
+			proc.Close()
+		}
+	}
+
+	var messages, published int
+	// sync waits until the consensus process handled and published all messages
+	// that were recorded before the current event.
+	sync := func(round uint32) {
+		if !waitReplay(ctx, proc, proc.transcript.notify, func() bool {
+			handled, sent := proc.transcript.recorded()
+			return handled >= messages && sent >= published
+		}) {
+			handled, sent := proc.transcript.recorded()
+			logger.With().Warning("consensus process diverged from the transcript",
+				log.Uint32("round", round),
+				log.Int("recorded_handled", messages),
+				log.Int("recorded_published", published),
+				log.Int("handled", handled),
+				log.Int("published", sent),
+			)
+		}
+	}
+replay:
+	for _, ev := range events[1:] {
+		switch ev.Type {
+		case transcriptMessage:
+			if ev.Pending {
+				// consensus process passes pending messages to the inbox by itself
+				messages++
+				continue
+			}
+			sync(ev.Round)
+			messages++
+			msg := &Msg{Message: ev.Message, PubKey: signing.NewPublicKey(ev.PubKey)}
+			select {
+			case inbox <- msg:
+			case <-proc.CloseChannel():
+				break replay
+			case <-ctx.Done():
+				stop()
+				return nil, nil, ctx.Err()
+			}
+		case transcriptPublish:
+			published++
+		case transcriptRoundEnd:
+			sync(ev.Round)
+			clock.end(ev.Round)
+			waitReplay(ctx, proc, clock.notify, func() bool {
+				return clock.awaitedAfter(ev.Round)
+			})
+		}
+		if err := ctx.Err(); err != nil {
+			stop()
+			return nil, nil, err
+		}
+	}
+	select {
+	case <-proc.transcript.done:
+	case <-time.After(replayTimeout):
+		// the consensus process was stopped externally when the transcript was recorded
+		stop()
+		<-proc.transcript.done
+	case <-ctx.Done():
+		stop()
+		return nil, nil, ctx.Err()
+	}
+	replayedEvents, err := readTranscript(&buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return transcriptResult(events), transcriptResult(append(events[:1:1], replayedEvents...)), nil
+}
+
+// waitReplay waits until condition is true or the consensus process is closed.
+// It returns false if the condition wasn't true before the timeout.
+func waitReplay(ctx context.Context, proc *consensusProcess, notify <-chan struct{}, condition func() bool) bool {
+	timer := time.NewTimer(replayTimeout)
+	defer timer.Stop()
+	for !condition() {
+		select {
+		case <-notify:
+		case <-proc.CloseChannel():
+			return true
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+type decisionKey struct {
+	kind  uint8
+	round uint32
+	id    string
+}
+
+// replayDecisions answers queries of the consensus process with the decisions from the transcript.
+type replayDecisions struct {
+	logger    log.Log
+	decisions map[decisionKey]*oracleDecision
+}
+
+func newReplayDecisions(events []transcriptEvent, logger log.Log) *replayDecisions {
+	rd := &replayDecisions{logger: logger, decisions: map[decisionKey]*oracleDecision{}}
+	for _, ev := range events {
+		if ev.Type == transcriptDecision {
+			rd.decisions[decisionKey{kind: ev.Decision.Kind, round: ev.Round, id: ev.Decision.ID}] = ev.Decision
+		}
+	}
+	return rd
+}
+
+func (rd *replayDecisions) get(kind uint8, round uint32, id string) (*oracleDecision, error) {
+	decision, exist := rd.decisions[decisionKey{kind: kind, round: round, id: id}]
+	if !exist {
+		rd.logger.With().Warning("decision is not recorded",
+			log.Uint32("kind", uint32(kind)),
+			log.Uint32("round", round),
+			log.String("id", id),
+		)
+		return nil, errNotRecorded
+	}
+	if decision.Failed {
+		return nil, fmt.Errorf("recorded failure for decision %d in round %d", kind, round)
+	}
+	return decision, nil
+}
+
+func (rd *replayDecisions) Validate(context.Context, types.LayerID, uint32, int, types.NodeID, []byte, uint16) (bool, error) {
+	return false, errNotRecorded
+}
+
+func (rd *replayDecisions) CalcEligibility(_ context.Context, _ types.LayerID, k uint32, _ int, _ types.NodeID, _ []byte) (uint16, error) {
+	decision, err := rd.get(decisionEligibility, k, "")
+	if err != nil {
+		return 0, err
+	}
+	return decision.Eligibility, nil
+}
+
+func (rd *replayDecisions) Proof(_ context.Context, _ types.LayerID, k uint32) ([]byte, error) {
+	decision, err := rd.get(decisionProof, k, "")
+	if err != nil {
+		return nil, err
+	}
+	return decision.Proof, nil
+}
+
+func (rd *replayDecisions) IsIdentityActiveOnConsensusView(_ context.Context, id string, _ types.LayerID) (bool, error) {
+	decision, err := rd.get(decisionActive, 0, id)
+	if err != nil {
+		return false, err
+	}
+	return decision.Valid, nil
+}
+
+type replayRoleValidator struct {
+	*replayDecisions
+}
+
+func (v replayRoleValidator) Validate(_ context.Context, m *Msg) bool {
+	decision, err := v.get(decisionRole, m.InnerMsg.K, m.PubKey.String())
+	return err == nil && decision.Valid
+}
+
+// replayClock signals the end of the round only when requested by the replay.
+type replayClock struct {
+	mu      sync.Mutex
+	rounds  map[uint32]chan struct{}
+	awaited bool
+	last    uint32
+	notify  chan struct{}
+}
+
+func newReplayClock() *replayClock {
+	return &replayClock{rounds: map[uint32]chan struct{}{}, notify: make(chan struct{}, 1)}
+}
+
+func (c *replayClock) round(round uint32) chan struct{} {
+	ch, exist := c.rounds[round]
+	if !exist {
+		ch = make(chan struct{})
+		c.rounds[round] = ch
+	}
+	return ch
+}
+
+func (c *replayClock) AwaitWakeup() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func (c *replayClock) AwaitEndOfRound(round uint32) <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.awaited = true
+	c.last = round
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+	return c.round(round)
+}
+
+func (c *replayClock) end(round uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.round(round))
+}
+
+// awaitedAfter returns true if the consensus process waits for the end of the round that follows the round.
+func (c *replayClock) awaitedAfter(round uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.awaited && c.last != round
+}
+
+type replaySigner struct {
+	pub *signing.PublicKey
+}
+
+func (s replaySigner) Sign([]byte) []byte {
+	return nil
+}
+
+func (s replaySigner) PublicKey() *signing.PublicKey {
+	return s.pub
+}
+
+// replayPublisher drops messages, own messages are replayed from the transcript as received.
+type replayPublisher struct{}
+
+func (replayPublisher) Publish(context.Context, string, []byte) error {
+	return nil
+}
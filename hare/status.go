@@ -0,0 +1,124 @@
+package hare
+
+import (
+	"errors"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// ErrNoInstance is returned when there is no recent consensus process for the layer.
+var ErrNoInstance = errors.New("no consensus process for the layer")
+
+// InstanceStatus is a snapshot of the state of the consensus process.
+type InstanceStatus struct {
+	Layer types.LayerID
+	// Round is the round counter of the consensus process, it is set to special values
+	// during the pre-round and the certification.
+	Round uint32
+	// Set is the current set of values.
+	Set []types.ProposalID
+	// ProposedSet is the set proposed by the leader in the current iteration. It is nil
+	// if there is no valid proposal yet or if the leader sent conflicting proposals.
+	ProposedSet []types.ProposalID
+	// CommitCount and NotifyCount are the eligibility counts of the commit messages in the current
+	// iteration and of the notify messages for the current set.
+	CommitCount int
+	NotifyCount int
+	// Terminated is true once the consensus process stopped.
+	Terminated bool
+	Completed  bool
+	Certified  bool
+}
+
+// Iteration returns the current iteration, starting from 0.
+func (s *InstanceStatus) Iteration() uint32 {
+	if s.Round == preRound || s.Round == certifyRound {
+		return 0
+	}
+	return iterationFromCounter(s.Round)
+}
+
+// RoundType returns the name of the current round.
+func (s *InstanceStatus) RoundType() string {
+	switch s.Round {
+	case preRound:
+		return "preround"
+	case certifyRound:
+		return "certify"
+	}
+	switch s.Round % RoundsPerIteration {
+	case statusRound:
+		return "status"
+	case proposalRound:
+		return "proposal"
+	case commitRound:
+		return "commit"
+	default:
+		return "notify"
+	}
+}
+
+// iterations returns the number of the iterations that the consensus process started.
+func (proc *consensusProcess) iterations() uint32 {
+	k := proc.getK()
+	if k == preRound {
+		return 0
+	}
+	return iterationFromCounter(k) + 1
+}
+
+// updateStatus takes the snapshot of the state. It must be called from the event loop.
+func (proc *consensusProcess) updateStatus(terminated bool) {
+	status := InstanceStatus{
+		Layer:      proc.instanceID,
+		Round:      proc.getK(),
+		Set:        proc.s.ToSlice(),
+		Terminated: terminated,
+		Completed:  proc.completed,
+		Certified:  proc.certified,
+	}
+	if proc.proposalTracker != nil {
+		if s := proc.proposalTracker.ProposedSet(); s != nil {
+			status.ProposedSet = s.ToSlice()
+		}
+	}
+	if proc.commitTracker != nil {
+		status.CommitCount = proc.commitTracker.CommitCount()
+	}
+	status.NotifyCount = proc.notifyTracker.NotificationsCount(proc.s)
+
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	proc.lastStatus = status
+}
+
+// Status returns the snapshot of the state of the consensus process.
+func (proc *consensusProcess) Status() InstanceStatus {
+	proc.mu.RLock()
+	defer proc.mu.RUnlock()
+	return proc.lastStatus
+}
+
+// addInstance keeps the consensus process to report its status, instances out of the buffer range are dropped.
+func (h *Hare) addInstance(cp Consensus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for lid := range h.instances {
+		if h.outOfBufferRange(lid) {
+			delete(h.instances, lid)
+		}
+	}
+	h.instances[cp.ID()] = cp
+}
+
+// InstanceStatus returns the status of the consensus process for the layer.
+func (h *Hare) InstanceStatus(lid types.LayerID) (*InstanceStatus, error) {
+	h.mu.RLock()
+	cp, exist := h.instances[lid]
+	h.mu.RUnlock()
+	if !exist {
+		return nil, ErrNoInstance
+	}
+	status := cp.Status()
+	return &status, nil
+}
@@ -0,0 +1,83 @@
+package hare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+func TestInstanceStatus_Round(t *testing.T) {
+	for _, tc := range []struct {
+		round     uint32
+		iteration uint32
+		name      string
+	}{
+		{round: preRound, name: "preround"},
+		{round: statusRound, name: "status"},
+		{round: proposalRound, name: "proposal"},
+		{round: commitRound, name: "commit"},
+		{round: notifyRound, name: "notify"},
+		{round: 2*RoundsPerIteration + commitRound, iteration: 2, name: "commit"},
+		{round: certifyRound, name: "certify"},
+	} {
+		status := InstanceStatus{Round: tc.round}
+		require.Equal(t, tc.iteration, status.Iteration(), tc.round)
+		require.Equal(t, tc.name, status.RoundType(), tc.round)
+	}
+}
+
+func TestConsensusProcess_Status(t *testing.T) {
+	proc := generateConsensusProcess(t)
+	proc.updateStatus(false)
+	status := proc.Status()
+	require.Equal(t, instanceID1, status.Layer)
+	require.Equal(t, uint32(preRound), status.Round)
+	require.False(t, status.Terminated)
+	require.Zero(t, proc.iterations())
+
+	proc.advanceToNextRound(context.TODO())
+	s := NewSetFromValues(value1)
+	proc.setK(notifyRound)
+	for i := 0; i < cfg.F+1; i++ {
+		proc.processNotifyMsg(context.TODO(), BuildNotifyMsg(signing.NewEdSigner(), s))
+	}
+	proc.updateStatus(false)
+	status = proc.Status()
+	require.Equal(t, "notify", status.RoundType())
+	require.Equal(t, cfg.F+1, status.NotifyCount)
+	require.ElementsMatch(t, s.ToSlice(), status.Set)
+	require.True(t, status.Completed)
+	require.Equal(t, uint32(1), proc.iterations())
+
+	proc.updateStatus(true)
+	require.True(t, proc.Status().Terminated)
+}
+
+func TestHare_InstanceStatus(t *testing.T) {
+	h := createTestHare(t, config.DefaultConfig(), newMockClock(), "test", noopPubSub(t), t.Name())
+	h.bufferSize = 1
+
+	first := types.NewLayerID(10)
+	h.setLastLayer(first)
+	h.addInstance(newMockConsensusProcess(h.config, first, nil, nil, nil, nil, nil))
+	status, err := h.InstanceStatus(first)
+	require.NoError(t, err)
+	require.Equal(t, first, status.Layer)
+	_, err = h.InstanceStatus(first.Add(1))
+	require.ErrorIs(t, err, ErrNoInstance)
+
+	// instances out of the buffer range are dropped
+	last := first.Add(2)
+	h.setLastLayer(last)
+	h.addInstance(newMockConsensusProcess(h.config, last, nil, nil, nil, nil, nil))
+	_, err = h.InstanceStatus(first)
+	require.ErrorIs(t, err, ErrNoInstance)
+	status, err = h.InstanceStatus(last)
+	require.NoError(t, err)
+	require.Equal(t, last, status.Layer)
+}
@@ -0,0 +1,201 @@
+package hare
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/hare/metrics"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+const (
+	// minTimingSamples is the minimal number of delays observed in the epoch to suggest the timing.
+	minTimingSamples = 100
+	// maxTimingSamples bounds the number of delays kept for the epoch.
+	maxTimingSamples = 10000
+	// delayQuantile is the quantile of the observed delays that is used to suggest the timing.
+	delayQuantile = 0.95
+	// roundDelayFactor is the ratio between the round duration and the delay quantile,
+	// so that the messages sent at the start of the round arrive in the first half of it.
+	roundDelayFactor = 2
+)
+
+// ClockOffset provides the offset of the local clock from the clocks of the peers.
+type ClockOffset interface {
+	Offset() time.Duration
+}
+
+// Timing is the timing of the hare rounds in the layer.
+type Timing struct {
+	WakeupDelta   time.Duration
+	RoundDuration time.Duration
+}
+
+// roundStart returns the time when the round starts. The pre-round starts after the wakeup delta.
+func (t Timing) roundStart(layerTime time.Time, round uint32) time.Time {
+	return layerTime.Add(t.WakeupDelta + t.RoundDuration*time.Duration(round+1))
+}
+
+// roundTiming decides the timing of the rounds for every epoch, records delays of the hare messages
+// and suggests the timing from them. The timing is decided once per epoch when it is requested
+// for the first time, so that the timing doesn't change within the epoch.
+//
+// The applied timing is a function of the config and the epoch: the configured timing, or the agreed
+// timing starting from the timing epoch if the adaptive timing is enabled. The suggestion is computed
+// from the delays observed by the local node and from the offset of the local clock, therefore honest
+// nodes may suggest different timings. It is only logged and exported in metrics, as the input
+// for the timing that the network agrees on.
+type roundTiming struct {
+	log.Log
+	cfg        config.Config
+	layerClock LayerClock
+	offset     ClockOffset
+
+	mu     sync.Mutex
+	epochs map[types.EpochID]Timing
+	delays map[types.EpochID][]time.Duration
+}
+
+func newRoundTiming(cfg config.Config, layerClock LayerClock, logger log.Log) *roundTiming {
+	return &roundTiming{
+		Log:        logger,
+		cfg:        cfg,
+		layerClock: layerClock,
+		epochs:     make(map[types.EpochID]Timing),
+		delays:     make(map[types.EpochID][]time.Duration),
+	}
+}
+
+func (rt *roundTiming) configured() Timing {
+	return Timing{
+		WakeupDelta:   time.Duration(rt.cfg.WakeupDelta) * time.Second,
+		RoundDuration: time.Duration(rt.cfg.RoundDuration) * time.Second,
+	}
+}
+
+// agreed returns the timing that the network agreed to switch to in the timing epoch.
+func (rt *roundTiming) agreed() Timing {
+	return Timing{
+		WakeupDelta:   time.Duration(rt.cfg.TimingWakeupDelta) * time.Second,
+		RoundDuration: time.Duration(rt.cfg.TimingRoundDuration) * time.Second,
+	}
+}
+
+// forEpoch returns the timing of the epoch. The agreed timing is applied from the start of the timing
+// epoch if the adaptive timing is enabled, and the configured timing is applied otherwise.
+// The timing is also suggested from the delays observed in the previous epoch.
+func (rt *roundTiming) forEpoch(epoch types.EpochID) Timing {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if timing, exist := rt.epochs[epoch]; exist {
+		return timing
+	}
+
+	timing := rt.configured()
+	if rt.cfg.AdaptiveTiming && epoch >= types.EpochID(rt.cfg.TimingEpoch) {
+		timing = rt.agreed()
+	}
+	if prev, exist := rt.epochs[epoch-1]; exist && epoch > 0 && prev != timing {
+		rt.With().Info("switched hare round timing",
+			epoch,
+			log.Duration("wakeup_delta", timing.WakeupDelta),
+			log.Duration("round_duration", timing.RoundDuration),
+		)
+	}
+	if epoch > 0 {
+		if suggested, ok := rt.suggest(rt.delays[epoch-1]); ok {
+			rt.With().Info("suggested hare round timing",
+				epoch,
+				log.Int("samples", len(rt.delays[epoch-1])),
+				log.Duration("wakeup_delta", suggested.WakeupDelta),
+				log.Duration("round_duration", suggested.RoundDuration),
+			)
+			metrics.RoundDuration.WithLabelValues("suggested").Set(suggested.RoundDuration.Seconds())
+			metrics.WakeupDelta.WithLabelValues("suggested").Set(suggested.WakeupDelta.Seconds())
+		}
+	}
+	metrics.RoundDuration.WithLabelValues("applied").Set(timing.RoundDuration.Seconds())
+	metrics.WakeupDelta.WithLabelValues("applied").Set(timing.WakeupDelta.Seconds())
+
+	rt.epochs[epoch] = timing
+	// timing of the previous epoch is needed for the messages that arrive after the boundary
+	for e := range rt.epochs {
+		if e+1 < epoch {
+			delete(rt.epochs, e)
+		}
+	}
+	for e := range rt.delays {
+		if e < epoch {
+			delete(rt.delays, e)
+		}
+	}
+	return timing
+}
+
+// suggest the timing that fits the delays quantile into the half of the round, within the configured bounds.
+// The wakeup delta is scaled to keep the configured ratio to the round duration.
+func (rt *roundTiming) suggest(delays []time.Duration) (Timing, bool) {
+	if len(delays) < minTimingSamples || rt.cfg.RoundDuration <= 0 {
+		return Timing{}, false
+	}
+	sorted := make([]time.Duration, len(delays))
+	copy(sorted, delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	delay := sorted[int(float64(len(sorted)-1)*delayQuantile)]
+	if delay < 0 {
+		delay = 0
+	}
+	// peers observe our messages shifted by the offset of the local clock
+	if rt.offset != nil {
+		offset := rt.offset.Offset()
+		if offset < 0 {
+			offset = -offset
+		}
+		delay += offset
+	}
+
+	minDuration, maxDuration := rt.cfg.MinRoundDuration, rt.cfg.MaxRoundDuration
+	if minDuration <= 0 || minDuration > rt.cfg.RoundDuration {
+		minDuration = rt.cfg.RoundDuration
+	}
+	if maxDuration < rt.cfg.RoundDuration {
+		maxDuration = rt.cfg.RoundDuration
+	}
+	duration := roundUpToSecond(roundDelayFactor * delay)
+	if min := time.Duration(minDuration) * time.Second; duration < min {
+		duration = min
+	}
+	if max := time.Duration(maxDuration) * time.Second; duration > max {
+		duration = max
+	}
+	wakeup := roundUpToSecond(duration * time.Duration(rt.cfg.WakeupDelta) / time.Duration(rt.cfg.RoundDuration))
+	return Timing{WakeupDelta: wakeup, RoundDuration: duration}, true
+}
+
+// observe records the delay of the message received from other node.
+func (rt *roundTiming) observe(m *Message, received time.Time) {
+	// certify messages are sent once the process completes, not at the start of the round
+	if m.InnerMsg.Type == certify {
+		return
+	}
+	lid := m.InnerMsg.InstanceID
+	epoch := lid.GetEpoch()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	timing, exist := rt.epochs[epoch]
+	if !exist {
+		return
+	}
+	delay := received.Sub(timing.roundStart(rt.layerClock.LayerToTime(lid), m.InnerMsg.K))
+	metrics.MessageDelay.WithLabelValues(m.InnerMsg.Type.String()).Observe(delay.Seconds())
+	if len(rt.delays[epoch]) < maxTimingSamples {
+		rt.delays[epoch] = append(rt.delays[epoch], delay)
+	}
+}
+
+func roundUpToSecond(d time.Duration) time.Duration {
+	return (d + time.Second - 1).Truncate(time.Second)
+}
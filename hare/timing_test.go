@@ -0,0 +1,160 @@
+package hare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+type fixedOffset time.Duration
+
+func (o fixedOffset) Offset() time.Duration {
+	return time.Duration(o)
+}
+
+func observeDelays(rt *roundTiming, clock *mockClock, lid types.LayerID, delay time.Duration, n int) {
+	timing := rt.forEpoch(lid.GetEpoch())
+	signer := signing.NewEdSigner()
+	for i := 0; i < n; i++ {
+		msg := BuildPreRoundMsg(signer, NewSetFromValues(value1), nil).Message
+		msg.InnerMsg.InstanceID = lid
+		rt.observe(msg, timing.roundStart(clock.LayerToTime(lid), msg.InnerMsg.K).Add(delay))
+	}
+}
+
+func timingConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.RoundDuration = 10
+	cfg.WakeupDelta = 20
+	cfg.MinRoundDuration = 5
+	cfg.MaxRoundDuration = 30
+	return cfg
+}
+
+func TestTiming_roundStart(t *testing.T) {
+	timing := Timing{WakeupDelta: 10 * time.Second, RoundDuration: 3 * time.Second}
+	layerTime := time.Now()
+	require.Equal(t, layerTime.Add(10*time.Second), timing.roundStart(layerTime, preRound))
+	require.Equal(t, layerTime.Add(13*time.Second), timing.roundStart(layerTime, statusRound))
+	require.Equal(t, layerTime.Add(22*time.Second), timing.roundStart(layerTime, notifyRound))
+
+	// round ends when the next one starts
+	clock := NewSimpleRoundClock(layerTime, timing.WakeupDelta, timing.RoundDuration)
+	require.Equal(t, timing.roundStart(layerTime, commitRound),
+		layerTime.Add(clock.WakeupDelta+clock.RoundDuration*time.Duration(proposalRound+2)))
+}
+
+func TestRoundTiming_SuggestOnly(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	clock := newMockClock()
+	lid := types.GetEffectiveGenesis().Add(1)
+	epoch := lid.GetEpoch()
+	rt := newRoundTiming(timingConfig(), clock, logtest.New(t))
+
+	configured := Timing{WakeupDelta: 20 * time.Second, RoundDuration: 10 * time.Second}
+	require.Equal(t, configured, rt.forEpoch(epoch))
+	observeDelays(rt, clock, lid, 3500*time.Millisecond, minTimingSamples)
+	suggested, ok := rt.suggest(rt.delays[epoch])
+	require.True(t, ok)
+	require.Equal(t, Timing{WakeupDelta: 14 * time.Second, RoundDuration: 7 * time.Second}, suggested)
+
+	// suggested timing is not applied
+	require.Equal(t, configured, rt.forEpoch(epoch+1))
+
+	// not enough samples
+	observeDelays(rt, clock, lid.Add(uint32(types.GetLayersPerEpoch())), time.Second, minTimingSamples-1)
+	_, ok = rt.suggest(rt.delays[epoch+1])
+	require.False(t, ok)
+}
+
+func TestRoundTiming_Apply(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	clock := newMockClock()
+	lid := types.GetEffectiveGenesis().Add(1)
+	epoch := lid.GetEpoch()
+	cfg := timingConfig()
+	cfg.AdaptiveTiming = true
+	cfg.TimingEpoch = uint32(epoch + 2)
+	cfg.TimingRoundDuration = 7
+	cfg.TimingWakeupDelta = 14
+
+	configured := Timing{WakeupDelta: 20 * time.Second, RoundDuration: 10 * time.Second}
+	agreed := Timing{WakeupDelta: 14 * time.Second, RoundDuration: 7 * time.Second}
+	// nodes that observe different delays apply the same timing
+	for _, delay := range []time.Duration{time.Second, 20 * time.Second} {
+		rt := newRoundTiming(cfg, clock, logtest.New(t))
+		require.Equal(t, configured, rt.forEpoch(epoch))
+		observeDelays(rt, clock, lid, delay, minTimingSamples)
+		require.Equal(t, configured, rt.forEpoch(epoch+1))
+		require.Equal(t, agreed, rt.forEpoch(epoch+2))
+		require.Equal(t, agreed, rt.forEpoch(epoch+3))
+	}
+
+	// delays are observed relative to the applied timing
+	rt := newRoundTiming(cfg, clock, logtest.New(t))
+	lid = lid.Add(2 * uint32(types.GetLayersPerEpoch()))
+	require.Equal(t, agreed, rt.forEpoch(lid.GetEpoch()))
+	msg := BuildStatusMsg(signing.NewEdSigner(), NewSetFromValues(value1)).Message
+	msg.InnerMsg.InstanceID = lid
+	rt.observe(msg, agreed.roundStart(clock.LayerToTime(lid), msg.InnerMsg.K).Add(time.Second))
+	require.Equal(t, []time.Duration{time.Second}, rt.delays[lid.GetEpoch()])
+}
+
+func TestRoundTiming_Bounds(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	lid := types.GetEffectiveGenesis().Add(1)
+	for _, tc := range []struct {
+		desc     string
+		delay    time.Duration
+		offset   time.Duration
+		expected time.Duration
+	}{
+		{desc: "min", delay: 100 * time.Millisecond, expected: 5 * time.Second},
+		{desc: "max", delay: time.Minute, expected: 30 * time.Second},
+		{desc: "early", delay: -time.Second, expected: 5 * time.Second},
+		{desc: "offset", delay: 3 * time.Second, offset: -2 * time.Second, expected: 10 * time.Second},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			clock := newMockClock()
+			rt := newRoundTiming(timingConfig(), clock, logtest.New(t))
+			rt.offset = fixedOffset(tc.offset)
+
+			observeDelays(rt, clock, lid, tc.delay, minTimingSamples)
+			timing, ok := rt.suggest(rt.delays[lid.GetEpoch()])
+			require.True(t, ok)
+			require.Equal(t, tc.expected, timing.RoundDuration)
+			require.Equal(t, 2*tc.expected, timing.WakeupDelta)
+		})
+	}
+}
+
+func TestRoundTiming_Observe(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	clock := newMockClock()
+	lid := types.GetEffectiveGenesis().Add(1)
+	rt := newRoundTiming(timingConfig(), clock, logtest.New(t))
+	signer := signing.NewEdSigner()
+
+	// epoch didn't start
+	msg := BuildStatusMsg(signer, NewSetFromValues(value1)).Message
+	msg.InnerMsg.InstanceID = lid
+	rt.observe(msg, time.Now())
+	require.Empty(t, rt.delays)
+
+	timing := rt.forEpoch(lid.GetEpoch())
+	rt.observe(msg, timing.roundStart(clock.LayerToTime(lid), msg.InnerMsg.K).Add(time.Second))
+	require.Equal(t, []time.Duration{time.Second}, rt.delays[lid.GetEpoch()])
+
+	// certify messages are not sent at the start of the round
+	msg = BuildCertifyMsg(signer, NewSetFromValues(value1)).Message
+	msg.InnerMsg.InstanceID = lid
+	rt.observe(msg, time.Now())
+	require.Len(t, rt.delays[lid.GetEpoch()], 1)
+}
@@ -0,0 +1,381 @@
+package hare
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+)
+
+// types of the transcript events.
+const (
+	transcriptStart uint8 = iota + 1
+	transcriptMessage
+	transcriptPublish
+	transcriptRoundEnd
+	transcriptDecision
+	transcriptOutput
+	transcriptCertified
+)
+
+// kinds of the oracle decisions.
+const (
+	decisionProof uint8 = iota + 1
+	decisionEligibility
+	decisionActive
+	decisionRole
+)
+
+// maxTranscriptEvent is the upper bound for the size of a single encoded event.
+const maxTranscriptEvent = 1 << 24
+
+// transcriptEvent is a single record in the transcript of the consensus process.
+type transcriptEvent struct {
+	Type uint8
+	// Round is the round counter of the consensus process when the event was recorded.
+	// For decisions it is the round that was queried.
+	Round uint32
+	// Time when the event was recorded in unix nanoseconds.
+	Time int64
+
+	// Header is set for transcriptStart.
+	Header *transcriptHeader
+	// Message and PubKey of the sender are set for transcriptMessage. Message is set for transcriptPublish.
+	Message *Message
+	PubKey  []byte
+	// Pending is true if the message arrived early and was handled again by the consensus process.
+	Pending bool
+	// Decision is set for transcriptDecision.
+	Decision *oracleDecision
+	// Output is set for transcriptOutput.
+	Output *recordedOutput
+}
+
+// transcriptHeader is recorded once when the consensus process is created.
+type transcriptHeader struct {
+	Layer           types.LayerID
+	Set             []types.ProposalID
+	N               uint32
+	F               uint32
+	ExpectedLeaders uint32
+	LimitIterations uint32
+	LayersPerEpoch  uint16
+	NodeID          types.NodeID
+	PubKey          []byte
+}
+
+// oracleDecision is a result of the query to the oracle or to the eligibility validator.
+type oracleDecision struct {
+	Kind uint8
+	// ID is an identity for active and role decisions.
+	ID          string
+	Failed      bool
+	Valid       bool
+	Eligibility uint16
+	Proof       []byte
+}
+
+// recordedOutput is an output of the consensus process.
+type recordedOutput struct {
+	Completed bool
+	Coinflip  bool
+	Set       []types.ProposalID
+}
+
+func transcriptPath(dir string, lid types.LayerID) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.transcript", lid.Uint32()))
+}
+
+// transcript writes events of a single consensus process. Each event is encoded with codec and prefixed
+// with its length. Methods are safe to call on nil transcript, in which case nothing is recorded.
+type transcript struct {
+	logger log.Log
+
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	err    error
+	closed bool
+	// pending messages that consensus process will handle again.
+	pending map[*Msg]struct{}
+	// messages and published are the numbers of received and published messages.
+	// notify is signaled every time one of them is recorded.
+	messages  int
+	published int
+	notify    chan struct{}
+	done      chan struct{}
+}
+
+func newTranscript(w io.Writer, closer io.Closer, logger log.Log) *transcript {
+	return &transcript{
+		logger:  logger,
+		w:       bufio.NewWriter(w),
+		closer:  closer,
+		pending: map[*Msg]struct{}{},
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func createTranscript(path string, logger log.Log) (*transcript, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create transcript dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create transcript: %w", err)
+	}
+	return newTranscript(f, f, logger), nil
+}
+
+func (t *transcript) write(ev *transcriptEvent, flush bool) {
+	ev.Time = time.Now().UnixNano()
+	buf, err := codec.Encode(ev)
+	if err != nil {
+		t.logger.With().Panic("failed to encode transcript event", log.Err(err))
+	}
+	if t.err != nil || t.closed {
+		return
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(buf)))
+	if _, err = t.w.Write(prefix[:n]); err == nil {
+		_, err = t.w.Write(buf)
+	}
+	if err == nil && flush {
+		err = t.w.Flush()
+	}
+	if err != nil {
+		t.err = err
+		t.logger.With().Error("failed to write hare transcript", log.Err(err))
+	}
+}
+
+func (t *transcript) record(ev *transcriptEvent, flush bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write(ev, flush)
+}
+
+func (t *transcript) start(header *transcriptHeader) {
+	t.record(&transcriptEvent{Type: transcriptStart, Header: header}, true)
+}
+
+// setPending must be called with messages before they are passed to the inbox of the consensus process again.
+func (t *transcript) setPending(pending map[string]*Msg) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, m := range pending {
+		t.pending[m] = struct{}{}
+	}
+}
+
+func (t *transcript) message(k uint32, m *Msg) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, pending := t.pending[m]
+	delete(t.pending, m)
+	t.write(&transcriptEvent{
+		Type:    transcriptMessage,
+		Round:   k,
+		Message: m.Message,
+		PubKey:  m.PubKey.Bytes(),
+		Pending: pending,
+	}, false)
+	t.messages++
+	t.signal()
+}
+
+func (t *transcript) publish(k uint32, m *Msg) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write(&transcriptEvent{Type: transcriptPublish, Round: k, Message: m.Message}, false)
+	t.published++
+	t.signal()
+}
+
+func (t *transcript) signal() {
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (t *transcript) roundEnd(k uint32) {
+	t.record(&transcriptEvent{Type: transcriptRoundEnd, Round: k}, true)
+}
+
+func (t *transcript) decision(round uint32, decision *oracleDecision) {
+	t.record(&transcriptEvent{Type: transcriptDecision, Round: round, Decision: decision}, false)
+}
+
+func (t *transcript) output(k uint32, completed, coinflip bool, s *Set) {
+	out := &recordedOutput{Completed: completed, Coinflip: coinflip}
+	if s != nil {
+		out.Set = s.elements()
+	}
+	t.record(&transcriptEvent{Type: transcriptOutput, Round: k, Output: out}, true)
+}
+
+func (t *transcript) certified(k uint32) {
+	t.record(&transcriptEvent{Type: transcriptCertified, Round: k}, true)
+}
+
+func (t *transcript) recorded() (messages, published int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.messages, t.published
+}
+
+func (t *transcript) close() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.closed = true
+	if t.err == nil {
+		t.err = t.w.Flush()
+	}
+	if t.closer != nil {
+		if err := t.closer.Close(); err != nil {
+			t.logger.With().Error("failed to close hare transcript", log.Err(err))
+		}
+	}
+	close(t.done)
+}
+
+func readTranscript(r io.Reader) ([]transcriptEvent, error) {
+	var (
+		br     = bufio.NewReader(r)
+		events []transcriptEvent
+	)
+	for {
+		size, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("read transcript event size: %w", err)
+		}
+		if size > maxTranscriptEvent {
+			return nil, fmt.Errorf("transcript event is too large: %d", size)
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, fmt.Errorf("read transcript event: %w", err)
+		}
+		var ev transcriptEvent
+		if err := codec.Decode(buf, &ev); err != nil {
+			return nil, fmt.Errorf("decode transcript event: %w", err)
+		}
+		events = append(events, ev)
+	}
+}
+
+// newRecordedConsensusProcess creates consensus process that writes its transcript to the directory.
+// If transcript can't be created the process is not recorded.
+func newRecordedConsensusProcess(dir string, cfg config.Config, instanceID types.LayerID, s *Set, oracle Rolacle,
+	stateQuerier stateQuerier, layersPerEpoch uint16, signing Signer, nid types.NodeID, p2p pubsub.Publisher,
+	terminationReport chan TerminationOutput,
+	certificationReport chan CertificationOutput,
+	ev roleValidator, clock RoundClock, logger log.Log) *consensusProcess {
+	t, err := createTranscript(transcriptPath(dir, instanceID), logger)
+	if err != nil {
+		logger.With().Error("consensus process is not recorded", instanceID, log.Err(err))
+		return newConsensusProcess(cfg, instanceID, s, oracle, stateQuerier, layersPerEpoch, signing, nid, p2p,
+			terminationReport, certificationReport, ev, clock, logger)
+	}
+	t.start(&transcriptHeader{
+		Layer:           instanceID,
+		Set:             s.elements(),
+		N:               uint32(cfg.N),
+		F:               uint32(cfg.F),
+		ExpectedLeaders: uint32(cfg.ExpectedLeaders),
+		LimitIterations: uint32(cfg.LimitIterations),
+		LayersPerEpoch:  layersPerEpoch,
+		NodeID:          nid,
+		PubKey:          signing.PublicKey().Bytes(),
+	})
+	proc := newConsensusProcess(cfg, instanceID, s,
+		&recordingOracle{Rolacle: oracle, transcript: t},
+		&recordingStateQuerier{stateQuerier: stateQuerier, transcript: t},
+		layersPerEpoch, signing, nid, p2p,
+		terminationReport, certificationReport,
+		&recordingRoleValidator{roleValidator: ev, transcript: t},
+		clock, logger)
+	proc.transcript = t
+	return proc
+}
+
+// recordingOracle records decisions that are used by the consensus process.
+type recordingOracle struct {
+	Rolacle
+	transcript *transcript
+}
+
+func (o *recordingOracle) Proof(ctx context.Context, lid types.LayerID, k uint32) ([]byte, error) {
+	proof, err := o.Rolacle.Proof(ctx, lid, k)
+	o.transcript.decision(k, &oracleDecision{Kind: decisionProof, Failed: err != nil, Proof: proof})
+	return proof, err
+}
+
+func (o *recordingOracle) CalcEligibility(ctx context.Context, lid types.LayerID, k uint32, committee int, nid types.NodeID, proof []byte) (uint16, error) {
+	eligibility, err := o.Rolacle.CalcEligibility(ctx, lid, k, committee, nid, proof)
+	o.transcript.decision(k, &oracleDecision{Kind: decisionEligibility, Failed: err != nil, Eligibility: eligibility})
+	return eligibility, err
+}
+
+func (o *recordingOracle) IsIdentityActiveOnConsensusView(ctx context.Context, id string, lid types.LayerID) (bool, error) {
+	active, err := o.Rolacle.IsIdentityActiveOnConsensusView(ctx, id, lid)
+	o.transcript.decision(0, &oracleDecision{Kind: decisionActive, ID: id, Failed: err != nil, Valid: active})
+	return active, err
+}
+
+type recordingStateQuerier struct {
+	stateQuerier
+	transcript *transcript
+}
+
+func (q *recordingStateQuerier) IsIdentityActiveOnConsensusView(ctx context.Context, id string, lid types.LayerID) (bool, error) {
+	active, err := q.stateQuerier.IsIdentityActiveOnConsensusView(ctx, id, lid)
+	q.transcript.decision(0, &oracleDecision{Kind: decisionActive, ID: id, Failed: err != nil, Valid: active})
+	return active, err
+}
+
+type recordingRoleValidator struct {
+	roleValidator
+	transcript *transcript
+}
+
+func (v *recordingRoleValidator) Validate(ctx context.Context, m *Msg) bool {
+	valid := v.roleValidator.Validate(ctx, m)
+	v.transcript.decision(m.InnerMsg.K, &oracleDecision{Kind: decisionRole, ID: m.PubKey.String(), Valid: valid})
+	return valid
+}
@@ -0,0 +1,179 @@
+package hare
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/eligibility"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/hare/mocks"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	pmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+func createRecordedConsensusProcess(tb testing.TB, dir string, cfg config.Config, oracle fullRolacle,
+	network pubsub.PublishSubsciber, initialSet *Set, layer types.LayerID) *consensusProcess {
+	broker := buildBroker(tb, tb.Name())
+	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockStateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	broker.Start(context.TODO())
+	tb.Cleanup(broker.Close)
+	network.Register(protoName, broker.HandleMessage)
+	output := make(chan TerminationOutput, 1)
+	certs := make(chan CertificationOutput, 1)
+	signer := signing.NewEdSigner()
+	oracle.Register(true, signer.PublicKey().String())
+	proc := newRecordedConsensusProcess(dir, cfg, layer, initialSet, oracle, broker.mockStateQ, 10, signer,
+		types.NodeID{Key: signer.PublicKey().String(), VRFPublicKey: []byte{}}, network, output, certs, truer{},
+		nil, logtest.New(tb).WithName(signer.PublicKey().ShortString()))
+	require.NotNil(tb, proc.transcript)
+	c, err := broker.Register(context.TODO(), proc.ID())
+	require.NoError(tb, err)
+	proc.SetInbox(c)
+	return proc
+}
+
+func replayFile(tb testing.TB, path string) (*TranscriptResult, *TranscriptResult) {
+	tb.Helper()
+	f, err := os.Open(path)
+	require.NoError(tb, err)
+	defer f.Close()
+	recorded, replayed, err := ReplayTranscript(context.TODO(), f, logtest.New(tb))
+	require.NoError(tb, err)
+	return recorded, replayed
+}
+
+func TestTranscriptReplayTermination(t *testing.T) {
+	const totalNodes = 6
+	cfg := config.Config{N: totalNodes, F: totalNodes / 2, ExpectedLeaders: 5, LimitIterations: 4}
+	layer := types.GetEffectiveGenesis().Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mesh, err := mocknet.FullMeshLinked(ctx, totalNodes)
+	require.NoError(t, err)
+
+	oracle := eligibility.New(logtest.New(t))
+	dirs := make([]string, totalNodes)
+	procs := make([]*consensusProcess, totalNodes)
+	for i := range procs {
+		ps, err := pubsub.New(ctx, logtest.New(t), mesh.Hosts()[i], pubsub.DefaultConfig())
+		require.NoError(t, err)
+		dirs[i] = t.TempDir()
+		procs[i] = createRecordedConsensusProcess(t, dirs[i], cfg, oracle, ps, NewSetFromValues(value1), layer)
+	}
+	require.NoError(t, mesh.ConnectAllButSelf())
+	// rounds are counted from the moment all processes are ready to start
+	clock := NewSimpleRoundClock(time.Now(), 0, 500*time.Millisecond)
+	for _, proc := range procs {
+		proc.clock = clock
+		require.NoError(t, proc.Start(ctx))
+	}
+	for _, proc := range procs {
+		select {
+		case <-proc.transcript.done:
+		case <-time.After(30 * time.Second):
+			require.FailNow(t, "timed out waiting for termination")
+		}
+	}
+
+	for _, dir := range dirs {
+		recorded, replayed := replayFile(t, transcriptPath(dir, layer))
+		// depending on the timing of the messages the process may not complete
+		require.Equal(t, layer, recorded.Layer)
+		require.True(t, recorded.Terminated)
+		require.Equal(t, recorded, replayed)
+	}
+}
+
+func TestTranscriptReplayNonTermination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	publisher := pmocks.NewMockPublisher(ctrl)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	stateQ := mocks.NewMockstateQuerier(ctrl)
+	stateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	// own messages are not delivered, therefore the process never crosses the threshold
+	cfg := config.Config{N: 10, F: 5, ExpectedLeaders: 5, LimitIterations: 1}
+	layer := types.GetEffectiveGenesis().Add(1)
+	dir := t.TempDir()
+	oracle := eligibility.New(logtest.New(t))
+	signer := signing.NewEdSigner()
+	oracle.Register(true, signer.PublicKey().String())
+	proc := newRecordedConsensusProcess(dir, cfg, layer, NewSetFromValues(value1, value2), oracle, stateQ, 10, signer,
+		types.NodeID{Key: signer.PublicKey().String()}, publisher,
+		make(chan TerminationOutput, 1), make(chan CertificationOutput, 1), truer{},
+		NewSimpleRoundClock(time.Now(), 0, 20*time.Millisecond), logtest.New(t))
+	proc.SetInbox(make(chan *Msg))
+	require.NoError(t, proc.Start(context.TODO()))
+	select {
+	case <-proc.transcript.done:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "timed out waiting for iterations limit")
+	}
+
+	recorded, replayed := replayFile(t, transcriptPath(dir, layer))
+	require.True(t, recorded.Terminated)
+	require.False(t, recorded.Completed)
+	require.Equal(t, uint32(RoundsPerIteration), recorded.Round)
+	require.Equal(t, recorded, replayed)
+}
+
+func TestTranscriptEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	tr := newTranscript(&buf, nil, logtest.New(t))
+	signer := signing.NewEdSigner()
+	header := &transcriptHeader{
+		Layer:  types.NewLayerID(10),
+		Set:    []types.ProposalID{value1, value2},
+		N:      10,
+		F:      5,
+		NodeID: types.NodeID{Key: signer.PublicKey().String()},
+		PubKey: signer.PublicKey().Bytes(),
+	}
+	tr.start(header)
+	msg := BuildPreRoundMsg(signer, NewSetFromValues(value1), nil)
+	tr.setPending(map[string]*Msg{"": msg})
+	tr.message(preRound, msg)
+	tr.publish(preRound, msg)
+	tr.decision(preRound, &oracleDecision{Kind: decisionProof, Proof: []byte{1, 2}})
+	tr.roundEnd(preRound)
+	tr.output(3, true, false, NewSetFromValues(value1))
+	tr.close()
+	<-tr.done
+	handled, published := tr.recorded()
+	require.Equal(t, 1, handled)
+	require.Equal(t, 1, published)
+
+	events, err := readTranscript(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+	require.Equal(t, header, events[0].Header)
+	require.Equal(t, transcriptMessage, events[1].Type)
+	require.True(t, events[1].Pending)
+	require.Equal(t, msg.Message, events[1].Message)
+	require.Equal(t, signer.PublicKey().Bytes(), events[1].PubKey)
+	require.Equal(t, transcriptPublish, events[2].Type)
+	require.Equal(t, []byte{1, 2}, events[3].Decision.Proof)
+	require.Equal(t, transcriptRoundEnd, events[4].Type)
+	require.Equal(t, &TranscriptResult{
+		Layer:      header.Layer,
+		Terminated: true,
+		Completed:  true,
+		Round:      3,
+		Set:        []types.ProposalID{value1},
+	}, transcriptResult(events))
+
+	_, err = readTranscript(bytes.NewReader([]byte{1, 0}))
+	require.Error(t, err)
+}
@@ -67,6 +67,11 @@ type poetDB interface {
 	ValidateAndStoreMsg(data []byte) error
 }
 
+// hareOutputVerifier verifies the hare output that the peer reports for the layer.
+type hareOutputVerifier interface {
+	VerifyOutput(context.Context, p2p.Peer, types.LayerID, types.BlockID) error
+}
+
 type network interface {
 	bootstrap.Provider
 	server.Host
@@ -121,6 +126,7 @@ type Logic struct {
 	proposalHandler  proposalHandler
 	txHandler        txHandler
 	layerDB          layerDB
+	hareOutputs      hareOutputVerifier
 	atxIds           atxIDsDB
 	goldenATXID      types.ATXID
 }
@@ -176,6 +182,12 @@ func NewLogic(ctx context.Context, cfg Config, poet poetDB, atxIDs atxIDsDB, lay
 	return l
 }
 
+// SetHareOutputVerifier sets the verifier for the hare outputs reported by peers.
+// Hare outputs are adopted from peers without verification if it is not set.
+func (l *Logic) SetHareOutputVerifier(verifier hareOutputVerifier) {
+	l.hareOutputs = verifier
+}
+
 // Start starts layerFetcher logic and fetch component.
 func (l *Logic) Start() {
 	l.fetcher.Start()
@@ -300,7 +312,7 @@ func (l *Logic) PollLayerContent(ctx context.Context, layerID types.LayerID) cha
 }
 
 // fetchLayerData fetches the all content referenced in layerData.
-func (l *Logic) fetchLayerData(ctx context.Context, logger log.Log, layerID types.LayerID, blocks *layerData) error {
+func (l *Logic) fetchLayerData(ctx context.Context, logger log.Log, layerID types.LayerID, peer p2p.Peer, blocks *layerData) error {
 	logger = logger.WithFields(log.Int("num_ballots", len(blocks.Ballots)), log.Int("num_blocks", len(blocks.Blocks)))
 	l.mutex.Lock()
 	lyrResult := l.layerBlocksRes[layerID]
@@ -320,12 +332,8 @@ func (l *Logic) fetchLayerData(ctx context.Context, logger log.Log, layerID type
 			blocksToFetch = append(blocksToFetch, blkID)
 		}
 	}
-	if lyrResult.hareOutput == types.EmptyBlockID {
-		if blocks.HareOutput != types.EmptyBlockID {
-			logger.Info("adopting hare output", blocks.HareOutput)
-			lyrResult.hareOutput = blocks.HareOutput
-		}
-	} else if blocks.HareOutput != types.EmptyBlockID && lyrResult.hareOutput != blocks.HareOutput {
+	adopt := lyrResult.hareOutput == types.EmptyBlockID && blocks.HareOutput != types.EmptyBlockID
+	if !adopt && blocks.HareOutput != types.EmptyBlockID && lyrResult.hareOutput != blocks.HareOutput {
 		logger.With().Warning("found different hare output from peer", blocks.HareOutput)
 		// TODO do something in combination of the layer hash difference and resolve differences with peers
 	}
@@ -341,9 +349,32 @@ func (l *Logic) fetchLayerData(ctx context.Context, logger log.Log, layerID type
 		// fail sync for the entire layer
 		return err
 	}
+	if adopt {
+		l.adoptHareOutput(ctx, logger, layerID, peer, blocks.HareOutput)
+	}
 	return nil
 }
 
+// adoptHareOutput adopts the hare output reported by the peer if it is verified.
+// The output that fails verification is ignored, the layer is then decided by the tortoise.
+func (l *Logic) adoptHareOutput(ctx context.Context, logger log.Log, layerID types.LayerID, peer p2p.Peer, bid types.BlockID) {
+	if l.hareOutputs != nil {
+		if err := l.hareOutputs.VerifyOutput(ctx, peer, layerID, bid); err != nil {
+			logger.With().Warning("failed to verify hare output from peer", bid, log.Err(err))
+			return
+		}
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	lyrResult := l.layerBlocksRes[layerID]
+	if lyrResult.hareOutput == types.EmptyBlockID {
+		logger.Info("adopting hare output", bid)
+		lyrResult.hareOutput = bid
+	} else if lyrResult.hareOutput != bid {
+		logger.With().Warning("found different hare output from peer", bid)
+	}
+}
+
 func extractPeerResult(logger log.Log, layerID types.LayerID, data []byte, peerErr error) (result peerResult) {
 	result.err = peerErr
 	if peerErr != nil {
@@ -370,7 +401,7 @@ func (l *Logic) receiveLayerContent(ctx context.Context, layerID types.LayerID,
 	logger.Debug("received layer content from peer")
 	peerRes := extractPeerResult(logger, layerID, data, peerErr)
 	if peerRes.err == nil {
-		if err := l.fetchLayerData(ctx, logger, layerID, peerRes.data); err != nil {
+		if err := l.fetchLayerData(ctx, logger, layerID, peer, peerRes.data); err != nil {
 			peerRes.err = ErrLayerDataNotFetched
 		}
 	}
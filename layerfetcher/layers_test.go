@@ -494,6 +494,61 @@ func TestPollLayerBlocks_DifferentHareOutputIgnored(t *testing.T) {
 	assert.Equal(t, layerID, res.Layer)
 }
 
+func TestPollLayerBlocks_HareOutputVerified(t *testing.T) {
+	net := newMockNet(t)
+	numPeers := 4
+	for i := 0; i < numPeers; i++ {
+		peer := randPeer(t)
+		net.peers = append(net.peers, peer)
+		net.layerData[peer] = generateLayerContent(false)
+	}
+	verified := net.peers[2]
+	var data layerData
+	require.NoError(t, codec.Decode(net.layerData[verified], &data))
+
+	layerID := types.NewLayerID(10)
+	tl := createTestLogicWithMocknet(t, net)
+	verifier := mocks.NewMockhareOutputVerifier(tl.ctrl)
+	tl.SetHareOutputVerifier(verifier)
+	tl.mFetcher.EXPECT().GetHashes(gomock.Any(), fetch.BallotDB, false).Return(nil).Times(numPeers)
+	tl.mFetcher.EXPECT().GetHashes(gomock.Any(), fetch.BlockDB, false).Return(nil).Times(numPeers)
+	verifier.EXPECT().VerifyOutput(gomock.Any(), gomock.Any(), layerID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, peer p2p.Peer, _ types.LayerID, _ types.BlockID) error {
+			if peer == verified {
+				return nil
+			}
+			return errors.New("invalid output")
+		}).MinTimes(1).MaxTimes(numPeers)
+	tl.mLayerDB.EXPECT().SaveHareConsensusOutput(gomock.Any(), layerID, data.HareOutput).Return(nil).Times(1)
+
+	res := <-tl.PollLayerContent(context.TODO(), layerID)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, layerID, res.Layer)
+}
+
+func TestPollLayerBlocks_HareOutputNotVerified(t *testing.T) {
+	net := newMockNet(t)
+	numPeers := 4
+	for i := 0; i < numPeers; i++ {
+		peer := randPeer(t)
+		net.peers = append(net.peers, peer)
+		net.layerData[peer] = generateLayerContent(false)
+	}
+
+	layerID := types.NewLayerID(10)
+	tl := createTestLogicWithMocknet(t, net)
+	verifier := mocks.NewMockhareOutputVerifier(tl.ctrl)
+	tl.SetHareOutputVerifier(verifier)
+	tl.mFetcher.EXPECT().GetHashes(gomock.Any(), fetch.BallotDB, false).Return(nil).Times(numPeers)
+	tl.mFetcher.EXPECT().GetHashes(gomock.Any(), fetch.BlockDB, false).Return(nil).Times(numPeers)
+	verifier.EXPECT().VerifyOutput(gomock.Any(), gomock.Any(), layerID, gomock.Any()).Return(errors.New("invalid output")).Times(numPeers)
+	tl.mLayerDB.EXPECT().SaveHareConsensusOutput(gomock.Any(), layerID, types.EmptyBlockID).Return(nil).Times(1)
+
+	res := <-tl.PollLayerContent(context.TODO(), layerID)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, layerID, res.Layer)
+}
+
 func TestPollLayerBlocks_FailureToSaveZeroBlockLayerIgnored(t *testing.T) {
 	net := newMockNet(t)
 	numPeers := 4
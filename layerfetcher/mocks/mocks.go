@@ -13,6 +13,7 @@ import (
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	protocol "github.com/libp2p/go-libp2p-core/protocol"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	p2p "github.com/spacemeshos/go-spacemesh/p2p"
 )
 
 // MockatxHandler is a mock of atxHandler interface.
@@ -441,6 +442,43 @@ func (mr *MockpoetDBMockRecorder) ValidateAndStoreMsg(data interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAndStoreMsg", reflect.TypeOf((*MockpoetDB)(nil).ValidateAndStoreMsg), data)
 }
 
+// MockhareOutputVerifier is a mock of hareOutputVerifier interface.
+type MockhareOutputVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockhareOutputVerifierMockRecorder
+}
+
+// MockhareOutputVerifierMockRecorder is the mock recorder for MockhareOutputVerifier.
+type MockhareOutputVerifierMockRecorder struct {
+	mock *MockhareOutputVerifier
+}
+
+// NewMockhareOutputVerifier creates a new mock instance.
+func NewMockhareOutputVerifier(ctrl *gomock.Controller) *MockhareOutputVerifier {
+	mock := &MockhareOutputVerifier{ctrl: ctrl}
+	mock.recorder = &MockhareOutputVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockhareOutputVerifier) EXPECT() *MockhareOutputVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyOutput mocks base method.
+func (m *MockhareOutputVerifier) VerifyOutput(arg0 context.Context, arg1 p2p.Peer, arg2 types.LayerID, arg3 types.BlockID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyOutput", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyOutput indicates an expected call of VerifyOutput.
+func (mr *MockhareOutputVerifierMockRecorder) VerifyOutput(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyOutput", reflect.TypeOf((*MockhareOutputVerifier)(nil).VerifyOutput), arg0, arg1, arg2, arg3)
+}
+
 // Mocknetwork is a mock of network interface.
 type Mocknetwork struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,142 @@
+// Package malfeasance exchanges proofs that identities violated the protocol.
+package malfeasance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+)
+
+// Protocol is the gossip protocol for malfeasance proofs.
+const Protocol = "MalfeasanceProof"
+
+var (
+	errMalformedData = errors.New("malformed data")
+	errKnownProof    = errors.New("known proof")
+)
+
+// Handler verifies proofs of malfeasance from gossip, persists them and removes
+// weight of the malicious identities from the tortoise.
+type Handler struct {
+	logger log.Log
+	self   peer.ID
+
+	db        sql.Executor
+	publisher pubsub.Publisher
+	trtl      tortoise
+}
+
+// Opt for configuring Handler.
+type Opt func(*Handler)
+
+// WithLogger defines logger for Handler.
+func WithLogger(logger log.Log) Opt {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// NewHandler creates new Handler. Self is the id of the local peer, proofs that are
+// published by the node itself are persisted before they are published.
+func NewHandler(db sql.Executor, self peer.ID, publisher pubsub.Publisher, trtl tortoise, opts ...Opt) *Handler {
+	h := &Handler{
+		logger:    log.NewNop(),
+		self:      self,
+		db:        db,
+		publisher: publisher,
+		trtl:      trtl,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Publish proof that was detected locally. Proof must be already persisted.
+func (h *Handler) Publish(ctx context.Context, proof *types.MalfeasanceProof) error {
+	data, err := codec.Encode(proof)
+	if err != nil {
+		h.logger.With().Panic("failed to encode malfeasance proof", log.Err(err))
+	}
+	if err := h.publisher.Publish(ctx, Protocol, data); err != nil {
+		return fmt.Errorf("publish malfeasance proof: %w", err)
+	}
+	return nil
+}
+
+// Report persists proof that was detected locally, removes weight of the malicious identity
+// from the tortoise and publishes proof to peers. Proof is not published if it is already known.
+// Proof is persisted before it is published, and is accepted by the gossip validator because
+// it is published by the local peer.
+func (h *Handler) Report(ctx context.Context, proof *types.MalfeasanceProof) error {
+	data, err := codec.Encode(proof)
+	if err != nil {
+		h.logger.With().Panic("failed to encode malfeasance proof", log.Err(err))
+	}
+	if err := h.storeProof(h.logger.WithContext(ctx), proof, data); errors.Is(err, errKnownProof) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := h.publisher.Publish(ctx, Protocol, data); err != nil {
+		return fmt.Errorf("publish malfeasance proof: %w", err)
+	}
+	return nil
+}
+
+// HandleMalfeasanceProof is the gossip receiver for MalfeasanceProof.
+func (h *Handler) HandleMalfeasanceProof(ctx context.Context, pid peer.ID, msg []byte) pubsub.ValidationResult {
+	logger := h.logger.WithContext(ctx)
+	if err := h.handleProof(logger, msg); errors.Is(err, errKnownProof) {
+		if pid == h.self {
+			// gossip validates messages that are published locally
+			return pubsub.ValidationAccept
+		}
+		return pubsub.ValidationIgnore
+	} else if errors.Is(err, errMalformedData) || errors.Is(err, types.ErrInvalidProof) {
+		logger.With().Warning("rejected malfeasance proof", log.Err(err))
+		return pubsub.ValidationReject
+	} else if err != nil {
+		logger.With().Error("failed to process malfeasance proof", log.Err(err))
+		return pubsub.ValidationIgnore
+	}
+	return pubsub.ValidationAccept
+}
+
+func (h *Handler) handleProof(logger log.Log, data []byte) error {
+	var proof types.MalfeasanceProof
+	if err := codec.Decode(data, &proof); err != nil {
+		return fmt.Errorf("%w: %s", errMalformedData, err)
+	}
+	return h.storeProof(logger, &proof, data)
+}
+
+func (h *Handler) storeProof(logger log.Log, proof *types.MalfeasanceProof, data []byte) error {
+	smesher, err := proof.Verify()
+	if err != nil {
+		return err
+	}
+	logger = logger.WithFields(log.Stringer("smesher", smesher), log.Inline(proof))
+
+	_, lid, err := identities.GetProof(h.db, smesher.Bytes())
+	if err == nil && !proof.Layer.Before(lid) {
+		return errKnownProof
+	} else if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return err
+	}
+	if err := identities.SetProof(h.db, smesher.Bytes(), proof.Layer, data); err != nil {
+		return err
+	}
+	logger.With().Warning("smesher is proven to be malicious")
+	h.trtl.OnMalfeasance(smesher, proof.Layer)
+	return nil
+}
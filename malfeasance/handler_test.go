@@ -0,0 +1,228 @@
+package malfeasance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/malfeasance/mocks"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	pmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+)
+
+func signedBallot(signer *signing.EdSigner, lid types.LayerID) types.Ballot {
+	b := types.Ballot{
+		InnerBallot: types.InnerBallot{
+			AtxID:      types.RandomATXID(),
+			Votes:      types.Votes{Base: types.RandomBallotID()},
+			LayerIndex: lid,
+		},
+	}
+	b.Signature = signer.Sign(b.Bytes())
+	return b
+}
+
+func encodedProof(tb testing.TB, proof *types.MalfeasanceProof) []byte {
+	tb.Helper()
+	data, err := codec.Encode(proof)
+	require.NoError(tb, err)
+	return data
+}
+
+const localPeer = "local"
+
+type testHandler struct {
+	*Handler
+	db        *sql.Database
+	trtl      *mocks.Mocktortoise
+	publisher *pmocks.MockPublisher
+}
+
+func newTestHandler(tb testing.TB) *testHandler {
+	ctrl := gomock.NewController(tb)
+	th := &testHandler{
+		db:        sql.InMemory(),
+		trtl:      mocks.NewMocktortoise(ctrl),
+		publisher: pmocks.NewMockPublisher(ctrl),
+	}
+	th.Handler = NewHandler(th.db, localPeer, th.publisher, th.trtl, WithLogger(logtest.New(tb)))
+	return th
+}
+
+func TestHandleMalfeasanceProof(t *testing.T) {
+	lid := types.NewLayerID(10)
+	signer := signing.NewEdSigner()
+	proof := func(lid types.LayerID) *types.MalfeasanceProof {
+		return &types.MalfeasanceProof{
+			Layer:   lid,
+			Type:    types.MultipleBallots,
+			Ballots: []types.Ballot{signedBallot(signer, lid), signedBallot(signer, lid)},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		th := newTestHandler(t)
+		data := encodedProof(t, proof(lid))
+		th.trtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+		require.Equal(t, pubsub.ValidationAccept, th.HandleMalfeasanceProof(context.TODO(), "", data))
+
+		stored, got, err := identities.GetProof(th.db, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		require.Equal(t, data, stored)
+		require.Equal(t, lid, got)
+
+		// known proof is not broadcasted again
+		require.Equal(t, pubsub.ValidationIgnore, th.HandleMalfeasanceProof(context.TODO(), "", data))
+		require.Equal(t, pubsub.ValidationIgnore,
+			th.HandleMalfeasanceProof(context.TODO(), "", encodedProof(t, proof(lid.Add(1)))))
+
+		// proof for the earlier layer replaces known proof
+		earlier := encodedProof(t, proof(lid.Sub(1)))
+		th.trtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid.Sub(1))
+		require.Equal(t, pubsub.ValidationAccept, th.HandleMalfeasanceProof(context.TODO(), "", earlier))
+		stored, got, err = identities.GetProof(th.db, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		require.Equal(t, earlier, stored)
+		require.Equal(t, lid.Sub(1), got)
+	})
+	t.Run("known from local peer", func(t *testing.T) {
+		th := newTestHandler(t)
+		data := encodedProof(t, proof(lid))
+		th.trtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+		require.Equal(t, pubsub.ValidationAccept, th.HandleMalfeasanceProof(context.TODO(), "", data))
+
+		// proof is persisted before it is published by the node
+		require.Equal(t, pubsub.ValidationAccept, th.HandleMalfeasanceProof(context.TODO(), localPeer, data))
+	})
+	t.Run("malformed", func(t *testing.T) {
+		th := newTestHandler(t)
+		require.Equal(t, pubsub.ValidationReject,
+			th.HandleMalfeasanceProof(context.TODO(), "", []byte("malformed")))
+	})
+	t.Run("different identities", func(t *testing.T) {
+		th := newTestHandler(t)
+		invalid := proof(lid)
+		invalid.Ballots[1] = signedBallot(signing.NewEdSigner(), lid)
+		require.Equal(t, pubsub.ValidationReject,
+			th.HandleMalfeasanceProof(context.TODO(), "", encodedProof(t, invalid)))
+		mal, err := identities.IsMalicious(th.db, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		require.False(t, mal)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	th := newTestHandler(t)
+	signer := signing.NewEdSigner()
+	lid := types.NewLayerID(10)
+	proof := &types.MalfeasanceProof{
+		Layer:   lid,
+		Type:    types.MultipleBallots,
+		Ballots: []types.Ballot{signedBallot(signer, lid), signedBallot(signer, lid)},
+	}
+	th.publisher.EXPECT().Publish(gomock.Any(), Protocol, encodedProof(t, proof)).Return(nil)
+	require.NoError(t, th.Publish(context.TODO(), proof))
+}
+
+func signedHareMsg(signer *signing.EdSigner, lid types.LayerID, content []byte) types.HareProofMsg {
+	msg := types.HareProofMsg{InnerMsg: types.HareMetadata{Layer: lid, Round: 3, MsgHash: types.CalcHash32(content)}}
+	msg.Signature = signer.Sign(msg.InnerMsg.Bytes())
+	return msg
+}
+
+func TestReport(t *testing.T) {
+	signer := signing.NewEdSigner()
+	lid := types.NewLayerID(10)
+	proof := &types.MalfeasanceProof{
+		Layer:    lid,
+		Type:     types.HareEquivocation,
+		Messages: []types.HareProofMsg{signedHareMsg(signer, lid, []byte{1}), signedHareMsg(signer, lid, []byte{2})},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		th := newTestHandler(t)
+		data := encodedProof(t, proof)
+		th.trtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+		th.publisher.EXPECT().Publish(gomock.Any(), Protocol, data).Return(nil)
+		require.NoError(t, th.Report(context.TODO(), proof))
+
+		stored, got, err := identities.GetProof(th.db, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		require.Equal(t, data, stored)
+		require.Equal(t, lid, got)
+
+		// known proof is not published again
+		require.NoError(t, th.Report(context.TODO(), proof))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		th := newTestHandler(t)
+		invalid := *proof
+		invalid.Messages = []types.HareProofMsg{proof.Messages[0], signedHareMsg(signing.NewEdSigner(), lid, []byte{2})}
+		require.ErrorIs(t, th.Report(context.TODO(), &invalid), types.ErrInvalidProof)
+		mal, err := identities.IsMalicious(th.db, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		require.False(t, mal)
+	})
+}
+
+func TestReportGossip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mn, err := mocknet.FullMeshLinked(ctx, 2)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	hosts := mn.Hosts()
+	pubsubs := []*pubsub.PubSub{}
+	dbs := []*sql.Database{}
+	handlers := []*Handler{}
+	trtls := []*mocks.Mocktortoise{}
+	for _, h := range hosts {
+		ps, err := pubsub.New(ctx, logtest.New(t), h, pubsub.Config{Flood: true})
+		require.NoError(t, err)
+		db := sql.InMemory()
+		trtl := mocks.NewMocktortoise(ctrl)
+		handler := NewHandler(db, h.ID(), ps, trtl, WithLogger(logtest.New(t)))
+		ps.Register(Protocol, handler.HandleMalfeasanceProof)
+		pubsubs = append(pubsubs, ps)
+		dbs = append(dbs, db)
+		handlers = append(handlers, handler)
+		trtls = append(trtls, trtl)
+	}
+	require.NoError(t, mn.ConnectAllButSelf())
+	require.Eventually(t, func() bool {
+		for _, ps := range pubsubs {
+			if len(ps.ProtocolPeers(Protocol)) != len(hosts)-1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	signer := signing.NewEdSigner()
+	lid := types.NewLayerID(10)
+	proof := &types.MalfeasanceProof{
+		Layer:    lid,
+		Type:     types.HareEquivocation,
+		Messages: []types.HareProofMsg{signedHareMsg(signer, lid, []byte{1}), signedHareMsg(signer, lid, []byte{2})},
+	}
+	for _, trtl := range trtls {
+		trtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+	}
+	require.NoError(t, handlers[0].Report(ctx, proof))
+	require.Eventually(t, func() bool {
+		mal, err := identities.IsMalicious(dbs[1], signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		return mal
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,12 @@
+package malfeasance
+
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+//go:generate mockgen -package=mocks -destination=./mocks/mocks.go -source=./interface.go
+
+type tortoise interface {
+	OnMalfeasance(*signing.PublicKey, types.LayerID)
+}
@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./interface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/spacemeshos/go-spacemesh/common/types"
+	signing "github.com/spacemeshos/go-spacemesh/signing"
+)
+
+// Mocktortoise is a mock of tortoise interface.
+type Mocktortoise struct {
+	ctrl     *gomock.Controller
+	recorder *MocktortoiseMockRecorder
+}
+
+// MocktortoiseMockRecorder is the mock recorder for Mocktortoise.
+type MocktortoiseMockRecorder struct {
+	mock *Mocktortoise
+}
+
+// NewMocktortoise creates a new mock instance.
+func NewMocktortoise(ctrl *gomock.Controller) *Mocktortoise {
+	mock := &Mocktortoise{ctrl: ctrl}
+	mock.recorder = &MocktortoiseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocktortoise) EXPECT() *MocktortoiseMockRecorder {
+	return m.recorder
+}
+
+// OnMalfeasance mocks base method.
+func (m *Mocktortoise) OnMalfeasance(arg0 *signing.PublicKey, arg1 types.LayerID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnMalfeasance", arg0, arg1)
+}
+
+// OnMalfeasance indicates an expected call of OnMalfeasance.
+func (mr *MocktortoiseMockRecorder) OnMalfeasance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnMalfeasance", reflect.TypeOf((*Mocktortoise)(nil).OnMalfeasance), arg0, arg1)
+}
@@ -36,3 +36,12 @@ func (bc blockCache) Get(id types.BlockID) *types.Block {
 	blk := item.(types.Block)
 	return &blk
 }
+
+// evictBefore removes blocks from layers before lid.
+func (bc blockCache) evictBefore(lid types.LayerID) {
+	for _, key := range bc.Cache.Keys() {
+		if item, found := bc.Cache.Peek(key); found && item.(types.Block).LayerIndex.Before(lid) {
+			bc.Cache.Remove(key)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
 )
 
 //go:generate mockgen -package=mocks -destination=./mocks/mocks.go -source=./interface.go
@@ -28,5 +29,11 @@ type state interface {
 type tortoise interface {
 	OnBallot(*types.Ballot)
 	OnBlock(*types.Block)
+	OnMalfeasance(*signing.PublicKey, types.LayerID)
 	HandleIncomingLayer(context.Context, types.LayerID) (oldPbase, newPbase types.LayerID, reverted bool)
+	WindowStart() types.LayerID
+}
+
+type malfeasancePublisher interface {
+	Publish(context.Context, *types.MalfeasanceProof) error
 }
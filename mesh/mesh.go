@@ -42,6 +42,8 @@ type Mesh struct {
 	trtl   tortoise
 	txPool txMemPool
 
+	malfeasance malfeasancePublisher
+
 	mu sync.Mutex
 	// latestLayer is the latest layer this node had seen from blocks
 	latestLayer atomic.Value
@@ -53,10 +55,37 @@ type Mesh struct {
 	missingLayer        atomic.Value
 	nextProcessedLayers map[types.LayerID]struct{}
 	maxProcessedLayer   types.LayerID
+
+	// pruneRetention is a number of epochs behind the layer applied to the state,
+	// or the start of the tortoise window if it is older, for which bodies are kept.
+	// Zero disables pruning.
+	pruneRetention uint32
+}
+
+// Opt for configuring mesh.
+type Opt func(*Mesh)
+
+// WithPruneRetention enables pruning of ballots, blocks and transactions bodies
+// that are older than the last layer applied to the state, or the first layer
+// that tortoise may read during rerun, by more than epochs.
+// It must be larger than the tortoise window, as tortoise may need to revert
+// and reapply layers within the window.
+func WithPruneRetention(epochs uint32) Opt {
+	return func(msh *Mesh) {
+		msh.pruneRetention = epochs
+	}
+}
+
+// WithMalfeasancePublisher enables broadcasting proofs of malfeasance
+// for smeshers that produced more than one ballot in the same layer.
+func WithMalfeasancePublisher(publisher malfeasancePublisher) Opt {
+	return func(msh *Mesh) {
+		msh.malfeasance = publisher
+	}
 }
 
 // NewMesh creates a new instant of a mesh.
-func NewMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state, logger log.Log) *Mesh {
+func NewMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state, logger log.Log, opts ...Opt) *Mesh {
 	msh := &Mesh{
 		Log:                 logger,
 		trtl:                trtl,
@@ -66,6 +95,9 @@ func NewMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state,
 		AtxDB:               atxDb,
 		nextProcessedLayers: make(map[types.LayerID]struct{}),
 	}
+	for _, opt := range opts {
+		opt(msh)
+	}
 	msh.latestLayer.Store(types.GetEffectiveGenesis())
 	msh.latestLayerInState.Store(types.GetEffectiveGenesis())
 	msh.processedLayer.Store(types.LayerID{})
@@ -86,8 +118,8 @@ func NewMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state,
 }
 
 // NewRecoveredMesh creates new instance of mesh with recovered mesh data fom database.
-func NewRecoveredMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state, logger log.Log) *Mesh {
-	msh := NewMesh(db, atxDb, trtl, txPool, state, logger)
+func NewRecoveredMesh(db *DB, atxDb AtxDB, trtl tortoise, txPool txMemPool, state state, logger log.Log, opts ...Opt) *Mesh {
+	msh := NewMesh(db, atxDb, trtl, txPool, state, logger, opts...)
 
 	latest, err := msh.GetLatestLayer()
 	if err != nil {
@@ -284,6 +316,10 @@ func (msh *Mesh) ProcessLayer(ctx context.Context, layerID types.LayerID) error
 	// check for a state reversion: if tortoise reran and detected changes to historical data, it will request that
 	// state be reverted and reapplied. pushLayersToState, below, will handle the reapplication.
 	if reverted {
+		if err := msh.reportReorg(ctx, oldVerified); err != nil {
+			logger.With().Error("failed to report reorg", log.Err(err))
+			return err
+		}
 		msh.setLatestLayerInState(oldVerified)
 		if err := msh.revertState(ctx, oldVerified); err != nil {
 			logger.With().Error("failed to revert state, unable to process layer", log.Err(err))
@@ -310,12 +346,27 @@ func (msh *Mesh) ProcessLayer(ctx context.Context, layerID types.LayerID) error
 				Status:  events.LayerStatusTypeConfirmed,
 			})
 		}
+		if err := msh.prune(to); err != nil {
+			logger.With().Error("failed to prune mesh data", log.Err(err))
+		}
 	}
 
 	logger.Info("done processing layer")
 	return nil
 }
 
+func (msh *Mesh) prune(applied types.LayerID) error {
+	if msh.pruneRetention == 0 {
+		return nil
+	}
+	keep := minLayer(applied, msh.trtl.WindowStart())
+	retention := msh.pruneRetention * types.GetLayersPerEpoch()
+	if !keep.After(types.GetEffectiveGenesis().Add(retention)) {
+		return nil
+	}
+	return msh.Prune(keep.Sub(retention))
+}
+
 func (msh *Mesh) getAggregatedHash(lid types.LayerID) (types.Hash32, error) {
 	if !lid.After(types.NewLayerID(1)) {
 		return types.EmptyLayerHash, nil
@@ -430,6 +481,24 @@ func (msh *Mesh) pushLayer(ctx context.Context, layerID types.LayerID) error {
 		return fmt.Errorf("failed to get layer %s: %w", layerID, err)
 	}
 
+	applied, notApplied := msh.selectApplied(layerBlocks)
+	if err = msh.updateStateWithLayer(ctx, layerID, applied); err != nil {
+		return fmt.Errorf("failed to update state %s: %w", layerID, err)
+	}
+
+	msh.Event().Info("end of layer state root",
+		layerID,
+		log.Stringer("state_root", msh.state.GetStateRoot()),
+	)
+
+	if err = msh.reInsertTxsToPool(applied, notApplied, layerID); err != nil {
+		return fmt.Errorf("failed to reinsert TXs to pool %s: %w", layerID, err)
+	}
+	return nil
+}
+
+// selectApplied returns the block that is applied to the state and blocks that are not applied.
+func (msh *Mesh) selectApplied(layerBlocks []*types.Block) (*types.Block, []*types.Block) {
 	validBlocks, invalidBlocks := msh.BlocksByValidity(layerBlocks)
 	var (
 		applied    *types.Block
@@ -445,20 +514,7 @@ func (msh *Mesh) pushLayer(ctx context.Context, layerID types.LayerID) error {
 			notApplied = append(notApplied, blocks[1:]...)
 		}
 	}
-
-	if err = msh.updateStateWithLayer(ctx, layerID, applied); err != nil {
-		return fmt.Errorf("failed to update state %s: %w", layerID, err)
-	}
-
-	msh.Event().Info("end of layer state root",
-		layerID,
-		log.Stringer("state_root", msh.state.GetStateRoot()),
-	)
-
-	if err = msh.reInsertTxsToPool(applied, notApplied, layerID); err != nil {
-		return fmt.Errorf("failed to reinsert TXs to pool %s: %w", layerID, err)
-	}
-	return nil
+	return applied, notApplied
 }
 
 // RevertState reverts to state as of a previous layer.
@@ -468,6 +524,12 @@ func (msh *Mesh) revertState(ctx context.Context, layerID types.LayerID) error {
 	if _, err := msh.state.Rewind(layerID); err != nil {
 		return fmt.Errorf("failed to revert state to layer %v: %w", layerID, err)
 	}
+	if err := msh.revertRewards(layerID); err != nil {
+		return fmt.Errorf("failed to revert rewards to layer %v: %w", layerID, err)
+	}
+	if err := layers.UnsetAppliedFrom(msh.db, layerID.Add(1)); err != nil {
+		return fmt.Errorf("failed to revert applied blocks to layer %v: %w", layerID, err)
+	}
 	return nil
 }
 
@@ -574,10 +636,15 @@ func (msh *Mesh) updateStateWithLayer(ctx context.Context, layerID types.LayerID
 			log.FieldNamed("verified", layerID),
 			log.FieldNamed("latest", latest))
 	}
+	applied := types.EmptyBlockID
 	if block != nil {
 		if err := msh.applyState(block); err != nil {
 			return err
 		}
+		applied = block.ID()
+	}
+	if err := layers.SetApplied(msh.db, layerID, applied); err != nil {
+		return err
 	}
 	if err := msh.setLatestLayerInState(layerID); err != nil {
 		return err
@@ -667,10 +734,20 @@ func (msh *Mesh) AddTXsFromProposal(ctx context.Context, layerID types.LayerID,
 
 // AddBallot to the mesh.
 func (msh *Mesh) AddBallot(ballot *types.Ballot) error {
-	if err := msh.DB.AddBallot(ballot); err != nil {
+	proof, err := msh.DB.addBallot(ballot)
+	if err != nil {
 		return err
 	}
 	msh.trtl.OnBallot(ballot)
+	if proof == nil {
+		return nil
+	}
+	msh.trtl.OnMalfeasance(ballot.SmesherID(), proof.Layer)
+	if msh.malfeasance != nil {
+		if err := msh.malfeasance.Publish(context.Background(), proof); err != nil {
+			msh.With().Error("failed to publish malfeasance proof", log.Inline(proof), log.Err(err))
+		}
+	}
 	return nil
 }
 
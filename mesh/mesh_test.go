@@ -5,16 +5,24 @@ import (
 	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/malfeasance"
 	"github.com/spacemeshos/go-spacemesh/mesh/mocks"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/rand"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/sql/rewards"
 	"github.com/spacemeshos/go-spacemesh/svm/transaction"
 )
 
@@ -766,6 +774,158 @@ func TestMesh_ResetAppliedOnRevert(t *testing.T) {
 	require.Equal(t, failed.Sub(1), tm.LatestLayerInState())
 }
 
+func TestMesh_RevertRewards(t *testing.T) {
+	tm := createTestMesh(t)
+	defer tm.ctrl.Finish()
+	defer tm.Close()
+
+	genesis := types.GetEffectiveGenesis()
+	coinbase := types.Address{1}
+	key := [64]byte{1}
+	smesher, err := types.BytesToNodeID(key[:])
+	require.NoError(t, err)
+	for lid := genesis.Add(1); !lid.After(genesis.Add(4)); lid = lid.Add(1) {
+		require.NoError(t, tm.writeTransactionRewards(lid, []types.AnyReward{
+			{Address: coinbase, SmesherID: *smesher, Amount: 10, LayerReward: 7},
+		}))
+	}
+	total, err := rewards.CoinbaseTotal(tm.db, coinbase)
+	require.NoError(t, err)
+	require.Equal(t, rewards.Total{Total: 40, LayerReward: 28, Fee: 12}, total)
+
+	tm.mockState.EXPECT().Rewind(genesis.Add(2))
+	require.NoError(t, tm.revertState(context.TODO(), genesis.Add(2)))
+
+	rst, err := tm.GetRewards(coinbase)
+	require.NoError(t, err)
+	require.Len(t, rst, 2)
+	for _, reward := range rst {
+		require.False(t, reward.Layer.After(genesis.Add(2)))
+		require.Equal(t, uint64(3), reward.FeeReward)
+	}
+	total, err = rewards.CoinbaseTotal(tm.db, coinbase)
+	require.NoError(t, err)
+	require.Equal(t, rewards.Total{Total: 20, LayerReward: 14, Fee: 6}, total)
+}
+
+func TestMesh_ReportReorg(t *testing.T) {
+	tm := createTestMesh(t)
+	defer tm.ctrl.Finish()
+	defer tm.Close()
+	events.CloseEventReporter()
+	require.NoError(t, events.InitializeEventReporter(""))
+	defer events.CloseEventReporter()
+	sub := events.SubscribeReorgs()
+	defer sub.Close()
+
+	ctx := context.TODO()
+	genesis := types.GetEffectiveGenesis()
+	layerID := genesis.Add(1)
+	next := layerID.Add(1)
+	tm.mockTortoise.EXPECT().OnBlock(gomock.Any()).AnyTimes()
+	signer, _ := newSignerAndAddress(t, "origin")
+	tx1 := addTxToMempool(t, tm.Mesh, signer, 1)
+	tx2 := addTxToMempool(t, tm.Mesh, signer, 2)
+	tx3 := addTxToMempool(t, tm.Mesh, signer, 3)
+	tx4 := addTxToMempool(t, tm.Mesh, signer, 4)
+	block1 := addBlockWithTXsToMesh(t, tm.Mesh, layerID, true, tx1, tx2)
+	block2 := addBlockWithTXsToMesh(t, tm.Mesh, layerID, false, tx2, tx3)
+	// block in the next layer is never applied, and transactions from it are not reported
+	addBlockWithTXsToMesh(t, tm.Mesh, next, false, tx4)
+
+	tm.mockState.EXPECT().ValidateNonceAndBalance(gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().AddTxToPool(gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().GetStateRoot().AnyTimes()
+	tm.mockState.EXPECT().ApplyLayer(layerID, gomock.Any(), gomock.Any())
+	tm.mockTortoise.EXPECT().HandleIncomingLayer(gomock.Any(), layerID).Return(genesis, layerID, false)
+	require.NoError(t, tm.ProcessLayer(ctx, layerID))
+
+	require.NoError(t, tm.SaveContextualValidity(block1.ID(), layerID, false))
+	require.NoError(t, tm.SaveContextualValidity(block2.ID(), layerID, true))
+	tm.mockTortoise.EXPECT().HandleIncomingLayer(gomock.Any(), next).Return(genesis, next, true)
+	tm.mockState.EXPECT().Rewind(genesis)
+	// reorg is reported when the state is reverted, even if reverted layers fail to apply
+	errApply := errors.New("apply")
+	tm.mockState.EXPECT().ApplyLayer(layerID, gomock.Any(), gomock.Any()).Return(nil, errApply)
+	require.ErrorIs(t, tm.ProcessLayer(ctx, next), errApply)
+
+	select {
+	case ev := <-sub.Out():
+		reorg := ev.(events.Reorg)
+		require.Equal(t, genesis, reorg.RevertTo)
+		require.Equal(t, []types.BlockID{block1.ID()}, reorg.Invalid)
+		require.Equal(t, []types.BlockID{block2.ID()}, reorg.Valid)
+		require.Equal(t, []types.TransactionID{tx1.ID()}, reorg.Reinserted)
+	case <-time.After(time.Second):
+		require.FailNow(t, "reorg wasn't reported")
+	}
+}
+
+func TestMesh_PruneApplied(t *testing.T) {
+	const layers = 10
+	for _, tc := range []struct {
+		desc string
+		// windowStart and kept are offsets from genesis
+		windowStart, kept uint32
+	}{
+		{
+			desc:        "behind applied",
+			windowStart: layers,
+			kept:        layers - 3,
+		},
+		{
+			desc:        "behind tortoise window",
+			windowStart: 5,
+			kept:        2,
+		},
+		{
+			desc: "no checkpoint",
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			tm := createTestMesh(t)
+			defer tm.ctrl.Finish()
+			defer tm.Close()
+			require.EqualValues(t, 3, types.GetLayersPerEpoch(), "retention is one epoch")
+			genesis := types.GetEffectiveGenesis()
+			last := genesis.Add(layers)
+			tm.Mesh = NewMesh(tm.DB, nil, tm.mockTortoise, newMockTxMemPool(), tm.mockState, tm.Log, WithPruneRetention(1))
+
+			ctx := context.TODO()
+			layerBallots := map[types.LayerID][]*types.Ballot{}
+			layerBlocks := map[types.LayerID][]*types.Block{}
+			for lid := genesis.Add(1); !lid.After(last); lid = lid.Add(1) {
+				layerBallots[lid], layerBlocks[lid] = createLayerBallotsAndBlocks(t, tm.Mesh, lid)
+			}
+			// transactions from blocks that weren't applied are returned to the mempool
+			tm.mockState.EXPECT().ValidateNonceAndBalance(gomock.Any()).AnyTimes()
+			tm.mockState.EXPECT().AddTxToPool(gomock.Any()).AnyTimes()
+			tm.mockTortoise.EXPECT().WindowStart().Return(genesis.Add(tc.windowStart)).AnyTimes()
+			for lid := genesis.Add(1); !lid.After(last); lid = lid.Add(1) {
+				tm.mockTortoise.EXPECT().HandleIncomingLayer(gomock.Any(), gomock.Any()).
+					Return(lid.Sub(1), lid, false)
+				tm.mockState.EXPECT().ApplyLayer(lid, gomock.Any(), gomock.Any()).Return(nil, nil)
+				tm.mockState.EXPECT().GetStateRoot()
+				require.NoError(t, tm.ProcessLayer(ctx, lid))
+			}
+			require.Equal(t, last, tm.LatestLayerInState())
+
+			for lid := genesis.Add(1); !lid.After(last); lid = lid.Add(1) {
+				_, ballotErr := tm.GetBallot(layerBallots[lid][0].ID())
+				_, blockErr := tm.GetBlock(layerBlocks[lid][0].ID())
+				if lid.Before(genesis.Add(tc.kept)) {
+					require.ErrorIs(t, ballotErr, sql.ErrPruned, lid)
+					require.ErrorIs(t, blockErr, sql.ErrPruned, lid)
+				} else {
+					require.NoError(t, ballotErr, lid)
+					require.NoError(t, blockErr, lid)
+				}
+			}
+		})
+	}
+}
+
 func TestMesh_NoPanicOnIncorrectVerified(t *testing.T) {
 	tm := createTestMesh(t)
 	defer tm.ctrl.Finish()
@@ -821,3 +981,104 @@ func TestMesh_CallOnBallot(t *testing.T) {
 
 	require.NoError(t, tm.AddBallot(ballot))
 }
+
+func TestMesh_PublishMalfeasance(t *testing.T) {
+	tm := createTestMesh(t)
+	defer tm.ctrl.Finish()
+	defer tm.Close()
+	publisher := mocks.NewMockmalfeasancePublisher(tm.ctrl)
+	tm.malfeasance = publisher
+
+	lid := types.NewLayerID(10)
+	signer := signing.NewEdSigner()
+	ballots := make([]*types.Ballot, 3)
+	for i := range ballots {
+		ballot := types.RandomBallot()
+		ballot.LayerIndex = lid
+		ballot.Signature = signer.Sign(ballot.Bytes())
+		require.NoError(t, ballot.Initialize())
+		ballots[i] = ballot
+	}
+
+	tm.mockTortoise.EXPECT().OnBallot(ballots[0])
+	require.NoError(t, tm.AddBallot(ballots[0]))
+	require.False(t, ballots[0].IsMalicious())
+
+	tm.mockTortoise.EXPECT().OnBallot(ballots[1])
+	tm.mockTortoise.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, proof *types.MalfeasanceProof) error {
+			smesher, err := proof.Verify()
+			require.NoError(t, err)
+			require.Equal(t, signer.PublicKey(), smesher)
+			require.Equal(t, lid, proof.Layer)
+			return nil
+		})
+	require.NoError(t, tm.AddBallot(ballots[1]))
+	require.True(t, ballots[1].IsMalicious())
+
+	// proof is created only once
+	tm.mockTortoise.EXPECT().OnBallot(ballots[2])
+	require.NoError(t, tm.AddBallot(ballots[2]))
+	require.True(t, ballots[2].IsMalicious())
+
+	_, got, err := identities.GetProof(tm.db, signer.PublicKey().Bytes())
+	require.NoError(t, err)
+	require.Equal(t, lid, got)
+}
+
+func TestMesh_GossipMalfeasance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	tm := createTestMesh(t)
+	defer tm.ctrl.Finish()
+	defer tm.Close()
+
+	mn, err := mocknet.FullMeshLinked(ctx, 2)
+	require.NoError(t, err)
+	var (
+		hosts   = mn.Hosts()
+		remote  = sql.InMemory()
+		rtrtl   = mocks.NewMocktortoise(tm.ctrl)
+		pubsubs []*pubsub.PubSub
+	)
+	for _, h := range hosts {
+		ps, err := pubsub.New(ctx, logtest.New(t), h, pubsub.Config{Flood: true})
+		require.NoError(t, err)
+		pubsubs = append(pubsubs, ps)
+	}
+	local := malfeasance.NewHandler(tm.db, hosts[0].ID(), pubsubs[0], tm.mockTortoise, malfeasance.WithLogger(logtest.New(t)))
+	pubsubs[0].Register(malfeasance.Protocol, local.HandleMalfeasanceProof)
+	tm.malfeasance = local
+	pubsubs[1].Register(malfeasance.Protocol,
+		malfeasance.NewHandler(remote, hosts[1].ID(), pubsubs[1], rtrtl).HandleMalfeasanceProof)
+	require.NoError(t, mn.ConnectAllButSelf())
+	require.Eventually(t, func() bool {
+		for _, ps := range pubsubs {
+			if len(ps.ProtocolPeers(malfeasance.Protocol)) != len(hosts)-1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lid := types.NewLayerID(10)
+	signer := signing.NewEdSigner()
+	for i := 0; i < 2; i++ {
+		ballot := types.RandomBallot()
+		ballot.LayerIndex = lid
+		ballot.Signature = signer.Sign(ballot.Bytes())
+		require.NoError(t, ballot.Initialize())
+		tm.mockTortoise.EXPECT().OnBallot(ballot)
+		if i == 1 {
+			tm.mockTortoise.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+			rtrtl.EXPECT().OnMalfeasance(signer.PublicKey(), lid)
+		}
+		require.NoError(t, tm.AddBallot(ballot))
+	}
+	require.Eventually(t, func() bool {
+		mal, err := identities.IsMalicious(remote, signer.PublicKey().Bytes())
+		require.NoError(t, err)
+		return mal
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -129,9 +129,16 @@ func (m *DB) Transactions() database.Getter {
 
 // AddBallot adds a ballot to the database.
 func (m *DB) AddBallot(b *types.Ballot) error {
-	mal, err := identities.IsMalicious(m.db, b.SmesherID().Bytes())
+	_, err := m.addBallot(b)
+	return err
+}
+
+// addBallot adds a ballot to the database. If the smesher already produced a ballot
+// in the same layer, the proof of malfeasance is persisted and returned.
+func (m *DB) addBallot(b *types.Ballot) (*types.MalfeasanceProof, error) {
+	mal, err := identities.IsMaliciousAt(m.db, b.SmesherID().Bytes(), b.LayerIndex)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if mal {
 		b.SetMalicious()
@@ -140,21 +147,30 @@ func (m *DB) AddBallot(b *types.Ballot) error {
 	// it is important to run add ballot and set identity to malicious atomically
 	tx, err := m.db.Tx(context.Background())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Release()
 
 	if err := ballots.Add(tx, b); err != nil && !errors.Is(err, sql.ErrObjectExists) {
-		return err
+		return nil, err
 	}
+	var proof *types.MalfeasanceProof
 	if !mal {
 		count, err := ballots.CountByPubkeyLayer(tx, b.LayerIndex, b.SmesherID().Bytes())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if count > 1 {
-			if err := identities.SetMalicious(tx, b.SmesherID().Bytes()); err != nil {
-				return err
+			proof, err = ballotsProof(tx, b)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := codec.Encode(proof)
+			if err != nil {
+				m.Log.With().Panic("failed to encode malfeasance proof", log.Err(err))
+			}
+			if err := identities.SetProof(tx, b.SmesherID().Bytes(), b.LayerIndex, encoded); err != nil {
+				return nil, err
 			}
 			b.SetMalicious()
 			m.Log.With().Warning("smesher produced more than one ballot in the same layer",
@@ -163,7 +179,25 @@ func (m *DB) AddBallot(b *types.Ballot) error {
 			)
 		}
 	}
-	return tx.Commit()
+	return proof, tx.Commit()
+}
+
+// ballotsProof creates a proof from the ballot and another ballot from the same smesher in the same layer.
+func ballotsProof(db sql.Executor, b *types.Ballot) (*types.MalfeasanceProof, error) {
+	layer, err := ballots.Layer(db, b.LayerIndex)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range layer {
+		if other.ID() != b.ID() && other.SmesherID().Equals(b.SmesherID()) {
+			return &types.MalfeasanceProof{
+				Layer:   b.LayerIndex,
+				Type:    types.MultipleBallots,
+				Ballots: []types.Ballot{*other, *b},
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: other ballot from %s in layer %s", sql.ErrNotFound, b.SmesherID(), b.LayerIndex)
 }
 
 // SetMalicious updates smesher as malicious.
@@ -171,6 +205,11 @@ func (m *DB) SetMalicious(smesher *signing.PublicKey) error {
 	return identities.SetMalicious(m.db, smesher.Bytes())
 }
 
+// IsMaliciousAt returns true if smesher is known to be malicious in the layer.
+func (m *DB) IsMaliciousAt(smesher *signing.PublicKey, lid types.LayerID) (bool, error) {
+	return identities.IsMaliciousAt(m.db, smesher.Bytes(), lid)
+}
+
 // HasBallot returns true if the ballot is stored in a database.
 func (m *DB) HasBallot(ballot types.BallotID) bool {
 	exists, _ := ballots.Has(m.db, ballot)
@@ -372,6 +411,59 @@ func (m *DB) writeTransactionRewards(l types.LayerID, applied []types.AnyReward)
 	return tx.Commit()
 }
 
+// revertRewards removes rewards from layers after the given layer.
+func (m *DB) revertRewards(after types.LayerID) error {
+	tx, err := m.db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	if err := rewards.Revert(tx, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Prune removes bodies of ballots, blocks and transactions before the layer.
+// IDs, layer hashes and aggregated hashes are kept.
+func (m *DB) Prune(before types.LayerID) error {
+	tx, err := m.db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	nballots, err := ballots.Prune(tx, before)
+	if err != nil {
+		return err
+	}
+	nblocks, err := blocks.Prune(tx, before)
+	if err != nil {
+		return err
+	}
+	ntxs, err := transactions.Prune(tx, before)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit prune: %w", err)
+	}
+	m.blockCache.evictBefore(before)
+	if nballots+nblocks+ntxs > 0 {
+		m.With().Info("pruned mesh data",
+			log.Stringer("before", before),
+			log.Int("ballots", nballots),
+			log.Int("blocks", nblocks),
+			log.Int("transactions", ntxs),
+		)
+	}
+	return nil
+}
+
+// QueryTransactions returns a page of transactions that match the query.
+func (m *DB) QueryTransactions(q transactions.Query) ([]*types.MeshTransaction, *transactions.Cursor, error) {
+	return transactions.Find(m.db, q)
+}
+
 // GetRewards retrieves account's rewards by the coinbase address.
 func (m *DB) GetRewards(coinbase types.Address) ([]types.Reward, error) {
 	return rewards.FilterByCoinbase(m.db, coinbase)
@@ -492,23 +492,23 @@ func TestMeshDB_testGetRewards(t *testing.T) {
 	rewards, err := mdb.GetRewards(addrs[0])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[0], Coinbase: addrs[0]},
-		{Layer: types.NewLayerID(3), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[0], Coinbase: addrs[0]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[0], Coinbase: addrs[0]},
+		{Layer: types.NewLayerID(3), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[0], Coinbase: addrs[0]},
 	}, rewards)
 
 	rewards, err = mdb.GetRewards(addrs[1])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[1], Coinbase: addrs[1]},
-		{Layer: types.NewLayerID(2), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[1], Coinbase: addrs[1]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[1], Coinbase: addrs[1]},
+		{Layer: types.NewLayerID(2), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[1], Coinbase: addrs[1]},
 	}, rewards)
 
 	rewards, err = mdb.GetRewards(addrs[2])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
-		{Layer: types.NewLayerID(2), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
-		{Layer: types.NewLayerID(3), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(2), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(3), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[2], Coinbase: addrs[2]},
 	}, rewards)
 
 	_, addr4 := newSignerAndAddress(t, "999")
@@ -526,23 +526,23 @@ func TestMeshDB_testGetRewardsBySmesher(t *testing.T) {
 	rewards, err := mdb.GetRewardsBySmesherID(smeshers[0])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[0], Coinbase: addrs[0]},
-		{Layer: types.NewLayerID(3), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[0], Coinbase: addrs[0]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[0], Coinbase: addrs[0]},
+		{Layer: types.NewLayerID(3), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[0], Coinbase: addrs[0]},
 	}, rewards)
 
 	rewards, err = mdb.GetRewardsBySmesherID(smeshers[1])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[1], Coinbase: addrs[1]},
-		{Layer: types.NewLayerID(2), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[1], Coinbase: addrs[1]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[1], Coinbase: addrs[1]},
+		{Layer: types.NewLayerID(2), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[1], Coinbase: addrs[1]},
 	}, rewards)
 
 	rewards, err = mdb.GetRewardsBySmesherID(smeshers[2])
 	require.NoError(t, err)
 	require.Equal(t, []types.Reward{
-		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
-		{Layer: types.NewLayerID(2), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
-		{Layer: types.NewLayerID(3), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(1), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(2), TotalReward: unitReward, LayerRewardEstimate: unitLayerReward, FeeReward: unitReward - unitLayerReward, SmesherID: smeshers[2], Coinbase: addrs[2]},
+		{Layer: types.NewLayerID(3), TotalReward: unitReward * 2, LayerRewardEstimate: unitLayerReward * 2, FeeReward: (unitReward - unitLayerReward) * 2, SmesherID: smeshers[2], Coinbase: addrs[2]},
 	}, rewards)
 
 	signer4, _ := newSignerAndAddress(t, "999")
@@ -676,6 +676,14 @@ func TestMaliciousBallots(t *testing.T) {
 		require.NoError(t, mdb.AddBallot(&ballot))
 		require.True(t, ballot.IsMalicious())
 	}
+
+	smesher := signing.NewPublicKey(pub)
+	mal, err := mdb.IsMaliciousAt(smesher, lid.Sub(1))
+	require.NoError(t, err)
+	require.False(t, mal)
+	mal, err = mdb.IsMaliciousAt(smesher, lid)
+	require.NoError(t, err)
+	require.True(t, mal)
 }
 
 func BenchmarkGetBlock(b *testing.B) {
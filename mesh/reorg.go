@@ -0,0 +1,68 @@
+package mesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+)
+
+func getApplied(db sql.Executor, lid types.LayerID) (types.BlockID, error) {
+	bid, err := layers.GetApplied(db, lid)
+	if errors.Is(err, sql.ErrNotFound) {
+		// layers applied before applied blocks were recorded
+		return types.EmptyBlockID, nil
+	}
+	return bid, err
+}
+
+// reportReorg reports changes to the applied state in layers after revertTo. Must be called
+// after tortoise updated validity and before the state is reverted.
+//
+// Blocks that will be applied instead are selected in the same way as when layers are pushed to the state.
+// Reinserted transactions are collected only from the reverted blocks, and exclude transactions
+// that are included in blocks that will be applied instead.
+func (msh *Mesh) reportReorg(ctx context.Context, revertTo types.LayerID) error {
+	ev := events.Reorg{RevertTo: revertTo}
+	var invalid, valid []*types.Block
+	for lid := revertTo.Add(1); !lid.After(msh.LatestLayerInState()); lid = lid.Add(1) {
+		before, err := getApplied(msh.db, lid)
+		if err != nil {
+			return fmt.Errorf("get applied block %s: %w", lid, err)
+		}
+		layerBlocks, err := msh.LayerBlocks(lid)
+		if err != nil {
+			return fmt.Errorf("get layer %s: %w", lid, err)
+		}
+		after, _ := msh.selectApplied(layerBlocks)
+		afterID := types.EmptyBlockID
+		if after != nil {
+			afterID = after.ID()
+		}
+		if before == afterID {
+			continue
+		}
+		if before != types.EmptyBlockID {
+			ev.Invalid = append(ev.Invalid, before)
+			block, err := msh.GetBlock(before)
+			if err != nil {
+				return fmt.Errorf("get reverted block %s: %w", before, err)
+			}
+			invalid = append(invalid, block)
+		}
+		if after != nil {
+			ev.Valid = append(ev.Valid, afterID)
+			valid = append(valid, after)
+		}
+	}
+	seen := map[types.TransactionID]struct{}{}
+	uniqueTxIds(valid, seen)
+	ev.Reinserted = uniqueTxIds(invalid, seen)
+	msh.WithContext(ctx).With().Info("state will be reverted", ev)
+	events.ReportReorg(ev)
+	return nil
+}
@@ -0,0 +1,184 @@
+package atxs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Get gets an ATX by a given ATX ID.
+func Get(db sql.Executor, id types.ATXID) (atx *types.ActivationTx, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindBytes(1, id.Bytes())
+	}
+	dec := func(stmt *sql.Statement) bool {
+		var v types.ActivationTx
+		if _, err = codec.DecodeFrom(stmt.ColumnReader(0), &v); err != nil {
+			err = fmt.Errorf("decode %w", err)
+			return false
+		}
+		v.SetID(&id)
+		atx = &v
+		return true
+	}
+	if rows, err := db.Exec("select atx from atxs where id = ?1;", enc, dec); err != nil {
+		return nil, fmt.Errorf("get atx %v: %w", id, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("get atx %s: %w", id, sql.ErrNotFound)
+	}
+	return atx, err
+}
+
+// GetBlob loads ATX as an encoded blob, ready to be sent over the wire.
+func GetBlob(db sql.Executor, id []byte) (buf []byte, err error) {
+	if rows, err := db.Exec("select atx from atxs where id = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, id)
+		}, func(stmt *sql.Statement) bool {
+			buf = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, buf)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("get %x: %w", id, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("%w: atx %x", sql.ErrNotFound, id)
+	}
+	return buf, nil
+}
+
+// Has checks if an ATX exists by a given ATX ID.
+func Has(db sql.Executor, id types.ATXID) (bool, error) {
+	rows, err := db.Exec("select 1 from atxs where id = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, id.Bytes())
+		}, nil,
+	)
+	if err != nil {
+		return false, fmt.Errorf("has atx %s: %w", id, err)
+	}
+	return rows > 0, nil
+}
+
+// GetTimestamp gets an ATX timestamp by a given ATX ID.
+func GetTimestamp(db sql.Executor, id types.ATXID) (timestamp time.Time, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindBytes(1, id.Bytes())
+	}
+	dec := func(stmt *sql.Statement) bool {
+		timestamp = time.Unix(0, stmt.ColumnInt64(0))
+		return true
+	}
+	if rows, err := db.Exec("select timestamp from atxs where id = ?1;", enc, dec); err != nil {
+		return time.Time{}, fmt.Errorf("get atx %v: %w", id, err)
+	} else if rows == 0 {
+		return time.Time{}, fmt.Errorf("get atx %s: %w", id, sql.ErrNotFound)
+	}
+	return timestamp, err
+}
+
+// GetLastIDByNodeID gets the last ATX ID for a given node ID.
+func GetLastIDByNodeID(db sql.Executor, nodeID types.NodeID) (id types.ATXID, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindText(1, nodeID.Key)
+	}
+	dec := func(stmt *sql.Statement) bool {
+		stmt.ColumnBytes(0, id[:])
+		return true
+	}
+	if rows, err := db.Exec(`select id from atxs
+		where smesher = ?1
+		order by epoch desc, timestamp desc
+		limit 1;`, enc, dec); err != nil {
+		return types.ATXID{}, fmt.Errorf("last atx for %v: %w", nodeID, err)
+	} else if rows == 0 {
+		return types.ATXID{}, fmt.Errorf("last atx for %s: %w", nodeID, sql.ErrNotFound)
+	}
+	return id, err
+}
+
+// GetIDByEpochAndNodeID gets an ATX ID for a given epoch and node ID.
+func GetIDByEpochAndNodeID(db sql.Executor, epoch types.EpochID, nodeID types.NodeID) (id types.ATXID, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(epoch))
+		stmt.BindText(2, nodeID.Key)
+	}
+	dec := func(stmt *sql.Statement) bool {
+		stmt.ColumnBytes(0, id[:])
+		return true
+	}
+	if rows, err := db.Exec(`select id from atxs
+		where epoch = ?1 and smesher = ?2
+		order by timestamp desc
+		limit 1;`, enc, dec); err != nil {
+		return types.ATXID{}, fmt.Errorf("atx for %v in epoch %d: %w", nodeID, epoch, err)
+	} else if rows == 0 {
+		return types.ATXID{}, fmt.Errorf("atx for %s in epoch %d: %w", nodeID, epoch, sql.ErrNotFound)
+	}
+	return id, err
+}
+
+// GetIDsByEpoch gets ATX IDs for a given epoch. If smesher published several ATXs
+// in the same epoch only the one that was received last is returned.
+func GetIDsByEpoch(db sql.Executor, epoch types.EpochID) (ids []types.ATXID, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(epoch))
+	}
+	dec := func(stmt *sql.Statement) bool {
+		var id types.ATXID
+		stmt.ColumnBytes(0, id[:])
+		ids = append(ids, id)
+		return true
+	}
+	// bare column id is taken from the row with max(timestamp)
+	// https://www.sqlite.org/lang_select.html#bareagg
+	if _, err := db.Exec(`select id, max(timestamp) from atxs
+		where epoch = ?1
+		group by smesher;`, enc, dec); err != nil {
+		return nil, fmt.Errorf("atxs in epoch %v: %w", epoch, err)
+	}
+	return ids, err
+}
+
+// GetTop gets the ATX with the highest layer. If several ATXs share the highest layer
+// the one that was received first is returned.
+func GetTop(db sql.Executor) (id types.ATXID, lid types.LayerID, err error) {
+	dec := func(stmt *sql.Statement) bool {
+		stmt.ColumnBytes(0, id[:])
+		lid = types.NewLayerID(uint32(stmt.ColumnInt64(1)))
+		return true
+	}
+	if rows, err := db.Exec(`select id, layer from atxs
+		order by layer desc, timestamp asc
+		limit 1;`, nil, dec); err != nil {
+		return types.ATXID{}, types.LayerID{}, fmt.Errorf("top atx: %w", err)
+	} else if rows == 0 {
+		return types.ATXID{}, types.LayerID{}, fmt.Errorf("top atx: %w", sql.ErrNotFound)
+	}
+	return id, lid, err
+}
+
+// Add adds an ATX for a given ATX ID.
+func Add(db sql.Executor, atx *types.ActivationTx, timestamp time.Time) error {
+	buf, err := codec.Encode(atx)
+	if err != nil {
+		return fmt.Errorf("encode %v: %w", atx.ID(), err)
+	}
+	enc := func(stmt *sql.Statement) {
+		stmt.BindBytes(1, atx.ID().Bytes())
+		stmt.BindInt64(2, int64(atx.PubLayerID.Uint32()))
+		stmt.BindInt64(3, int64(atx.PubLayerID.GetEpoch()))
+		stmt.BindText(4, atx.NodeID.Key)
+		stmt.BindBytes(5, buf)
+		stmt.BindInt64(6, timestamp.UnixNano())
+	}
+	_, err = db.Exec(`insert into atxs
+		(id, layer, epoch, smesher, atx, timestamp)
+		values (?1, ?2, ?3, ?4, ?5, ?6);`, enc, nil)
+	if err != nil {
+		return fmt.Errorf("insert ATX ID %v: %w", atx.ID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package atxs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func newAtx(node string, sequence uint64, lid types.LayerID) *types.ActivationTx {
+	return types.NewActivationTx(types.NIPostChallenge{
+		NodeID:     types.NodeID{Key: node},
+		Sequence:   sequence,
+		PubLayerID: lid,
+	}, types.Address{}, nil, 1, nil)
+}
+
+func TestGetHas(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := sql.InMemory()
+
+	atx := newAtx("aaaa", 1, types.NewLayerID(10))
+	has, err := Has(db, atx.ID())
+	require.NoError(t, err)
+	require.False(t, has)
+	_, err = Get(db, atx.ID())
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	_, err = GetBlob(db, atx.ID().Bytes())
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	timestamp := time.Now()
+	require.NoError(t, Add(db, atx, timestamp))
+	require.ErrorIs(t, Add(db, atx, timestamp), sql.ErrObjectExists)
+
+	has, err = Has(db, atx.ID())
+	require.NoError(t, err)
+	require.True(t, has)
+
+	got, err := Get(db, atx.ID())
+	require.NoError(t, err)
+	require.Equal(t, atx.ID(), got.ID())
+	require.Equal(t, atx.NIPostChallenge, got.NIPostChallenge)
+
+	blob, err := GetBlob(db, atx.ID().Bytes())
+	require.NoError(t, err)
+	require.NotEmpty(t, blob)
+
+	ts, err := GetTimestamp(db, atx.ID())
+	require.NoError(t, err)
+	require.Equal(t, timestamp.UnixNano(), ts.UnixNano())
+}
+
+func TestGetByNodeID(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := sql.InMemory()
+
+	node1, node2 := "aaaa", "bbbb"
+	atxs := []*types.ActivationTx{
+		newAtx(node1, 1, types.NewLayerID(3)),
+		newAtx(node1, 2, types.NewLayerID(6)),
+		newAtx(node2, 1, types.NewLayerID(3)),
+	}
+	now := time.Now()
+	for i, atx := range atxs {
+		require.NoError(t, Add(db, atx, now.Add(time.Duration(i))))
+	}
+
+	_, err := GetLastIDByNodeID(db, types.NodeID{Key: "cccc"})
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	last, err := GetLastIDByNodeID(db, types.NodeID{Key: node1})
+	require.NoError(t, err)
+	require.Equal(t, atxs[1].ID(), last)
+
+	id, err := GetIDByEpochAndNodeID(db, 1, types.NodeID{Key: node1})
+	require.NoError(t, err)
+	require.Equal(t, atxs[0].ID(), id)
+
+	_, err = GetIDByEpochAndNodeID(db, 2, types.NodeID{Key: node2})
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	ids, err := GetIDsByEpoch(db, 1)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.ATXID{atxs[0].ID(), atxs[2].ID()}, ids)
+}
+
+func TestGetIDsByEpochLatest(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := sql.InMemory()
+
+	first := newAtx("aaaa", 1, types.NewLayerID(3))
+	second := newAtx("aaaa", 2, types.NewLayerID(4))
+	now := time.Now()
+	require.NoError(t, Add(db, second, now.Add(time.Second)))
+	require.NoError(t, Add(db, first, now))
+
+	ids, err := GetIDsByEpoch(db, 1)
+	require.NoError(t, err)
+	require.Equal(t, []types.ATXID{second.ID()}, ids)
+}
+
+func TestGetTop(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := sql.InMemory()
+
+	_, _, err := GetTop(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	atxs := []*types.ActivationTx{
+		newAtx("aaaa", 1, types.NewLayerID(3)),
+		newAtx("bbbb", 1, types.NewLayerID(6)),
+		newAtx("cccc", 1, types.NewLayerID(6)),
+	}
+	now := time.Now()
+	for i, atx := range atxs {
+		require.NoError(t, Add(db, atx, now.Add(time.Duration(i))))
+	}
+	id, lid, err := GetTop(db)
+	require.NoError(t, err)
+	require.Equal(t, atxs[1].ID(), id)
+	require.Equal(t, types.NewLayerID(6), lid)
+}
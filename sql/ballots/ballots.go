@@ -74,12 +74,17 @@ func Has(db sql.Executor, id types.BallotID) (bool, error) {
 
 // Get ballot with id from database.
 func Get(db sql.Executor, id types.BallotID) (rst *types.Ballot, err error) {
-	if rows, err := db.Exec(`select signature, pubkey, ballot, identities.malicious 
-	from ballots left join identities using(pubkey)
+	if rows, err := db.Exec(`select signature, ballots.pubkey, ballot, identities.malicious 
+	from ballots left join identities on ballots.pubkey = identities.pubkey 
+		and ballots.layer >= ifnull(identities.malicious_layer, 0)
 	where id = ?1;`,
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id.Bytes())
 		}, func(stmt *sql.Statement) bool {
+			if stmt.ColumnLen(2) == 0 {
+				err = fmt.Errorf("%w ballot %s", sql.ErrPruned, id)
+				return false
+			}
 			rst, err = decodeBallot(id,
 				stmt.ColumnReader(0),
 				stmt.ColumnReader(1),
@@ -92,18 +97,23 @@ func Get(db sql.Executor, id types.BallotID) (rst *types.Ballot, err error) {
 	} else if rows == 0 {
 		return nil, fmt.Errorf("%w ballot %s", sql.ErrNotFound, id)
 	}
-	return rst, nil
+	return rst, err
 }
 
 // Layer returns full body ballot for layer.
 func Layer(db sql.Executor, lid types.LayerID) (rst []*types.Ballot, err error) {
-	if _, err = db.Exec(`select id, signature, pubkey, ballot, identities.malicious
-		from ballots left join identities using(pubkey)
-		where layer = ?1;`, func(stmt *sql.Statement) {
+	if _, qerr := db.Exec(`select id, signature, ballots.pubkey, ballot, identities.malicious
+		from ballots left join identities on ballots.pubkey = identities.pubkey 
+			and ballots.layer >= ifnull(identities.malicious_layer, 0)
+		where ballots.layer = ?1;`, func(stmt *sql.Statement) {
 		stmt.BindInt64(1, int64(lid.Value))
 	}, func(stmt *sql.Statement) bool {
 		id := types.BallotID{}
 		stmt.ColumnBytes(0, id[:])
+		if stmt.ColumnLen(3) == 0 {
+			err = fmt.Errorf("%w ballot %s", sql.ErrPruned, id)
+			return false
+		}
 		var ballot *types.Ballot
 		ballot, err = decodeBallot(id,
 			stmt.ColumnReader(1),
@@ -116,8 +126,8 @@ func Layer(db sql.Executor, lid types.LayerID) (rst []*types.Ballot, err error)
 		}
 		rst = append(rst, ballot)
 		return true
-	}); err != nil {
-		return nil, fmt.Errorf("ballots for layer %s: %w", lid, err)
+	}); qerr != nil {
+		return nil, fmt.Errorf("ballots for layer %s: %w", lid, qerr)
 	}
 	return rst, err
 }
@@ -137,6 +147,21 @@ func IDsInLayer(db sql.Executor, lid types.LayerID) (rst []types.BallotID, err e
 	return rst, err
 }
 
+// IDsInLayerByArrival returns ballots ids in the layer in the order they were added to the database.
+func IDsInLayerByArrival(db sql.Executor, lid types.LayerID) (rst []types.BallotID, err error) {
+	if _, err := db.Exec("select id from ballots where layer = ?1 order by rowid;", func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(lid.Uint32()))
+	}, func(stmt *sql.Statement) bool {
+		id := types.BallotID{}
+		stmt.ColumnBytes(0, id[:])
+		rst = append(rst, id)
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("ballots for layer %s: %w", lid, err)
+	}
+	return rst, err
+}
+
 // CountByPubkeyLayer counts number of ballots in the layer for the pubkey.
 func CountByPubkeyLayer(db sql.Executor, lid types.LayerID, pubkey []byte) (int, error) {
 	rows, err := db.Exec("select 1 from ballots where layer = ?1 and pubkey = ?2;", func(stmt *sql.Statement) {
@@ -148,3 +173,35 @@ func CountByPubkeyLayer(db sql.Executor, lid types.LayerID, pubkey []byte) (int,
 	}
 	return rows, nil
 }
+
+// Prune removes bodies and signatures of the ballots before the layer.
+// IDs, layers and pubkeys are kept. Returns number of pruned ballots.
+func Prune(db sql.Executor, before types.LayerID) (int, error) {
+	rows, err := db.Exec(`update ballots set ballot = null, signature = null
+		where layer < ?1 and ballot is not null returning id;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(before.Value))
+		}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("prune ballots before %s: %w", before, err)
+	}
+	return rows, nil
+}
+
+// PrunedRange returns the lowest and the highest layers with pruned ballots.
+func PrunedRange(db sql.Executor) (from, to types.LayerID, err error) {
+	var total int
+	if _, err := db.Exec(`select count(*), min(layer), max(layer) from ballots where ballot is null;`, nil,
+		func(stmt *sql.Statement) bool {
+			total = stmt.ColumnInt(0)
+			from = types.NewLayerID(uint32(stmt.ColumnInt64(1)))
+			to = types.NewLayerID(uint32(stmt.ColumnInt64(2)))
+			return true
+		}); err != nil {
+		return from, to, fmt.Errorf("pruned ballots range: %w", err)
+	}
+	if total == 0 {
+		return from, to, fmt.Errorf("pruned ballots range: %w", sql.ErrNotFound)
+	}
+	return from, to, nil
+}
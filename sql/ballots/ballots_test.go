@@ -42,6 +42,45 @@ func TestLayer(t *testing.T) {
 	}
 }
 
+func TestLayerMaliciousFromProof(t *testing.T) {
+	db := sql.InMemory()
+	start := types.NewLayerID(1)
+	pub := []byte{1, 1, 1}
+	for i := 0; i < 3; i++ {
+		ballot := types.NewExistingBallot(types.BallotID{byte(i + 1)}, nil, pub,
+			types.InnerBallot{LayerIndex: start.Add(uint32(i))})
+		require.NoError(t, Add(db, &ballot))
+	}
+
+	require.NoError(t, identities.SetProof(db, pub, start.Add(1), []byte("proof")))
+	for i, expected := range []bool{false, true, true} {
+		rst, err := Layer(db, start.Add(uint32(i)))
+		require.NoError(t, err)
+		require.Len(t, rst, 1)
+		require.Equal(t, expected, rst[0].IsMalicious())
+
+		stored, err := Get(db, rst[0].ID())
+		require.NoError(t, err)
+		require.Equal(t, expected, stored.IsMalicious())
+	}
+}
+
+func TestIDsInLayerByArrival(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.NewLayerID(1)
+	ballots := []types.Ballot{
+		types.NewExistingBallot(types.BallotID{3}, nil, []byte{3}, types.InnerBallot{LayerIndex: lid}),
+		types.NewExistingBallot(types.BallotID{1}, nil, []byte{1}, types.InnerBallot{LayerIndex: lid}),
+		types.NewExistingBallot(types.BallotID{2}, nil, []byte{2}, types.InnerBallot{LayerIndex: lid.Add(1)}),
+	}
+	for _, ballot := range ballots {
+		require.NoError(t, Add(db, &ballot))
+	}
+	ids, err := IDsInLayerByArrival(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, []types.BallotID{ballots[0].ID(), ballots[1].ID()}, ids)
+}
+
 func TestAdd(t *testing.T) {
 	db := sql.InMemory()
 	pub := []byte{1, 1}
@@ -100,3 +139,41 @@ func TestCountByPubkeyLayer(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, count)
 }
+
+func TestPrune(t *testing.T) {
+	db := sql.InMemory()
+	start := types.NewLayerID(1)
+	pub := []byte{1, 1}
+	ballots := []types.Ballot{
+		types.NewExistingBallot(types.BallotID{1}, []byte{1}, pub, types.InnerBallot{LayerIndex: start}),
+		types.NewExistingBallot(types.BallotID{2}, []byte{2}, pub, types.InnerBallot{LayerIndex: start.Add(1)}),
+	}
+	for _, ballot := range ballots {
+		require.NoError(t, Add(db, &ballot))
+	}
+	_, _, err := PrunedRange(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	pruned, err := Prune(db, start.Add(1))
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+	from, to, err := PrunedRange(db)
+	require.NoError(t, err)
+	require.Equal(t, start, from)
+	require.Equal(t, start, to)
+
+	_, err = Get(db, ballots[0].ID())
+	require.ErrorIs(t, err, sql.ErrPruned)
+	_, err = Layer(db, start)
+	require.ErrorIs(t, err, sql.ErrPruned)
+	ids, err := IDsInLayer(db, start)
+	require.NoError(t, err)
+	require.Equal(t, []types.BallotID{ballots[0].ID()}, ids)
+	count, err := CountByPubkeyLayer(db, start, pub)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	stored, err := Get(db, ballots[1].ID())
+	require.NoError(t, err)
+	require.Equal(t, &ballots[1], stored)
+}
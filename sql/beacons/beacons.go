@@ -41,3 +41,35 @@ func Add(db sql.Executor, epoch types.EpochID, beacon types.Beacon) error {
 
 	return nil
 }
+
+// SetRecord stores the record of the beacon protocol executed in the epoch.
+func SetRecord(db sql.Executor, epoch types.EpochID, record []byte) error {
+	if _, err := db.Exec(`insert into beacon_records (epoch, record) values (?1, ?2)
+					on conflict(epoch) do update set record=?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(epoch))
+			stmt.BindBytes(2, record)
+		}, nil); err != nil {
+		return fmt.Errorf("set beacon record %v: %w", epoch, err)
+	}
+	return nil
+}
+
+// GetRecord returns the record of the beacon protocol executed in the epoch.
+func GetRecord(db sql.Executor, epoch types.EpochID) (record []byte, err error) {
+	rows, err := db.Exec("select record from beacon_records where epoch = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(epoch))
+		}, func(stmt *sql.Statement) bool {
+			record = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, record)
+			return false
+		})
+	if err != nil {
+		return nil, fmt.Errorf("get beacon record %v: %w", epoch, err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("get beacon record %v: %w", epoch, sql.ErrNotFound)
+	}
+	return record, nil
+}
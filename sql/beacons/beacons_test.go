@@ -48,3 +48,21 @@ func TestAdd(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, beacon, got)
 }
+
+func TestRecord(t *testing.T) {
+	db := sql.InMemory()
+	epoch := types.EpochID(baseEpoch)
+
+	_, err := GetRecord(db, epoch)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, SetRecord(db, epoch, []byte{1, 2}))
+	record, err := GetRecord(db, epoch)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, record)
+
+	require.NoError(t, SetRecord(db, epoch, []byte{3}))
+	record, err = GetRecord(db, epoch)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, record)
+}
@@ -58,6 +58,10 @@ func Get(db sql.Executor, id types.BlockID) (rst *types.Block, err error) {
 	if rows, err := db.Exec("select block from blocks where id = ?1;", func(stmt *sql.Statement) {
 		stmt.BindBytes(1, id.Bytes())
 	}, func(stmt *sql.Statement) bool {
+		if stmt.ColumnLen(0) == 0 {
+			err = fmt.Errorf("%w block %s", sql.ErrPruned, id)
+			return false
+		}
 		rst, err = decodeBlock(stmt.ColumnReader(0), id)
 		return true
 	}); err != nil {
@@ -68,6 +72,21 @@ func Get(db sql.Executor, id types.BlockID) (rst *types.Block, err error) {
 	return rst, err
 }
 
+// GetLayer returns the layer of the block. Unlike Get it succeeds for pruned blocks.
+func GetLayer(db sql.Executor, id types.BlockID) (lid types.LayerID, err error) {
+	if rows, err := db.Exec("select layer from blocks where id = ?1;", func(stmt *sql.Statement) {
+		stmt.BindBytes(1, id.Bytes())
+	}, func(stmt *sql.Statement) bool {
+		lid = types.NewLayerID(uint32(stmt.ColumnInt64(0)))
+		return true
+	}); err != nil {
+		return lid, fmt.Errorf("get layer %s: %w", id, err)
+	} else if rows == 0 {
+		return lid, fmt.Errorf("%w block %s", sql.ErrNotFound, id)
+	}
+	return lid, nil
+}
+
 // SetValid updates verified status for a block.
 func SetValid(db sql.Executor, id types.BlockID) error {
 	return setValidity(db, id, valid)
@@ -124,3 +143,51 @@ func IDsInLayer(db sql.Executor, lid types.LayerID) ([]types.BlockID, error) {
 	}
 	return rst, nil
 }
+
+// IDsInLayerByArrival returns list of block ids in the layer in the order they were added to the database.
+func IDsInLayerByArrival(db sql.Executor, lid types.LayerID) ([]types.BlockID, error) {
+	var rst []types.BlockID
+	if _, err := db.Exec("select id from blocks where layer = ?1 order by rowid;", func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(lid.Uint32()))
+	}, func(stmt *sql.Statement) bool {
+		id := types.BlockID{}
+		stmt.ColumnBytes(0, id[:])
+		rst = append(rst, id)
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("select in layer %s: %w", lid, err)
+	}
+	return rst, nil
+}
+
+// Prune removes bodies of the blocks before the layer. IDs, layers and validity are kept.
+// Returns number of pruned blocks.
+func Prune(db sql.Executor, before types.LayerID) (int, error) {
+	rows, err := db.Exec(`update blocks set block = null
+		where layer < ?1 and block is not null returning id;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(before.Value))
+		}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("prune blocks before %s: %w", before, err)
+	}
+	return rows, nil
+}
+
+// PrunedRange returns the lowest and the highest layers with pruned blocks.
+func PrunedRange(db sql.Executor) (from, to types.LayerID, err error) {
+	var total int
+	if _, err := db.Exec(`select count(*), min(layer), max(layer) from blocks where block is null;`, nil,
+		func(stmt *sql.Statement) bool {
+			total = stmt.ColumnInt(0)
+			from = types.NewLayerID(uint32(stmt.ColumnInt64(1)))
+			to = types.NewLayerID(uint32(stmt.ColumnInt64(2)))
+			return true
+		}); err != nil {
+		return from, to, fmt.Errorf("pruned blocks range: %w", err)
+	}
+	if total == 0 {
+		return from, to, fmt.Errorf("pruned blocks range: %w", sql.ErrNotFound)
+	}
+	return from, to, nil
+}
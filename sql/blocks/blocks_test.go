@@ -106,3 +106,78 @@ func TestLayerFilter(t *testing.T) {
 		require.Equal(t, bid, blocks[i].ID())
 	}
 }
+
+func TestIDsInLayerByArrival(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.NewLayerID(1)
+	blocks := []*types.Block{
+		types.NewExistingBlock(
+			types.BlockID{3, 3},
+			types.InnerBlock{LayerIndex: lid},
+		),
+		types.NewExistingBlock(
+			types.BlockID{1, 1},
+			types.InnerBlock{LayerIndex: lid},
+		),
+		types.NewExistingBlock(
+			types.BlockID{2, 2},
+			types.InnerBlock{LayerIndex: lid.Add(1)},
+		),
+	}
+	for _, block := range blocks {
+		require.NoError(t, Add(db, block))
+	}
+	bids, err := IDsInLayerByArrival(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, []types.BlockID{blocks[0].ID(), blocks[1].ID()}, bids)
+}
+
+func TestPrune(t *testing.T) {
+	db := sql.InMemory()
+	start := types.NewLayerID(1)
+	blocks := []*types.Block{
+		types.NewExistingBlock(types.BlockID{1}, types.InnerBlock{LayerIndex: start}),
+		types.NewExistingBlock(types.BlockID{2}, types.InnerBlock{LayerIndex: start.Add(1)}),
+		types.NewExistingBlock(types.BlockID{3}, types.InnerBlock{LayerIndex: start.Add(2)}),
+	}
+	for _, block := range blocks {
+		require.NoError(t, Add(db, block))
+	}
+	require.NoError(t, SetValid(db, blocks[0].ID()))
+	_, _, err := PrunedRange(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	pruned, err := Prune(db, start.Add(2))
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+	from, to, err := PrunedRange(db)
+	require.NoError(t, err)
+	require.Equal(t, start, from)
+	require.Equal(t, start.Add(1), to)
+	pruned, err = Prune(db, start.Add(2))
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	for _, block := range blocks[:2] {
+		_, err := Get(db, block.ID())
+		require.ErrorIs(t, err, sql.ErrPruned)
+		exists, err := Has(db, block.ID())
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+	got, err := Get(db, blocks[2].ID())
+	require.NoError(t, err)
+	require.Equal(t, blocks[2], got)
+
+	valid, err := IsValid(db, blocks[0].ID())
+	require.NoError(t, err)
+	require.True(t, valid)
+	ids, err := IDsInLayer(db, start)
+	require.NoError(t, err)
+	require.Equal(t, []types.BlockID{blocks[0].ID()}, ids)
+	lid, err := GetLayer(db, blocks[1].ID())
+	require.NoError(t, err)
+	require.Equal(t, start.Add(1), lid)
+	_, err = GetLayer(db, types.BlockID{4})
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
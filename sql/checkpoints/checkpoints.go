@@ -0,0 +1,48 @@
+package checkpoints
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Add checkpoint of the tortoise state taken after the layer was processed.
+func Add(db sql.Executor, lid types.LayerID, data []byte) error {
+	if _, err := db.Exec(`insert into tortoise_checkpoints (layer, data) values (?1, ?2)
+					on conflict(layer) do update set data=?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+			stmt.BindBytes(2, data)
+		}, nil); err != nil {
+		return fmt.Errorf("add checkpoint %s: %w", lid, err)
+	}
+	return nil
+}
+
+// Last returns the checkpoint with the highest layer.
+func Last(db sql.Executor) (lid types.LayerID, data []byte, err error) {
+	if rows, err := db.Exec("select layer, data from tortoise_checkpoints order by layer desc limit 1;", nil,
+		func(stmt *sql.Statement) bool {
+			lid = types.NewLayerID(uint32(stmt.ColumnInt64(0)))
+			data = make([]byte, stmt.ColumnLen(1))
+			stmt.ColumnBytes(1, data)
+			return false
+		}); err != nil {
+		return lid, nil, fmt.Errorf("last checkpoint: %w", err)
+	} else if rows == 0 {
+		return lid, nil, fmt.Errorf("last checkpoint: %w", sql.ErrNotFound)
+	}
+	return lid, data, nil
+}
+
+// Prune deletes checkpoints before the layer.
+func Prune(db sql.Executor, before types.LayerID) error {
+	if _, err := db.Exec("delete from tortoise_checkpoints where layer < ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(before.Value))
+		}, nil); err != nil {
+		return fmt.Errorf("prune checkpoints before %s: %w", before, err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package checkpoints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestCheckpoints(t *testing.T) {
+	db := sql.InMemory()
+
+	_, _, err := Last(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, Add(db, types.NewLayerID(uint32(i*10)), []byte{byte(i)}))
+	}
+	lid, data, err := Last(db)
+	require.NoError(t, err)
+	require.Equal(t, types.NewLayerID(30), lid)
+	require.Equal(t, []byte{3}, data)
+
+	require.NoError(t, Add(db, lid, []byte{4, 4}))
+	_, data, err = Last(db)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 4}, data)
+
+	require.NoError(t, Prune(db, lid))
+	var count int
+	_, err = db.Exec("select count(*) from tortoise_checkpoints", nil, func(stmt *sql.Statement) bool {
+		count = stmt.ColumnInt(0)
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"crawshaw.io/sqlite"
 	"crawshaw.io/sqlite/sqlitex"
@@ -19,6 +22,8 @@ var (
 	ErrNotFound = database.ErrNotFound
 	// ErrObjectExists is returned if database contraints didn't allow to insert an object.
 	ErrObjectExists = errors.New("database: object exists")
+	// ErrPruned is returned if object is known but its body was pruned by retention policy.
+	ErrPruned = errors.New("database: object was pruned")
 )
 
 // Executor is an interface for executing raw statement.
@@ -42,9 +47,13 @@ type Encoder func(*Statement)
 // Decoder for sqlite rows.
 type Decoder func(*Statement) bool
 
+// backupStepPages is a number of pages copied before checking if backup was canceled.
+const backupStepPages = 1024
+
 func defaultConf() *conf {
 	return &conf{
 		connections: 16,
+		busyTimeout: 10 * time.Second,
 		migrations:  embeddedMigrations,
 	}
 }
@@ -52,16 +61,26 @@ func defaultConf() *conf {
 type conf struct {
 	flags       sqlite.OpenFlags
 	connections int
+	busyTimeout time.Duration
 	migrations  Migrations
 }
 
 // WithConnections overwrites number of pooled connections.
+// One connection is reserved for writes, the rest are read-only.
 func WithConnections(n int) Opt {
 	return func(c *conf) {
 		c.connections = n
 	}
 }
 
+// WithBusyTimeout overwrites the time that Exec waits for the writer connection,
+// and that every connection waits for a database lock, before failing with SQLITE_BUSY.
+func WithBusyTimeout(timeout time.Duration) Opt {
+	return func(c *conf) {
+		c.busyTimeout = timeout
+	}
+}
+
 // WithMigrations overwrites embedded migrations.
 func WithMigrations(migrations Migrations) Opt {
 	return func(c *conf) {
@@ -87,43 +106,106 @@ func InMemory(opts ...Opt) *Database {
 // Database is opened in WAL mode and pragma synchronous=normal.
 // https://sqlite.org/wal.html
 // https://www.sqlite.org/pragma.html#pragma_synchronous
+//
+// All writes are serialized through a single connection, so that writers never wait
+// on busy timeout. Remaining connections are opened in query only mode and used
+// by Exec for select statements. In WAL mode readers are not blocked by the writer.
+// Exec that waits for the writer connection longer than busy timeout, for example
+// when it is called while a transaction is held by the same goroutine, fails with SQLITE_BUSY.
 func Open(uri string, opts ...Opt) (*Database, error) {
 	config := defaultConf()
 	for _, opt := range opts {
 		opt(config)
 	}
-	pool, err := sqlitex.Open(uri, config.flags, config.connections)
+	if config.connections < 1 {
+		return nil, fmt.Errorf("open db %s: at least one connection is required", uri)
+	}
+	writer, err := sqlitex.Open(uri, config.flags, 1)
 	if err != nil {
 		return nil, fmt.Errorf("open db %s: %w", uri, err)
 	}
-	db := &Database{pool: pool}
+	db := &Database{writer: writer, busyTimeout: config.busyTimeout, statements: map[*sqlite.Conn]*statements{}}
+	if err := db.register(writer, 1); err != nil {
+		writer.Close()
+		return nil, err
+	}
 	if config.migrations != nil {
 		tx, err := db.Tx(context.Background())
 		if err != nil {
+			db.Close()
 			return nil, err
 		}
 		err = config.migrations(tx)
 		if err == nil {
-			tx.Commit()
+			err = tx.Commit()
 		}
 		tx.Release()
 		if err != nil {
+			db.Close()
 			return nil, err
 		}
 	}
-	for i := 0; i < config.connections; i++ {
-		conn := pool.Get(context.Background())
-		if err := registerFunctions(conn); err != nil {
+	// readers are opened after migrations, so that they are never exposed to an outdated schema
+	if readers := config.connections - 1; readers > 0 {
+		db.readers, err = sqlitex.OpenInit(context.Background(), uri, config.flags, readers, "PRAGMA query_only = ON;")
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("open readers %s: %w", uri, err)
+		}
+		if err := db.register(db.readers, readers); err != nil {
+			db.Close()
 			return nil, err
 		}
-		defer pool.Put(conn)
 	}
 	return db, nil
 }
 
 // Database is an instance of sqlite database.
 type Database struct {
-	pool *sqlitex.Pool
+	writer *sqlitex.Pool
+	// readers is nil if database was opened with a single connection.
+	readers     *sqlitex.Pool
+	busyTimeout time.Duration
+	// statements is populated when database is opened and is not modified afterwards.
+	statements map[*sqlite.Conn]*statements
+}
+
+// register custom functions, busy timeout and statements for every connection in the pool.
+func (db *Database) register(pool *sqlitex.Pool, size int) error {
+	conns := make([]*sqlite.Conn, 0, size)
+	defer func() {
+		for _, conn := range conns {
+			pool.Put(conn)
+		}
+	}()
+	for i := 0; i < size; i++ {
+		conn := pool.Get(context.Background())
+		if conn == nil {
+			return ErrNoConnection
+		}
+		conns = append(conns, conn)
+		if err := registerFunctions(conn); err != nil {
+			return err
+		}
+		conn.SetBusyTimeout(db.busyTimeout)
+		db.statements[conn] = newStatements(conn)
+	}
+	return nil
+}
+
+// getConn returns a connection for the query. Queries that only read
+// from the database are executed on the read-only connections.
+func (db *Database) getConn(ctx context.Context, query string) (*sqlitex.Pool, *sqlite.Conn) {
+	pool := db.writer
+	if db.readers != nil && isSelect(query) {
+		pool = db.readers
+	}
+	return pool, pool.Get(ctx)
+}
+
+func isSelect(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n")
+	return len(query) >= 6 && strings.EqualFold(query[:6], "select")
 }
 
 // Tx creates deferred sqlite transaction.
@@ -134,7 +216,7 @@ type Database struct {
 //
 // https://www.sqlite.org/lang_transaction.html
 func (db *Database) Tx(ctx context.Context) (*Tx, error) {
-	conn := db.pool.Get(ctx)
+	conn := db.writer.Get(ctx)
 	if conn == nil {
 		return nil, ErrNoConnection
 	}
@@ -143,6 +225,8 @@ func (db *Database) Tx(ctx context.Context) (*Tx, error) {
 }
 
 // Exec statement using one of the connection from the pool.
+// Select statements are executed on read-only connections, everything else
+// waits for the writer connection.
 //
 // If you care about atomicity of the operation (for example writing rewards to multiple accounts)
 // Tx should be used. Otherwise sqlite will not guarantee that all side-effects of operations are
@@ -150,32 +234,127 @@ func (db *Database) Tx(ctx context.Context) (*Tx, error) {
 //
 // Note that Exec will block until datatabase is closed or statement has finished.
 // If application needs to control statement execution lifetime use one of the transaction.
+// Waiting for a connection is bounded by busy timeout, see WithBusyTimeout.
 func (db *Database) Exec(query string, encoder Encoder, decoder Decoder) (int, error) {
-	conn := db.pool.Get(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), db.busyTimeout)
+	defer cancel()
+	pool, conn := db.getConn(ctx, query)
 	if conn == nil {
+		if ctx.Err() != nil {
+			return 0, sqlite.Error{Code: sqlite.SQLITE_BUSY, Loc: "Database.Exec", Query: query,
+				Msg: "no free connection within busy timeout"}
+		}
 		return 0, ErrNoConnection
 	}
-	defer db.pool.Put(conn)
-	return exec(conn, query, encoder, decoder)
+	defer pool.Put(conn)
+	return exec(db.statements[conn], query, encoder, decoder)
+}
+
+// Backup writes a consistent snapshot of the database to path.
+//
+// Snapshot is created using sqlite online backup api. Backup holds a read transaction
+// for the whole duration of the copy, so that writers, that are not blocked in WAL mode,
+// don't force backup to restart. Snapshot is written to a temporary file and renamed
+// to path only when it is complete. Read-only connection is used if available, so that
+// backup doesn't block the writer.
+//
+// https://www.sqlite.org/backup.html
+func (db *Database) Backup(ctx context.Context, path string) error {
+	pool, conn := db.getConn(ctx, "select")
+	if conn == nil {
+		return ErrNoConnection
+	}
+	defer pool.Put(conn)
+
+	tmp := path + ".tmp"
+	if err := removeDBFiles(tmp); err != nil {
+		return err
+	}
+	if err := backup(ctx, db.statements[conn], tmp); err != nil {
+		removeDBFiles(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename %s to %s: %w", tmp, path, err)
+	}
+	return nil
+}
+
+func backup(ctx context.Context, stmts *statements, path string) error {
+	conn := stmts.conn
+	if _, err := exec(stmts, "BEGIN;", nil, nil); err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer exec(stmts, "ROLLBACK;", nil, nil)
+	// read transaction is started by the first select
+	if _, err := exec(stmts, "select count(*) from sqlite_master;", nil, nil); err != nil {
+		return fmt.Errorf("start read: %w", err)
+	}
+
+	dst, err := sqlite.OpenConn(path, 0)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer dst.Close()
+	b, err := conn.BackupInit("", "", dst)
+	if err != nil {
+		return fmt.Errorf("init backup to %s: %w", path, err)
+	}
+	for {
+		// Step returns nil both when backup is done and when more pages remain
+		if err := b.Step(backupStepPages); err == nil {
+			if b.Remaining() == 0 {
+				break
+			}
+		} else if code := sqlite.ErrCode(err); code != sqlite.SQLITE_BUSY && code != sqlite.SQLITE_LOCKED {
+			b.Finish()
+			return fmt.Errorf("backup step: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			b.Finish()
+			return ctx.Err()
+		default:
+		}
+	}
+	if err := b.Finish(); err != nil {
+		return fmt.Errorf("finish backup: %w", err)
+	}
+	return nil
+}
+
+// removeDBFiles removes database file together with wal and shared memory files.
+func removeDBFiles(path string) error {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", path+suffix, err)
+		}
+	}
+	return nil
 }
 
 // Close closes all pooled connections.
 func (db *Database) Close() error {
-	if err := db.pool.Close(); err != nil {
+	if db.readers != nil {
+		if err := db.readers.Close(); err != nil {
+			return fmt.Errorf("close readers %w", err)
+		}
+	}
+	if err := db.writer.Close(); err != nil {
 		return fmt.Errorf("close pool %w", err)
 	}
 	return nil
 }
 
-func exec(conn *sqlite.Conn, query string, encoder Encoder, decoder Decoder) (int, error) {
-	stmt, err := conn.Prepare(query)
+func exec(stmts *statements, query string, encoder Encoder, decoder Decoder) (int, error) {
+	stmt, err := stmts.prepare(query)
 	if err != nil {
 		return 0, fmt.Errorf("prepare %s: %w", query, err)
 	}
+	defer stmts.done(query, stmt)
 	if encoder != nil {
 		encoder(stmt)
 	}
-	defer stmt.ClearBindings()
 
 	rows := 0
 	for {
@@ -196,9 +375,6 @@ func exec(conn *sqlite.Conn, query string, encoder Encoder, decoder Decoder) (in
 			continue
 		}
 		if !decoder(stmt) {
-			if err := stmt.Reset(); err != nil {
-				return rows, fmt.Errorf("statement reset %w", err)
-			}
 			return rows, nil
 		}
 	}
@@ -234,7 +410,7 @@ func (tx *Tx) Commit() error {
 
 // Release transaction. Every transaction that was created must be released.
 func (tx *Tx) Release() error {
-	defer tx.db.pool.Put(tx.conn)
+	defer tx.db.writer.Put(tx.conn)
 	if tx.committed {
 		return nil
 	}
@@ -245,5 +421,5 @@ func (tx *Tx) Release() error {
 
 // Exec query.
 func (tx *Tx) Exec(query string, encoder Encoder, decoder Decoder) (int, error) {
-	return exec(tx.conn, query, encoder, decoder)
+	return exec(tx.db.statements[tx.conn], query, encoder, decoder)
 }
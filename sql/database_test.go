@@ -3,9 +3,13 @@ package sql
 import (
 	"context"
 	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
+	"crawshaw.io/sqlite"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 )
 
 func testTables(db Executor) error {
@@ -57,3 +61,231 @@ func TestTransactionIsolation(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, rows, 0)
 }
+
+func insertTesting(tb testing.TB, db Executor, id string, field int) {
+	tb.Helper()
+	_, err := db.Exec("insert into testing1(id, field) values (?1, ?2)", func(stmt *Statement) {
+		stmt.BindText(1, id)
+		stmt.BindInt64(2, int64(field))
+	}, nil)
+	require.NoError(tb, err)
+}
+
+func countTesting(tb testing.TB, db Executor) int {
+	tb.Helper()
+	var count int
+	_, err := db.Exec("select count(*) from testing1", nil, func(stmt *Statement) bool {
+		count = stmt.ColumnInt(0)
+		return true
+	})
+	require.NoError(tb, err)
+	return count
+}
+
+func TestBackup(t *testing.T) {
+	db := persistentDB(t)
+	const n = 10000
+	for i := 0; i < n; i++ {
+		insertTesting(t, db, strconv.Itoa(i), i)
+	}
+
+	var (
+		eg  errgroup.Group
+		ctx = context.Background()
+	)
+	eg.Go(func() error {
+		for i := n; i < 2*n; i++ {
+			if _, err := db.Exec("insert into testing1(id, field) values (?1, ?2)", func(stmt *Statement) {
+				stmt.BindText(1, strconv.Itoa(i))
+				stmt.BindInt64(2, int64(i))
+			}, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	path := filepath.Join(t.TempDir(), "backup.sql")
+	require.NoError(t, db.Backup(ctx, path))
+	require.NoError(t, eg.Wait())
+	require.NoFileExists(t, path+".tmp")
+
+	backup, err := Open("file:"+path, WithMigrations(nil))
+	require.NoError(t, err)
+	defer backup.Close()
+	count := countTesting(t, backup)
+	require.GreaterOrEqual(t, count, n)
+	require.LessOrEqual(t, count, 2*n)
+	require.Equal(t, 2*n, countTesting(t, db))
+}
+
+func TestBackupCanceled(t *testing.T) {
+	db := persistentDB(t)
+	insertTesting(t, db, "1", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := filepath.Join(t.TempDir(), "backup.sql")
+	require.Error(t, db.Backup(ctx, path))
+	require.NoFileExists(t, path)
+	require.NoFileExists(t, path+".tmp")
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	db := persistentDB(t)
+	defer db.Close()
+
+	const (
+		writers = 8
+		n       = 100
+	)
+	var eg errgroup.Group
+	for w := 0; w < writers; w++ {
+		w := w
+		eg.Go(func() error {
+			for i := 0; i < n; i++ {
+				if _, err := db.Exec("insert into testing1(id, field) values (?1, ?2)", func(stmt *Statement) {
+					stmt.BindText(1, strconv.Itoa(w*n+i))
+					stmt.BindInt64(2, int64(i))
+				}, nil); err != nil {
+					return err
+				}
+				if _, err := db.Exec("select count(*) from testing1", nil, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	require.NoError(t, eg.Wait())
+	require.Equal(t, writers*n, countTesting(t, db))
+}
+
+func TestReadersNotBlockedByWriter(t *testing.T) {
+	db := persistentDB(t)
+	defer db.Close()
+	insertTesting(t, db, "1", 1)
+
+	tx, err := db.Tx(context.Background())
+	require.NoError(t, err)
+	defer tx.Release()
+	insertTesting(t, tx, "2", 2)
+
+	// writer connection is held by tx, select is executed on read-only connection
+	// and doesn't observe uncommitted insert
+	require.Equal(t, 1, countTesting(t, db))
+	require.NoError(t, tx.Commit())
+	require.Equal(t, 2, countTesting(t, db))
+}
+
+func selectField(tb testing.TB, db Executor, id string) int {
+	tb.Helper()
+	field := -1
+	_, err := db.Exec("select field from testing1 where id = ?1", func(stmt *Statement) {
+		stmt.BindText(1, id)
+	}, func(stmt *Statement) bool {
+		field = stmt.ColumnInt(0)
+		return true
+	})
+	require.NoError(tb, err)
+	return field
+}
+
+func TestNestedExec(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		open func(testing.TB) *Database
+		// readers is true if nested select is executed on another connection,
+		// otherwise nested Exec waits for the connection held by the outer Exec
+		readers bool
+	}{
+		{desc: "in memory", open: func(testing.TB) *Database { return InMemory(WithMigrations(testTables)) }},
+		{desc: "with readers", open: persistentDB, readers: true},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			db := tc.open(t)
+			defer db.Close()
+			const n = 10
+			for i := 0; i < n; i++ {
+				insertTesting(t, db, strconv.Itoa(i), i)
+			}
+
+			for _, exec := range []struct {
+				desc string
+				db   func(testing.TB) (Executor, func())
+			}{
+				{desc: "db", db: func(testing.TB) (Executor, func()) { return db, func() {} }},
+				{desc: "tx", db: func(tb testing.TB) (Executor, func()) {
+					tx, err := db.Tx(context.Background())
+					require.NoError(tb, err)
+					return tx, func() { require.NoError(tb, tx.Release()) }
+				}},
+			} {
+				exec := exec
+				if exec.desc == "db" && !tc.readers {
+					continue
+				}
+				t.Run(exec.desc, func(t *testing.T) {
+					ex, release := exec.db(t)
+					defer release()
+					outer := 0
+					_, err := ex.Exec("select id, field from testing1 order by field", nil, func(stmt *Statement) bool {
+						// the same query is executed while the outer statement is stepped
+						require.Equal(t, n, countTesting(t, ex))
+						inner := 0
+						_, err := ex.Exec("select id, field from testing1 order by field", nil, func(*Statement) bool {
+							inner++
+							return true
+						})
+						require.NoError(t, err)
+						require.Equal(t, n, inner)
+						require.Equal(t, outer, selectField(t, ex, stmt.ColumnText(0)))
+						require.Equal(t, outer, stmt.ColumnInt(1))
+						outer++
+						return true
+					})
+					require.NoError(t, err)
+					require.Equal(t, n, outer)
+				})
+			}
+		})
+	}
+}
+
+func TestExecWhileTx(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	t.Run("in memory", func(t *testing.T) {
+		db := InMemory(WithMigrations(testTables), WithBusyTimeout(timeout))
+		defer db.Close()
+		tx, err := db.Tx(context.Background())
+		require.NoError(t, err)
+		defer tx.Release()
+
+		// the only connection is held by tx
+		_, err = db.Exec("select count(*) from testing1", nil, nil)
+		require.Equal(t, sqlite.SQLITE_BUSY, sqlite.ErrCode(err))
+		_, err = db.Exec("insert into testing1(id, field) values ('1', 1)", nil, nil)
+		require.Equal(t, sqlite.SQLITE_BUSY, sqlite.ErrCode(err))
+	})
+	t.Run("with readers", func(t *testing.T) {
+		db, err := Open(testURI(t), WithMigrations(testTables), WithBusyTimeout(timeout))
+		require.NoError(t, err)
+		defer db.Close()
+		insertTesting(t, db, "1", 1)
+
+		tx, err := db.Tx(context.Background())
+		require.NoError(t, err)
+		insertTesting(t, tx, "2", 2)
+
+		// select is executed on a reader, write waits for the writer held by tx
+		require.Equal(t, 1, countTesting(t, db))
+		require.Equal(t, 1, selectField(t, db, "1"))
+		_, err = db.Exec("insert into testing1(id, field) values ('3', 3)", nil, nil)
+		require.Equal(t, sqlite.SQLITE_BUSY, sqlite.ErrCode(err))
+
+		require.NoError(t, tx.Commit())
+		require.NoError(t, tx.Release())
+		insertTesting(t, db, "3", 3)
+		require.Equal(t, 3, countTesting(t, db))
+	})
+}
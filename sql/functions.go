@@ -16,5 +16,10 @@ func registerFunctions(conn *sqlite.Conn) error {
 	}, nil, nil); err != nil {
 		return fmt.Errorf("registering add_uint64: %w", err)
 	}
+	if err := conn.CreateFunction("sub_uint64", true, 2, func(ctx sqlite.Context, values ...sqlite.Value) {
+		ctx.ResultInt64(int64(uint64(values[0].Int64()) - uint64(values[1].Int64())))
+	}, nil, nil); err != nil {
+		return fmt.Errorf("registering sub_uint64: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,77 @@
+package hareoutputs
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Add the output of the hare consensus for the layer.
+func Add(db sql.Executor, lid types.LayerID, output []byte) error {
+	if _, err := db.Exec(`insert into hare_outputs (layer, output) values (?1, ?2)
+					on conflict(layer) do update set output=?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+			stmt.BindBytes(2, output)
+		}, nil); err != nil {
+		return fmt.Errorf("add hare output %s: %w", lid, err)
+	}
+	return nil
+}
+
+// SetCertificate of the hare output for the layer.
+// Certificate may be set before the output, as they are reported independently.
+func SetCertificate(db sql.Executor, lid types.LayerID, certificate []byte) error {
+	if _, err := db.Exec(`insert into hare_outputs (layer, certificate) values (?1, ?2)
+					on conflict(layer) do update set certificate=?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+			stmt.BindBytes(2, certificate)
+		}, nil); err != nil {
+		return fmt.Errorf("set hare certificate %s: %w", lid, err)
+	}
+	return nil
+}
+
+func decodeOutput(stmt *sql.Statement) (output, certificate []byte) {
+	output = make([]byte, stmt.ColumnLen(1))
+	stmt.ColumnBytes(1, output)
+	if n := stmt.ColumnLen(2); n > 0 {
+		certificate = make([]byte, n)
+		stmt.ColumnBytes(2, certificate)
+	}
+	return output, certificate
+}
+
+// Get the hare output and the certificate for the layer. Certificate is nil if it wasn't set.
+func Get(db sql.Executor, lid types.LayerID) (output, certificate []byte, err error) {
+	if rows, err := db.Exec("select layer, output, certificate from hare_outputs where layer = ?1 and output is not null;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+		}, func(stmt *sql.Statement) bool {
+			output, certificate = decodeOutput(stmt)
+			return false
+		}); err != nil {
+		return nil, nil, fmt.Errorf("get hare output %s: %w", lid, err)
+	} else if rows == 0 {
+		return nil, nil, fmt.Errorf("get hare output %s: %w", lid, sql.ErrNotFound)
+	}
+	return output, certificate, nil
+}
+
+// IterateFrom calls fn for every hare output starting from the layer in the ascending order
+// until fn returns false.
+func IterateFrom(db sql.Executor, from types.LayerID, fn func(lid types.LayerID, output, certificate []byte) bool) error {
+	if _, err := db.Exec(`select layer, output, certificate from hare_outputs
+					where layer >= ?1 and output is not null order by layer;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(from.Value))
+		}, func(stmt *sql.Statement) bool {
+			output, certificate := decodeOutput(stmt)
+			return fn(types.NewLayerID(uint32(stmt.ColumnInt64(0))), output, certificate)
+		}); err != nil {
+		return fmt.Errorf("iterate hare outputs from %s: %w", from, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package hareoutputs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestHareOutputs(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.NewLayerID(10)
+
+	_, _, err := Get(db, lid)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, Add(db, lid, []byte{1, 1}))
+	output, cert, err := Get(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 1}, output)
+	require.Nil(t, cert)
+
+	require.NoError(t, SetCertificate(db, lid, []byte{2, 2}))
+	output, cert, err = Get(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 1}, output)
+	require.Equal(t, []byte{2, 2}, cert)
+}
+
+func TestCertificateBeforeOutput(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.NewLayerID(10)
+
+	require.NoError(t, SetCertificate(db, lid, []byte{2}))
+	_, _, err := Get(db, lid)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	require.NoError(t, IterateFrom(db, lid, func(types.LayerID, []byte, []byte) bool {
+		require.Fail(t, "output is not set")
+		return true
+	}))
+
+	require.NoError(t, Add(db, lid, []byte{1}))
+	output, cert, err := Get(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, output)
+	require.Equal(t, []byte{2}, cert)
+}
+
+func TestIterateFrom(t *testing.T) {
+	db := sql.InMemory()
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, Add(db, types.NewLayerID(uint32(i)), []byte{byte(i)}))
+	}
+	require.NoError(t, SetCertificate(db, types.NewLayerID(3), []byte{33}))
+
+	var (
+		layers []types.LayerID
+		certs  [][]byte
+	)
+	require.NoError(t, IterateFrom(db, types.NewLayerID(2), func(lid types.LayerID, output, cert []byte) bool {
+		require.Equal(t, []byte{byte(lid.Uint32())}, output)
+		layers = append(layers, lid)
+		certs = append(certs, cert)
+		return lid.Before(types.NewLayerID(3))
+	}))
+	require.Equal(t, []types.LayerID{types.NewLayerID(2), types.NewLayerID(3)}, layers)
+	require.Equal(t, [][]byte{nil, {33}}, certs)
+}
@@ -3,6 +3,7 @@ package identities
 import (
 	"fmt"
 
+	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
@@ -10,7 +11,7 @@ import (
 func SetMalicious(db sql.Executor, pubkey []byte) error {
 	_, err := db.Exec(`insert into identities (pubkey, malicious) 
 	values (?1, 1) 
-	on conflict do nothing;`,
+	on conflict(pubkey) do update set malicious = 1;`,
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, pubkey)
 		}, nil,
@@ -23,7 +24,7 @@ func SetMalicious(db sql.Executor, pubkey []byte) error {
 
 // IsMalicious returns true if identity is known to be malicious.
 func IsMalicious(db sql.Executor, pubkey []byte) (bool, error) {
-	rows, err := db.Exec("select 1 from identities where pubkey = ?1;",
+	rows, err := db.Exec("select 1 from identities where pubkey = ?1 and malicious = 1;",
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, pubkey)
 		}, nil)
@@ -32,3 +33,106 @@ func IsMalicious(db sql.Executor, pubkey []byte) (bool, error) {
 	}
 	return rows > 0, nil
 }
+
+// IsMaliciousAt returns true if identity is known to be malicious in the layer.
+// Identity that was recorded as malicious without a proof is malicious in every layer.
+func IsMaliciousAt(db sql.Executor, pubkey []byte, lid types.LayerID) (bool, error) {
+	rows, err := db.Exec(`select 1 from identities 
+	where pubkey = ?1 and malicious = 1 and ifnull(malicious_layer, 0) <= ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, pubkey)
+			stmt.BindInt64(2, int64(lid.Value))
+		}, nil)
+	if err != nil {
+		return false, fmt.Errorf("is malicious 0x%x in %s: %w", pubkey, lid, err)
+	}
+	return rows > 0, nil
+}
+
+// SetProof records identity as malicious starting from the layer, together with the proof.
+// Proof is replaced only if it proves malfeasance in the earlier layer.
+func SetProof(db sql.Executor, pubkey []byte, lid types.LayerID, proof []byte) error {
+	_, err := db.Exec(`insert into identities (pubkey, malicious, malicious_layer, proof) 
+	values (?1, 1, ?2, ?3) 
+	on conflict(pubkey) do update set malicious = 1, malicious_layer = ?2, proof = ?3
+	where proof is null or malicious_layer > ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, pubkey)
+			stmt.BindInt64(2, int64(lid.Value))
+			stmt.BindBytes(3, proof)
+		}, nil,
+	)
+	if err != nil {
+		return fmt.Errorf("set proof 0x%x: %w", pubkey, err)
+	}
+	return nil
+}
+
+// GetProof returns the proof of malfeasance and the layer from which identity is malicious.
+func GetProof(db sql.Executor, pubkey []byte) (proof []byte, lid types.LayerID, err error) {
+	rows, err := db.Exec("select proof, malicious_layer from identities where pubkey = ?1 and proof is not null;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, pubkey)
+		}, func(stmt *sql.Statement) bool {
+			proof = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, proof)
+			lid = types.NewLayerID(uint32(stmt.ColumnInt64(1)))
+			return true
+		})
+	if err != nil {
+		return nil, types.LayerID{}, fmt.Errorf("get proof 0x%x: %w", pubkey, err)
+	} else if rows == 0 {
+		return nil, types.LayerID{}, fmt.Errorf("%w proof for 0x%x", sql.ErrNotFound, pubkey)
+	}
+	return proof, lid, nil
+}
+
+// IterateProven calls fn for every identity with a proof of malfeasance, until fn returns false.
+func IterateProven(db sql.Executor, fn func(pubkey []byte, lid types.LayerID) bool) error {
+	_, err := db.Exec("select pubkey, malicious_layer from identities where proof is not null;", nil,
+		func(stmt *sql.Statement) bool {
+			pubkey := make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, pubkey)
+			return fn(pubkey, types.NewLayerID(uint32(stmt.ColumnInt64(1))))
+		})
+	if err != nil {
+		return fmt.Errorf("iterate proven identities: %w", err)
+	}
+	return nil
+}
+
+// SetVRFPubkey records vrf public key that is coupled with the identity.
+func SetVRFPubkey(db sql.Executor, pubkey, vrf []byte) error {
+	// empty vrf key is bound as null, but it still must be distinguishable
+	// from identities that were only recorded as malicious
+	_, err := db.Exec(`insert into identities (pubkey, vrf_pubkey) 
+	values (?1, ifnull(?2, x'')) 
+	on conflict(pubkey) do update set vrf_pubkey = ifnull(?2, x'');`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, pubkey)
+			stmt.BindBytes(2, vrf)
+		}, nil,
+	)
+	if err != nil {
+		return fmt.Errorf("set vrf pubkey 0x%x: %w", pubkey, err)
+	}
+	return nil
+}
+
+// GetVRFPubkey returns vrf public key of the identity.
+func GetVRFPubkey(db sql.Executor, pubkey []byte) (vrf []byte, err error) {
+	rows, err := db.Exec("select vrf_pubkey from identities where pubkey = ?1 and vrf_pubkey is not null;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, pubkey)
+		}, func(stmt *sql.Statement) bool {
+			vrf = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, vrf)
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("get vrf pubkey 0x%x: %w", pubkey, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("%w vrf pubkey for 0x%x", sql.ErrNotFound, pubkey)
+	}
+	return vrf, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
@@ -21,3 +22,92 @@ func TestMalicious(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, mal)
 }
+
+func TestProof(t *testing.T) {
+	db := sql.InMemory()
+
+	pub := []byte{1, 1, 1, 1}
+	_, _, err := GetProof(db, pub)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	lid := types.NewLayerID(10)
+	require.NoError(t, SetProof(db, pub, lid, []byte("first")))
+	proof, got, err := GetProof(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), proof)
+	require.Equal(t, lid, got)
+
+	// later proof doesn't replace proof for the earlier layer
+	require.NoError(t, SetProof(db, pub, lid.Add(1), []byte("later")))
+	proof, got, err = GetProof(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), proof)
+	require.Equal(t, lid, got)
+
+	require.NoError(t, SetProof(db, pub, lid.Sub(1), []byte("earlier")))
+	proof, got, err = GetProof(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, []byte("earlier"), proof)
+	require.Equal(t, lid.Sub(1), got)
+
+	mal, err := IsMalicious(db, pub)
+	require.NoError(t, err)
+	require.True(t, mal)
+	for _, tc := range []struct {
+		lid       types.LayerID
+		malicious bool
+	}{
+		{lid: lid.Sub(2), malicious: false},
+		{lid: lid.Sub(1), malicious: true},
+		{lid: lid, malicious: true},
+	} {
+		mal, err := IsMaliciousAt(db, pub, tc.lid)
+		require.NoError(t, err)
+		require.Equal(t, tc.malicious, mal, "layer %s", tc.lid)
+	}
+
+	other := []byte{2, 2, 2, 2}
+	require.NoError(t, SetMalicious(db, other))
+	mal, err = IsMaliciousAt(db, other, types.NewLayerID(1))
+	require.NoError(t, err)
+	require.True(t, mal)
+
+	proven := map[string]types.LayerID{}
+	require.NoError(t, IterateProven(db, func(pubkey []byte, lid types.LayerID) bool {
+		proven[string(pubkey)] = lid
+		return true
+	}))
+	require.Equal(t, map[string]types.LayerID{string(pub): lid.Sub(1)}, proven)
+}
+
+func TestVRFPubkey(t *testing.T) {
+	db := sql.InMemory()
+
+	pub := []byte{1, 1, 1, 1}
+	_, err := GetVRFPubkey(db, pub)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	vrf := []byte{2, 2, 2}
+	require.NoError(t, SetVRFPubkey(db, pub, vrf))
+	got, err := GetVRFPubkey(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, vrf, got)
+
+	require.NoError(t, SetMalicious(db, pub))
+	got, err = GetVRFPubkey(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, vrf, got)
+	mal, err := IsMalicious(db, pub)
+	require.NoError(t, err)
+	require.True(t, mal)
+}
+
+func TestVRFPubkeyNotMalicious(t *testing.T) {
+	db := sql.InMemory()
+
+	pub := []byte{1, 1, 1, 1}
+	require.NoError(t, SetVRFPubkey(db, pub, []byte{2, 2}))
+	mal, err := IsMalicious(db, pub)
+	require.NoError(t, err)
+	require.False(t, mal)
+}
@@ -10,6 +10,7 @@ import (
 const (
 	hashField           = "hash"
 	aggregatedHashField = "aggregated_hash"
+	stateHashField      = "state_hash"
 )
 
 // Status of the layer.
@@ -71,6 +72,52 @@ func GetHareOutput(db sql.Executor, lid types.LayerID) (rst types.BlockID, err e
 	return rst, err
 }
 
+// SetApplied updates the block that was applied to the state in the layer.
+// Empty block id is stored if the layer was applied without a block.
+func SetApplied(db sql.Executor, lid types.LayerID, applied types.BlockID) error {
+	if _, err := db.Exec(`insert into layers (id, applied_block) values (?1, ?2) 
+					on conflict(id) do update set applied_block=?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+			stmt.BindBytes(2, applied[:])
+		}, nil); err != nil {
+		return fmt.Errorf("set applied %s: %w", lid, err)
+	}
+	return nil
+}
+
+// GetApplied returns the block that was applied to the state in the layer.
+func GetApplied(db sql.Executor, lid types.LayerID) (rst types.BlockID, err error) {
+	if rows, err := db.Exec("select applied_block from layers where id = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+		},
+		func(stmt *sql.Statement) bool {
+			if stmt.ColumnLen(0) == 0 {
+				err = fmt.Errorf("%w applied block for %s is null", sql.ErrNotFound, lid)
+				return false
+			}
+			stmt.ColumnBytes(0, rst[:])
+			return true
+		}); err != nil {
+		return rst, fmt.Errorf("get applied %s: %w", lid, err)
+	} else if rows == 0 {
+		return rst, fmt.Errorf("%w applied block is not set for %s", sql.ErrNotFound, lid)
+	}
+	return rst, err
+}
+
+// UnsetAppliedFrom removes applied blocks for layers starting from lid.
+func UnsetAppliedFrom(db sql.Executor, lid types.LayerID) error {
+	if _, err := db.Exec("update layers set applied_block = null where id >= ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Value))
+		}, nil); err != nil {
+		return fmt.Errorf("unset applied from %s: %w", lid, err)
+	}
+	return nil
+}
+
 // SetStatus updates status of the layer.
 func SetStatus(db sql.Executor, lid types.LayerID, status Status) error {
 	if _, err := db.Exec(`insert into mesh_status (layer, status) values (?1, ?2) 
@@ -146,3 +193,57 @@ func GetHash(db sql.Executor, lid types.LayerID) (types.Hash32, error) {
 func GetAggregatedHash(db sql.Executor, lid types.LayerID) (types.Hash32, error) {
 	return getHash(db, aggregatedHashField, lid)
 }
+
+// SetStateHash updates state hash for layer.
+func SetStateHash(db sql.Executor, lid types.LayerID, hash types.Hash32) error {
+	return setHash(db, stateHashField, lid, hash)
+}
+
+// GetStateHash for layer. Unlike other hashes it is not set for every layer,
+// and ErrNotFound is returned if state wasn't applied for the layer.
+func GetStateHash(db sql.Executor, lid types.LayerID) (rst types.Hash32, err error) {
+	if rows, err := db.Exec("select state_hash from layers where id = ?1 and state_hash is not null;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid.Uint32()))
+		},
+		func(stmt *sql.Statement) bool {
+			stmt.ColumnBytes(0, rst[:])
+			return true
+		}); err != nil {
+		return rst, fmt.Errorf("select state hash for %s: %w", lid, err)
+	} else if rows == 0 {
+		return rst, fmt.Errorf("%w state hash for %s", sql.ErrNotFound, lid)
+	}
+	return rst, err
+}
+
+// GetAggregatedHashRange returns the lowest and the highest layers with aggregated hash
+// and the number of layers in that range that have both layer and aggregated hashes.
+func GetAggregatedHashRange(db sql.Executor) (from, to types.LayerID, complete int, err error) {
+	var total int
+	if _, err := db.Exec(`select count(*), min(id), max(id) from layers where aggregated_hash is not null;`, nil,
+		func(stmt *sql.Statement) bool {
+			total = stmt.ColumnInt(0)
+			from = types.NewLayerID(uint32(stmt.ColumnInt64(1)))
+			to = types.NewLayerID(uint32(stmt.ColumnInt64(2)))
+			return true
+		}); err != nil {
+		return from, to, 0, fmt.Errorf("aggregated hash range: %w", err)
+	}
+	if total == 0 {
+		return from, to, 0, fmt.Errorf("aggregated hash range: %w", sql.ErrNotFound)
+	}
+	if _, err := db.Exec(`select count(*) from layers
+		where id between ?1 and ?2 and hash is not null and aggregated_hash is not null;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(from.Value))
+			stmt.BindInt64(2, int64(to.Value))
+		},
+		func(stmt *sql.Statement) bool {
+			complete = stmt.ColumnInt(0)
+			return true
+		}); err != nil {
+		return from, to, 0, fmt.Errorf("count hashed layers: %w", err)
+	}
+	return from, to, complete, nil
+}
@@ -33,6 +33,31 @@ func TestHareOutput(t *testing.T) {
 	require.Equal(t, expected, output)
 }
 
+func TestApplied(t *testing.T) {
+	db := sql.InMemory()
+	start := types.NewLayerID(10)
+
+	_, err := GetApplied(db, start)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, SetApplied(db, start, types.EmptyBlockID))
+	applied, err := GetApplied(db, start)
+	require.NoError(t, err)
+	require.Equal(t, types.EmptyBlockID, applied)
+
+	for i := uint32(1); i <= 3; i++ {
+		require.NoError(t, SetApplied(db, start.Add(i), types.BlockID{byte(i)}))
+	}
+	require.NoError(t, UnsetAppliedFrom(db, start.Add(2)))
+	applied, err = GetApplied(db, start.Add(1))
+	require.NoError(t, err)
+	require.Equal(t, types.BlockID{1}, applied)
+	for i := uint32(2); i <= 3; i++ {
+		_, err = GetApplied(db, start.Add(i))
+		require.ErrorIs(t, err, sql.ErrNotFound)
+	}
+}
+
 func TestStatus(t *testing.T) {
 	db := sql.InMemory()
 	lid := types.NewLayerID(10)
@@ -54,3 +79,42 @@ func TestStatus(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, lid, applied)
 }
+
+func TestStateHash(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.NewLayerID(10)
+
+	_, err := GetStateHash(db, lid)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, SetHash(db, lid, types.Hash32{2}))
+	_, err = GetStateHash(db, lid)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	expected := types.Hash32{1, 1}
+	require.NoError(t, SetStateHash(db, lid, expected))
+	hash, err := GetStateHash(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+}
+
+func TestAggregatedHashRange(t *testing.T) {
+	db := sql.InMemory()
+
+	_, _, _, err := GetAggregatedHashRange(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	start := types.NewLayerID(5)
+	for lid := start; lid.Before(start.Add(3)); lid = lid.Add(1) {
+		require.NoError(t, SetHash(db, lid, types.Hash32{1}))
+		require.NoError(t, SetAggregatedHash(db, lid, types.Hash32{2}))
+	}
+	// layer without hash
+	require.NoError(t, SetAggregatedHash(db, start.Add(4), types.Hash32{2}))
+
+	from, to, complete, err := GetAggregatedHashRange(db)
+	require.NoError(t, err)
+	require.Equal(t, start, from)
+	require.Equal(t, start.Add(4), to)
+	require.Equal(t, 3, complete)
+}
@@ -0,0 +1,200 @@
+// Package ldbimport imports data from legacy LevelDB stores into sql.Database.
+package ldbimport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/database"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/poets"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+const (
+	// AtxDir is a name of the legacy ATX store.
+	AtxDir = "atx"
+	// PoetDir is a name of the legacy PoET proofs store.
+	PoetDir = "poet"
+	// IdentitiesDir is a name of the legacy identities store.
+	IdentitiesDir = "ids"
+	// AppliedTxsDir is a name of the legacy applied transactions store.
+	AppliedTxsDir = "appliedTxs"
+)
+
+const (
+	atxHeaderPrefix    = "h"
+	atxTimestampPrefix = "t"
+	stateRootPrefix    = "root"
+)
+
+type importer func(log.Log, *database.LDBDatabase, *sql.Tx) error
+
+// Import copies data from the legacy LevelDB stores found in dir into db and removes
+// them once all data is committed. Stores that don't exist in dir are skipped, so it is
+// safe to call Import on every start.
+func Import(dir string, db *sql.Database, logger log.Log) error {
+	stores := []struct {
+		name string
+		fn   importer
+	}{
+		{name: AtxDir, fn: importAtxs},
+		{name: PoetDir, fn: importPoets},
+		{name: IdentitiesDir, fn: importIdentities},
+		{name: AppliedTxsDir, fn: importAppliedTxs},
+	}
+	for _, store := range stores {
+		path := filepath.Join(dir, store.name)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("stat %s: %w", path, err)
+		}
+		if err := importStore(logger, path, db, store.fn); err != nil {
+			return fmt.Errorf("import %s: %w", store.name, err)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("remove %s: %w", path, err)
+		}
+		logger.With().Info("imported legacy store", log.String("path", path))
+	}
+	return nil
+}
+
+func importStore(logger log.Log, path string, db *sql.Database, fn importer) error {
+	ldb, err := database.NewLDBDatabase(path, 0, 0, logger)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer ldb.Close()
+	tx, err := db.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	if err := fn(logger, ldb, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func importAtxs(logger log.Log, ldb *database.LDBDatabase, tx *sql.Tx) error {
+	it := ldb.Find([]byte(atxHeaderPrefix))
+	defer it.Release()
+	count := 0
+	for it.Next() {
+		if len(it.Key()) != len(atxHeaderPrefix)+types.ATXIDSize {
+			continue
+		}
+		id := types.ATXID(types.BytesToHash(it.Key()[len(atxHeaderPrefix):]))
+		buf, err := ldb.Get(id.Bytes())
+		if err != nil {
+			return fmt.Errorf("get atx body %s: %w", id, err)
+		}
+		var atx types.ActivationTx
+		if err := codec.Decode(buf, &atx); err != nil {
+			return fmt.Errorf("decode atx %s: %w", id, err)
+		}
+		atx.SetID(&id)
+		timestamp := time.Now()
+		if ts, err := ldb.Get(append([]byte(atxTimestampPrefix), id.Bytes()...)); err == nil && len(ts) == 8 {
+			timestamp = time.Unix(0, int64(binary.LittleEndian.Uint64(ts)))
+		}
+		if err := atxs.Add(tx, &atx, timestamp); err != nil && !errors.Is(err, sql.ErrObjectExists) {
+			return err
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("iterate atxs: %w", err)
+	}
+	logger.With().Info("imported atxs", log.Int("count", count))
+	return nil
+}
+
+func importPoets(logger log.Log, ldb *database.LDBDatabase, tx *sql.Tx) error {
+	it := ldb.Iterator()
+	defer it.Release()
+	count := 0
+	for it.Next() {
+		// keys for proofs and for (service id, round id) index are both 32 bytes long,
+		// index values are refs and can be distinguished by length.
+		if len(it.Value()) == types.Hash32Length {
+			continue
+		}
+		var msg types.PoetProofMessage
+		if err := codec.Decode(it.Value(), &msg); err != nil {
+			return fmt.Errorf("decode poet proof %x: %w", it.Key(), err)
+		}
+		ref := make([]byte, len(it.Key()))
+		copy(ref, it.Key())
+		if err := poets.Add(tx, ref, it.Value(), msg.PoetServiceID, msg.RoundID); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("iterate poets: %w", err)
+	}
+	logger.With().Info("imported poet proofs", log.Int("count", count))
+	return nil
+}
+
+func importIdentities(logger log.Log, ldb *database.LDBDatabase, tx *sql.Tx) error {
+	it := ldb.Iterator()
+	defer it.Release()
+	count := 0
+	for it.Next() {
+		if err := identities.SetVRFPubkey(tx, it.Key(), it.Value()); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("iterate identities: %w", err)
+	}
+	logger.With().Info("imported identities", log.Int("count", count))
+	return nil
+}
+
+func importAppliedTxs(logger log.Log, ldb *database.LDBDatabase, tx *sql.Tx) error {
+	it := ldb.Iterator()
+	defer it.Release()
+	txs, roots := 0, 0
+	for it.Next() {
+		key := it.Key()
+		switch {
+		case len(key) == len(stateRootPrefix)+4 && string(key[:len(stateRootPrefix)]) == stateRootPrefix:
+			lid := types.BytesToLayerID(key[len(stateRootPrefix):])
+			if err := layers.SetStateHash(tx, lid, types.BytesToHash(it.Value())); err != nil {
+				return err
+			}
+			roots++
+		case len(key) == types.Hash32Length:
+			id := types.TransactionID(types.BytesToHash(key))
+			if err := transactions.SetAppliedLayer(tx, id, types.BytesToLayerID(it.Value())); err != nil {
+				return err
+			}
+			txs++
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("iterate applied txs: %w", err)
+	}
+	logger.With().Info("imported applied transactions",
+		log.Int("transactions", txs),
+		log.Int("state_roots", roots),
+	)
+	return nil
+}
@@ -0,0 +1,107 @@
+package ldbimport
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/database"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/poets"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func openLDB(t *testing.T, dir, name string) *database.LDBDatabase {
+	ldb, err := database.NewLDBDatabase(filepath.Join(dir, name), 0, 0, logtest.New(t))
+	require.NoError(t, err)
+	return ldb
+}
+
+func TestImport(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	dir := t.TempDir()
+
+	atx := types.NewActivationTx(types.NIPostChallenge{
+		NodeID:     types.NodeID{Key: "aaaa"},
+		PubLayerID: types.NewLayerID(5),
+	}, types.Address{}, nil, 1, nil)
+	timestamp := time.Unix(0, 1000)
+	ldb := openLDB(t, dir, AtxDir)
+	buf, err := codec.Encode(atx.ActivationTxHeader)
+	require.NoError(t, err)
+	require.NoError(t, ldb.Put(append([]byte(atxHeaderPrefix), atx.ID().Bytes()...), buf))
+	buf, err = codec.Encode(atx)
+	require.NoError(t, err)
+	require.NoError(t, ldb.Put(atx.ID().Bytes(), buf))
+	ts := make([]byte, 8)
+	binary.LittleEndian.PutUint64(ts, uint64(timestamp.UnixNano()))
+	require.NoError(t, ldb.Put(append([]byte(atxTimestampPrefix), atx.ID().Bytes()...), ts))
+	ldb.Close()
+
+	msg := types.PoetProofMessage{PoetServiceID: []byte{1, 2}, RoundID: "7"}
+	ref, err := msg.Ref()
+	require.NoError(t, err)
+	poet, err := codec.Encode(&msg)
+	require.NoError(t, err)
+	index := types.CalcHash32([]byte("index"))
+	ldb = openLDB(t, dir, PoetDir)
+	require.NoError(t, ldb.Put(ref, poet))
+	require.NoError(t, ldb.Put(index.Bytes(), ref))
+	ldb.Close()
+
+	pub, vrf := []byte{1, 1, 1}, []byte{2, 2, 2}
+	ldb = openLDB(t, dir, IdentitiesDir)
+	require.NoError(t, ldb.Put(pub, vrf))
+	ldb.Close()
+
+	txid := types.TransactionID{3, 3}
+	lid := types.NewLayerID(9)
+	root := types.Hash32{4, 4}
+	ldb = openLDB(t, dir, AppliedTxsDir)
+	require.NoError(t, ldb.Put(txid.Bytes(), lid.Bytes()))
+	require.NoError(t, ldb.Put(append([]byte(stateRootPrefix), lid.Bytes()...), root.Bytes()))
+	ldb.Close()
+
+	db := sql.InMemory()
+	require.NoError(t, Import(dir, db, logtest.New(t)))
+
+	got, err := atxs.Get(db, atx.ID())
+	require.NoError(t, err)
+	require.Equal(t, atx.NIPostChallenge, got.NIPostChallenge)
+	gotTs, err := atxs.GetTimestamp(db, atx.ID())
+	require.NoError(t, err)
+	require.Equal(t, timestamp.UnixNano(), gotTs.UnixNano())
+
+	gotPoet, err := poets.Get(db, ref)
+	require.NoError(t, err)
+	require.Equal(t, poet, gotPoet)
+	gotRef, err := poets.GetRef(db, msg.PoetServiceID, msg.RoundID)
+	require.NoError(t, err)
+	require.Equal(t, ref, gotRef)
+
+	gotVrf, err := identities.GetVRFPubkey(db, pub)
+	require.NoError(t, err)
+	require.Equal(t, vrf, gotVrf)
+
+	applied, err := transactions.GetAppliedLayer(db, txid)
+	require.NoError(t, err)
+	require.Equal(t, lid, applied)
+	gotRoot, err := layers.GetStateHash(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, root, gotRoot)
+
+	for _, name := range []string{AtxDir, PoetDir, IdentitiesDir, AppliedTxsDir} {
+		require.NoDirExists(t, filepath.Join(dir, name))
+	}
+	// second import is a noop
+	require.NoError(t, Import(dir, db, logtest.New(t)))
+}
@@ -3,85 +3,356 @@ package sql
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //go:embed migrations/*.sql
 var embedded embed.FS
 
-type migration struct {
-	order   int
-	name    string
-	content *bufio.Scanner
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+var (
+	// ErrSchemaTooNew is returned if database schema was created by a newer version of the node.
+	ErrSchemaTooNew = errors.New("database: schema is newer than supported")
+	// ErrChecksumMismatch is returned if applied migration doesn't match the one known to the node.
+	ErrChecksumMismatch = errors.New("database: migration checksum mismatch")
+)
+
+// Migration is a numbered pair of up and down scripts.
+type Migration struct {
+	Order int
+	Name  string
+	Up    string
+	Down  string
+}
+
+// Checksum of the up script.
+func (m *Migration) Checksum() []byte {
+	sum := sha256.Sum256([]byte(m.Up))
+	return sum[:]
+}
+
+// MigrationStatus is a migration together with the state recorded in the database.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Checksum recorded in the database when migration was applied.
+	Checksum []byte
 }
 
 // Migrations is interface for migrations provider.
 type Migrations func(Executor) error
 
 func embeddedMigrations(db Executor) error {
-	files, err := embedded.ReadDir("migrations")
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	return migrate(db, migrations, latest(migrations))
+}
+
+// LoadMigrations loads migrations embedded into the binary, sorted by order.
+func LoadMigrations() ([]Migration, error) {
+	return loadMigrations(embedded, "migrations")
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, dir)
 	if err != nil {
-		return fmt.Errorf("readdir migrations: %w", err)
+		return nil, fmt.Errorf("readdir migrations: %w", err)
 	}
-	var migrations []migration
+	byOrder := map[int]*Migration{}
 	for _, file := range files {
-		parts := strings.Split(file.Name(), "_")
-		if len(parts) < 1 {
-			return fmt.Errorf("invalid migration %s", file.Name())
+		var name string
+		switch {
+		case strings.HasSuffix(file.Name(), upSuffix):
+			name = strings.TrimSuffix(file.Name(), upSuffix)
+		case strings.HasSuffix(file.Name(), downSuffix):
+			name = strings.TrimSuffix(file.Name(), downSuffix)
+		default:
+			return nil, fmt.Errorf("invalid migration %s: expected %s or %s suffix", file.Name(), upSuffix, downSuffix)
 		}
+		parts := strings.SplitN(name, "_", 2)
 		order, err := strconv.Atoi(parts[0])
 		if err != nil {
-			return fmt.Errorf("invalid migration %s: %w", file.Name(), err)
+			return nil, fmt.Errorf("invalid migration %s: %w", file.Name(), err)
 		}
-		fpath := path.Join("migrations", file.Name())
-		content, err := embedded.ReadFile(fpath)
+		fpath := path.Join(dir, file.Name())
+		content, err := fs.ReadFile(fsys, fpath)
 		if err != nil {
-			return fmt.Errorf("readfile %s: %w", fpath, err)
+			return nil, fmt.Errorf("readfile %s: %w", fpath, err)
 		}
-		scanner := bufio.NewScanner(bytes.NewBuffer(content))
-		scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-			if i := bytes.Index(data, []byte(";")); i >= 0 {
-				return i + 1, data[0 : i+1], nil
-			}
-			return 0, nil, nil
-		})
-		migrations = append(migrations, migration{
-			order:   order,
-			name:    file.Name(),
-			content: scanner,
-		})
+		m, exist := byOrder[order]
+		if !exist {
+			m = &Migration{Order: order, Name: name}
+			byOrder[order] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migrations %s and %s share order %d", m.Name, name, order)
+		}
+		if strings.HasSuffix(file.Name(), upSuffix) {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(byOrder))
+	for _, m := range byOrder {
+		if len(m.Up) == 0 {
+			return nil, fmt.Errorf("migration %s doesn't have up script", m.Name)
+		}
+		migrations = append(migrations, *m)
 	}
 	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].order < migrations[j].order
+		return migrations[i].Order < migrations[j].Order
 	})
+	return migrations, nil
+}
 
-	var current int
+func latest(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Order
+}
 
+// Version returns the schema version of the database.
+func Version(db Executor) (int, error) {
+	var current int
 	if _, err := db.Exec("PRAGMA user_version;", nil, func(stmt *Statement) bool {
 		current = stmt.ColumnInt(0)
 		return true
 	}); err != nil {
-		return fmt.Errorf("read user_version %w", err)
+		return 0, fmt.Errorf("read user_version %w", err)
 	}
+	return current, nil
+}
+
+func setVersion(db Executor, version int) error {
+	// binding values in pragma statement is not allowed
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d;", version), nil, nil); err != nil {
+		return fmt.Errorf("update user_version to %d: %w", version, err)
+	}
+	return nil
+}
+
+func ensureMigrationsTable(db Executor) error {
+	if _, err := db.Exec(`create table if not exists schema_migrations
+	(
+		version    INT PRIMARY KEY,
+		name       VARCHAR NOT NULL,
+		checksum   CHAR(32) NOT NULL,
+		applied_at INT NOT NULL
+	) WITHOUT ROWID;`, nil, nil); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func recordMigration(db Executor, m *Migration) error {
+	if _, err := db.Exec(`insert into schema_migrations (version, name, checksum, applied_at)
+		values (?1, ?2, ?3, ?4)
+		on conflict(version) do update set name = ?2, checksum = ?3, applied_at = ?4;`,
+		func(stmt *Statement) {
+			stmt.BindInt64(1, int64(m.Order))
+			stmt.BindText(2, m.Name)
+			stmt.BindBytes(3, m.Checksum())
+			stmt.BindInt64(4, time.Now().Unix())
+		}, nil); err != nil {
+		return fmt.Errorf("record migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+func deleteMigration(db Executor, m *Migration) error {
+	if _, err := db.Exec("delete from schema_migrations where version = ?1;",
+		func(stmt *Statement) {
+			stmt.BindInt64(1, int64(m.Order))
+		}, nil); err != nil {
+		return fmt.Errorf("delete migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+type appliedMigration struct {
+	checksum  []byte
+	appliedAt time.Time
+}
+
+func appliedMigrations(db Executor) (map[int]appliedMigration, error) {
+	rst := map[int]appliedMigration{}
+	if _, err := db.Exec("select version, checksum, applied_at from schema_migrations;", nil,
+		func(stmt *Statement) bool {
+			checksum := make([]byte, stmt.ColumnLen(1))
+			stmt.ColumnBytes(1, checksum)
+			rst[stmt.ColumnInt(0)] = appliedMigration{
+				checksum:  checksum,
+				appliedAt: time.Unix(stmt.ColumnInt64(2), 0),
+			}
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
+	}
+	return rst, nil
+}
 
+// MigrationsStatus returns every embedded migration together with the state
+// recorded in the database. Database is not modified.
+func MigrationsStatus(db Executor) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	current, err := Version(db)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[int]appliedMigration{}
+	exists, err := hasTable(db, "schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+	rst := make([]MigrationStatus, 0, len(migrations))
 	for _, m := range migrations {
-		if m.order <= current {
+		status := MigrationStatus{Migration: m, Applied: m.Order <= current}
+		if recorded, exist := applied[m.Order]; exist {
+			status.Checksum = recorded.checksum
+			status.AppliedAt = recorded.appliedAt
+		}
+		rst = append(rst, status)
+	}
+	return rst, nil
+}
+
+func hasTable(db Executor, name string) (bool, error) {
+	rows, err := db.Exec("select 1 from sqlite_master where type = 'table' and name = ?1;",
+		func(stmt *Statement) {
+			stmt.BindText(1, name)
+		}, nil)
+	if err != nil {
+		return false, fmt.Errorf("lookup table %s: %w", name, err)
+	}
+	return rows > 0, nil
+}
+
+// MigrateTo applies up or down migrations until database schema is at the target version.
+// Migrations must be executed in a transaction, otherwise failed migration may leave database
+// in an inconsistent state.
+func MigrateTo(db Executor, target int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	return migrate(db, migrations, target)
+}
+
+func migrate(db Executor, migrations []Migration, target int) error {
+	if target < 0 || target > latest(migrations) {
+		return fmt.Errorf("target version %d is out of range [0, %d]", target, latest(migrations))
+	}
+	current, err := Version(db)
+	if err != nil {
+		return err
+	}
+	if current > latest(migrations) {
+		return fmt.Errorf("%w: database version %d, supported %d", ErrSchemaTooNew, current, latest(migrations))
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	for i := range migrations {
+		m := &migrations[i]
+		if m.Order > current {
+			break
+		}
+		recorded, exist := applied[m.Order]
+		if !exist {
+			// databases created before checksums were recorded
+			if err := recordMigration(db, m); err != nil {
+				return err
+			}
 			continue
 		}
-		for m.content.Scan() {
-			if _, err := db.Exec(m.content.Text(), nil, nil); err != nil {
-				return fmt.Errorf("exec %s: %w", m.content.Text(), err)
+		if !bytes.Equal(recorded.checksum, m.Checksum()) {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, m.Name)
+		}
+	}
+	if target >= current {
+		for i := range migrations {
+			m := &migrations[i]
+			if m.Order <= current || m.Order > target {
+				continue
+			}
+			if err := execScript(db, m.Up); err != nil {
+				return fmt.Errorf("up %s: %w", m.Name, err)
+			}
+			if err := recordMigration(db, m); err != nil {
+				return err
+			}
+			if err := setVersion(db, m.Order); err != nil {
+				return err
 			}
 		}
-		// binding values in pragma statement is not allowed
-		if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d;", m.order), nil, nil); err != nil {
-			return fmt.Errorf("update user_version to %d: %w", m.order, err)
+		return nil
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := &migrations[i]
+		if m.Order > current || m.Order <= target {
+			continue
+		}
+		if len(m.Down) == 0 {
+			return fmt.Errorf("migration %s doesn't have down script", m.Name)
+		}
+		if err := execScript(db, m.Down); err != nil {
+			return fmt.Errorf("down %s: %w", m.Name, err)
+		}
+		if err := deleteMigration(db, m); err != nil {
+			return err
+		}
+		prev := 0
+		if i > 0 {
+			prev = migrations[i-1].Order
+		}
+		if err := setVersion(db, prev); err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+func execScript(db Executor, script string) error {
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if i := bytes.Index(data, []byte(";")); i >= 0 {
+			return i + 1, data[0 : i+1], nil
+		}
+		return 0, nil, nil
+	})
+	for scanner.Scan() {
+		if _, err := db.Exec(scanner.Text(), nil, nil); err != nil {
+			return fmt.Errorf("exec %s: %w", scanner.Text(), err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,8 @@
+DROP TABLE beacons;
+DROP TABLE transactions;
+DROP TABLE rewards;
+DROP TABLE mesh_status;
+DROP TABLE layers;
+DROP TABLE identities;
+DROP TABLE ballots;
+DROP TABLE blocks;
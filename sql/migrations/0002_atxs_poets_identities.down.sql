@@ -0,0 +1,5 @@
+DROP TABLE applied_transactions;
+DROP TABLE poets;
+DROP TABLE atxs;
+ALTER TABLE layers DROP COLUMN state_hash;
+ALTER TABLE identities DROP COLUMN vrf_pubkey;
@@ -0,0 +1,30 @@
+ALTER TABLE identities ADD COLUMN vrf_pubkey VARCHAR;
+ALTER TABLE layers ADD COLUMN state_hash CHAR(32);
+
+CREATE TABLE atxs
+(
+    id        CHAR(32) PRIMARY KEY,
+    layer     INT NOT NULL,
+    epoch     INT NOT NULL,
+    smesher   VARCHAR,
+    atx       BLOB,
+    timestamp INT NOT NULL
+);
+CREATE INDEX atxs_by_smesher_by_epoch ON atxs (smesher, epoch);
+CREATE INDEX atxs_by_epoch ON atxs (epoch);
+CREATE INDEX atxs_by_layer ON atxs (layer);
+
+CREATE TABLE poets
+(
+    ref        CHAR(32) PRIMARY KEY,
+    poet       BLOB,
+    service_id VARCHAR,
+    round_id   VARCHAR
+);
+CREATE INDEX poets_by_service_id_by_round_id ON poets (service_id, round_id);
+
+CREATE TABLE applied_transactions
+(
+    id    CHAR(32) PRIMARY KEY,
+    layer INT NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1,7 @@
+DROP INDEX transactions_by_fee;
+DROP INDEX transactions_by_amount;
+DROP INDEX transactions_by_status;
+DROP INDEX transactions_by_block;
+DROP INDEX transactions_by_layer;
+ALTER TABLE transactions DROP COLUMN fee;
+ALTER TABLE transactions DROP COLUMN amount;
@@ -0,0 +1,8 @@
+ALTER TABLE transactions ADD COLUMN amount CHAR(8);
+ALTER TABLE transactions ADD COLUMN fee CHAR(8);
+
+CREATE INDEX transactions_by_layer ON transactions (layer, id);
+CREATE INDEX transactions_by_block ON transactions (block, layer, id);
+CREATE INDEX transactions_by_status ON transactions (status, layer, id);
+CREATE INDEX transactions_by_amount ON transactions (amount);
+CREATE INDEX transactions_by_fee ON transactions (fee);
@@ -0,0 +1,4 @@
+DROP TABLE coinbase_rewards;
+DROP TABLE epoch_rewards;
+DROP INDEX rewards_by_layer;
+ALTER TABLE rewards DROP COLUMN fee_reward;
@@ -0,0 +1,20 @@
+ALTER TABLE rewards ADD COLUMN fee_reward UNSIGNED LONG INT;
+UPDATE rewards SET fee_reward = sub_uint64(total_reward, layer_reward);
+
+CREATE INDEX rewards_by_layer ON rewards (layer);
+
+CREATE TABLE epoch_rewards
+(
+    epoch        INT PRIMARY KEY,
+    total_reward UNSIGNED LONG INT,
+    layer_reward UNSIGNED LONG INT,
+    fee_reward   UNSIGNED LONG INT
+) WITHOUT ROWID;
+
+CREATE TABLE coinbase_rewards
+(
+    coinbase     CHAR(20) PRIMARY KEY,
+    total_reward UNSIGNED LONG INT,
+    layer_reward UNSIGNED LONG INT,
+    fee_reward   UNSIGNED LONG INT
+) WITHOUT ROWID;
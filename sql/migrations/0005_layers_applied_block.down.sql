@@ -0,0 +1 @@
+ALTER TABLE layers DROP COLUMN applied_block;
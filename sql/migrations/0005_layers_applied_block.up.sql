@@ -0,0 +1 @@
+ALTER TABLE layers ADD COLUMN applied_block CHAR(20);
@@ -0,0 +1,5 @@
+CREATE TABLE tortoise_checkpoints
+(
+    layer INT PRIMARY KEY,
+    data  BLOB NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1,2 @@
+ALTER TABLE identities DROP COLUMN malicious_layer;
+ALTER TABLE identities DROP COLUMN proof;
@@ -0,0 +1,2 @@
+ALTER TABLE identities ADD COLUMN proof BLOB;
+ALTER TABLE identities ADD COLUMN malicious_layer INT;
@@ -0,0 +1 @@
+DROP TABLE hare_outputs;
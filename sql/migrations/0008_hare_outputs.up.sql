@@ -0,0 +1,6 @@
+CREATE TABLE hare_outputs
+(
+    layer       INT PRIMARY KEY,
+    output      BLOB,
+    certificate BLOB
+) WITHOUT ROWID;
@@ -0,0 +1 @@
+DROP TABLE beacon_records;
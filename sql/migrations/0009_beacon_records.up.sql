@@ -0,0 +1,5 @@
+CREATE TABLE beacon_records
+(
+    epoch  INT PRIMARY KEY,
+    record BLOB
+) WITHOUT ROWID;
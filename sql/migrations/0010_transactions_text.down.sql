@@ -0,0 +1 @@
+DROP TABLE transactions_text;
@@ -0,0 +1,3 @@
+CREATE VIRTUAL TABLE transactions_text USING fts5(origin, destination, content='');
+INSERT INTO transactions_text (rowid, origin, destination)
+    SELECT rowid, lower(hex(origin)), lower(hex(destination)) FROM transactions;
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
@@ -17,7 +18,7 @@ func TestMigrationsAppliedOnce(t *testing.T) {
 		return true
 	})
 	require.NoError(t, err)
-	require.Equal(t, version, 1)
+	require.Equal(t, version, 10)
 
 	require.NoError(t, db.Close())
 
@@ -25,3 +26,116 @@ func TestMigrationsAppliedOnce(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, db)
 }
+
+func migrateTo(tb testing.TB, db *Database, target int) {
+	tb.Helper()
+	tx, err := db.Tx(context.Background())
+	require.NoError(tb, err)
+	defer tx.Release()
+	require.NoError(tb, MigrateTo(tx, target))
+	require.NoError(tb, tx.Commit())
+}
+
+func TestMigrationsDownUp(t *testing.T) {
+	uri := testURI(t)
+	db, err := Open(uri)
+	require.NoError(t, err)
+
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for _, m := range migrations {
+		require.NotEmpty(t, m.Down, m.Name)
+	}
+
+	status, err := MigrationsStatus(db)
+	require.NoError(t, err)
+	require.Len(t, status, len(migrations))
+	for _, s := range status {
+		require.True(t, s.Applied)
+		require.Equal(t, s.Migration.Checksum(), s.Checksum)
+	}
+
+	migrateTo(t, db, 0)
+	version, err := Version(db)
+	require.NoError(t, err)
+	require.Zero(t, version)
+	status, err = MigrationsStatus(db)
+	require.NoError(t, err)
+	for _, s := range status {
+		require.False(t, s.Applied)
+		require.Empty(t, s.Checksum)
+	}
+	exists, err := hasTable(db, "blocks")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	migrateTo(t, db, 1)
+	version, err = Version(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	require.NoError(t, db.Close())
+
+	// remaining migrations are applied on open
+	db, err = Open(uri)
+	require.NoError(t, err)
+	version, err = Version(db)
+	require.NoError(t, err)
+	require.Equal(t, migrations[len(migrations)-1].Order, version)
+	require.NoError(t, db.Close())
+}
+
+func TestMigrationsRefuseNewerSchema(t *testing.T) {
+	uri := testURI(t)
+	db, err := Open(uri)
+	require.NoError(t, err)
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.NoError(t, setVersion(db, latest(migrations)+1))
+	require.NoError(t, db.Close())
+
+	_, err = Open(uri)
+	require.ErrorIs(t, err, ErrSchemaTooNew)
+}
+
+func TestMigrationsChecksumMismatch(t *testing.T) {
+	uri := testURI(t)
+	db, err := Open(uri)
+	require.NoError(t, err)
+	_, err = db.Exec("update schema_migrations set checksum = ?1 where version = 1;",
+		func(stmt *Statement) {
+			stmt.BindBytes(1, []byte{1, 2, 3})
+		}, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = Open(uri)
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestMigrationsRecordedForLegacyDatabase(t *testing.T) {
+	uri := testURI(t)
+	db, err := Open(uri)
+	require.NoError(t, err)
+	_, err = db.Exec("drop table schema_migrations;", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = Open(uri)
+	require.NoError(t, err)
+	status, err := MigrationsStatus(db)
+	require.NoError(t, err)
+	for _, s := range status {
+		require.True(t, s.Applied)
+		require.Equal(t, s.Migration.Checksum(), s.Checksum)
+	}
+	require.NoError(t, db.Close())
+}
+
+func TestMigrationsTargetOutOfRange(t *testing.T) {
+	db := InMemory()
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.Error(t, MigrateTo(db, latest(migrations)+1))
+	require.Error(t, MigrateTo(db, -1))
+}
@@ -0,0 +1,72 @@
+package poets
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Has checks if a PoET exists by the given ref.
+func Has(db sql.Executor, ref []byte) (bool, error) {
+	rows, err := db.Exec("select 1 from poets where ref = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, ref)
+		}, nil,
+	)
+	if err != nil {
+		return false, fmt.Errorf("has poet %x: %w", ref, err)
+	}
+	return rows > 0, nil
+}
+
+// Get gets a PoET for a given ref.
+func Get(db sql.Executor, ref []byte) (poet []byte, err error) {
+	if rows, err := db.Exec("select poet from poets where ref = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, ref)
+		}, func(stmt *sql.Statement) bool {
+			poet = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, poet)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("get poet %x: %w", ref, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("get poet %x: %w", ref, sql.ErrNotFound)
+	}
+	return poet, nil
+}
+
+// Add adds a poet for a given ref. Adding the same poet twice is a no-op.
+func Add(db sql.Executor, ref, poet, serviceID []byte, roundID string) error {
+	if _, err := db.Exec(`insert into poets (ref, poet, service_id, round_id)
+		values (?1, ?2, ?3, ?4)
+		on conflict do nothing;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, ref)
+			stmt.BindBytes(2, poet)
+			stmt.BindBytes(3, serviceID)
+			stmt.BindText(4, roundID)
+		}, nil); err != nil {
+		return fmt.Errorf("insert poet %x: %w", ref, err)
+	}
+	return nil
+}
+
+// GetRef gets a PoET ref for a given service ID and round ID.
+func GetRef(db sql.Executor, serviceID []byte, roundID string) (ref []byte, err error) {
+	if rows, err := db.Exec(`select ref from poets
+		where service_id = ?1 and round_id = ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, serviceID)
+			stmt.BindText(2, roundID)
+		}, func(stmt *sql.Statement) bool {
+			ref = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, ref)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("get ref for service %x round %s: %w", serviceID, roundID, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("get ref for service %x round %s: %w", serviceID, roundID, sql.ErrNotFound)
+	}
+	return ref, nil
+}
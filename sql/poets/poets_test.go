@@ -0,0 +1,45 @@
+package poets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestAddGet(t *testing.T) {
+	db := sql.InMemory()
+
+	ref := []byte{1, 1, 1}
+	poet := []byte{2, 2, 2}
+	service := []byte{3, 3}
+	round := "1"
+
+	has, err := Has(db, ref)
+	require.NoError(t, err)
+	require.False(t, has)
+	_, err = Get(db, ref)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	_, err = GetRef(db, service, round)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, Add(db, ref, poet, service, round))
+	// duplicates are ignored
+	require.NoError(t, Add(db, ref, poet, service, round))
+
+	has, err = Has(db, ref)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	got, err := Get(db, ref)
+	require.NoError(t, err)
+	require.Equal(t, poet, got)
+
+	gotRef, err := GetRef(db, service, round)
+	require.NoError(t, err)
+	require.Equal(t, ref, gotRef)
+
+	_, err = GetRef(db, service, "2")
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
@@ -12,28 +12,201 @@ const (
 	smesherReward
 )
 
-// Add reward to the database.
+// Add reward to the database and update epoch and coinbase totals.
+// Reward is split into the layer reward (subsidy) and the fee reward, that is the rest of the amount.
+//
+// Add should be executed within a transaction, otherwise totals may diverge from rewards.
 func Add(db sql.Executor, lid types.LayerID, reward *types.AnyReward) error {
+	fee := reward.Amount - reward.LayerReward
 	if _, err := db.Exec(`insert into rewards 
-			(smesher, coinbase, layer, total_reward, layer_reward) 
-			values (?1, ?2, ?3, ?4, ?5) 
+			(smesher, coinbase, layer, total_reward, layer_reward, fee_reward) 
+			values (?1, ?2, ?3, ?4, ?5, ?6) 
 		on conflict(smesher,layer) 
 			do update set 
 				total_reward=add_uint64(total_reward, ?4),
-		 		layer_reward=add_uint64(layer_reward, ?5);`,
+		 		layer_reward=add_uint64(layer_reward, ?5),
+		 		fee_reward=add_uint64(fee_reward, ?6);`,
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, reward.SmesherID.ToBytes())
 			stmt.BindBytes(2, reward.Address[:])
 			stmt.BindInt64(3, int64(lid.Uint32()))
 			stmt.BindInt64(4, int64(reward.Amount))
 			stmt.BindInt64(5, int64(reward.LayerReward))
+			stmt.BindInt64(6, int64(fee))
 		}, nil); err != nil {
 		return fmt.Errorf("insert %s %+x: %w", lid, reward, err)
 	}
+	total := Total{Total: reward.Amount, LayerReward: reward.LayerReward, Fee: fee}
+	return updateTotals(db, "add_uint64", lid.GetEpoch(), reward.Address, total)
+}
+
+// Total is an aggregate of rewards.
+type Total struct {
+	Total       uint64
+	LayerReward uint64
+	Fee         uint64
+}
+
+// updateTotals applies op (add_uint64 or sub_uint64) to the epoch and coinbase totals.
+func updateTotals(db sql.Executor, op string, epoch types.EpochID, coinbase types.Address, total Total) error {
+	bind := func(stmt *sql.Statement) {
+		stmt.BindInt64(2, int64(total.Total))
+		stmt.BindInt64(3, int64(total.LayerReward))
+		stmt.BindInt64(4, int64(total.Fee))
+	}
+	for _, table := range []struct {
+		name, key string
+		bind      func(*sql.Statement)
+	}{
+		{
+			name: "epoch_rewards", key: "epoch",
+			bind: func(stmt *sql.Statement) { stmt.BindInt64(1, int64(epoch)) },
+		},
+		{
+			name: "coinbase_rewards", key: "coinbase",
+			bind: func(stmt *sql.Statement) { stmt.BindBytes(1, coinbase[:]) },
+		},
+	} {
+		table := table
+		if _, err := db.Exec(fmt.Sprintf(`insert into %[1]s
+				(%[2]s, total_reward, layer_reward, fee_reward)
+				values (?1, %[3]s(0, ?2), %[3]s(0, ?3), %[3]s(0, ?4))
+			on conflict(%[2]s)
+				do update set
+					total_reward=%[3]s(total_reward, ?2),
+					layer_reward=%[3]s(layer_reward, ?3),
+					fee_reward=%[3]s(fee_reward, ?4);`, table.name, table.key, op),
+			func(stmt *sql.Statement) {
+				table.bind(stmt)
+				bind(stmt)
+			}, nil); err != nil {
+			return fmt.Errorf("update %s for epoch %s and coinbase %s: %w", table.name, epoch, coinbase, err)
+		}
+	}
 	return nil
 }
 
-// order of fields - smesher, coinbase, layer, total_reward, layer_reward .
+// Revert removes rewards from layers after the given layer and subtracts them from totals.
+//
+// Revert should be executed within a transaction.
+func Revert(db sql.Executor, after types.LayerID) error {
+	var (
+		reverted []types.Reward
+		derr     error
+	)
+	if _, err := db.Exec(`select smesher, coinbase, layer, total_reward, layer_reward, fee_reward
+		from rewards where layer > ?1;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(after.Uint32()))
+		}, func(stmt *sql.Statement) bool {
+			var reward types.Reward
+			reward, derr = decodeReward(stmt)
+			if derr != nil {
+				return false
+			}
+			reverted = append(reverted, reward)
+			return true
+		}); err != nil {
+		return fmt.Errorf("select rewards after %s: %w", after, err)
+	}
+	if derr != nil {
+		return derr
+	}
+	for _, reward := range reverted {
+		if err := updateTotals(db, "sub_uint64", reward.Layer.GetEpoch(), reward.Coinbase, totalOf(reward)); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec("delete from rewards where layer > ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(after.Uint32()))
+		}, nil); err != nil {
+		return fmt.Errorf("delete rewards after %s: %w", after, err)
+	}
+	return nil
+}
+
+func totalOf(reward types.Reward) Total {
+	return Total{Total: reward.TotalReward, LayerReward: reward.LayerRewardEstimate, Fee: reward.FeeReward}
+}
+
+func decodeTotal(stmt *sql.Statement) Total {
+	return Total{
+		Total:       uint64(stmt.ColumnInt64(0)),
+		LayerReward: uint64(stmt.ColumnInt64(1)),
+		Fee:         uint64(stmt.ColumnInt64(2)),
+	}
+}
+
+// EpochTotal returns total of rewards for layers in the epoch.
+func EpochTotal(db sql.Executor, epoch types.EpochID) (total Total, err error) {
+	if rows, err := db.Exec("select total_reward, layer_reward, fee_reward from epoch_rewards where epoch = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(epoch))
+		}, func(stmt *sql.Statement) bool {
+			total = decodeTotal(stmt)
+			return true
+		}); err != nil {
+		return Total{}, fmt.Errorf("get total for epoch %s: %w", epoch, err)
+	} else if rows == 0 {
+		return Total{}, fmt.Errorf("%w total for epoch %s", sql.ErrNotFound, epoch)
+	}
+	return total, nil
+}
+
+// CoinbaseTotal returns total of rewards for the coinbase in all layers.
+func CoinbaseTotal(db sql.Executor, coinbase types.Address) (total Total, err error) {
+	if rows, err := db.Exec("select total_reward, layer_reward, fee_reward from coinbase_rewards where coinbase = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, coinbase[:])
+		}, func(stmt *sql.Statement) bool {
+			total = decodeTotal(stmt)
+			return true
+		}); err != nil {
+		return Total{}, fmt.Errorf("get total for coinbase %s: %w", coinbase, err)
+	} else if rows == 0 {
+		return Total{}, fmt.Errorf("%w total for coinbase %s", sql.ErrNotFound, coinbase)
+	}
+	return total, nil
+}
+
+// FillTotals computes epoch and coinbase totals for rewards that were stored before totals
+// were maintained. Totals are not modified if they were computed already.
+// Returns number of rewards that were added to totals.
+func FillTotals(db sql.Executor) (int, error) {
+	if rows, err := db.Exec("select 1 from epoch_rewards limit 1;", nil, nil); err != nil {
+		return 0, fmt.Errorf("check epoch totals: %w", err)
+	} else if rows > 0 {
+		return 0, nil
+	}
+	var (
+		stored []types.Reward
+		derr   error
+	)
+	if _, err := db.Exec("select smesher, coinbase, layer, total_reward, layer_reward, fee_reward from rewards;", nil,
+		func(stmt *sql.Statement) bool {
+			var reward types.Reward
+			reward, derr = decodeReward(stmt)
+			if derr != nil {
+				return false
+			}
+			stored = append(stored, reward)
+			return true
+		}); err != nil {
+		return 0, fmt.Errorf("select rewards: %w", err)
+	}
+	if derr != nil {
+		return 0, derr
+	}
+	for _, reward := range stored {
+		if err := updateTotals(db, "add_uint64", reward.Layer.GetEpoch(), reward.Coinbase, totalOf(reward)); err != nil {
+			return 0, err
+		}
+	}
+	return len(stored), nil
+}
+
+// order of fields - smesher, coinbase, layer, total_reward, layer_reward, fee_reward .
 func decodeReward(stmt *sql.Statement) (types.Reward, error) {
 	key := make([]byte, stmt.ColumnLen(0))
 	stmt.ColumnBytes(0, key)
@@ -45,6 +218,7 @@ func decodeReward(stmt *sql.Statement) (types.Reward, error) {
 		Layer:               types.NewLayerID(uint32(stmt.ColumnInt64(2))),
 		TotalReward:         uint64(stmt.ColumnInt64(3)),
 		LayerRewardEstimate: uint64(stmt.ColumnInt64(4)),
+		FeeReward:           uint64(stmt.ColumnInt64(5)),
 		SmesherID:           *nodeid,
 	}
 	stmt.ColumnBytes(1, reward.Coinbase[:])
@@ -53,7 +227,7 @@ func decodeReward(stmt *sql.Statement) (types.Reward, error) {
 
 // FilterByCoinbase filters rewards from all layers by coinbase address.
 func FilterByCoinbase(db sql.Executor, address types.Address) (rst []types.Reward, err error) {
-	if _, err := db.Exec("select smesher, coinbase, layer, total_reward, layer_reward from rewards where coinbase = ?1;",
+	if _, err := db.Exec("select smesher, coinbase, layer, total_reward, layer_reward, fee_reward from rewards where coinbase = ?1;",
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, address[:])
 		}, func(stmt *sql.Statement) bool {
@@ -73,7 +247,7 @@ func FilterByCoinbase(db sql.Executor, address types.Address) (rst []types.Rewar
 
 // FilterBySmesher filters rewards from all layers by smesher address.
 func FilterBySmesher(db sql.Executor, address []byte) (rst []types.Reward, err error) {
-	if _, err := db.Exec("select smesher, coinbase, layer, total_reward, layer_reward from rewards where smesher = ?1",
+	if _, err := db.Exec("select smesher, coinbase, layer, total_reward, layer_reward, fee_reward from rewards where smesher = ?1",
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, address[:])
 		}, func(stmt *sql.Statement) bool {
@@ -11,6 +11,7 @@ import (
 )
 
 func TestFilter(t *testing.T) {
+	types.SetLayersPerEpoch(4)
 	db := sql.InMemory()
 
 	var part uint64 = math.MaxUint64 / 2
@@ -64,3 +65,110 @@ func TestFilter(t *testing.T) {
 		require.Equal(t, part*2, reward.TotalReward)
 	}
 }
+
+func TestTotals(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	db := sql.InMemory()
+
+	key := [64]byte{1}
+	node, err := types.BytesToNodeID(key[:])
+	require.NoError(t, err)
+	coinbases := []types.Address{{1}, {2}}
+	for i := 0; i < 8; i++ {
+		reward := types.AnyReward{
+			SmesherID:   *node,
+			Address:     coinbases[i%2],
+			Amount:      math.MaxUint64 / 16,
+			LayerReward: math.MaxUint64 / 32,
+		}
+		require.NoError(t, Add(db, types.NewLayerID(uint32(i)), &reward))
+	}
+
+	for epoch := types.EpochID(0); epoch < 2; epoch++ {
+		total, err := EpochTotal(db, epoch)
+		require.NoError(t, err)
+		require.Equal(t, Total{
+			Total:       4 * (math.MaxUint64 / 16),
+			LayerReward: 4 * (math.MaxUint64 / 32),
+			Fee:         4 * (math.MaxUint64/16 - math.MaxUint64/32),
+		}, total)
+	}
+	_, err = EpochTotal(db, 2)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	for _, coinbase := range coinbases {
+		total, err := CoinbaseTotal(db, coinbase)
+		require.NoError(t, err)
+		require.Equal(t, uint64(4*(math.MaxUint64/16)), total.Total)
+	}
+	_, err = CoinbaseTotal(db, types.Address{3})
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	rst, err := FilterBySmesher(db, node.ToBytes())
+	require.NoError(t, err)
+	for _, reward := range rst {
+		require.Equal(t, uint64(math.MaxUint64/16-math.MaxUint64/32), reward.FeeReward)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	db := sql.InMemory()
+
+	key := [64]byte{1}
+	node, err := types.BytesToNodeID(key[:])
+	require.NoError(t, err)
+	coinbase := types.Address{1}
+	for i := 0; i < 8; i++ {
+		reward := types.AnyReward{SmesherID: *node, Address: coinbase, Amount: 3, LayerReward: 2}
+		require.NoError(t, Add(db, types.NewLayerID(uint32(i)), &reward))
+	}
+	require.NoError(t, Revert(db, types.NewLayerID(5)))
+
+	rst, err := FilterByCoinbase(db, coinbase)
+	require.NoError(t, err)
+	require.Len(t, rst, 6)
+
+	total, err := EpochTotal(db, 0)
+	require.NoError(t, err)
+	require.Equal(t, Total{Total: 12, LayerReward: 8, Fee: 4}, total)
+	total, err = EpochTotal(db, 1)
+	require.NoError(t, err)
+	require.Equal(t, Total{Total: 6, LayerReward: 4, Fee: 2}, total)
+	total, err = CoinbaseTotal(db, coinbase)
+	require.NoError(t, err)
+	require.Equal(t, Total{Total: 18, LayerReward: 12, Fee: 6}, total)
+}
+
+func TestFillTotals(t *testing.T) {
+	types.SetLayersPerEpoch(4)
+	db := sql.InMemory()
+
+	key := [64]byte{1}
+	node, err := types.BytesToNodeID(key[:])
+	require.NoError(t, err)
+	coinbase := types.Address{1}
+	for i := 0; i < 8; i++ {
+		reward := types.AnyReward{SmesherID: *node, Address: coinbase, Amount: 3, LayerReward: 2}
+		require.NoError(t, Add(db, types.NewLayerID(uint32(i)), &reward))
+	}
+	n, err := FillTotals(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// totals for rewards that were stored before totals were maintained
+	for _, table := range []string{"epoch_rewards", "coinbase_rewards"} {
+		_, err := db.Exec("delete from "+table, nil, nil)
+		require.NoError(t, err)
+	}
+	n, err = FillTotals(db)
+	require.NoError(t, err)
+	require.Equal(t, 8, n)
+
+	total, err := EpochTotal(db, 1)
+	require.NoError(t, err)
+	require.Equal(t, Total{Total: 12, LayerReward: 8, Fee: 4}, total)
+	total, err = CoinbaseTotal(db, coinbase)
+	require.NoError(t, err)
+	require.Equal(t, Total{Total: 24, LayerReward: 16, Fee: 8}, total)
+}
@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"fmt"
+
+	"crawshaw.io/sqlite"
+)
+
+// statements prepares statements for a single connection.
+//
+// Statements are cached by the connection, keyed by query text, and finalized when it is closed.
+// Connection returns the same statement for the same query, therefore a query that is executed
+// while the same query is still stepped on the connection (e.g. from the decoder) is prepared
+// as a transient statement, so that the outer statement is not reset.
+type statements struct {
+	conn *sqlite.Conn
+	// active is a number of executions in progress for each query.
+	active map[string]int
+}
+
+func newStatements(conn *sqlite.Conn) *statements {
+	return &statements{conn: conn, active: map[string]int{}}
+}
+
+// prepare returns statement for the query. Statement must be released with done.
+func (s *statements) prepare(query string) (*sqlite.Stmt, error) {
+	var (
+		stmt *sqlite.Stmt
+		err  error
+	)
+	if s.active[query] == 0 {
+		stmt, err = s.conn.Prepare(query)
+	} else {
+		var trailing int
+		stmt, trailing, err = s.conn.PrepareTransient(query)
+		if err == nil && trailing != 0 {
+			stmt.Finalize()
+			return nil, fmt.Errorf("statement has %d trailing bytes", trailing)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.active[query]++
+	return stmt, nil
+}
+
+// done releases locks held by the statement. Cached statement is reset and transient
+// statement is finalized.
+func (s *statements) done(query string, stmt *sqlite.Stmt) {
+	s.active[query]--
+	if s.active[query] > 0 {
+		stmt.Finalize()
+		return
+	}
+	delete(s.active, query)
+	stmt.Reset()
+	stmt.ClearBindings()
+}
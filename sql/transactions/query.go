@@ -0,0 +1,201 @@
+package transactions
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Status of the transaction in the database.
+type Status uint8
+
+const (
+	// StatusPending is a transaction that was not yet applied to the state.
+	StatusPending Status = pending
+	// StatusApplied is a transaction that was applied to the state.
+	StatusApplied Status = applied
+	// StatusDeleted is a transaction that was removed from the mesh.
+	StatusDeleted Status = deleted
+)
+
+// ErrInvalidText is returned if Query.Text is not a list of hex terms.
+var ErrInvalidText = errors.New("text must be space separated hex terms")
+
+// Range of values, both bounds are inclusive.
+type Range struct {
+	Min, Max uint64
+}
+
+// Cursor is a position of the transaction in the results ordered by layer and id.
+type Cursor struct {
+	Layer types.LayerID
+	ID    types.TransactionID
+}
+
+// Query selects transactions. Fields that are not set don't restrict the results.
+type Query struct {
+	// From and To are inclusive bounds of the layers range. Zero To is not bounded.
+	From, To types.LayerID
+	// Status matches transactions with any of the statuses.
+	Status []Status
+	Amount *Range
+	Fee    *Range
+	Block  *types.BlockID
+	// Text is a full-text search over origin and recipient addresses. Every space separated hex term,
+	// optionally prefixed with 0x, must be a prefix of either address.
+	Text string
+	// After skips transactions up to and including the cursor.
+	After *Cursor
+	// Limit is a maximum number of transactions returned. Zero is not limited.
+	Limit int
+}
+
+// Find transactions that match the query ordered by layer and id. Pruned transactions are not returned.
+// If results were limited, cursor of the last returned transaction is returned, so that the next
+// page can be queried by setting it as Query.After.
+func Find(db sql.Executor, q Query) ([]*types.MeshTransaction, *Cursor, error) {
+	var (
+		conds    = []string{"tx is not null", "layer >= ?1"}
+		encoders = []func(*sql.Statement, int){
+			func(stmt *sql.Statement, i int) { stmt.BindInt64(i, int64(q.From.Value)) },
+		}
+	)
+	param := func(cond string, encoder func(*sql.Statement, int)) {
+		conds = append(conds, strings.ReplaceAll(cond, "?", fmt.Sprintf("?%d", len(encoders)+1)))
+		encoders = append(encoders, encoder)
+	}
+	if q.To != (types.LayerID{}) {
+		param("layer <= ?", func(stmt *sql.Statement, i int) { stmt.BindInt64(i, int64(q.To.Value)) })
+	}
+	if len(q.Status) > 0 {
+		placeholders := make([]string, 0, len(q.Status))
+		for _, status := range q.Status {
+			status := status
+			placeholders = append(placeholders, fmt.Sprintf("?%d", len(encoders)+1))
+			encoders = append(encoders, func(stmt *sql.Statement, i int) { stmt.BindInt64(i, int64(status)) })
+		}
+		conds = append(conds, fmt.Sprintf("status in (%s)", strings.Join(placeholders, ", ")))
+	}
+	for _, r := range []struct {
+		field string
+		rng   *Range
+	}{{field: "amount", rng: q.Amount}, {field: "fee", rng: q.Fee}} {
+		if r.rng == nil {
+			continue
+		}
+		rng := r.rng
+		param(r.field+" >= ?", func(stmt *sql.Statement, i int) { stmt.BindBytes(i, encodeUint64(rng.Min)) })
+		param(r.field+" <= ?", func(stmt *sql.Statement, i int) { stmt.BindBytes(i, encodeUint64(rng.Max)) })
+	}
+	if q.Block != nil {
+		param("block = ?", func(stmt *sql.Statement, i int) { stmt.BindBytes(i, q.Block.Bytes()) })
+	}
+	if len(q.Text) > 0 {
+		match, err := textMatch(q.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+		param("rowid in (select rowid from transactions_text where transactions_text match ?)",
+			func(stmt *sql.Statement, i int) { stmt.BindText(i, match) })
+	}
+	if q.After != nil {
+		conds = append(conds, fmt.Sprintf("(layer, id) > (?%d, ?%d)", len(encoders)+1, len(encoders)+2))
+		encoders = append(encoders,
+			func(stmt *sql.Statement, i int) { stmt.BindInt64(i, int64(q.After.Layer.Value)) },
+			func(stmt *sql.Statement, i int) { stmt.BindBytes(i, q.After.ID.Bytes()) },
+		)
+	}
+	query := `select tx, layer, block, origin, id from transactions where ` +
+		strings.Join(conds, " and ") + ` order by layer, id`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" limit ?%d", len(encoders)+1)
+		encoders = append(encoders, func(stmt *sql.Statement, i int) { stmt.BindInt64(i, int64(q.Limit)) })
+	}
+	rst, err := filter(db, query, func(stmt *sql.Statement) {
+		for i, encoder := range encoders {
+			encoder(stmt, i+1)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if q.Limit == 0 || len(rst) < q.Limit {
+		return rst, nil, nil
+	}
+	last := rst[len(rst)-1]
+	return rst, &Cursor{Layer: last.LayerID, ID: last.ID()}, nil
+}
+
+// ValidateText checks that text can be used as Query.Text.
+func ValidateText(text string) error {
+	_, err := textMatch(text)
+	return err
+}
+
+// textMatch converts text to fts5 query with a prefix query for every term.
+// Terms are validated as hex, so that they can't be interpreted as fts5 syntax.
+func textMatch(text string) (string, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return "", ErrInvalidText
+	}
+	match := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(term, "0x"), "0X"))
+		if len(term) == 0 {
+			return "", fmt.Errorf("%w: empty term", ErrInvalidText)
+		}
+		// odd length is allowed for prefixes
+		if _, err := hex.DecodeString(term + strings.Repeat("0", len(term)%2)); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidText, term)
+		}
+		match = append(match, fmt.Sprintf("%q*", term))
+	}
+	return strings.Join(match, " "), nil
+}
+
+// FillAmounts sets amount and fee for transactions that were stored before
+// these fields were added. Returns number of updated transactions.
+func FillAmounts(db sql.Executor) (int, error) {
+	var (
+		ids  []types.TransactionID
+		txs  []types.Transaction
+		derr error
+	)
+	if _, err := db.Exec("select id, tx from transactions where amount is null and tx is not null", nil,
+		func(stmt *sql.Statement) bool {
+			var (
+				id types.TransactionID
+				tx types.Transaction
+			)
+			stmt.ColumnBytes(0, id[:])
+			if _, derr = codec.DecodeFrom(stmt.ColumnReader(1), &tx); derr != nil {
+				derr = fmt.Errorf("decode %s: %w", id, derr)
+				return false
+			}
+			ids = append(ids, id)
+			txs = append(txs, tx)
+			return true
+		}); err != nil {
+		return 0, fmt.Errorf("select transactions without amount: %w", err)
+	}
+	if derr != nil {
+		return 0, derr
+	}
+	for i := range ids {
+		if _, err := db.Exec("update transactions set amount = ?2, fee = ?3 where id = ?1",
+			func(stmt *sql.Statement) {
+				stmt.BindBytes(1, ids[i].Bytes())
+				stmt.BindBytes(2, encodeUint64(txs[i].Amount))
+				stmt.BindBytes(3, encodeUint64(txs[i].Fee))
+			}, nil); err != nil {
+			return 0, fmt.Errorf("update amount for %s: %w", ids[i], err)
+		}
+	}
+	return len(ids), nil
+}
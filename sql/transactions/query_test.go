@@ -0,0 +1,230 @@
+package transactions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/svm/transaction"
+)
+
+func TestFind(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer := signing.NewEdSignerFromRand(rng)
+	var (
+		start  = types.NewLayerID(10)
+		blocks = []types.BlockID{{1}, {2}}
+		all    []*types.Transaction
+	)
+	// amounts and fees above max int64 to check that values are compared as uint64
+	amounts := []uint64{1, 100, math.MaxInt64 + 1, math.MaxUint64}
+	for i := 0; i < 20; i++ {
+		tx := mustTx(transaction.GenerateCallTransaction(signer, types.Address{1}, uint64(i),
+			amounts[i%len(amounts)], 1, amounts[(i+1)%len(amounts)]))
+		require.NoError(t, Add(db, start.Add(uint32(i/4)), blocks[i%2], tx))
+		all = append(all, tx)
+	}
+	require.NoError(t, Applied(db, all[0].ID()))
+	require.NoError(t, MarkDeleted(db, all[1].ID()))
+
+	for _, tc := range []struct {
+		desc     string
+		query    Query
+		expected func(int, *types.Transaction) bool
+	}{
+		{
+			desc:     "all",
+			expected: func(int, *types.Transaction) bool { return true },
+		},
+		{
+			desc:     "layers",
+			query:    Query{From: start.Add(1), To: start.Add(2)},
+			expected: func(i int, _ *types.Transaction) bool { return i >= 4 && i < 12 },
+		},
+		{
+			desc:     "status",
+			query:    Query{Status: []Status{StatusApplied, StatusDeleted}},
+			expected: func(i int, _ *types.Transaction) bool { return i < 2 },
+		},
+		{
+			desc:     "amount",
+			query:    Query{Amount: &Range{Min: 100, Max: math.MaxInt64 + 1}},
+			expected: func(_ int, tx *types.Transaction) bool { return tx.Amount >= 100 && tx.Amount <= math.MaxInt64+1 },
+		},
+		{
+			desc:     "fee",
+			query:    Query{Fee: &Range{Min: math.MaxInt64 + 1, Max: math.MaxUint64}},
+			expected: func(_ int, tx *types.Transaction) bool { return tx.Fee > math.MaxInt64 },
+		},
+		{
+			desc:     "block",
+			query:    Query{Block: &blocks[1], From: start.Add(3)},
+			expected: func(i int, _ *types.Transaction) bool { return i%2 == 1 && i >= 12 },
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			var expected []types.TransactionID
+			for i, tx := range all {
+				if tc.expected(i, tx) {
+					expected = append(expected, tx.ID())
+				}
+			}
+			rst, cursor, err := Find(db, tc.query)
+			require.NoError(t, err)
+			require.Nil(t, cursor)
+			require.ElementsMatch(t, expected, ids(rst))
+		})
+	}
+}
+
+func TestFindPagination(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer := signing.NewEdSignerFromRand(rng)
+	start := types.NewLayerID(10)
+	const n = 15
+	for i := 0; i < n; i++ {
+		tx := mustTx(transaction.GenerateCallTransaction(signer, types.Address{1}, uint64(i), 1, 1, 1))
+		require.NoError(t, Add(db, start.Add(uint32(i/4)), types.BlockID{1}, tx))
+	}
+	expected, cursor, err := Find(db, Query{})
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+	require.Len(t, expected, n)
+
+	var (
+		query = Query{Limit: 4}
+		pages [][]*types.MeshTransaction
+	)
+	for {
+		rst, cursor, err := Find(db, query)
+		require.NoError(t, err)
+		pages = append(pages, rst)
+		if cursor == nil {
+			break
+		}
+		require.Equal(t, rst[len(rst)-1].ID(), cursor.ID)
+		query.After = cursor
+	}
+	require.Len(t, pages, 4)
+	var received []*types.MeshTransaction
+	for _, page := range pages {
+		received = append(received, page...)
+	}
+	require.Equal(t, ids(expected), ids(received))
+	for i := 1; i < len(received); i++ {
+		require.False(t, received[i].LayerID.Before(received[i-1].LayerID))
+	}
+}
+
+func TestFindText(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signers := []*signing.EdSigner{signing.NewEdSignerFromRand(rng), signing.NewEdSignerFromRand(rng)}
+	recipients := []types.Address{types.HexToAddress("0xabcd01"), types.HexToAddress("0xabef02")}
+	var all []*types.Transaction
+	for i := 0; i < 8; i++ {
+		tx := mustTx(transaction.GenerateCallTransaction(signers[i%2], recipients[i/4], uint64(i), 1, 1, 1))
+		require.NoError(t, Add(db, types.NewLayerID(10), types.BlockID{1}, tx))
+		// transaction is updated when it is included into a block
+		require.NoError(t, Add(db, types.NewLayerID(11), types.BlockID{2}, tx))
+		all = append(all, tx)
+	}
+	origin := all[1].Origin().Hex()
+
+	for _, tc := range []struct {
+		desc     string
+		text     string
+		expected func(int) bool
+	}{
+		{
+			desc:     "recipient",
+			text:     recipients[1].Hex(),
+			expected: func(i int) bool { return i >= 4 },
+		},
+		{
+			desc:     "recipient prefix",
+			text:     recipients[0].Hex()[:len(recipients[0].Hex())-1],
+			expected: func(i int) bool { return i < 4 },
+		},
+		{
+			desc:     "origin prefix",
+			text:     origin[:10],
+			expected: func(i int) bool { return i%2 == 1 },
+		},
+		{
+			desc:     "origin and recipient",
+			text:     origin + " " + recipients[0].Hex(),
+			expected: func(i int) bool { return i%2 == 1 && i < 4 },
+		},
+		{
+			desc:     "no match",
+			text:     "0xffff",
+			expected: func(int) bool { return false },
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			var expected []types.TransactionID
+			for i, tx := range all {
+				if tc.expected(i) {
+					expected = append(expected, tx.ID())
+				}
+			}
+			rst, _, err := Find(db, Query{Text: tc.text})
+			require.NoError(t, err)
+			require.ElementsMatch(t, expected, ids(rst))
+		})
+	}
+
+	for _, text := range []string{" ", "0x", "abc*", `"ab" OR cd`, "zz"} {
+		_, _, err := Find(db, Query{Text: text})
+		require.ErrorIs(t, err, ErrInvalidText, text)
+		require.ErrorIs(t, ValidateText(text), ErrInvalidText, text)
+	}
+}
+
+func TestFillAmounts(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer := signing.NewEdSignerFromRand(rng)
+	tx := mustTx(transaction.GenerateCallTransaction(signer, types.Address{1}, 1, 191, 1, 17))
+	require.NoError(t, Add(db, types.NewLayerID(10), types.BlockID{1}, tx))
+	_, err := db.Exec("update transactions set amount = null, fee = null", nil, nil)
+	require.NoError(t, err)
+
+	query := Query{Amount: &Range{Min: 191, Max: 191}, Fee: &Range{Min: 17, Max: 17}}
+	rst, _, err := Find(db, query)
+	require.NoError(t, err)
+	require.Empty(t, rst)
+
+	updated, err := FillAmounts(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, updated)
+	rst, _, err = Find(db, query)
+	require.NoError(t, err)
+	require.Equal(t, []types.TransactionID{tx.ID()}, ids(rst))
+
+	updated, err = FillAmounts(db)
+	require.NoError(t, err)
+	require.Zero(t, updated)
+}
+
+func ids(txs []*types.MeshTransaction) []types.TransactionID {
+	rst := make([]types.TransactionID, 0, len(txs))
+	for _, tx := range txs {
+		rst = append(rst, tx.ID())
+	}
+	return rst
+}
@@ -1,6 +1,7 @@
 package transactions
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/spacemeshos/go-spacemesh/codec"
@@ -16,6 +17,14 @@ const (
 	deleted
 )
 
+// encodeUint64 encodes value in big endian, so that sqlite compares blobs in the same order as values.
+// sqlite integers are signed and can't be used for uint64 ranges.
+func encodeUint64(value uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	return buf
+}
+
 // Add pending transaction to the database. If transaction already exists layer and block will be updated.
 func Add(db sql.Executor, lid types.LayerID, bid types.BlockID, tx *types.Transaction) error {
 	buf, err := codec.Encode(tx)
@@ -23,8 +32,8 @@ func Add(db sql.Executor, lid types.LayerID, bid types.BlockID, tx *types.Transa
 		return fmt.Errorf("encode %+v: %w", tx, err)
 	}
 	if _, err := db.Exec(`insert into transactions
-	(id, tx, layer, block, origin, destination, status)
-	values (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	(id, tx, layer, block, origin, destination, status, amount, fee)
+	values (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)
 	on conflict(id) do
 	update set layer = ?3, block=?4, status = ?7`,
 		func(stmt *sql.Statement) {
@@ -35,9 +44,20 @@ func Add(db sql.Executor, lid types.LayerID, bid types.BlockID, tx *types.Transa
 			stmt.BindBytes(5, tx.Origin().Bytes())
 			stmt.BindBytes(6, tx.Recipient.Bytes())
 			stmt.BindInt64(7, pending)
+			stmt.BindBytes(8, encodeUint64(tx.Amount))
+			stmt.BindBytes(9, encodeUint64(tx.Fee))
 		}, nil); err != nil {
 		return fmt.Errorf("insert %s: %w", tx.ID(), err)
 	}
+	// text index is contentless and rows are indexed only once, when transaction is inserted
+	if _, err := db.Exec(`insert into transactions_text (rowid, origin, destination)
+	select rowid, lower(hex(origin)), lower(hex(destination)) from transactions
+	where id = ?1 and not exists (select 1 from transactions_text where rowid = transactions.rowid)`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, tx.ID().Bytes())
+		}, nil); err != nil {
+		return fmt.Errorf("index text %s: %w", tx.ID(), err)
+	}
 	return nil
 }
 
@@ -91,6 +111,10 @@ func Get(db sql.Executor, id types.TransactionID) (tx *types.MeshTransaction, er
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id.Bytes())
 		}, func(stmt *sql.Statement) bool {
+			if stmt.ColumnLen(0) == 0 {
+				err = fmt.Errorf("%w: tx %s", sql.ErrPruned, id)
+				return false
+			}
 			tx, err = decodeTransaction(id, stmt)
 			return true
 		}); err != nil {
@@ -107,6 +131,10 @@ func GetBlob(db sql.Executor, id types.TransactionID) (buf []byte, err error) {
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id.Bytes())
 		}, func(stmt *sql.Statement) bool {
+			if stmt.ColumnLen(0) == 0 {
+				err = fmt.Errorf("%w: tx %s", sql.ErrPruned, id)
+				return false
+			}
 			buf = make([]byte, stmt.ColumnLen(0))
 			stmt.ColumnBytes(0, buf)
 			return true
@@ -115,7 +143,7 @@ func GetBlob(db sql.Executor, id types.TransactionID) (buf []byte, err error) {
 	} else if rows == 0 {
 		return nil, fmt.Errorf("%w: tx %s", sql.ErrNotFound, id)
 	}
-	return buf, nil
+	return buf, err
 }
 
 // Has returns true if transaction is stored in the database.
@@ -137,6 +165,10 @@ func filter(db sql.Executor, query string, encoder func(*sql.Statement)) (rst []
 			tx *types.MeshTransaction
 			id types.TransactionID
 		)
+		// pruned transactions are not returned
+		if stmt.ColumnLen(0) == 0 {
+			return true
+		}
 		stmt.ColumnBytes(4, id[:])
 		tx, err = decodeTransaction(id, stmt)
 		if err != nil {
@@ -194,3 +226,47 @@ func FilterPending(db sql.Executor, address types.Address) ([]*types.MeshTransac
 		stmt.BindInt64(2, pending)
 	})
 }
+
+// Prune removes bodies of applied and deleted transactions before the layer.
+// Pending transactions are kept. Returns number of pruned transactions.
+func Prune(db sql.Executor, before types.LayerID) (int, error) {
+	rows, err := db.Exec(`update transactions set tx = null
+		where layer < ?1 and status != ?2 and tx is not null returning id;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(before.Value))
+			stmt.BindInt64(2, pending)
+		}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("prune transactions before %s: %w", before, err)
+	}
+	return rows, nil
+}
+
+// SetAppliedLayer records the layer in which transaction was applied to the state.
+func SetAppliedLayer(db sql.Executor, id types.TransactionID, lid types.LayerID) error {
+	if _, err := db.Exec(`insert into applied_transactions (id, layer) values (?1, ?2)
+	on conflict(id) do update set layer = ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, id.Bytes())
+			stmt.BindInt64(2, int64(lid.Value))
+		}, nil); err != nil {
+		return fmt.Errorf("set applied layer %s for %s: %w", lid, id, err)
+	}
+	return nil
+}
+
+// GetAppliedLayer returns the layer in which transaction was applied to the state.
+func GetAppliedLayer(db sql.Executor, id types.TransactionID) (lid types.LayerID, err error) {
+	if rows, err := db.Exec("select layer from applied_transactions where id = ?1",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, id.Bytes())
+		}, func(stmt *sql.Statement) bool {
+			lid = types.NewLayerID(uint32(stmt.ColumnInt64(0)))
+			return true
+		}); err != nil {
+		return lid, fmt.Errorf("get applied layer for %s: %w", id, err)
+	} else if rows == 0 {
+		return lid, fmt.Errorf("%w: applied tx %s", sql.ErrNotFound, id)
+	}
+	return lid, nil
+}
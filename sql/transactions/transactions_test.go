@@ -190,3 +190,66 @@ func TestFilterByAddress(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, filtered, 2)
 }
+
+func TestAppliedLayer(t *testing.T) {
+	db := sql.InMemory()
+	id := types.TransactionID{1, 1}
+	lid := types.NewLayerID(10)
+
+	_, err := GetAppliedLayer(db, id)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, SetAppliedLayer(db, id, lid))
+	applied, err := GetAppliedLayer(db, id)
+	require.NoError(t, err)
+	require.Equal(t, lid, applied)
+
+	require.NoError(t, SetAppliedLayer(db, id, lid.Add(1)))
+	applied, err = GetAppliedLayer(db, id)
+	require.NoError(t, err)
+	require.Equal(t, lid.Add(1), applied)
+}
+
+func TestPrune(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer := signing.NewEdSignerFromRand(rng)
+	lid := types.NewLayerID(10)
+	bid := types.BlockID{1, 1}
+	txs := []*types.Transaction{
+		mustTx(transaction.GenerateCallTransaction(signer, types.Address{1}, 1, 191, 1, 1)),
+		mustTx(transaction.GenerateCallTransaction(signer, types.Address{2}, 2, 191, 1, 1)),
+		mustTx(transaction.GenerateCallTransaction(signer, types.Address{3}, 3, 191, 1, 1)),
+	}
+	for _, tx := range txs {
+		require.NoError(t, Add(db, lid, bid, tx))
+	}
+	require.NoError(t, Applied(db, txs[0].ID()))
+	require.NoError(t, MarkDeleted(db, txs[1].ID()))
+
+	pruned, err := Prune(db, lid)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+	pruned, err = Prune(db, lid.Add(1))
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+
+	for _, tx := range txs[:2] {
+		_, err := Get(db, tx.ID())
+		require.ErrorIs(t, err, sql.ErrPruned)
+		_, err = GetBlob(db, tx.ID())
+		require.ErrorIs(t, err, sql.ErrPruned)
+		has, err := Has(db, tx.ID())
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+	// pending transactions are not pruned
+	_, err = Get(db, txs[2].ID())
+	require.NoError(t, err)
+
+	filtered, err := FilterByOrigin(db, lid, lid, txs[2].Origin())
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, txs[2].ID(), filtered[0].ID())
+}
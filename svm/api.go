@@ -12,6 +12,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/mempool"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/svm/state"
 )
 
@@ -25,7 +26,7 @@ type SVM struct {
 }
 
 // New creates a new `SVM` instance from the given `state` and `logger`.
-func New(allStates, processorDb database.Database, projector state.Projector, txPool *mempool.TxMempool, logger log.Log) *SVM {
+func New(allStates database.Database, processorDb sql.Executor, projector state.Projector, txPool *mempool.TxMempool, logger log.Log) *SVM {
 	state := state.NewTransactionProcessor(allStates, processorDb, projector, txPool, logger)
 	return &SVM{state, log.NewDefault("svm")}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/svm/transaction"
 )
 
@@ -27,16 +28,6 @@ func (p *ProjectorMock) GetProjection(addr types.Address, prevNonce, prevBalance
 	return prevNonce + p.nonceDiff, prevBalance - p.balanceDiff, nil
 }
 
-type appliedTxsMock struct{}
-
-func (appliedTxsMock) Put(key []byte, value []byte) error { return nil }
-func (appliedTxsMock) Delete(key []byte) error            { panic("implement me") }
-func (appliedTxsMock) Get(key []byte) ([]byte, error)     { panic("implement me") }
-func (appliedTxsMock) Has(key []byte) (bool, error)       { panic("implement me") }
-func (appliedTxsMock) Close()                             { panic("implement me") }
-func (appliedTxsMock) NewBatch() database.Batch           { panic("implement me") }
-func (appliedTxsMock) Find(key []byte) database.Iterator  { panic("implement me") }
-
 func createTransaction(t *testing.T, nonce uint64, destination types.Address, amount, fee uint64, signer *signing.EdSigner) *types.Transaction {
 	tx, err := transaction.GenerateCallTransaction(signer, destination, nonce, amount, 100, fee)
 	assert.NoError(t, err)
@@ -54,7 +45,7 @@ func TestHandleGossipTransaction_ValidationAccepted(t *testing.T) {
 
 	db := database.NewMemDatabase()
 	lg := logtest.New(t).WithName("svm_logger")
-	svm := New(db, appliedTxsMock{}, &ProjectorMock{}, mempool.NewTxMemPool(), lg)
+	svm := New(db, sql.InMemory(), &ProjectorMock{}, mempool.NewTxMemPool(), lg)
 
 	signer := signing.NewEdSigner()
 	origin := types.GenerateAddress(signer.PublicKey().Bytes())
@@ -74,7 +65,7 @@ func TestHandleGossipTransaction_ValidationIgnored_WrongNonce(t *testing.T) {
 
 	db := database.NewMemDatabase()
 	lg := logtest.New(t).WithName("svm_logger")
-	svm := New(db, appliedTxsMock{}, &ProjectorMock{}, mempool.NewTxMemPool(), lg)
+	svm := New(db, sql.InMemory(), &ProjectorMock{}, mempool.NewTxMemPool(), lg)
 
 	signer := signing.NewEdSigner()
 	origin := types.BytesToAddress(signer.PublicKey().Bytes())
@@ -94,7 +85,7 @@ func TestHandleGossipTransaction_ValidationIgnored_InsufficientBalance(t *testin
 
 	db := database.NewMemDatabase()
 	lg := logtest.New(t).WithName("svm_logger")
-	svm := New(db, appliedTxsMock{}, &ProjectorMock{}, mempool.NewTxMemPool(), lg)
+	svm := New(db, sql.InMemory(), &ProjectorMock{}, mempool.NewTxMemPool(), lg)
 
 	signer := signing.NewEdSigner()
 	origin := types.BytesToAddress(signer.PublicKey().Bytes())
@@ -114,7 +105,7 @@ func TestHandleGossipTransaction_ValidationIgnored_NoTxOrigin(t *testing.T) {
 
 	db := database.NewMemDatabase()
 	lg := logtest.New(t).WithName("svm_logger")
-	svm := New(db, appliedTxsMock{}, &ProjectorMock{}, mempool.NewTxMemPool(), lg)
+	svm := New(db, sql.InMemory(), &ProjectorMock{}, mempool.NewTxMemPool(), lg)
 
 	signer := signing.NewEdSigner()
 	tx := newTx(t, 3, 10, signer)
@@ -10,6 +10,9 @@ import (
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/mempool"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
 	"github.com/spacemeshos/go-spacemesh/trie"
 )
 
@@ -33,7 +36,7 @@ type TransactionProcessor struct {
 	log.Log
 	*DB
 	pool         *mempool.TxMempool
-	processorDb  database.Database
+	processorDb  sql.Executor
 	currentLayer types.LayerID
 	rootHash     types.Hash32
 	stateQueue   list.List
@@ -43,10 +46,8 @@ type TransactionProcessor struct {
 	rootMu       sync.RWMutex
 }
 
-const newRootKey = "root"
-
 // NewTransactionProcessor returns a new state processor.
-func NewTransactionProcessor(allStates, processorDb database.Database, projector Projector, txPool *mempool.TxMempool, logger log.Log) *TransactionProcessor {
+func NewTransactionProcessor(allStates database.Database, processorDb sql.Executor, projector Projector, txPool *mempool.TxMempool, logger log.Log) *TransactionProcessor {
 	stateDb, err := New(types.Hash32{}, NewDatabase(allStates))
 	if err != nil {
 		log.With().Panic("cannot load state db", log.Err(err))
@@ -74,11 +75,10 @@ func (tp *TransactionProcessor) AddressExists(addr types.Address) bool {
 
 // GetLayerApplied gets the layer id at which this tx was applied.
 func (tp *TransactionProcessor) GetLayerApplied(txID types.TransactionID) *types.LayerID {
-	layerIDBytes, err := tp.processorDb.Get(txID.Bytes())
+	layerID, err := transactions.GetAppliedLayer(tp.processorDb, txID)
 	if err != nil {
 		return nil
 	}
-	layerID := types.BytesToLayerID(layerIDBytes)
 	return &layerID
 }
 
@@ -149,12 +149,8 @@ func (tp *TransactionProcessor) addStateToHistory(layer types.LayerID, newHash t
 	return nil
 }
 
-func getStateRootLayerKey(layer types.LayerID) []byte {
-	return append([]byte(newRootKey), layer.Bytes()...)
-}
-
 func (tp *TransactionProcessor) saveStateRoot(stateRoot types.Hash32, layer types.LayerID) error {
-	if err := tp.processorDb.Put(getStateRootLayerKey(layer), stateRoot.Bytes()); err != nil {
+	if err := layers.SetStateHash(tp.processorDb, layer, stateRoot); err != nil {
 		return fmt.Errorf("put into DB: %w", err)
 	}
 	tp.rootMu.Lock()
@@ -165,13 +161,11 @@ func (tp *TransactionProcessor) saveStateRoot(stateRoot types.Hash32, layer type
 
 // GetLayerStateRoot returns the state root at a given layer.
 func (tp *TransactionProcessor) GetLayerStateRoot(layer types.LayerID) (types.Hash32, error) {
-	bts, err := tp.processorDb.Get(getStateRootLayerKey(layer))
+	root, err := layers.GetStateHash(tp.processorDb, layer)
 	if err != nil {
 		return types.Hash32{}, fmt.Errorf("get from DB: %w", err)
 	}
-	var x types.Hash32
-	x.SetBytes(bts)
-	return x, nil
+	return root, nil
 }
 
 // ApplyRewards applies reward reward to miners vector for layer.
@@ -279,7 +273,7 @@ func (tp *TransactionProcessor) ApplyTransaction(tx *types.Transaction, layerID
 
 	// subtract fee from account, fee will be sent to miners in layers after
 	tp.SubBalance(tx.Origin(), tx.GetFee())
-	if err := tp.processorDb.Put(tx.ID().Bytes(), layerID.Bytes()); err != nil {
+	if err := transactions.SetAppliedLayer(tp.processorDb, tx.ID(), layerID); err != nil {
 		return fmt.Errorf("failed to add to applied txs: %v", err)
 	}
 	tp.With().Info("transaction processed", log.String("transaction", tx.String()))
@@ -21,6 +21,8 @@ import (
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	mmocks "github.com/spacemeshos/go-spacemesh/mesh/mocks"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/syncer/mocks"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
@@ -113,6 +115,12 @@ func (mv *mockValidator) OnBlock(*types.Block) {}
 
 func (mv *mockValidator) OnBallot(*types.Ballot) {}
 
+func (mv *mockValidator) OnMalfeasance(*signing.PublicKey, types.LayerID) {}
+
+func (mv *mockValidator) WindowStart() types.LayerID {
+	return types.GetEffectiveGenesis()
+}
+
 func (mv *mockValidator) HandleIncomingLayer(_ context.Context, layerID types.LayerID) (types.LayerID, types.LayerID, bool) {
 	return layerID, layerID.Sub(1), false
 }
@@ -129,10 +137,10 @@ func feedLayerResult(from, to types.LayerID, mf *mockFetcher, msh *mesh.Mesh) {
 
 func newMemMesh(t *testing.T, lg log.Log) *mesh.Mesh {
 	memdb := mesh.NewMemMeshDB(lg.WithName("meshDB"))
-	atxStore := database.NewMemDatabase()
+	atxStore := sql.InMemory()
 	goldenATXID := types.ATXID(types.HexToHash32("77777"))
 	atxdb := activation.NewDB(atxStore, nil,
-		activation.NewIdentityStore(database.NewMemDatabase()),
+		activation.NewIdentityStore(sql.InMemory()),
 		layersPerEpoch, goldenATXID, nil, lg.WithName("atxDB"))
 	svmstate := mmocks.NewMockstate(gomock.NewController(t))
 	svmstate.EXPECT().GetStateRoot().AnyTimes()
@@ -1138,10 +1146,10 @@ func TestSyncMissingLayer(t *testing.T) {
 	failed := last.Sub(1)
 
 	memdb := mesh.NewMemMeshDB(lg.WithName("meshDB"))
-	atxStore := database.NewMemDatabase()
+	atxStore := sql.InMemory()
 	goldenATXID := types.ATXID(types.HexToHash32("77777"))
 	atxdb := activation.NewDB(atxStore, nil,
-		activation.NewIdentityStore(database.NewMemDatabase()),
+		activation.NewIdentityStore(sql.InMemory()),
 		layersPerEpoch, goldenATXID, nil, lg.WithName("atxDB"))
 	svmstate := mmocks.NewMockstate(gomock.NewController(t))
 	svmstate.EXPECT().GetStateRoot().AnyTimes()
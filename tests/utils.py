@@ -209,7 +209,7 @@ def validate_beacon_weak_coins(log_messages):
 
 
 def get_pod_id(ns, pod_name):
-    hits = q.query_protocol_started(ns, pod_name, "HARE_PROTOCOL")
+    hits = q.query_protocol_started(ns, pod_name, "HARE_PROTOCOL/2")
     if not hits:
         return None
 
@@ -126,6 +126,7 @@ func New(h host.Host, peers bootstrap.Waiter, opts ...Option) *Sync {
 // Sync manages background worker that compares peers time with system time.
 type Sync struct {
 	errCnt uint32
+	offset int64
 
 	config       Config
 	log          log.Log
@@ -219,6 +220,7 @@ func (s *Sync) run() error {
 				)
 				atomic.StoreUint32(&s.errCnt, 0)
 			}
+			atomic.StoreInt64(&s.offset, int64(offset))
 			timeout = s.config.RoundInterval
 		} else {
 			s.log.With().Error("failed to fetch offset from peers", log.Err(err))
@@ -239,6 +241,11 @@ func (s *Sync) run() error {
 	}
 }
 
+// Offset returns the clock offset from peers measured in the last successful round.
+func (s *Sync) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
 // GetOffset computes offset from received response. The method is stateless and safe to use concurrently.
 func (s *Sync) GetOffset(ctx context.Context, id uint64, prs []p2p.Peer) (time.Duration, error) {
 	var (
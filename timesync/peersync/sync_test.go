@@ -173,3 +173,43 @@ func TestSyncSimulateMultiple(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncOffset(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxClockOffset = time.Minute
+	config.RoundInterval = time.Hour
+
+	var (
+		start           = time.Time{}
+		roundStartTime  = start.Add(10 * time.Second)
+		peerResponse    = start.Add(30 * time.Second)
+		responseReceive = start.Add(30 * time.Second)
+	)
+
+	mesh, err := mocknet.FullMeshConnected(context.TODO(), 4)
+	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	waiter := bootmocks.NewMockWaiter(ctrl)
+	tm := mocks.NewMockTime(ctrl)
+
+	sync := New(mesh.Hosts()[0], waiter,
+		WithTime(tm),
+		WithConfig(config),
+	)
+	tm.EXPECT().Now().Return(roundStartTime)
+	tm.EXPECT().Now().Return(responseReceive).AnyTimes()
+
+	peers := []p2p.Peer{}
+	for _, h := range mesh.Hosts()[1:] {
+		peers = append(peers, h.ID())
+		_ = New(h, nil, WithTime(adjustedTime(peerResponse)))
+	}
+	waiter.EXPECT().WaitPeers(gomock.Any(), gomock.Any()).Return(peers, nil)
+
+	require.Zero(t, sync.Offset())
+	sync.Start()
+	t.Cleanup(sync.Stop)
+	require.Eventually(t, func() bool {
+		return sync.Offset() == 10*time.Second
+	}, time.Second, 10*time.Millisecond)
+}
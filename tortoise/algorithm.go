@@ -2,6 +2,7 @@ package tortoise
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"sync"
 	"time"
@@ -10,6 +11,9 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
 	"github.com/spacemeshos/go-spacemesh/system"
 	"github.com/spacemeshos/go-spacemesh/tortoise/organizer"
 )
@@ -26,6 +30,8 @@ type Config struct {
 	MaxExceptions                   int           `mapstructure:"tortoise-max-exceptions"` // if candidate for base block has more than max exceptions it will be ignored
 	VerifyingModeVerificationWindow uint32        `mapstructure:"verifying-mode-verification-window"`
 	FullModeVerificationWindow      uint32        `mapstructure:"full-mode-verification-window"`
+	CheckpointInterval              uint32        `mapstructure:"tortoise-checkpoint-interval"` // number of processed layers between checkpoints. zero disables checkpoints
+	VoteCounter                     string        `mapstructure:"tortoise-vote-counter"`        // backend for counting votes by full tortoise: recount or incremental
 
 	LayerSize                uint32
 	BadBeaconVoteDelayLayers uint32 // number of layers to delay votes for blocks with bad beacon values during self-healing
@@ -47,6 +53,8 @@ func DefaultConfig() Config {
 		MaxExceptions:                   30 * 100, // 100 layers of average size
 		VerifyingModeVerificationWindow: 1000,
 		FullModeVerificationWindow:      20,
+		CheckpointInterval:              50,
+		VoteCounter:                     RecountVotes,
 	}
 }
 
@@ -65,8 +73,15 @@ type Tortoise struct {
 		err error
 	}
 
+	// db stores checkpoints of the tortoise state. checkpoints are disabled if db is nil.
+	db sql.Executor
+
 	mu  sync.Mutex
 	org *organizer.Organizer
+	// checkpointed is the last processed layer when checkpoint was saved.
+	checkpointed types.LayerID
+	// checkpointedWindow is the first layer in the window of the last checkpoint.
+	checkpointedWindow types.LayerID
 
 	// update will be set to non-nil after rerun completes, and must be set to nil once
 	// used to replace trtl.
@@ -98,6 +113,13 @@ func WithConfig(cfg Config) Opt {
 	}
 }
 
+// WithDatabase defines database for checkpoints of the tortoise state.
+func WithDatabase(db sql.Executor) Opt {
+	return func(t *Tortoise) {
+		t.db = db
+	}
+}
+
 // New creates Tortoise instance.
 func New(mdb blockDataProvider, atxdb atxDataProvider, beacons system.BeaconGetter, opts ...Opt) *Tortoise {
 	t := &Tortoise{
@@ -116,6 +138,11 @@ func New(mdb blockDataProvider, atxdb atxDataProvider, beacons system.BeaconGett
 			log.Uint32("zdist", t.cfg.Zdist),
 		)
 	}
+	switch t.cfg.VoteCounter {
+	case "", RecountVotes, IncrementalVotes:
+	default:
+		t.logger.With().Panic("unknown vote counter", log.String("vote_counter", t.cfg.VoteCounter))
+	}
 
 	ctx, cancel := context.WithCancel(t.ctx)
 	t.cancel = cancel
@@ -130,7 +157,17 @@ func New(mdb blockDataProvider, atxdb atxDataProvider, beacons system.BeaconGett
 		t.cfg,
 	)
 	t.trtl.init(t.ctx, types.GenesisLayer())
-	if needsRecovery {
+	if needsRecovery && t.restoreCheckpoint() {
+		t.logger.Info("loaded checkpoint from disk. make sure to wait until tortoise is ready",
+			log.Stringer("checkpoint_layer", t.checkpointed),
+			log.Stringer("last_layer", t.cfg.MeshProcessed),
+		)
+		t.eg.Go(func() error {
+			t.ready <- t.catchup(ctx)
+			close(t.ready)
+			return nil
+		})
+	} else if needsRecovery {
 		t.trtl.processed = t.cfg.MeshProcessed
 		// TODO(dshulyak) last should be set according to the clock.
 		t.trtl.last = t.cfg.MeshProcessed
@@ -148,12 +185,16 @@ func New(mdb blockDataProvider, atxdb atxDataProvider, beacons system.BeaconGett
 		})
 	} else {
 		t.logger.Info("no state on disk. initialized with genesis")
+		if err := t.restoreMalfeasance(t.trtl); err != nil {
+			t.logger.With().Error("failed to restore malicious smeshers", log.Err(err))
+			t.ready <- err
+		}
 		close(t.ready)
 	}
 
 	t.org = organizer.New(
 		organizer.WithLogger(t.logger),
-		organizer.WithLastLayer(t.trtl.processed),
+		organizer.WithLastLayer(maxLayer(t.trtl.processed, t.cfg.MeshProcessed)),
 	)
 
 	// TODO(dshulyak) with low rerun interval it is possible to start a rerun
@@ -175,6 +216,19 @@ func (t *Tortoise) LatestComplete() types.LayerID {
 	return t.trtl.verified
 }
 
+// WindowStart returns the first layer that tortoise may read from the database. Reruns start
+// from the last checkpoint if layers after genesis are pruned, therefore it is the start of the window
+// in the last checkpoint or of the current window, whichever is older. Without a checkpoint reruns
+// start from genesis and all layers are needed.
+func (t *Tortoise) WindowStart() types.LayerID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.checkpointed == (types.LayerID{}) {
+		return types.GetEffectiveGenesis()
+	}
+	return minLayer(t.checkpointedWindow, t.trtl.evicted.Add(1))
+}
+
 // BaseBallot chooses a base ballot and creates a differences list. needs the hare results for latest layers.
 func (t *Tortoise) BaseBallot(ctx context.Context) (*types.Votes, error) {
 	t.mu.Lock()
@@ -206,9 +260,101 @@ func (t *Tortoise) HandleIncomingLayer(ctx context.Context, layerID types.LayerI
 		}
 	})
 
+	t.saveCheckpoint(logger)
 	return old, t.trtl.verified, reverted
 }
 
+// restoreCheckpoint loads the last checkpoint that doesn't exceed processed layer in the mesh.
+func (t *Tortoise) restoreCheckpoint() bool {
+	if t.db == nil {
+		return false
+	}
+	trtl := t.trtl.cloneTurtleParams()
+	restored, err := loadCheckpoint(t.db, trtl, t.cfg.MeshProcessed)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNotFound) {
+			t.logger.With().Error("failed to load checkpoint. state will be loaded from scratch", log.Err(err))
+		}
+		return false
+	}
+	if !restored {
+		return false
+	}
+	t.trtl = trtl
+	t.checkpointed = trtl.processed
+	t.checkpointedWindow = trtl.evicted.Add(1)
+	return true
+}
+
+// catchup processes layers after the checkpoint up to the last processed layer in the mesh.
+func (t *Tortoise) catchup(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.restoreMalfeasance(t.trtl); err != nil {
+		t.logger.With().Error("failed to restore malicious smeshers", log.Err(err))
+		return err
+	}
+	for lid := t.trtl.processed.Add(1); !lid.After(t.cfg.MeshProcessed); lid = lid.Add(1) {
+		if err := ctx.Err(); err != nil {
+			return err //nolint
+		}
+		if err := t.trtl.HandleIncomingLayer(ctx, lid); err != nil {
+			t.logger.With().Error("tortoise catchup failed", lid, log.Err(err))
+			return err
+		}
+	}
+	t.logger.With().Info("tortoise caught up from the checkpoint",
+		log.Stringer("checkpoint_layer", t.checkpointed),
+		log.Stringer("last_layer", t.trtl.last),
+		log.Stringer("verified_layer", t.trtl.verified),
+	)
+	return nil
+}
+
+// restoreMalfeasance removes weight of the smeshers that were proven malicious from the turtle.
+// Malicious smeshers are not persisted in the checkpoint, they are loaded from the database
+// every time turtle is initialized: on start and before reruns.
+func (t *Tortoise) restoreMalfeasance(trtl *turtle) error {
+	if t.db == nil {
+		return nil
+	}
+	proven := map[string]types.LayerID{}
+	if err := identities.IterateProven(t.db, func(pubkey []byte, lid types.LayerID) bool {
+		proven[string(pubkey)] = lid
+		return true
+	}); err != nil {
+		return err
+	}
+	for pubkey, lid := range proven {
+		if err := trtl.onMalfeasance(signing.NewPublicKey([]byte(pubkey)), lid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveCheckpoint must be called while holding mutex.
+func (t *Tortoise) saveCheckpoint(logger log.FieldLogger) {
+	if t.db == nil || t.cfg.CheckpointInterval == 0 {
+		return
+	}
+	if t.trtl.processed.Before(t.checkpointed.Add(t.cfg.CheckpointInterval)) {
+		return
+	}
+	data, err := t.trtl.checkpoint()
+	if err != nil {
+		logger.Error("failed to create checkpoint", log.Err(err))
+		return
+	}
+	if err := saveCheckpoint(t.db, t.trtl.processed, data); err != nil {
+		logger.Error("failed to save checkpoint", log.Err(err))
+		return
+	}
+	t.checkpointed = t.trtl.processed
+	t.checkpointedWindow = t.trtl.evicted.Add(1)
+	logger.Debug("saved checkpoint", log.Stringer("checkpoint_layer", t.checkpointed), log.Int("size", len(data)))
+}
+
 // OnBlock should be called every time new block is received.
 func (t *Tortoise) OnBlock(block *types.Block) {
 	t.mu.Lock()
@@ -226,6 +372,17 @@ func (t *Tortoise) OnBallot(ballot *types.Ballot) {
 	}
 }
 
+// OnMalfeasance should be called when the smesher is proven to be malicious starting from the layer.
+// Ballots from the smesher in that layer and all later layers don't have weight.
+func (t *Tortoise) OnMalfeasance(smesher *signing.PublicKey, lid types.LayerID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.trtl.onMalfeasance(smesher, lid); err != nil {
+		t.logger.With().Error("failed to remove weight of the malicious smesher",
+			log.Stringer("smesher", smesher), lid, log.Err(err))
+	}
+}
+
 // WaitReady waits until state will be reloaded from disk.
 func (t *Tortoise) WaitReady(ctx context.Context) error {
 	select {
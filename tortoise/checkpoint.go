@@ -0,0 +1,394 @@
+package tortoise
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/checkpoints"
+)
+
+// checkpoint is a serialized state of the tortoise within the sliding window.
+//
+// caches that can be recovered from the database (e.g. beacons of the reference ballots)
+// are not stored.
+type checkpoint struct {
+	Last, Verified, HistoricallyVerified types.LayerID
+	Processed, Evicted, Counted          types.LayerID
+	Full                                 bool
+
+	// weights are encoded as big.Rat strings. empty string is a nil weight.
+	LocalThreshold, GlobalThreshold string
+	TotalGoodWeight                 string
+
+	Epochs  []checkpointEpoch
+	Layers  []checkpointLayer
+	Delayed []checkpointDelayed
+}
+
+type checkpointEpoch struct {
+	ID     types.EpochID
+	Weight string
+}
+
+type checkpointLayer struct {
+	ID              types.LayerID
+	Undecided       bool
+	GoodWeight      string
+	AbstainedWeight string
+	// Decided is true if the layer was verified. other Decision fields are set only for decided layers.
+	Decided                                         bool
+	DecisionFull, DecisionRerun                     bool
+	DecisionLocalThreshold, DecisionGlobalThreshold string
+	Blocks                                          []checkpointBlock
+	Ballots                                         []checkpointBallot
+}
+
+type checkpointBlock struct {
+	ID types.BlockID
+	// HareOutput and Validity are encoded with encodeVote.
+	HareOutput, Validity uint8
+	Weight               string
+}
+
+type checkpointBallot struct {
+	ID              types.BallotID
+	Weight          string
+	ReferenceWeight string
+	BadBeacon       bool
+	Goodness        uint8
+	// Full is false for ballots which votes are not tracked by full tortoise (e.g. genesis ballot).
+	Full    bool
+	Base    types.BallotID
+	Votes   []checkpointVote
+	Abstain []types.LayerID
+}
+
+type checkpointVote struct {
+	Block types.BlockID
+	Sign  int8
+}
+
+type checkpointDelayed struct {
+	Layer   types.LayerID
+	Ballots []types.BallotID
+}
+
+func encodeWeight(w weight) string {
+	if w.isNil() {
+		return ""
+	}
+	return w.Rat.String()
+}
+
+func decodeWeight(s string) (weight, error) {
+	if len(s) == 0 {
+		return weight{}, nil
+	}
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return weight{}, fmt.Errorf("invalid weight %q", s)
+	}
+	return weight{Rat: rat}, nil
+}
+
+// encodeVote encodes optional vote. zero means that vote doesn't exist.
+func encodeVote(vote sign, exist bool) uint8 {
+	if !exist {
+		return 0
+	}
+	return uint8(vote + 2)
+}
+
+func decodeVote(encoded uint8) (sign, bool) {
+	if encoded == 0 {
+		return 0, false
+	}
+	return sign(encoded) - 2, true
+}
+
+// checkpoint encodes state that is required to continue processing layers after the last processed layer.
+func (t *turtle) checkpoint() ([]byte, error) {
+	cp := checkpoint{
+		Last:                 t.last,
+		Verified:             t.verified,
+		HistoricallyVerified: t.historicallyVerified,
+		Processed:            t.processed,
+		Evicted:              t.evicted,
+		Counted:              t.full.counted,
+		Full:                 t.mode.isFull(),
+		LocalThreshold:       encodeWeight(t.localThreshold),
+		GlobalThreshold:      encodeWeight(t.globalThreshold),
+		TotalGoodWeight:      encodeWeight(t.verifying.totalGoodWeight),
+	}
+	for epoch, w := range t.epochWeight {
+		cp.Epochs = append(cp.Epochs, checkpointEpoch{ID: epoch, Weight: encodeWeight(w)})
+	}
+	sort.Slice(cp.Epochs, func(i, j int) bool {
+		return cp.Epochs[i].ID < cp.Epochs[j].ID
+	})
+
+	lids := map[types.LayerID]struct{}{}
+	for lid := range t.blocks {
+		lids[lid] = struct{}{}
+	}
+	for lid := range t.ballots {
+		lids[lid] = struct{}{}
+	}
+	for lid := range t.undecided {
+		lids[lid] = struct{}{}
+	}
+	for lid := range t.verifying.goodWeight {
+		lids[lid] = struct{}{}
+	}
+	for lid := range t.verifying.abstainedWeight {
+		lids[lid] = struct{}{}
+	}
+	for lid := range t.decisions {
+		lids[lid] = struct{}{}
+	}
+	for lid := range lids {
+		_, undecided := t.undecided[lid]
+		layer := checkpointLayer{
+			ID:              lid,
+			Undecided:       undecided,
+			GoodWeight:      encodeWeight(t.verifying.goodWeight[lid]),
+			AbstainedWeight: encodeWeight(t.verifying.abstainedWeight[lid]),
+		}
+		if decision, exist := t.decisions[lid]; exist {
+			layer.Decided = true
+			layer.DecisionFull = decision.mode.isFull()
+			layer.DecisionRerun = decision.mode.isRerun()
+			layer.DecisionLocalThreshold = encodeWeight(decision.localThreshold)
+			layer.DecisionGlobalThreshold = encodeWeight(decision.globalThreshold)
+		}
+		for _, block := range t.blocks[lid] {
+			hare, hareExist := t.hareOutput[block]
+			validity, validityExist := t.validity[block]
+			layer.Blocks = append(layer.Blocks, checkpointBlock{
+				ID:         block,
+				HareOutput: encodeVote(hare, hareExist),
+				Validity:   encodeVote(validity, validityExist),
+				Weight:     encodeWeight(t.full.weight(t.logger, lid, block)),
+			})
+		}
+		for _, ballot := range t.ballots[lid] {
+			_, bad := t.badBeaconBallots[ballot]
+			base, full := t.full.base[ballot]
+			encoded := checkpointBallot{
+				ID:              ballot,
+				Weight:          encodeWeight(t.ballotWeight[ballot]),
+				ReferenceWeight: encodeWeight(t.referenceWeight[ballot]),
+				BadBeacon:       bad,
+				Goodness:        uint8(t.verifying.goodBallots[ballot]),
+				Full:            full,
+				Base:            base,
+			}
+			for block, vote := range t.full.votes[ballot] {
+				encoded.Votes = append(encoded.Votes, checkpointVote{Block: block, Sign: int8(vote)})
+			}
+			sort.Slice(encoded.Votes, func(i, j int) bool {
+				return encoded.Votes[i].Block.Compare(encoded.Votes[j].Block)
+			})
+			for abstained := range t.full.abstain[ballot] {
+				encoded.Abstain = append(encoded.Abstain, abstained)
+			}
+			sort.Slice(encoded.Abstain, func(i, j int) bool {
+				return encoded.Abstain[i].Before(encoded.Abstain[j])
+			})
+			layer.Ballots = append(layer.Ballots, encoded)
+		}
+		cp.Layers = append(cp.Layers, layer)
+	}
+	sort.Slice(cp.Layers, func(i, j int) bool {
+		return cp.Layers[i].ID.Before(cp.Layers[j].ID)
+	})
+	for front := t.full.delayedQueue.Front(); front != nil; front = front.Next() {
+		delayed := front.Value.(delayedBallots)
+		cp.Delayed = append(cp.Delayed, checkpointDelayed{Layer: delayed.lid, Ballots: delayed.ballots})
+	}
+	buf, err := codec.Encode(&cp)
+	if err != nil {
+		return nil, fmt.Errorf("encode checkpoint: %w", err)
+	}
+	return buf, nil
+}
+
+// restore replaces state of the fresh turtle with the state from the checkpoint.
+func (t *turtle) restore(data []byte) error {
+	var cp checkpoint
+	if err := codec.Decode(data, &cp); err != nil {
+		return fmt.Errorf("decode checkpoint: %w", err)
+	}
+	t.commonState = newCommonState()
+	t.verifying = newVerifying(t.Config, &t.commonState)
+	t.full = newFullTortoise(t.Config, &t.commonState)
+
+	t.last = cp.Last
+	t.verified = cp.Verified
+	t.historicallyVerified = cp.HistoricallyVerified
+	t.processed = cp.Processed
+	t.evicted = cp.Evicted
+	t.full.counted = cp.Counted
+	t.mode = mode{}
+	if cp.Full {
+		t.mode = t.mode.toggleMode()
+	}
+
+	decoded := make([]weight, 3)
+	for i, encoded := range []string{cp.LocalThreshold, cp.GlobalThreshold, cp.TotalGoodWeight} {
+		w, err := decodeWeight(encoded)
+		if err != nil {
+			return err
+		}
+		decoded[i] = w
+	}
+	t.localThreshold, t.globalThreshold = decoded[0], decoded[1]
+	if !decoded[2].isNil() {
+		t.verifying.totalGoodWeight = decoded[2]
+	}
+
+	for _, epoch := range cp.Epochs {
+		w, err := decodeWeight(epoch.Weight)
+		if err != nil {
+			return err
+		}
+		t.epochWeight[epoch.ID] = w
+	}
+	for i := range cp.Layers {
+		layer := &cp.Layers[i]
+		if err := t.restoreLayer(layer); err != nil {
+			return fmt.Errorf("layer %s: %w", layer.ID, err)
+		}
+	}
+	for _, delayed := range cp.Delayed {
+		t.full.delayedQueue.PushBack(delayedBallots{lid: delayed.Layer, ballots: delayed.Ballots})
+	}
+	t.full.counter.restored(t.logger)
+	return nil
+}
+
+func (t *turtle) restoreLayer(layer *checkpointLayer) error {
+	lid := layer.ID
+	if layer.Undecided {
+		t.undecided[lid] = struct{}{}
+	}
+	for _, item := range []struct {
+		encoded string
+		weights map[types.LayerID]weight
+	}{
+		{encoded: layer.GoodWeight, weights: t.verifying.goodWeight},
+		{encoded: layer.AbstainedWeight, weights: t.verifying.abstainedWeight},
+	} {
+		w, err := decodeWeight(item.encoded)
+		if err != nil {
+			return err
+		}
+		if !w.isNil() {
+			item.weights[lid] = w
+		}
+	}
+	if layer.Decided {
+		var decided decision
+		if layer.DecisionFull {
+			decided.mode = decided.mode.toggleMode()
+		}
+		if layer.DecisionRerun {
+			decided.mode = decided.mode.toggleRerun()
+		}
+		var err error
+		if decided.localThreshold, err = decodeWeight(layer.DecisionLocalThreshold); err != nil {
+			return err
+		}
+		if decided.globalThreshold, err = decodeWeight(layer.DecisionGlobalThreshold); err != nil {
+			return err
+		}
+		t.decisions[lid] = decided
+	}
+	for _, block := range layer.Blocks {
+		t.blockLayer[block.ID] = lid
+		t.blocks[lid] = append(t.blocks[lid], block.ID)
+		if vote, exist := decodeVote(block.HareOutput); exist {
+			t.hareOutput[block.ID] = vote
+		}
+		if vote, exist := decodeVote(block.Validity); exist {
+			t.validity[block.ID] = vote
+		}
+		w, err := decodeWeight(block.Weight)
+		if err != nil {
+			return err
+		}
+		if !w.isNil() {
+			t.full.counter.restoreWeight(block.ID, w)
+		}
+	}
+	for _, ballot := range layer.Ballots {
+		t.ballotLayer[ballot.ID] = lid
+		t.ballots[lid] = append(t.ballots[lid], ballot.ID)
+		for _, item := range []struct {
+			encoded string
+			weights map[types.BallotID]weight
+		}{
+			{encoded: ballot.Weight, weights: t.ballotWeight},
+			{encoded: ballot.ReferenceWeight, weights: t.referenceWeight},
+		} {
+			w, err := decodeWeight(item.encoded)
+			if err != nil {
+				return err
+			}
+			if !w.isNil() {
+				item.weights[ballot.ID] = w
+			}
+		}
+		if ballot.BadBeacon {
+			t.badBeaconBallots[ballot.ID] = struct{}{}
+		}
+		if goodness(ballot.Goodness) != bad {
+			t.verifying.goodBallots[ballot.ID] = goodness(ballot.Goodness)
+		}
+		if !ballot.Full {
+			continue
+		}
+		votes := votes{}
+		for _, vote := range ballot.Votes {
+			votes[vote.Block] = sign(vote.Sign)
+		}
+		abstain := map[types.LayerID]struct{}{}
+		for _, abstained := range ballot.Abstain {
+			abstain[abstained] = struct{}{}
+		}
+		t.full.onBallot(&tortoiseBallot{
+			id:      ballot.ID,
+			base:    ballot.Base,
+			votes:   votes,
+			abstain: abstain,
+		})
+	}
+	return nil
+}
+
+// saveCheckpoint persists checkpoint and removes older ones.
+func saveCheckpoint(db sql.Executor, lid types.LayerID, data []byte) error {
+	if err := checkpoints.Add(db, lid, data); err != nil {
+		return err
+	}
+	return checkpoints.Prune(db, lid)
+}
+
+// loadCheckpoint restores turtle from the last checkpoint. returns false if there is no usable checkpoint.
+func loadCheckpoint(db sql.Executor, trtl *turtle, processed types.LayerID) (bool, error) {
+	lid, data, err := checkpoints.Last(db)
+	if err != nil {
+		return false, err
+	}
+	if lid.After(processed) {
+		return false, nil
+	}
+	if err := trtl.restore(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,171 @@
+package tortoise
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/checkpoints"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+func TestCheckpointEncoding(t *testing.T) {
+	ctx := context.Background()
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	tortoise := tortoiseFromSimState(s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+	for i := 0; i < 30; i++ {
+		tortoise.HandleIncomingLayer(ctx, s.Next())
+	}
+
+	data, err := tortoise.trtl.checkpoint()
+	require.NoError(t, err)
+
+	restored := tortoise.trtl.cloneTurtleParams()
+	require.NoError(t, restored.restore(data))
+	require.Equal(t, tortoise.trtl.verified, restored.verified)
+	require.Equal(t, tortoise.trtl.processed, restored.processed)
+	require.Equal(t, tortoise.trtl.evicted, restored.evicted)
+	require.Equal(t, tortoise.trtl.blocks, restored.blocks)
+	require.Equal(t, tortoise.trtl.ballots, restored.ballots)
+	require.Equal(t, tortoise.trtl.validity, restored.validity)
+	require.Equal(t, tortoise.trtl.hareOutput, restored.hareOutput)
+	require.Equal(t, tortoise.trtl.verifying.goodBallots, restored.verifying.goodBallots)
+	require.Equal(t, tortoise.trtl.full.votes, restored.full.votes)
+
+	encoded, err := restored.checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, data, encoded)
+
+	require.Error(t, restored.restore(data[:len(data)/2]))
+}
+
+func TestRecoverFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	const (
+		size     = 10
+		interval = 10
+	)
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	db := sql.InMemory()
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.CheckpointInterval = interval
+	tortoise := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	var last, verified types.LayerID
+	for i := 0; i < 25; i++ {
+		last = s.Next()
+		_, verified, _ = tortoise.HandleIncomingLayer(ctx, last)
+	}
+	require.Equal(t, last.Sub(1), verified)
+
+	checkpointed, _, err := checkpoints.Last(db)
+	require.NoError(t, err)
+	require.True(t, checkpointed.Before(last))
+	require.Equal(t, tortoise.checkpointed, checkpointed)
+
+	cfg.MeshVerified = verified
+	cfg.MeshProcessed = last
+	tortoise2 := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	initctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, tortoise2.WaitReady(initctx))
+	require.Equal(t, checkpointed, tortoise2.checkpointed)
+	require.Equal(t, last, tortoise2.trtl.processed)
+	require.Equal(t, verified, tortoise2.LatestComplete())
+
+	for i := 0; i < interval; i++ {
+		last = s.Next()
+		_, expected, _ := tortoise.HandleIncomingLayer(ctx, last)
+		_, verified, _ := tortoise2.HandleIncomingLayer(ctx, last)
+		require.Equal(t, expected, verified)
+		require.Equal(t, last.Sub(1), verified)
+	}
+	checkpointed, _, err = checkpoints.Last(db)
+	require.NoError(t, err)
+	require.Equal(t, tortoise2.checkpointed, checkpointed)
+}
+
+func TestCheckpointAfterMesh(t *testing.T) {
+	ctx := context.Background()
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	db := sql.InMemory()
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.CheckpointInterval = 1
+	tortoise := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	var last, verified types.LayerID
+	for i := 0; i < 10; i++ {
+		last = s.Next()
+		_, verified, _ = tortoise.HandleIncomingLayer(ctx, last)
+	}
+
+	// checkpoint is ignored if mesh wasn't persisted up to the checkpoint layer
+	cfg.MeshVerified = verified.Sub(2)
+	cfg.MeshProcessed = last.Sub(2)
+	tortoise2 := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	initctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, tortoise2.WaitReady(initctx))
+	require.Equal(t, types.LayerID{}, tortoise2.checkpointed)
+}
+
+func TestRerunFromCheckpointIfPruned(t *testing.T) {
+	ctx := context.Background()
+	const (
+		size     = 10
+		interval = 10
+	)
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	db := sql.InMemory()
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.CheckpointInterval = interval
+	cfg.WindowSize = interval
+	tortoise := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	require.Equal(t, types.GetEffectiveGenesis(), tortoise.WindowStart())
+
+	var last types.LayerID
+	for i := 0; i < 4*interval; i++ {
+		last = s.Next()
+		tortoise.HandleIncomingLayer(ctx, last)
+	}
+	windowStart := tortoise.WindowStart()
+	require.True(t, windowStart.After(types.GetEffectiveGenesis()))
+	require.False(t, windowStart.After(tortoise.trtl.evicted.Add(1)))
+	require.NoError(t, s.GetState(0).MeshDB.Prune(windowStart))
+
+	require.NoError(t, tortoise.rerun(ctx))
+	last = s.Next()
+	_, verified, reverted := tortoise.HandleIncomingLayer(ctx, last)
+	require.False(t, reverted)
+	require.Equal(t, last.Sub(1), verified)
+
+	t.Run("no checkpoint", func(t *testing.T) {
+		cfg.CheckpointInterval = 0
+		tortoise := tortoiseFromSimState(s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+		tortoise.trtl.last = last
+		require.ErrorIs(t, tortoise.rerun(ctx), sql.ErrPruned)
+	})
+}
@@ -0,0 +1,279 @@
+package tortoise
+
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+const (
+	// RecountVotes counts votes from every ballot for every block between verified layer and the layer
+	// of the ballot. Votes are counted only when verifying tortoise can't make progress.
+	RecountVotes = "recount"
+	// IncrementalVotes counts votes from the ballot once its layer is processed. Block tallies
+	// are computed only from ballots that changed opinion relative to their base ballot.
+	IncrementalVotes = "incremental"
+)
+
+// voteCounter accumulates weight of the votes from ballots for blocks.
+type voteCounter interface {
+	// eager is true if votes must be counted as soon as the layer of the ballot is processed.
+	eager() bool
+	onBlock(types.BlockID)
+	onBallot(*tortoiseBallot)
+	// countBallot adds weight of the ballot to the blocks that it votes for.
+	countBallot(logger log.Log, ballotlid types.LayerID, ballot types.BallotID, ballotWeight weight)
+	// weight of the votes for the block. Weight of the block in the verified layer is not updated
+	// and remains as it was counted when the layer was verified.
+	//
+	// Note that recounting counter counts votes only when verifying tortoise can't make progress,
+	// so for layers verified by verifying tortoise it returns weight of the votes that were counted
+	// before, possibly zero, while incremental counter returns weight of all ballots counted before
+	// the layer was verified.
+	weight(logger log.Log, lid types.LayerID, block types.BlockID) weight
+	// decided is called with the weight of the block when its layer is verified.
+	decided(block types.BlockID, w weight)
+	// restoreWeight sets weight for the block from the checkpoint.
+	restoreWeight(block types.BlockID, w weight)
+	// restored is called after all ballots are restored from the checkpoint.
+	restored(logger log.Log)
+	// evict must be called before the layer is removed from common state.
+	evict(lid types.LayerID)
+}
+
+func newVoteCounter(name string, f *full) voteCounter {
+	if name == IncrementalVotes {
+		return newIncrementalCounter(f)
+	}
+	return newRecountingCounter(f)
+}
+
+func newRecountingCounter(f *full) *recountingCounter {
+	return &recountingCounter{
+		full:    f,
+		weights: map[types.BlockID]weight{},
+	}
+}
+
+// recountingCounter adds weight of each ballot to every block in the layers between verified layer
+// and the layer of the ballot.
+type recountingCounter struct {
+	*full
+
+	// counted weights of all blocks up to counted layer.
+	weights map[types.BlockID]weight
+}
+
+func (c *recountingCounter) eager() bool {
+	return false
+}
+
+func (c *recountingCounter) onBlock(block types.BlockID) {
+	c.weights[block] = weightFromUint64(0)
+}
+
+func (c *recountingCounter) onBallot(*tortoiseBallot) {}
+
+func (c *recountingCounter) countBallot(logger log.Log, ballotlid types.LayerID, ballot types.BallotID, ballotWeight weight) {
+	for lid := c.verified.Add(1); lid.Before(ballotlid); lid = lid.Add(1) {
+		for _, block := range c.blocks[lid] {
+			vote := c.getVote(logger, ballot, lid, block)
+			current := c.weights[block]
+			switch vote {
+			case support:
+				current = current.add(ballotWeight)
+			case against:
+				current = current.sub(ballotWeight)
+			}
+			c.weights[block] = current
+		}
+	}
+}
+
+func (c *recountingCounter) weight(_ log.Log, _ types.LayerID, block types.BlockID) weight {
+	return c.weights[block]
+}
+
+func (c *recountingCounter) decided(types.BlockID, weight) {}
+
+func (c *recountingCounter) restoreWeight(block types.BlockID, w weight) {
+	c.weights[block] = w
+}
+
+func (c *recountingCounter) restored(log.Log) {}
+
+func (c *recountingCounter) evict(lid types.LayerID) {
+	for _, block := range c.blocks[lid] {
+		delete(c.weights, block)
+	}
+}
+
+func newIncrementalCounter(f *full) *incrementalCounter {
+	return &incrementalCounter{
+		full:    f,
+		through: newBallotTree(),
+		voters:  map[types.LayerID]map[types.BallotID]struct{}{},
+		after:   &layerWeights{},
+		frozen:  map[types.BlockID]weight{},
+		cache:   map[types.LayerID]map[types.BlockID]weight{},
+	}
+}
+
+// incrementalCounter counts ballot once and makes counting independent from the size of the window.
+//
+// Ballot votes the same way as its base ballot, except for explicit votes and abstained layers.
+// So the sum of votes for the block is:
+//
+//   - weight of all counted ballots after the layer of the block, as every ballot votes against by default
+//   - plus (vote of the ballot - vote of its base ballot) multiplied by the weight of all ballots
+//     that reference this ballot directly or through base ballots, for ballots with explicit votes on the layer.
+//
+// Both sums are maintained in O(log n) per counted ballot: weight of the ballots after the layer
+// in a fenwick tree over layers, and weight that flows through the ballot in a tree of base ballots
+// (see ballotTree). Ballots in the layer after verified and older can't vote on blocks that are not
+// verified yet and are not counted. Weight of the block in the verified layer is not updated,
+// and the last counted weight is frozen when the layer is verified.
+type incrementalCounter struct {
+	*full
+
+	// through is a weight of counted ballots that use ballot as a base directly or indirectly,
+	// including weight of the ballot itself.
+	through *ballotTree
+	// voters are ballots with explicit votes or abstained votes on the layer.
+	voters map[types.LayerID]map[types.BallotID]struct{}
+	// after is a weight of counted ballots in each layer.
+	after *layerWeights
+	// frozen weights of the blocks in verified layers.
+	frozen map[types.BlockID]weight
+	// cache of computed weights by layer of the block. invalidated for layers before the layer
+	// of the counted ballot, as ballot votes only on blocks in those layers.
+	cache map[types.LayerID]map[types.BlockID]weight
+}
+
+func (c *incrementalCounter) eager() bool {
+	return true
+}
+
+func (c *incrementalCounter) onBlock(types.BlockID) {}
+
+func (c *incrementalCounter) onBallot(ballot *tortoiseBallot) {
+	c.through.insert(ballot.id, ballot.base)
+	for block := range ballot.votes {
+		lid, exist := c.blockLayer[block]
+		if !exist {
+			continue
+		}
+		c.addVoter(lid, ballot.id)
+	}
+	for lid := range ballot.abstain {
+		c.addVoter(lid, ballot.id)
+	}
+}
+
+func (c *incrementalCounter) addVoter(lid types.LayerID, ballot types.BallotID) {
+	if _, exist := c.voters[lid]; !exist {
+		c.voters[lid] = map[types.BallotID]struct{}{}
+	}
+	c.voters[lid][ballot] = struct{}{}
+}
+
+func (c *incrementalCounter) countBallot(_ log.Log, ballotlid types.LayerID, ballot types.BallotID, ballotWeight weight) {
+	if !ballotlid.After(c.verified.Add(1)) {
+		return
+	}
+	for lid := range c.cache {
+		if lid.Before(ballotlid) {
+			delete(c.cache, lid)
+		}
+	}
+	c.after.add(ballotlid, ballotWeight)
+	// ballots restored from checkpoint without votes are not passed to onBallot
+	c.through.insert(ballot, c.base[ballot])
+	c.through.add(ballot, ballotWeight)
+}
+
+func (c *incrementalCounter) weight(logger log.Log, lid types.LayerID, block types.BlockID) weight {
+	if !lid.After(c.verified) {
+		if w, exist := c.frozen[block]; exist {
+			return w
+		}
+		return weightFromUint64(0)
+	}
+	if w, exist := c.cache[lid][block]; exist {
+		return w
+	}
+	sum := weightFromUint64(0).sub(c.after.after(lid))
+	for ballot := range c.voters[lid] {
+		if !lid.Before(c.ballotLayer[ballot]) {
+			continue
+		}
+		through, exist := c.through.weight(ballot)
+		if !exist {
+			continue
+		}
+		vote, exist := c.votes[ballot][block]
+		if !exist {
+			if _, exist = c.abstain[ballot][lid]; !exist {
+				continue
+			}
+			vote = abstain
+		}
+		base := c.getVote(logger, c.base[ballot], lid, block)
+		switch vote - base {
+		case 2:
+			sum = sum.add(through).add(through)
+		case 1:
+			sum = sum.add(through)
+		case -1:
+			sum = sum.sub(through)
+		case -2:
+			sum = sum.sub(through).sub(through)
+		}
+	}
+	if _, exist := c.cache[lid]; !exist {
+		c.cache[lid] = map[types.BlockID]weight{}
+	}
+	c.cache[lid][block] = sum
+	return sum
+}
+
+func (c *incrementalCounter) decided(block types.BlockID, w weight) {
+	c.frozen[block] = w
+}
+
+// restoreWeight restores only frozen weights. weights for blocks after verified layer are counted again.
+func (c *incrementalCounter) restoreWeight(block types.BlockID, w weight) {
+	if lid, exist := c.blockLayer[block]; exist && !lid.After(c.verified) {
+		c.frozen[block] = w
+	}
+}
+
+// restored counts all ballots that were counted before the checkpoint, except delayed ballots.
+func (c *incrementalCounter) restored(logger log.Log) {
+	delayed := map[types.BallotID]struct{}{}
+	for front := c.delayedQueue.Front(); front != nil; front = front.Next() {
+		for _, ballot := range front.Value.(delayedBallots).ballots {
+			delayed[ballot] = struct{}{}
+		}
+	}
+	for lid := c.verified.Add(1); !lid.After(c.counted); lid = lid.Add(1) {
+		for _, ballot := range c.ballots[lid] {
+			ballotWeight := c.ballotWeight[ballot]
+			if _, exist := delayed[ballot]; exist || ballotWeight.isNil() {
+				continue
+			}
+			c.countBallot(logger, lid, ballot, ballotWeight)
+		}
+	}
+}
+
+func (c *incrementalCounter) evict(lid types.LayerID) {
+	for _, ballot := range c.ballots[lid] {
+		c.through.remove(ballot)
+	}
+	for _, block := range c.blocks[lid] {
+		delete(c.frozen, block)
+	}
+	delete(c.cache, lid)
+	delete(c.voters, lid)
+	c.after.evict(lid)
+}
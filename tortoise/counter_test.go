@@ -0,0 +1,218 @@
+package tortoise
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim/scenario"
+)
+
+func TestIncrementalCounterMatchesRecount(t *testing.T) {
+	const size = 10
+	ctx := context.Background()
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.VoteCounter = IncrementalVotes
+	logger := logtest.New(t)
+	tortoise := tortoiseFromSimState(s.GetState(0), WithLogger(logger), WithConfig(cfg))
+
+	counted := 0
+	for _, lid := range sim.GenLayers(s,
+		sim.WithSequence(5),
+		sim.WithSequence(5, sim.WithEmptyHareOutput()),
+		sim.WithSequence(1, sim.WithVoteGenerator(gapVote)),
+		sim.WithSequence(5, sim.WithVoteGenerator(splitVoting(size))),
+		sim.WithSequence(5, sim.WithVoteGenerator(skipLayers(2))),
+		sim.WithSequence(10, sim.WithEmptyHareOutput()),
+		sim.WithSequence(5),
+	) {
+		tortoise.HandleIncomingLayer(ctx, lid)
+
+		trtl := tortoise.trtl
+		recount := newRecountingCounter(trtl.full)
+		for blocklid := trtl.verified.Add(1); !blocklid.After(trtl.processed); blocklid = blocklid.Add(1) {
+			for _, block := range trtl.blocks[blocklid] {
+				recount.onBlock(block)
+			}
+		}
+		for ballotlid := trtl.verified.Add(1); !ballotlid.After(trtl.processed); ballotlid = ballotlid.Add(1) {
+			for _, ballot := range trtl.ballots[ballotlid] {
+				if w := trtl.ballotWeight[ballot]; !w.isNil() {
+					recount.countBallot(logger, ballotlid, ballot, w)
+				}
+			}
+		}
+		for blocklid := trtl.verified.Add(1); !blocklid.After(trtl.processed); blocklid = blocklid.Add(1) {
+			for _, block := range trtl.blocks[blocklid] {
+				expected := recount.weight(logger, blocklid, block)
+				require.Equal(t,
+					expected.String(),
+					trtl.full.weight(logger, blocklid, block).String(),
+					"block %s in layer %s. last layer %s", block, blocklid, lid,
+				)
+				if expected.Sign() != 0 {
+					counted++
+				}
+			}
+		}
+	}
+	require.NotZero(t, counted)
+}
+
+func TestIncrementalCounterFreezesVerified(t *testing.T) {
+	const size = 10
+	ctx := context.Background()
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.VoteCounter = IncrementalVotes
+	logger := logtest.New(t)
+	tortoise := tortoiseFromSimState(s.GetState(0), WithLogger(logger), WithConfig(cfg))
+
+	frozen := map[types.BlockID]string{}
+	for _, lid := range sim.GenLayers(s, sim.WithSequence(10)) {
+		tortoise.HandleIncomingLayer(ctx, lid)
+		trtl := tortoise.trtl
+		require.True(t, trtl.mode.isVerifying())
+		for blocklid := types.GetEffectiveGenesis().Add(1); !blocklid.After(trtl.verified); blocklid = blocklid.Add(1) {
+			for _, block := range trtl.blocks[blocklid] {
+				w := trtl.full.weight(logger, blocklid, block)
+				// layers verified by verifying tortoise keep weight counted before verification
+				require.NotZero(t, w.Sign(), "block %s in layer %s", block, blocklid)
+				if expected, exist := frozen[block]; exist {
+					require.Equal(t, expected, w.String())
+				}
+				frozen[block] = w.String()
+			}
+		}
+	}
+	require.NotEmpty(t, frozen)
+}
+
+func windowTortoise(b *testing.B, counter string, window uint32, opts ...sim.NextOpt) (*sim.Generator, *Tortoise) {
+	const size = 30
+	ctx := context.Background()
+	s := sim.New(
+		sim.WithLayerSize(size),
+		sim.WithPath(b.TempDir()),
+	)
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	cfg.VoteCounter = counter
+	cfg.WindowSize = window
+	cfg.VerifyingModeVerificationWindow = window
+	cfg.FullModeVerificationWindow = window
+	tortoise := tortoiseFromSimState(s.GetState(0), WithConfig(cfg))
+	tortoise.HandleIncomingLayer(ctx, s.Next())
+	for i := uint32(0); i < window; i++ {
+		tortoise.HandleIncomingLayer(ctx, s.Next(opts...))
+	}
+	return s, tortoise
+}
+
+func benchmarkVoteCounting(b *testing.B, counter string, window uint32, opts ...sim.NextOpt) {
+	ctx := context.Background()
+	s, tortoise := windowTortoise(b, counter, window, opts...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		lid := s.Next(opts...)
+		b.StartTimer()
+		tortoise.HandleIncomingLayer(ctx, lid)
+	}
+}
+
+var stalledOpts = []sim.NextOpt{
+	sim.WithEmptyHareOutput(),
+	sim.WithVoteGenerator(scenario.BalancingVoting(30)),
+}
+
+// BenchmarkVoteCounting measures cost of handling a single layer after the tortoise
+// processed number of layers equal to the window.
+//
+// In Stalled benchmarks no layer is verified and every layer from the window is counted.
+// Recounting cost grows with the square of the window, as every ballot votes on every block
+// in the window. Cost of the incremental counter doesn't depend on the window, but handling
+// a layer includes verifying tortoise and loading the layer, see BenchmarkCountLayer for the
+// cost of counting alone.
+func BenchmarkVoteCounting(b *testing.B) {
+	for _, counter := range []string{RecountVotes, IncrementalVotes} {
+		for _, window := range []uint32{10, 100, 1000} {
+			counter := counter
+			window := window
+			b.Run(fmt.Sprintf("Verifying/%s/%d", counter, window), func(b *testing.B) {
+				benchmarkVoteCounting(b, counter, window)
+			})
+			b.Run(fmt.Sprintf("Full/%s/%d", counter, window), func(b *testing.B) {
+				benchmarkVoteCounting(b, counter, window, sim.WithEmptyHareOutput())
+			})
+		}
+		for _, window := range []uint32{10, 50, 200} {
+			counter := counter
+			window := window
+			b.Run(fmt.Sprintf("Stalled/%s/%d", counter, window), func(b *testing.B) {
+				benchmarkVoteCounting(b, counter, window, stalledOpts...)
+			})
+		}
+	}
+}
+
+// BenchmarkCountLayer measures cost of counting ballots from the last layer when verification
+// is stalled. Every iteration counts ballots and then removes their weight. Incremental counter
+// grows only with the logarithm of the window:
+//
+//	BenchmarkCountLayer/recount/10        	     285	   4242992 ns/op
+//	BenchmarkCountLayer/recount/50        	      15	  70869225 ns/op
+//	BenchmarkCountLayer/recount/200       	       1	1136196652 ns/op
+//	BenchmarkCountLayer/incremental/10    	    2854	    527277 ns/op
+//	BenchmarkCountLayer/incremental/50    	    2181	    636067 ns/op
+//	BenchmarkCountLayer/incremental/200   	    1620	    904844 ns/op
+func BenchmarkCountLayer(b *testing.B) {
+	for _, counter := range []string{RecountVotes, IncrementalVotes} {
+		for _, window := range []uint32{10, 50, 200} {
+			counter := counter
+			window := window
+			b.Run(fmt.Sprintf("%s/%d", counter, window), func(b *testing.B) {
+				logger := log.NewNop()
+				_, tortoise := windowTortoise(b, IncrementalVotes, window, stalledOpts...)
+				trtl := tortoise.trtl
+				var vc voteCounter = trtl.full.counter
+				if counter == RecountVotes {
+					recount := newRecountingCounter(trtl.full)
+					for lid := trtl.verified.Add(1); !lid.After(trtl.processed); lid = lid.Add(1) {
+						for _, block := range trtl.blocks[lid] {
+							recount.onBlock(block)
+						}
+					}
+					vc = recount
+				}
+				lid := trtl.processed
+				ballots := trtl.ballots[lid]
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for _, ballot := range ballots {
+						if w := trtl.ballotWeight[ballot]; !w.isNil() {
+							vc.countBallot(logger, lid, ballot, w)
+							vc.countBallot(logger, lid, ballot, w.copy().neg())
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package tortoise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// ErrNotInWindow is returned if the block was evicted from the tortoise sliding window or wasn't received yet.
+var ErrNotInWindow = errors.New("block is not in the tortoise window")
+
+// LayerVotes is a weight of ballots from a single layer that voted on a block.
+type LayerVotes struct {
+	Layer                     types.LayerID
+	Support, Against, Abstain *big.Rat
+	// Ballots is a number of ballots with non-zero weight in the layer.
+	Ballots int
+}
+
+// Explanation describes how tortoise decided validity of a block.
+type Explanation struct {
+	Block types.BlockID
+	Layer types.LayerID
+
+	// Decided is true if the layer of the block is verified.
+	Decided bool
+	Valid   bool
+	// Mode is a mode of the tortoise that verified the layer (verifying or full, with rerun prefix
+	// if it was verified during rerun). Empty if the layer was verified before the node was restarted
+	// from the checkpoint that doesn't include it, or if layer is not verified.
+	Mode string
+	// LocalThreshold and GlobalThreshold that were used when the layer was verified.
+	// Current thresholds if layer is not verified.
+	LocalThreshold, GlobalThreshold *big.Rat
+
+	// Vote is a local opinion about the block.
+	Vote string
+	// Reason is the data that local opinion is based on: hare output, validity, local threshold or weak coin.
+	Reason string
+	// Weight is a sum of votes counted by full tortoise. It is counted only in full mode.
+	Weight *big.Rat
+	// Votes from ballots in layers after the layer of the block.
+	Votes []LayerVotes
+}
+
+// Explain returns votes, thresholds and the tortoise mode that decided validity of the block.
+func (t *Tortoise) Explain(ctx context.Context, bid types.BlockID) (*Explanation, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.trtl.explain(ctx, bid)
+}
+
+func (t *turtle) explain(ctx context.Context, bid types.BlockID) (*Explanation, error) {
+	lid, exist := t.blockLayer[bid]
+	if !exist {
+		return nil, fmt.Errorf("%w: %s", ErrNotInWindow, bid)
+	}
+	rst := &Explanation{
+		Block:           bid,
+		Layer:           lid,
+		Decided:         !lid.After(t.verified),
+		LocalThreshold:  ratFromWeight(t.localThreshold),
+		GlobalThreshold: ratFromWeight(t.globalThreshold),
+		Weight:          ratFromWeight(t.full.weight(t.logger, lid, bid)),
+	}
+	if rst.Decided {
+		rst.Valid = t.validity[bid] == support
+		if decided, exist := t.decisions[lid]; exist {
+			rst.Mode = decided.mode.String()
+			rst.LocalThreshold = ratFromWeight(decided.localThreshold)
+			rst.GlobalThreshold = ratFromWeight(decided.globalThreshold)
+		}
+	}
+
+	vote, reason, err := t.getFullVote(ctx, lid, bid)
+	if err != nil {
+		// weak coin is not recorded for the last layer
+		vote, reason = getLocalVote(&t.commonState, t.Config, lid, bid)
+	}
+	rst.Vote = vote.String()
+	rst.Reason = reason.String()
+
+	for ballotlid := lid.Add(1); !ballotlid.After(t.processed); ballotlid = ballotlid.Add(1) {
+		votes := LayerVotes{
+			Layer:   ballotlid,
+			Support: new(big.Rat),
+			Against: new(big.Rat),
+			Abstain: new(big.Rat),
+		}
+		for _, ballot := range t.ballots[ballotlid] {
+			ballotWeight := t.ballotWeight[ballot]
+			if ballotWeight.isNil() || ballotWeight.Sign() == 0 {
+				continue
+			}
+			votes.Ballots++
+			switch t.full.getVote(t.logger, ballot, lid, bid) {
+			case support:
+				votes.Support.Add(votes.Support, ballotWeight.Rat)
+			case against:
+				votes.Against.Add(votes.Against, ballotWeight.Rat)
+			case abstain:
+				votes.Abstain.Add(votes.Abstain, ballotWeight.Rat)
+			}
+		}
+		rst.Votes = append(rst.Votes, votes)
+	}
+	return rst, nil
+}
+
+func ratFromWeight(w weight) *big.Rat {
+	if w.isNil() {
+		return nil
+	}
+	return new(big.Rat).Set(w.Rat)
+}
@@ -0,0 +1,94 @@
+package tortoise
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+func TestExplain(t *testing.T) {
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	ctx := context.Background()
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	tortoise := tortoiseFromSimState(s.GetState(0), WithConfig(cfg), WithLogger(logtest.New(t)))
+
+	var (
+		last     types.LayerID
+		genesis  = types.GetEffectiveGenesis()
+		verified types.LayerID
+	)
+	for _, lid := range sim.GenLayers(s,
+		sim.WithSequence(5),
+		sim.WithSequence(2, sim.WithoutHareOutput()),
+		sim.WithSequence(21),
+	) {
+		last = lid
+		_, verified, _ = tortoise.HandleIncomingLayer(ctx, lid)
+	}
+	require.Equal(t, last.Sub(1), verified)
+
+	layerBlock := func(t *testing.T, lid types.LayerID) types.BlockID {
+		t.Helper()
+		blocks, err := s.GetState(0).MeshDB.LayerBlockIds(lid)
+		require.NoError(t, err)
+		require.NotEmpty(t, blocks)
+		return blocks[0]
+	}
+
+	t.Run("Verifying", func(t *testing.T) {
+		lid := genesis.Add(2)
+		rst, err := tortoise.Explain(ctx, layerBlock(t, lid))
+		require.NoError(t, err)
+		require.Equal(t, lid, rst.Layer)
+		require.True(t, rst.Decided)
+		require.True(t, rst.Valid)
+		require.Equal(t, verifyingTortoise, rst.Mode)
+		require.NotNil(t, rst.GlobalThreshold)
+		require.NotNil(t, rst.LocalThreshold)
+		require.Equal(t, support.String(), rst.Vote)
+		require.Equal(t, reasonValidity.String(), rst.Reason)
+		require.Len(t, rst.Votes, int(last.Difference(lid)))
+		for i, votes := range rst.Votes {
+			require.Equal(t, lid.Add(uint32(i)+1), votes.Layer)
+			require.Equal(t, size, votes.Ballots)
+			require.Zero(t, votes.Against.Sign())
+			require.Zero(t, votes.Abstain.Sign())
+		}
+		total := new(big.Rat)
+		for _, votes := range rst.Votes {
+			total.Add(total, votes.Support)
+		}
+		require.Equal(t, 1, total.Cmp(rst.GlobalThreshold))
+	})
+	t.Run("Full", func(t *testing.T) {
+		lid := genesis.Add(6)
+		rst, err := tortoise.Explain(ctx, layerBlock(t, lid))
+		require.NoError(t, err)
+		require.True(t, rst.Decided)
+		require.Equal(t, fullTortoise, rst.Mode)
+		require.NotNil(t, rst.Weight)
+		require.Equal(t, rst.Valid, rst.Weight.Sign() > 0)
+	})
+	t.Run("Undecided", func(t *testing.T) {
+		rst, err := tortoise.Explain(ctx, layerBlock(t, last))
+		require.NoError(t, err)
+		require.False(t, rst.Decided)
+		require.Empty(t, rst.Mode)
+		require.Equal(t, reasonHareOutput.String(), rst.Reason)
+		require.Empty(t, rst.Votes)
+	})
+	t.Run("NotInWindow", func(t *testing.T) {
+		_, err := tortoise.Explain(ctx, types.BlockID{1, 2, 3})
+		require.ErrorIs(t, err, ErrNotInWindow)
+	})
+}
@@ -8,15 +8,16 @@ import (
 )
 
 func newFullTortoise(config Config, common *commonState) *full {
-	return &full{
+	f := &full{
 		Config:       config,
 		commonState:  common,
 		votes:        map[types.BallotID]votes{},
 		abstain:      map[types.BallotID]map[types.LayerID]struct{}{},
 		base:         map[types.BallotID]types.BallotID{},
-		weights:      map[types.BlockID]weight{},
 		delayedQueue: list.New(),
 	}
+	f.counter = newVoteCounter(config.VoteCounter, f)
+	return f
 }
 
 type full struct {
@@ -30,20 +31,18 @@ type full struct {
 	// counted weights up to this layer.
 	//
 	// counting votes is what makes full tortoise expensive during rerun.
-	// we want to wait until verifying can't make progress before they are counted.
+	// unless counter is eager we want to wait until verifying can't make progress before they are counted.
 	// storing them in current version is cheap.
 	counted types.LayerID
-	// counted weights of all blocks up to counted layer.
-	weights map[types.BlockID]weight
+	// counter of the weight of votes for blocks.
+	counter voteCounter
 	// queue of the ballots with bad beacon
 	delayedQueue *list.List
 }
 
 func (f *full) processBallots(ballots []tortoiseBallot) {
-	for _, ballot := range ballots {
-		f.base[ballot.id] = ballot.base
-		f.votes[ballot.id] = ballot.votes
-		f.abstain[ballot.id] = ballot.abstain
+	for i := range ballots {
+		f.onBallot(&ballots[i])
 	}
 }
 
@@ -51,13 +50,23 @@ func (f *full) onBallot(ballot *tortoiseBallot) {
 	f.base[ballot.id] = ballot.base
 	f.votes[ballot.id] = ballot.votes
 	f.abstain[ballot.id] = ballot.abstain
+	f.counter.onBallot(ballot)
 }
 
 func (f *full) onBlock(block types.BlockID) {
-	f.weights[block] = weightFromUint64(0)
+	f.counter.onBlock(block)
+}
+
+// weight returns counted weight of the votes for the block.
+func (f *full) weight(logger log.Log, lid types.LayerID, block types.BlockID) weight {
+	return f.counter.weight(logger, lid, block)
 }
 
 func (f *full) getVote(logger log.Log, ballot types.BallotID, blocklid types.LayerID, block types.BlockID) sign {
+	// ballot can't vote for blocks in the same or later layers, neither can its base ballots
+	if ballotlid, exist := f.ballotLayer[ballot]; exist && !blocklid.Before(ballotlid) {
+		return against
+	}
 	sign, exist := f.votes[ballot][block]
 	if !exist {
 		_, exist = f.abstain[ballot][blocklid]
@@ -85,19 +94,7 @@ func (f *full) countVotesFromBallots(logger log.Log, ballotlid types.LayerID, ba
 		if ballotWeight.isNil() {
 			continue
 		}
-		for lid := f.verified.Add(1); lid.Before(ballotlid); lid = lid.Add(1) {
-			for _, block := range f.blocks[lid] {
-				vote := f.getVote(logger, ballot, lid, block)
-				current := f.weights[block]
-				switch vote {
-				case support:
-					current = current.add(ballotWeight)
-				case against:
-					current = current.sub(ballotWeight)
-				}
-				f.weights[block] = current
-			}
-		}
+		f.counter.countBallot(logger, ballotlid, ballot, ballotWeight)
 	}
 	if len(delayed) > 0 {
 		f.delayedQueue.PushBack(delayedBallots{
@@ -151,9 +148,10 @@ func (f *full) verify(logger log.Log, lid types.LayerID) bool {
 	blocks := f.blocks[lid]
 	// necessary only to log debug only once for each block
 	decisions := make([]sign, 0, len(blocks))
+	weights := make([]weight, 0, len(blocks))
 
 	for _, block := range blocks {
-		current := f.weights[block]
+		current := f.weight(logger, lid, block)
 		decision := current.cmp(f.globalThreshold)
 		if decision == abstain {
 			logger.With().Info("candidate layer is not verified. not enough weight in votes",
@@ -163,6 +161,7 @@ func (f *full) verify(logger log.Log, lid types.LayerID) bool {
 			return false
 		}
 		decisions = append(decisions, decision)
+		weights = append(weights, current)
 	}
 	for i, block := range blocks {
 		logger.With().Debug("full tortoise decided on a block",
@@ -170,11 +169,20 @@ func (f *full) verify(logger log.Log, lid types.LayerID) bool {
 			log.Stringer("decision", decisions[i]),
 		)
 		f.validity[block] = decisions[i]
+		f.counter.decided(block, weights[i])
 	}
 	logger.With().Info("candidate layer is verified")
 	return true
 }
 
+// freeze passes weights of the blocks in the layer to the counter when the layer is verified
+// by verifying tortoise. Full tortoise passes them when it decides on the blocks.
+func (f *full) freeze(logger log.Log, lid types.LayerID) {
+	for _, block := range f.blocks[lid] {
+		f.counter.decided(block, f.weight(logger, lid, block))
+	}
+}
+
 // shouldBeDelayed is true if ballot has a different beacon and it wasn't created sufficiently
 // long time ago.
 func (f *full) shouldBeDelayed(ballotID types.BallotID, ballotlid types.LayerID) bool {
@@ -182,6 +190,21 @@ func (f *full) shouldBeDelayed(ballotID types.BallotID, ballotlid types.LayerID)
 	return bad && f.last.Difference(ballotlid) <= f.BadBeaconVoteDelayLayers
 }
 
+func (f *full) isDelayed(ballotlid types.LayerID, ballot types.BallotID) bool {
+	for front := f.delayedQueue.Front(); front != nil; front = front.Next() {
+		delayed := front.Value.(delayedBallots)
+		if delayed.lid != ballotlid {
+			continue
+		}
+		for _, id := range delayed.ballots {
+			if id == ballot {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type delayedBallots struct {
 	lid     types.LayerID
 	ballots []types.BallotID
@@ -293,80 +293,85 @@ func TestFullCountVotes(t *testing.T) {
 			expect: weightFromFloat64(0),
 		},
 	} {
-		tc := tc
-		t.Run(tc.desc, func(t *testing.T) {
-			logger := logtest.New(t)
-			ctrl := gomock.NewController(t)
-			atxdb := mocks.NewMockatxDataProvider(ctrl)
-			activeset := []types.ATXID{}
-			for i, weight := range tc.activeset {
-				header := makeAtxHeaderWithWeight(weight)
-				atxid := types.ATXID{byte(i)}
-				header.SetID(&atxid)
-				atxdb.EXPECT().GetAtxHeader(atxid).Return(header, nil).AnyTimes()
-				activeset = append(activeset, atxid)
-			}
+		for _, counter := range []string{RecountVotes, IncrementalVotes} {
+			tc := tc
+			counter := counter
+			t.Run(tc.desc+"/"+counter, func(t *testing.T) {
+				logger := logtest.New(t)
+				ctrl := gomock.NewController(t)
+				atxdb := mocks.NewMockatxDataProvider(ctrl)
+				activeset := []types.ATXID{}
+				for i, weight := range tc.activeset {
+					header := makeAtxHeaderWithWeight(weight)
+					atxid := types.ATXID{byte(i)}
+					header.SetID(&atxid)
+					atxdb.EXPECT().GetAtxHeader(atxid).Return(header, nil).AnyTimes()
+					activeset = append(activeset, atxid)
+				}
 
-			tortoise := defaultAlgorithm(t, getInMemMesh(t))
-			tortoise.trtl.atxdb = atxdb
-			consensus := tortoise.trtl
+				tortoise := defaultAlgorithm(t, getInMemMesh(t))
+				tortoise.trtl.atxdb = atxdb
+				consensus := tortoise.trtl
+				consensus.full.counter = newVoteCounter(counter, consensus.full)
 
-			blocks := [][]types.BlockID{}
-			for i, layer := range tc.layerBlocks {
-				layerBlocks := []types.BlockID{}
-				lid := genesis.Add(uint32(i) + 1)
-				for j := range layer {
-					p := &types.Proposal{}
-					p.EligibilityProofs = []types.VotingEligibilityProof{{J: uint32(j)}}
-					p.LayerIndex = lid
-					p.Ballot.Signature = signer.Sign(p.Ballot.Bytes())
-					p.Signature = signer.Sign(p.Bytes())
-					require.NoError(t, p.Initialize())
-					layerBlocks = append(layerBlocks, types.BlockID(p.ID()))
-				}
+				blocks := [][]types.BlockID{}
+				for i, layer := range tc.layerBlocks {
+					layerBlocks := []types.BlockID{}
+					lid := genesis.Add(uint32(i) + 1)
+					for j := range layer {
+						p := &types.Proposal{}
+						p.EligibilityProofs = []types.VotingEligibilityProof{{J: uint32(j)}}
+						p.LayerIndex = lid
+						p.Ballot.Signature = signer.Sign(p.Ballot.Bytes())
+						p.Signature = signer.Sign(p.Bytes())
+						require.NoError(t, p.Initialize())
+						layerBlocks = append(layerBlocks, types.BlockID(p.ID()))
+					}
 
-				for _, block := range layerBlocks {
-					consensus.onBlock(lid, block)
+					for _, block := range layerBlocks {
+						consensus.onBlock(lid, block)
+					}
+					blocks = append(blocks, layerBlocks)
 				}
-				blocks = append(blocks, layerBlocks)
-			}
 
-			ballots := [][]*types.Ballot{}
-			for i, layer := range tc.layerBallots {
-				layerBallots := []*types.Ballot{}
-				lid := genesis.Add(uint32(i) + 1)
-				for j, b := range layer {
-					ballot := &types.Ballot{}
-					ballot.EligibilityProofs = []types.VotingEligibilityProof{{J: uint32(j)}}
-					ballot.AtxID = activeset[b.ATX]
-					ballot.EpochData = &types.EpochData{ActiveSet: activeset}
-					ballot.LayerIndex = lid
-					// don't vote on genesis for simplicity,
-					// since we don't care about block goodness in this test
-					if i > 0 {
-						ballot.Votes.Support = getDiff(blocks, b.Support)
-						ballot.Votes.Against = getDiff(blocks, b.Against)
-						for _, layerNumber := range b.Abstain {
-							ballot.Votes.Abstain = append(ballot.Votes.Abstain, genesis.Add(uint32(layerNumber)+1))
+				ballots := [][]*types.Ballot{}
+				for i, layer := range tc.layerBallots {
+					layerBallots := []*types.Ballot{}
+					lid := genesis.Add(uint32(i) + 1)
+					for j, b := range layer {
+						ballot := &types.Ballot{}
+						ballot.EligibilityProofs = []types.VotingEligibilityProof{{J: uint32(j)}}
+						ballot.AtxID = activeset[b.ATX]
+						ballot.EpochData = &types.EpochData{ActiveSet: activeset}
+						ballot.LayerIndex = lid
+						// don't vote on genesis for simplicity,
+						// since we don't care about block goodness in this test
+						if i > 0 {
+							ballot.Votes.Support = getDiff(blocks, b.Support)
+							ballot.Votes.Against = getDiff(blocks, b.Against)
+							for _, layerNumber := range b.Abstain {
+								ballot.Votes.Abstain = append(ballot.Votes.Abstain, genesis.Add(uint32(layerNumber)+1))
+							}
+							ballot.Votes.Base = ballots[b.Base[0]][b.Base[1]].ID()
 						}
-						ballot.Votes.Base = ballots[b.Base[0]][b.Base[1]].ID()
+						ballot.Signature = signer.Sign(ballot.Bytes())
+						require.NoError(t, ballot.Initialize())
+						layerBallots = append(layerBallots, ballot)
 					}
-					ballot.Signature = signer.Sign(ballot.Bytes())
-					require.NoError(t, ballot.Initialize())
-					layerBallots = append(layerBallots, ballot)
-				}
-				ballots = append(ballots, layerBallots)
+					ballots = append(ballots, layerBallots)
 
-				consensus.processed = lid
-				consensus.last = lid
-				for _, ballot := range layerBallots {
-					require.NoError(t, consensus.onBallot(ballot))
-				}
+					consensus.processed = lid
+					consensus.last = lid
+					for _, ballot := range layerBallots {
+						require.NoError(t, consensus.onBallot(ballot))
+					}
 
-				consensus.full.countVotes(logger)
-			}
-			bid := blocks[tc.target[0]][tc.target[1]]
-			require.Equal(t, tc.expect.String(), consensus.full.weights[bid].String())
-		})
+					consensus.full.countVotes(logger)
+				}
+				bid := blocks[tc.target[0]][tc.target[1]]
+				lid := genesis.Add(uint32(tc.target[0]) + 1)
+				require.Equal(t, tc.expect.String(), consensus.full.weight(logger, lid, bid).String())
+			})
+		}
 	}
 }
@@ -0,0 +1,171 @@
+package tortoise
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim/scenario"
+)
+
+func TestMalfeasanceVerifyingWeight(t *testing.T) {
+	const size = 10
+	ctx := context.Background()
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	tortoise := tortoiseFromSimState(s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+	var last types.LayerID
+	for _, last = range sim.GenLayers(s, sim.WithSequence(5)) {
+		tortoise.HandleIncomingLayer(ctx, last)
+	}
+	trtl := tortoise.trtl
+	require.Equal(t, last.Sub(1), trtl.verified)
+
+	ballots, err := s.GetState(0).MeshDB.LayerBallots(last)
+	require.NoError(t, err)
+	ballot := ballots[0]
+	ballotWeight := trtl.ballotWeight[ballot.ID()].copy()
+	require.Equal(t, good, trtl.verifying.goodBallots[ballot.ID()])
+
+	expected := trtl.verifying.totalGoodWeight.copy().sub(ballotWeight)
+	tortoise.OnMalfeasance(ballot.SmesherID(), last)
+	require.True(t, trtl.ballotWeight[ballot.ID()].isNil())
+	require.Equal(t, expected.String(), trtl.verifying.totalGoodWeight.String())
+	require.Equal(t, expected.String(), trtl.verifying.goodWeight[last].String())
+
+	// repeated proof for the same smesher doesn't change weight
+	tortoise.OnMalfeasance(ballot.SmesherID(), last)
+	require.Equal(t, expected.String(), trtl.verifying.totalGoodWeight.String())
+}
+
+func TestMalfeasanceRemovesCountedWeight(t *testing.T) {
+	const size = 10
+	for _, counter := range []string{RecountVotes, IncrementalVotes} {
+		counter := counter
+		t.Run(counter, func(t *testing.T) {
+			ctx := context.Background()
+			s := sim.New(sim.WithLayerSize(size))
+			s.Setup()
+
+			cfg := defaultTestConfig()
+			cfg.LayerSize = size
+			cfg.VoteCounter = counter
+			logger := logtest.New(t)
+			// live tortoise learns about malfeasance after votes from the smesher were counted.
+			// another tortoise learns about it before receiving any ballot.
+			live := tortoiseFromSimState(s.GetState(0), WithLogger(logger.Named("live")), WithConfig(cfg))
+			late := tortoiseFromSimState(s.GetState(0), WithLogger(logger.Named("late")), WithConfig(cfg))
+
+			var (
+				last    types.LayerID
+				target  types.LayerID
+				smesher *signing.PublicKey
+			)
+			for i, lid := range sim.GenLayers(s,
+				sim.WithSequence(5),
+				sim.WithSequence(10,
+					sim.WithEmptyHareOutput(),
+					sim.WithVoteGenerator(scenario.BalancingVoting(size)),
+				),
+			) {
+				last = lid
+				live.HandleIncomingLayer(ctx, lid)
+				if i == 6 {
+					target = lid
+					ballots, err := s.GetState(0).MeshDB.LayerBallots(lid)
+					require.NoError(t, err)
+					smesher = ballots[0].SmesherID()
+					late.OnMalfeasance(smesher, target)
+				}
+			}
+			for lid := types.GetEffectiveGenesis().Add(1); !lid.After(last); lid = lid.Add(1) {
+				late.HandleIncomingLayer(ctx, lid)
+			}
+
+			trtl := live.trtl
+			require.True(t, trtl.verified.Before(target), "verified %s", trtl.verified)
+			require.Equal(t, trtl.verified, late.trtl.verified)
+
+			live.OnMalfeasance(smesher, target)
+			removed := 0
+			for lid := target.Sub(1); !lid.After(last); lid = lid.Add(1) {
+				ballots, err := s.GetState(0).MeshDB.LayerBallots(lid)
+				require.NoError(t, err)
+				for _, ballot := range ballots {
+					if !ballot.SmesherID().Equals(smesher) {
+						continue
+					}
+					if lid.Before(target) {
+						require.False(t, trtl.ballotWeight[ballot.ID()].isNil())
+					} else {
+						require.True(t, trtl.ballotWeight[ballot.ID()].isNil())
+						removed++
+					}
+				}
+			}
+			require.NotZero(t, removed)
+
+			for lid := trtl.verified.Add(1); !lid.After(last); lid = lid.Add(1) {
+				for _, block := range trtl.blocks[lid] {
+					require.Equal(t,
+						late.trtl.full.weight(logger, lid, block).String(),
+						trtl.full.weight(logger, lid, block).String(),
+						"block %s in layer %s", block, lid,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestMalfeasanceRestoredFromDatabase(t *testing.T) {
+	const size = 10
+	ctx := context.Background()
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	db := sql.InMemory()
+	tortoise := tortoiseFromSimState(s.GetState(0),
+		WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+	var last types.LayerID
+	for _, last = range sim.GenLayers(s, sim.WithSequence(5)) {
+		tortoise.HandleIncomingLayer(ctx, last)
+	}
+	ballots, err := s.GetState(0).MeshDB.LayerBallots(last)
+	require.NoError(t, err)
+	ballot := ballots[0]
+	require.NoError(t, identities.SetProof(db, ballot.SmesherID().Bytes(), last, []byte("proof")))
+
+	requireMalicious := func(t *testing.T, trtl *turtle) {
+		t.Helper()
+		require.Contains(t, trtl.malicious, ballot.SmesherID().String())
+		require.True(t, trtl.ballotWeight[ballot.ID()].isNil())
+	}
+	t.Run("rerun", func(t *testing.T) {
+		require.NoError(t, tortoise.rerun(ctx))
+		tortoise.HandleIncomingLayer(ctx, last)
+		requireMalicious(t, tortoise.trtl)
+	})
+	t.Run("restart", func(t *testing.T) {
+		cfg := cfg
+		cfg.MeshProcessed = last
+		cfg.MeshVerified = last.Sub(1)
+		restarted := tortoiseFromSimState(s.GetState(0),
+			WithLogger(logtest.New(t)), WithConfig(cfg), WithDatabase(db))
+		require.NoError(t, restarted.WaitReady(ctx))
+		restarted.HandleIncomingLayer(ctx, last)
+		requireMalicious(t, restarted.trtl)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
 type rerunResult struct {
@@ -48,6 +49,12 @@ func (t *Tortoise) updateFromRerun(ctx context.Context) (bool, types.LayerID) {
 			logger.Info("catchup finished", log.Duration("duration", time.Since(start)))
 		}
 	}
+	if err == nil {
+		// smeshers could be proven malicious while rerun was in progress
+		if err = t.restoreMalfeasance(updated); err != nil {
+			logger.Error("failed to restore malicious smeshers", log.Err(err))
+		}
+	}
 	if err != nil {
 		return reverted, observed
 	}
@@ -88,9 +95,54 @@ func (t *Tortoise) rerun(ctx context.Context) error {
 
 	start := time.Now()
 
+	consensus, tracer, err := t.replay(ctx, logger, last, historicallyVerified, false)
+	if errors.Is(err, sql.ErrPruned) {
+		logger.With().Info("ballots are pruned. rerun from the last checkpoint", log.Err(err))
+		consensus, tracer, err = t.replay(ctx, logger, last, historicallyVerified, true)
+	}
+	if err != nil {
+		logger.With().Error("tortoise rerun failed", log.Err(err))
+		return err
+	}
+	if !tracer.Reverted() && consensus.verified.Before(consensus.historicallyVerified) {
+		revertFrom := consensus.verified.Add(1)
+		tracer.firstUpdatedLayer = &revertFrom
+	}
+	if tracer.Reverted() {
+		logger = logger.WithFields(log.Stringer("first_reverted_layer", tracer.FirstLayer()))
+	}
+	consensus.historicallyVerified = consensus.verified
+	logger.With().Info("tortoise rerun completed", last, log.Duration("duration", time.Since(start)))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.update = &rerunResult{Consensus: consensus, Tracer: tracer}
+	return nil
+}
+
+// replay processes layers up to the last layer with a new instance of the turtle. Layers are processed
+// from genesis, or from the last checkpoint if bodies of the earlier layers are pruned.
+func (t *Tortoise) replay(ctx context.Context, logger log.Log, last, historicallyVerified types.LayerID, fromCheckpoint bool) (*turtle, *validityTracer, error) {
 	consensus := t.trtl.cloneTurtleParams()
 	consensus.logger = logger
 	consensus.init(ctx, types.GenesisLayer())
+	first := types.GetEffectiveGenesis().Add(1)
+	if fromCheckpoint {
+		if t.db == nil {
+			return nil, nil, fmt.Errorf("%w: checkpoints are disabled", sql.ErrPruned)
+		}
+		restored, err := loadCheckpoint(t.db, consensus, last)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: load checkpoint: %v", sql.ErrPruned, err)
+		}
+		if !restored {
+			return nil, nil, fmt.Errorf("%w: checkpoint is after the last layer %s", sql.ErrPruned, last)
+		}
+		first = consensus.processed.Add(1)
+	}
+	if err := t.restoreMalfeasance(consensus); err != nil {
+		return nil, nil, fmt.Errorf("restore malicious smeshers: %w", err)
+	}
 	tracer := &validityTracer{blockDataProvider: consensus.bdp}
 	consensus.bdp = tracer
 	consensus.last = last
@@ -98,31 +150,18 @@ func (t *Tortoise) rerun(ctx context.Context) error {
 	consensus.mode = consensus.mode.toggleRerun()
 
 	logger.With().Info("tortoise rerun started",
+		log.Stringer("first_layer", first),
 		log.Stringer("last_layer", last),
 		log.Stringer("historically_verified", historicallyVerified),
 		log.Stringer("mode", consensus.mode),
 	)
 
-	for lid := types.GetEffectiveGenesis().Add(1); !lid.After(last); lid = lid.Add(1) {
+	for lid := first; !lid.After(last); lid = lid.Add(1) {
 		if err := consensus.HandleIncomingLayer(ctx, lid); err != nil {
-			logger.With().Error("tortoise rerun failed", log.Err(err))
-			return err
+			return nil, nil, err
 		}
 	}
-	if !tracer.Reverted() && consensus.verified.Before(consensus.historicallyVerified) {
-		revertFrom := consensus.verified.Add(1)
-		tracer.firstUpdatedLayer = &revertFrom
-	}
-	if tracer.Reverted() {
-		logger = logger.WithFields(log.Stringer("first_reverted_layer", tracer.FirstLayer()))
-	}
-	consensus.historicallyVerified = consensus.verified
-	logger.With().Info("tortoise rerun completed", last, log.Duration("duration", time.Since(start)))
-
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	t.update = &rerunResult{Consensus: consensus, Tracer: tracer}
-	return nil
+	return consensus, tracer, nil
 }
 
 // validityTracer monitors the tortoise rerun for database changes that would cause us to need to revert state.
@@ -0,0 +1,54 @@
+package tortoise
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim/scenario"
+)
+
+// scenarioSeeds is a number of seeds for every scenario. The default is chosen for regular runs,
+// nightly runs use -scenario-seeds=1000 (see test-tortoise-scenarios in Makefile).
+var scenarioSeeds = flag.Int("scenario-seeds", 30, "number of seeds for every adversarial scenario")
+
+type scenarioTortoise struct {
+	*Tortoise
+}
+
+func (t scenarioTortoise) Rerun(ctx context.Context) error {
+	return t.rerun(ctx)
+}
+
+func scenarioFactory(counter string) scenario.Factory {
+	return func(state sim.State, conf scenario.Config) scenario.Tortoise {
+		cfg := defaultTestConfig()
+		cfg.LayerSize = conf.LayerSize
+		cfg.Hdist = conf.Hdist
+		cfg.Zdist = conf.Zdist
+		cfg.VoteCounter = counter
+		return scenarioTortoise{Tortoise: tortoiseFromSimState(state, WithConfig(cfg))}
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	seeds := *scenarioSeeds
+	if testing.Short() {
+		seeds = 1
+	}
+	for _, counter := range []string{RecountVotes, IncrementalVotes} {
+		for _, s := range scenario.All() {
+			s := s
+			factory := scenarioFactory(counter)
+			t.Run(counter+"/"+s.Name, func(t *testing.T) {
+				t.Parallel()
+				for seed := 0; seed < seeds; seed++ {
+					require.NoError(t, s.Run(context.Background(), int64(seed), factory))
+				}
+			})
+		}
+	}
+}
@@ -89,6 +89,8 @@ func New(opts ...GenOpt) *Generator {
 		conf:      defaults(),
 		logger:    log.NewNop(),
 		reordered: map[types.LayerID]types.LayerID{},
+		withheld:  map[types.LayerID][]*types.Ballot{},
+		hidden:    map[int][]*types.Ballot{},
 	}
 	for _, opt := range opts {
 		opt(g)
@@ -111,11 +113,15 @@ type Generator struct {
 	nextLayer types.LayerID
 	// key is when to return => value is the layer to return
 	reordered   map[types.LayerID]types.LayerID
+	withheld    map[types.LayerID][]*types.Ballot
 	layers      []*types.Layer
 	units       [2]int
 	activations []types.ATXID
 
 	keys []*signing.EdSigner
+
+	// hidden ballots were not delivered to the eclipsed states, by index of the state.
+	hidden map[int][]*types.Ballot
 }
 
 // SetupOpt configures setup.
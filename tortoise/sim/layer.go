@@ -25,6 +25,13 @@ type nextConf struct {
 	Coinflip  bool
 	LayerSize int
 	VoteGen   VotesGenerator
+	Withhold  int
+	// Eclipse is a number of miners in the layer whose ballots are not delivered to the Eclipsed states.
+	Eclipse  int
+	Eclipsed []int
+	// Equivocators is a number of miners that publish second ballot with votes from EquivocationGen.
+	Equivocators    int
+	EquivocationGen VotesGenerator
 }
 
 // WithNextReorder configures when reordered layer should be returned.
@@ -76,6 +83,64 @@ func WithCoin(coin bool) NextOpt {
 	}
 }
 
+// WithWithheldBallots will withhold first n ballots in the layer until Release is called.
+// Withheld ballots are not used as base ballots by ballots in later layers.
+func WithWithheldBallots(n int) NextOpt {
+	return func(c *nextConf) {
+		c.Withhold = n
+	}
+}
+
+// WithEquivocators will make first n miners in the layer publish two ballots,
+// second ballot will use votes from gen.
+func WithEquivocators(n int, gen VotesGenerator) NextOpt {
+	return func(c *nextConf) {
+		c.Equivocators = n
+		c.EquivocationGen = gen
+	}
+}
+
+// WithEclipse will not deliver ballots from the first n miners in the layer to the states
+// with the given indexes until Reveal is called. Other states receive these ballots
+// and may use them as base ballots.
+func WithEclipse(n int, states ...int) NextOpt {
+	return func(c *nextConf) {
+		c.Eclipse = n
+		c.Eclipsed = states
+	}
+}
+
+// Reveal delivers ballots that were hidden from the eclipsed states and returns them
+// by index of the state.
+func (g *Generator) Reveal() map[int][]*types.Ballot {
+	hidden := g.hidden
+	g.hidden = map[int][]*types.Ballot{}
+	for i, ballots := range hidden {
+		for _, ballot := range ballots {
+			g.states[i].OnBallot(ballot)
+		}
+	}
+	return hidden
+}
+
+// Release publishes ballots that were withheld in the layer and returns them.
+func (g *Generator) Release(lid types.LayerID) []*types.Ballot {
+	ballots := g.withheld[lid]
+	delete(g.withheld, lid)
+	for _, layer := range g.layers {
+		if layer.Index() != lid {
+			continue
+		}
+		for _, ballot := range ballots {
+			for _, state := range g.states {
+				state.OnBallot(ballot)
+			}
+			layer.AddBallot(ballot)
+		}
+	}
+	return ballots
+}
+
 // Next generates the next layer.
 func (g *Generator) Next(opts ...NextOpt) types.LayerID {
 	cfg := nextConfDefaults()
@@ -129,14 +194,20 @@ func (g *Generator) genLayer(cfg nextConf) types.LayerID {
 	activeset := make([]types.ATXID, len(g.activations))
 	copy(activeset, g.activations)
 
-	miners := make(map[int]uint32, size)
+	// miners are iterated in order so that layers are reproducible with the same seed
+	miners := make([]uint32, len(g.activations))
 	for i := 0; i < size; i++ {
-		miner := i % len(g.activations)
-		miners[miner]++
+		miners[i%len(g.activations)]++
 	}
 	i := 0
 	for miner, maxj := range miners {
+		if maxj == 0 {
+			continue
+		}
 		voting := cfg.VoteGen(g.rng, g.layers, i)
+		withhold := i < cfg.Withhold
+		eclipse := i < cfg.Eclipse
+		equivocate := i < cfg.Equivocators
 		i++
 		atxid := g.activations[miner]
 		signer := g.keys[miner]
@@ -164,10 +235,30 @@ func (g *Generator) genLayer(cfg nextConf) types.LayerID {
 		if err = ballot.Initialize(); err != nil {
 			g.logger.With().Panic("failed to init ballot", log.Err(err))
 		}
-		for _, state := range g.states {
+		if withhold {
+			g.withheld[g.nextLayer] = append(g.withheld[g.nextLayer], ballot)
+			continue
+		}
+		for j, state := range g.states {
+			if eclipse && contains(cfg.Eclipsed, j) {
+				g.hidden[j] = append(g.hidden[j], ballot)
+				continue
+			}
 			state.OnBallot(ballot)
 		}
 		layer.AddBallot(ballot)
+		if equivocate {
+			equivocation := &types.Ballot{InnerBallot: ballot.InnerBallot}
+			equivocation.Votes = cfg.EquivocationGen(g.rng, g.layers, i-1)
+			equivocation.Signature = signer.Sign(equivocation.Bytes())
+			if err = equivocation.Initialize(); err != nil {
+				g.logger.With().Panic("failed to init ballot", log.Err(err))
+			}
+			for _, state := range g.states {
+				state.OnBallot(equivocation)
+			}
+			layer.AddBallot(equivocation)
+		}
 	}
 	for i := 0; i < numBlocks; i++ {
 		block := types.GenLayerBlock(g.nextLayer, g.genTXIDs(3))
@@ -192,3 +283,12 @@ func (g *Generator) genLayer(cfg nextConf) types.LayerID {
 	g.nextLayer = g.nextLayer.Add(1)
 	return layer.Index()
 }
+
+func contains(states []int, i int) bool {
+	for _, state := range states {
+		if state == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package scenario
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+// AgainstHare supports the second block from the previous layer and votes against the first block,
+// which is a hare output in layers generated by sim.
+func AgainstHare(rng *rand.Rand, layers []*types.Layer, _ int) sim.Voting {
+	last := layers[len(layers)-1]
+	blocks := last.BlocksIDs()
+	ballots := last.Ballots()
+	base := ballots[rng.Intn(len(ballots))]
+	return sim.Voting{Base: base.ID(), Support: blocks[1:2], Against: blocks[:1]}
+}
+
+// BalancingVoting splits ballots into two halves with opposite votes for the blocks in the previous layer,
+// so that no block can cross threshold unless weak coin breaks the tie.
+func BalancingVoting(size int) sim.VotesGenerator {
+	return func(rng *rand.Rand, layers []*types.Layer, i int) sim.Voting {
+		last := layers[len(layers)-1]
+		blocks := last.BlocksIDs()
+		ballots := last.Ballots()
+		half := len(blocks) / 2
+		voting := sim.Voting{Base: ballots[rng.Intn(len(ballots))].ID()}
+		if i < size/2 {
+			voting.Support, voting.Against = blocks[:half], blocks[half:]
+		} else {
+			voting.Support, voting.Against = blocks[half:], blocks[:half]
+		}
+		return voting
+	}
+}
+
+// ForkVoting makes first n ballots build a fork on top of the fork ballots from the previous layer,
+// voting against hare output. Other ballots vote perfectly and use only honest ballots as base.
+func ForkVoting(n int) sim.VotesGenerator {
+	return func(rng *rand.Rand, layers []*types.Layer, i int) sim.Voting {
+		last := layers[len(layers)-1]
+		ballots := last.Ballots()
+		if len(ballots) <= n {
+			panic(fmt.Sprintf("fork requires more than %d ballots in the layer", n))
+		}
+		blocks := last.BlocksIDs()
+		if i < n {
+			return sim.Voting{
+				Base:    ballots[rng.Intn(n)].ID(),
+				Support: blocks[1:2],
+				Against: blocks[:1],
+			}
+		}
+		honest := ballots[n:]
+		return sim.Voting{
+			Base:    honest[rng.Intn(len(honest))].ID(),
+			Support: blocks[:1],
+			Against: blocks[1:],
+		}
+	}
+}
+
+// Balancing adversary splits votes evenly and hare fails to produce output for the attack layers.
+// Honest nodes must converge using weak coin once the attack stops.
+func Balancing(layers int) Scenario {
+	const size = 6
+	return Scenario{
+		Name:     fmt.Sprintf("balancing/%d", layers),
+		Config:   Config{LayerSize: size, Miners: size, Hdist: 2, Zdist: 2},
+		Warmup:   5,
+		Recovery: 10,
+		attack: func(r *Runner) {
+			// blocks in this layer are balanced by the first layer of the attack
+			r.Next()
+			for i := 0; i < layers; i++ {
+				r.Next(
+					sim.WithCoin(true),
+					sim.WithEmptyHareOutput(),
+					sim.WithVoteGenerator(BalancingVoting(size)),
+				)
+			}
+		},
+	}
+}
+
+// LateBallots adversary withholds n ballots in every attack layer that vote against hare output
+// and publishes them after delay layers. Delay equal to hdist releases ballots when honest nodes stop
+// voting according to hare output.
+func LateBallots(n int, delay uint32, layers int) Scenario {
+	const size = 10
+	return Scenario{
+		Name:     fmt.Sprintf("late_ballots/%d/%d/%d", n, delay, layers),
+		Config:   Config{LayerSize: size, Miners: size, Hdist: 4, Zdist: 4},
+		Warmup:   2,
+		Recovery: 4,
+		attack: func(r *Runner) {
+			var attacked []types.LayerID
+			release := func() {
+				for len(attacked) > 0 && r.last.Difference(attacked[0]) >= delay {
+					r.Release(attacked[0])
+					attacked = attacked[1:]
+				}
+			}
+			for i := 0; i < layers; i++ {
+				lid := r.Honest(
+					sim.WithWithheldBallots(n),
+					sim.WithVoteGenerator(sim.VaryingVoting(n, AgainstHare, sim.PerfectVoting)),
+				)
+				attacked = append(attacked, lid)
+				release()
+			}
+			for len(attacked) > 0 {
+				r.Honest()
+				release()
+			}
+		},
+	}
+}
+
+// Equivocation adversary controls n miners that publish two ballots in every attack layer,
+// second ballot votes against hare output. Honest miners vote according to the tortoise,
+// so that they don't select ballots from equivocating miners as a base.
+func Equivocation(n, layers int) Scenario {
+	const size = 10
+	return Scenario{
+		Name:     fmt.Sprintf("equivocation/%d/%d", n, layers),
+		Config:   Config{LayerSize: size, Miners: size, Hdist: 4, Zdist: 4},
+		Warmup:   2,
+		Recovery: 4,
+		// ballots from equivocating miners don't count, but they are accounted in the expected weight
+		Lag: 3,
+		attack: func(r *Runner) {
+			for i := 0; i < layers; i++ {
+				r.Honest(
+					sim.WithEquivocators(n, AgainstHare),
+					sim.WithVoteGenerator(r.TortoiseVoting()),
+				)
+			}
+		},
+	}
+}
+
+// MinorityFork adversary controls n miners that build their own fork against hare output.
+func MinorityFork(n, layers int) Scenario {
+	const size = 10
+	return Scenario{
+		Name:     fmt.Sprintf("minority_fork/%d/%d", n, layers),
+		Config:   Config{LayerSize: size, Miners: size, Hdist: 4, Zdist: 4},
+		Warmup:   2,
+		Recovery: 4,
+		attack: func(r *Runner) {
+			for i := 0; i < layers; i++ {
+				r.Honest(sim.WithVoteGenerator(ForkVoting(n)))
+			}
+		},
+	}
+}
+
+// Partition splits the network into two partitions for the number of layers. After partition is healed
+// both instances rerun and must agree on blocks from both partitions.
+func Partition(part sim.Fraction, layers int) Scenario {
+	const size = 10
+	return Scenario{
+		Name:     fmt.Sprintf("partition/%s/%d", part, layers),
+		Config:   Config{LayerSize: size, Miners: 15, Hdist: 3, Zdist: 3, Instances: 2},
+		Warmup:   int(types.GetLayersPerEpoch()),
+		Recovery: 4 * int(types.GetLayersPerEpoch()),
+		attack: func(r *Runner) {
+			r.Split(part)
+			for i := 0; i < layers; i++ {
+				r.Next()
+			}
+			r.Merge()
+		},
+	}
+}
+
+// Eclipse isolates the last instance from the fraction of miners for the number of layers,
+// ballots from these miners are received by other instances and used as base ballots.
+// After eclipse is over hidden ballots are delivered, instances rerun and must agree.
+func Eclipse(part sim.Fraction, layers int) Scenario {
+	const (
+		size      = 10
+		instances = 2
+	)
+	return Scenario{
+		Name:     fmt.Sprintf("eclipse/%s/%d", part, layers),
+		Config:   Config{LayerSize: size, Miners: size, Hdist: 3, Zdist: 3, Instances: instances},
+		Warmup:   2,
+		Recovery: 4,
+		attack: func(r *Runner) {
+			n := size * part.Nominator / part.Denominator
+			for i := 0; i < layers; i++ {
+				r.Honest(sim.WithEclipse(n, instances-1))
+			}
+			r.Reveal()
+		},
+	}
+}
+
+// All returns scenarios with default parameters.
+func All() []Scenario {
+	lpe := int(types.GetLayersPerEpoch())
+	return []Scenario{
+		Balancing(3),
+		LateBallots(3, 4, 6),
+		LateBallots(3, 1, 6),
+		Equivocation(3, 6),
+		MinorityFork(3, 8),
+		Partition(sim.Frac(1, 2), lpe),
+		Eclipse(sim.Frac(1, 2), 6),
+	}
+}
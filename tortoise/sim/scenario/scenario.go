@@ -0,0 +1,298 @@
+// Package scenario contains adversarial models for the tortoise with expected safety and liveness properties.
+//
+// Scenario generates layers with tortoise/sim and feeds them to tortoise instances that
+// are created by the caller. After attack is over honest miners vote according to the tortoise
+// for Recovery layers, then properties are checked:
+//   - safety: all instances agree on validity of blocks in verified layers,
+//     and hare output in layers produced by honest majority is valid.
+//   - liveness: all instances verified the layer before the last one, or a layer within the allowed lag.
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+// Tortoise is a tortoise API that is exercised by scenarios.
+type Tortoise interface {
+	HandleIncomingLayer(context.Context, types.LayerID) (types.LayerID, types.LayerID, bool)
+	BaseBallot(context.Context) (*types.Votes, error)
+	OnBallot(*types.Ballot)
+	// Rerun recounts votes from all layers. Scenarios use it after partition is healed.
+	Rerun(context.Context) error
+}
+
+// Config for the generated network and tortoise instances.
+type Config struct {
+	LayerSize    uint32
+	Miners       int
+	Hdist, Zdist uint32
+	// Instances is a number of honest nodes that run the tortoise.
+	Instances int
+}
+
+// Factory creates tortoise instance that reads data from the state.
+type Factory func(state sim.State, conf Config) Tortoise
+
+// Scenario is a named adversarial model.
+type Scenario struct {
+	Name   string
+	Config Config
+	// Warmup is a number of honest layers before the attack.
+	Warmup int
+	// Recovery is a number of honest layers after the attack.
+	// All instances are expected to verify the layer before the last by the end of recovery.
+	Recovery int
+	// Lag is a number of layers that instances are allowed to be behind the last layer
+	// by the end of recovery. Default is 1. Adversary with significant weight makes
+	// honest instances collect votes from more layers to cross the threshold.
+	Lag uint32
+
+	attack func(*Runner)
+}
+
+var (
+	// ErrSafety is returned if instances disagree on validity or honest hare output is not valid.
+	ErrSafety = errors.New("safety violated")
+	// ErrLiveness is returned if instances didn't verify layers after recovery.
+	ErrLiveness = errors.New("liveness violated")
+)
+
+// Run the scenario with the seed. Returns ErrSafety or ErrLiveness if expected property doesn't hold.
+func (s Scenario) Run(ctx context.Context, seed int64, factory Factory) error {
+	conf := s.Config
+	if conf.Instances == 0 {
+		conf.Instances = 1
+	}
+	gen := sim.New(
+		sim.WithSeed(seed),
+		sim.WithLayerSize(conf.LayerSize),
+		sim.WithStates(conf.Instances),
+	)
+	gen.Setup(sim.WithSetupMinerRange(conf.Miners, conf.Miners))
+
+	r := &Runner{
+		ctx:      ctx,
+		conf:     conf,
+		checked:  map[types.LayerID]struct{}{},
+		verified: make([]types.LayerID, conf.Instances),
+	}
+	r.parts = []partition{{gen: gen}}
+	for i := 0; i < conf.Instances; i++ {
+		r.instances = append(r.instances, factory(gen.GetState(i), conf))
+		r.states = append(r.states, gen.GetState(i))
+		r.parts[0].instances = append(r.parts[0].instances, i)
+	}
+
+	for i := 0; i < s.Warmup; i++ {
+		r.Honest()
+	}
+	s.attack(r)
+	for i := 0; i < s.Recovery; i++ {
+		r.Next(sim.WithVoteGenerator(r.TortoiseVoting()))
+	}
+	if r.err != nil {
+		return fmt.Errorf("%s (seed %d): %w", s.Name, seed, r.err)
+	}
+	if err := r.checkSafety(); err != nil {
+		return fmt.Errorf("%s (seed %d): %w", s.Name, seed, err)
+	}
+	lag := s.Lag
+	if lag == 0 {
+		lag = 1
+	}
+	if err := r.checkLiveness(lag); err != nil {
+		return fmt.Errorf("%s (seed %d): %w", s.Name, seed, err)
+	}
+	return nil
+}
+
+type partition struct {
+	gen       *sim.Generator
+	instances []int
+}
+
+// Runner drives generators and tortoise instances during the attack.
+type Runner struct {
+	ctx  context.Context
+	conf Config
+
+	parts     []partition
+	instances []Tortoise
+	states    []sim.State
+
+	last     types.LayerID
+	verified []types.LayerID
+	// checked layers are produced by honest majority and hare output in them must be valid.
+	checked map[types.LayerID]struct{}
+	err     error
+}
+
+// Next generates a layer in every partition and feeds it to instances in that partition.
+func (r *Runner) Next(opts ...sim.NextOpt) types.LayerID {
+	for _, part := range r.parts {
+		lid := part.gen.Next(opts...)
+		for _, i := range part.instances {
+			_, r.verified[i], _ = r.instances[i].HandleIncomingLayer(r.ctx, lid)
+		}
+		r.last = lid
+	}
+	return r.last
+}
+
+// Honest generates a layer with perfect voting and marks it as checked.
+func (r *Runner) Honest(opts ...sim.NextOpt) types.LayerID {
+	lid := r.Next(opts...)
+	r.Check(lid)
+	return lid
+}
+
+// Check marks layer as produced by honest majority.
+func (r *Runner) Check(lid types.LayerID) {
+	r.checked[lid] = struct{}{}
+}
+
+// Release publishes withheld ballots from the layer to all instances.
+func (r *Runner) Release(lid types.LayerID) {
+	for _, part := range r.parts {
+		for _, ballot := range part.gen.Release(lid) {
+			for _, i := range part.instances {
+				r.instances[i].OnBallot(ballot)
+			}
+		}
+	}
+}
+
+// Split the network into partitions. Each partition will have one instance.
+func (r *Runner) Split(parts ...sim.Fraction) {
+	if len(r.parts) != 1 || len(parts)+1 != r.conf.Instances {
+		panic("network can be split only into partitions with one instance")
+	}
+	gens := r.parts[0].gen.Split(sim.WithPartitions(parts...))
+	r.parts = r.parts[:0]
+	for i, gen := range gens {
+		r.parts = append(r.parts, partition{gen: gen, instances: []int{i}})
+	}
+}
+
+// Merge partitions and rerun all instances.
+func (r *Runner) Merge() {
+	merged := r.parts[0]
+	for _, part := range r.parts[1:] {
+		merged.gen.Merge(part.gen)
+		merged.instances = append(merged.instances, part.instances...)
+	}
+	r.parts = []partition{merged}
+	r.rerun()
+}
+
+// Reveal delivers ballots that were hidden from eclipsed instances and reruns all instances.
+func (r *Runner) Reveal() {
+	for _, part := range r.parts {
+		for state, ballots := range part.gen.Reveal() {
+			instance := r.instances[part.instances[state]]
+			for _, ballot := range ballots {
+				instance.OnBallot(ballot)
+			}
+		}
+	}
+	r.rerun()
+}
+
+func (r *Runner) rerun() {
+	for _, instance := range r.instances {
+		if err := instance.Rerun(r.ctx); err != nil && r.err == nil {
+			r.err = fmt.Errorf("rerun: %w", err)
+		}
+	}
+}
+
+// TortoiseVoting creates generator that votes according to the instances. Ballots are evenly
+// distributed between instances.
+func (r *Runner) TortoiseVoting() sim.VotesGenerator {
+	return func(rng *rand.Rand, layers []*types.Layer, i int) sim.Voting {
+		votes, err := r.instances[i%len(r.instances)].BaseBallot(r.ctx)
+		if err != nil {
+			if r.err == nil {
+				r.err = fmt.Errorf("base ballot: %w", err)
+			}
+			return sim.PerfectVoting(rng, layers, i)
+		}
+		return *votes
+	}
+}
+
+func (r *Runner) minVerified() types.LayerID {
+	verified := r.verified[0]
+	for _, lid := range r.verified[1:] {
+		if lid.Before(verified) {
+			verified = lid
+		}
+	}
+	return verified
+}
+
+func (r *Runner) checkSafety() error {
+	verified := r.minVerified()
+	for lid := types.GetEffectiveGenesis().Add(1); !lid.After(verified); lid = lid.Add(1) {
+		expected, err := r.states[0].MeshDB.LayerContextuallyValidBlocks(r.ctx, lid)
+		if err != nil {
+			return err
+		}
+		for i, state := range r.states[1:] {
+			valid, err := state.MeshDB.LayerContextuallyValidBlocks(r.ctx, lid)
+			if err != nil {
+				return err
+			}
+			if !equalBlocks(expected, valid) {
+				return fmt.Errorf("%w: instances 0 and %d disagree on valid blocks in layer %s",
+					ErrSafety, i+1, lid)
+			}
+		}
+		if _, exist := r.checked[lid]; !exist {
+			continue
+		}
+		for i, state := range r.states {
+			output, err := state.MeshDB.GetHareConsensusOutput(lid)
+			if err != nil || output == types.EmptyBlockID {
+				continue
+			}
+			valid, err := state.MeshDB.ContextualValidity(output)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return fmt.Errorf("%w: hare output %s in layer %s is not valid for instance %d",
+					ErrSafety, output, lid, i)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Runner) checkLiveness(lag uint32) error {
+	expected := r.last.Sub(lag)
+	for i, verified := range r.verified {
+		if verified.Before(expected) {
+			return fmt.Errorf("%w: instance %d verified %s, expected %s", ErrLiveness, i, verified, expected)
+		}
+	}
+	return nil
+}
+
+func equalBlocks(a, b map[types.BlockID]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for bid := range a {
+		if _, exist := b[bid]; !exist {
+			return false
+		}
+	}
+	return true
+}
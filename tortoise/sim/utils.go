@@ -23,13 +23,16 @@ const (
 
 func newAtxDB(logger log.Log, conf config) *activation.DB {
 	var (
-		db  database.Database
+		db  *sql.Database
 		err error
 	)
 	if len(conf.Path) == 0 {
-		db = database.NewMemDatabase()
+		db = sql.InMemory()
 	} else {
-		db, err = database.NewLDBDatabase(filepath.Join(conf.Path, atxpath), 0, 0, logger)
+		if err := os.MkdirAll(filepath.Join(conf.Path, atxpath), os.ModePerm); err != nil {
+			panic(err)
+		}
+		db, err = sql.Open("file:" + filepath.Join(conf.Path, atxpath, "state.sql"))
 		if err != nil {
 			panic(err)
 		}
@@ -16,6 +16,7 @@ func newCommonState() commonState {
 		referenceWeight:  map[types.BallotID]weight{},
 		ballotWeight:     map[types.BallotID]weight{},
 		undecided:        map[types.LayerID]struct{}{},
+		decisions:        map[types.LayerID]decision{},
 		hareOutput:       votes{},
 		validity:         votes{},
 	}
@@ -68,4 +69,13 @@ type commonState struct {
 	undecided  map[types.LayerID]struct{}
 	hareOutput votes
 	validity   votes
+
+	// decisions records how verified layers were verified. used only to explain decisions.
+	decisions map[types.LayerID]decision
+}
+
+// decision records the mode and thresholds that were used when layer was verified.
+type decision struct {
+	mode                            mode
+	localThreshold, globalThreshold weight
 }
@@ -0,0 +1,301 @@
+package tortoise
+
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// layerWeights is a fenwick tree over the weight of counted ballots in each layer. Adding weight
+// and computing weight of the ballots after the layer take O(log n), where n is the number of layers
+// since the first layer in the tree.
+type layerWeights struct {
+	first types.LayerID
+	// tree is 1-based. tree[i-1] is a sum of layers with indexes in (i - i&-i, i].
+	tree  []weight
+	total weight
+}
+
+func (l *layerWeights) index(lid types.LayerID) int {
+	return int(lid.Difference(l.first)) + 1
+}
+
+// prefix returns weight of layers with indexes up to and including i.
+func (l *layerWeights) prefix(i int) weight {
+	sum := weightFromUint64(0)
+	if i > len(l.tree) {
+		i = len(l.tree)
+	}
+	for ; i > 0; i -= i & -i {
+		sum = sum.add(l.tree[i-1])
+	}
+	return sum
+}
+
+// add weight of the ballot from the layer.
+func (l *layerWeights) add(lid types.LayerID, w weight) {
+	if l.total.isNil() {
+		l.first = lid
+		l.total = weightFromUint64(0)
+	}
+	if lid.Before(l.first) {
+		l.rebuild(lid)
+	}
+	i := l.index(lid)
+	for len(l.tree) < i {
+		n := len(l.tree) + 1
+		l.tree = append(l.tree, l.prefix(n-1).sub(l.prefix(n-n&-n)))
+	}
+	for ; i <= len(l.tree); i += i & -i {
+		l.tree[i-1] = l.tree[i-1].add(w)
+	}
+	l.total = l.total.add(w)
+}
+
+// after returns weight of the ballots in layers after lid.
+func (l *layerWeights) after(lid types.LayerID) weight {
+	if l.total.isNil() {
+		return weightFromUint64(0)
+	}
+	rst := l.total.copy()
+	if lid.Before(l.first) {
+		return rst
+	}
+	return rst.sub(l.prefix(l.index(lid)))
+}
+
+// evict drops weight of the layers up to and including lid. The tree is rebuilt once more than
+// a half of it is evicted, so that the cost of eviction is amortized.
+func (l *layerWeights) evict(lid types.LayerID) {
+	if l.total.isNil() || lid.Before(l.first) {
+		return
+	}
+	if l.index(lid) > len(l.tree)/2 {
+		l.rebuild(lid.Add(1))
+	}
+}
+
+// rebuild the tree starting from the first layer.
+func (l *layerWeights) rebuild(first types.LayerID) {
+	var values []weight
+	total := weightFromUint64(0)
+	for lid := first; lid.Before(l.first) || l.index(lid) <= len(l.tree); lid = lid.Add(1) {
+		if lid.Before(l.first) {
+			values = append(values, weightFromUint64(0))
+			continue
+		}
+		i := l.index(lid)
+		value := l.prefix(i).sub(l.prefix(i - 1))
+		total = total.add(value)
+		values = append(values, value)
+	}
+	for i := range values {
+		if j := i + 1 + (i+1)&-(i+1); j <= len(values) {
+			values[j-1] = values[j-1].add(values[i])
+		}
+	}
+	l.first = first
+	l.total = total
+	l.tree = values
+}
+
+// ballotTree maintains weight of the counted ballots that use the ballot as a base directly or
+// indirectly, including weight of the ballot itself.
+//
+// Ballots are kept in the euler tour order, every ballot is represented by enter and exit nodes,
+// and ballots that use it as a base are inserted between them. The tour is stored in a treap,
+// therefore inserting a ballot, adding its weight and summing weight between enter and exit nodes
+// take O(log n), where n is the number of ballots in the tree.
+type ballotTree struct {
+	root  *tourNode
+	enter map[types.BallotID]*tourNode
+	seed  uint64
+}
+
+func newBallotTree() *ballotTree {
+	return &ballotTree{enter: map[types.BallotID]*tourNode{}, seed: 1}
+}
+
+type tourNode struct {
+	left, right, parent *tourNode
+	priority            uint64
+	size                int
+	// own weight is set only for enter nodes of the counted ballots.
+	own weight
+	// sum of the weights in the subtree of the treap.
+	sum weight
+	// exit node is set only for enter nodes.
+	exit *tourNode
+}
+
+func (t *ballotTree) node() *tourNode {
+	// xorshift, priorities only need to be random enough to keep the treap balanced
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 7
+	t.seed ^= t.seed << 17
+	return &tourNode{priority: t.seed, size: 1, sum: weightFromUint64(0)}
+}
+
+func treeSize(n *tourNode) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *tourNode) update() {
+	n.size = 1 + treeSize(n.left) + treeSize(n.right)
+	n.sum.Rat.SetInt64(0)
+	for _, w := range []weight{n.own, sumOf(n.left), sumOf(n.right)} {
+		if !w.isNil() {
+			n.sum = n.sum.add(w)
+		}
+	}
+}
+
+func sumOf(n *tourNode) weight {
+	if n == nil {
+		return weight{}
+	}
+	return n.sum
+}
+
+func setParent(n, parent *tourNode) {
+	if n != nil {
+		n.parent = parent
+	}
+}
+
+func merge(a, b *tourNode) *tourNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = merge(a.right, b)
+		setParent(a.right, a)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	setParent(b.left, b)
+	b.update()
+	return b
+}
+
+// split returns first k nodes and the rest.
+func split(n *tourNode, k int) (*tourNode, *tourNode) {
+	if n == nil {
+		return nil, nil
+	}
+	if treeSize(n.left) >= k {
+		left, right := split(n.left, k)
+		n.left = right
+		setParent(right, n)
+		n.update()
+		setParent(left, nil)
+		return left, n
+	}
+	left, right := split(n.right, k-treeSize(n.left)-1)
+	n.right = left
+	setParent(left, n)
+	n.update()
+	setParent(right, nil)
+	return n, right
+}
+
+// rank returns the number of nodes before n.
+func rank(n *tourNode) int {
+	rst := treeSize(n.left)
+	for child, parent := n, n.parent; parent != nil; child, parent = parent, parent.parent {
+		if parent.right == child {
+			rst += treeSize(parent.left) + 1
+		}
+	}
+	return rst
+}
+
+// before returns weight of the nodes before n.
+func before(n *tourNode) weight {
+	rst := weightFromUint64(0)
+	if n.left != nil {
+		rst = rst.add(n.left.sum)
+	}
+	for child, parent := n, n.parent; parent != nil; child, parent = parent, parent.parent {
+		if parent.right != child {
+			continue
+		}
+		for _, w := range []weight{parent.own, sumOf(parent.left)} {
+			if !w.isNil() {
+				rst = rst.add(w)
+			}
+		}
+	}
+	return rst
+}
+
+func (t *ballotTree) join(nodes ...*tourNode) {
+	for _, n := range nodes {
+		t.root = merge(t.root, n)
+	}
+	setParent(t.root, nil)
+}
+
+// insert ballot after ballots that use the same base. Ballot is a root if base is not in the tree.
+func (t *ballotTree) insert(ballot, base types.BallotID) {
+	if _, exist := t.enter[ballot]; exist {
+		return
+	}
+	enter, exit := t.node(), t.node()
+	enter.exit = exit
+	t.enter[ballot] = enter
+	pair := merge(enter, exit)
+	parent, exist := t.enter[base]
+	if !exist {
+		t.join(pair)
+		return
+	}
+	left, right := split(t.root, rank(parent.exit))
+	t.root = nil
+	t.join(left, pair, right)
+}
+
+// add weight to the ballot and every ballot that it uses as a base directly or indirectly.
+func (t *ballotTree) add(ballot types.BallotID, w weight) {
+	enter, exist := t.enter[ballot]
+	if !exist {
+		return
+	}
+	if enter.own.isNil() {
+		enter.own = weightFromUint64(0)
+	}
+	enter.own = enter.own.add(w)
+	for n := enter; n != nil; n = n.parent {
+		n.sum = n.sum.add(w)
+	}
+}
+
+// weight returns weight of the ballot and ballots that use it as a base directly or indirectly.
+func (t *ballotTree) weight(ballot types.BallotID) (weight, bool) {
+	enter, exist := t.enter[ballot]
+	if !exist {
+		return weight{}, false
+	}
+	return before(enter.exit).sub(before(enter)), true
+}
+
+// remove ballot from the tree. Ballots that use it as a base remain in the subtree
+// of its base ballot.
+func (t *ballotTree) remove(ballot types.BallotID) {
+	enter, exist := t.enter[ballot]
+	if !exist {
+		return
+	}
+	delete(t.enter, ballot)
+	for _, n := range []*tourNode{enter, enter.exit} {
+		left, right := split(t.root, rank(n))
+		_, right = split(right, 1)
+		t.root = nil
+		t.join(left, right)
+	}
+}
@@ -0,0 +1,102 @@
+package tortoise
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestLayerWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1001))
+	var (
+		tree     layerWeights
+		expected = map[types.LayerID]int64{}
+		evicted  = types.NewLayerID(0)
+	)
+	check := func() {
+		for lid := evicted.Add(1); lid.Before(types.NewLayerID(200)); lid = lid.Add(1) {
+			var sum int64
+			for other, w := range expected {
+				if other.After(lid) {
+					sum += w
+				}
+			}
+			require.Equal(t, weightFromInt64(sum).String(), tree.after(lid).String(), "layer %s", lid)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		lid := evicted.Add(1 + uint32(rng.Intn(50)))
+		w := int64(rng.Intn(100) - 10)
+		tree.add(lid, weightFromInt64(w))
+		expected[lid] += w
+		if i%50 == 49 {
+			check()
+			evicted = evicted.Add(uint32(rng.Intn(20)))
+			tree.evict(evicted)
+			for lid := range expected {
+				if !lid.After(evicted) {
+					delete(expected, lid)
+				}
+			}
+			check()
+		}
+	}
+}
+
+func TestBallotTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1001))
+	var (
+		tree    = newBallotTree()
+		base    = map[types.BallotID]types.BallotID{}
+		weights = map[types.BallotID]int64{}
+		ballots []types.BallotID
+	)
+	check := func() {
+		for _, ballot := range ballots {
+			var sum int64
+			for other, w := range weights {
+				for current := other; ; {
+					if current == ballot {
+						sum += w
+						break
+					}
+					var exist bool
+					if current, exist = base[current]; !exist {
+						break
+					}
+				}
+			}
+			through, exist := tree.weight(ballot)
+			require.True(t, exist)
+			require.Equal(t, weightFromInt64(sum).String(), through.String(), "ballot %s", ballot)
+		}
+	}
+	for i := 0; i < 500; i++ {
+		ballot := types.BallotID{byte(i), byte(i >> 8), 1}
+		if len(ballots) > 0 && rng.Intn(10) > 0 {
+			base[ballot] = ballots[rng.Intn(len(ballots))]
+		}
+		tree.insert(ballot, base[ballot])
+		ballots = append(ballots, ballot)
+		w := int64(rng.Intn(100) - 10)
+		tree.add(ballot, weightFromInt64(w))
+		weights[ballot] += w
+		if i%50 == 49 {
+			check()
+			// remove the oldest ballots, as tortoise evicts them
+			for _, ballot := range ballots[:10] {
+				tree.remove(ballot)
+				delete(weights, ballot)
+				delete(base, ballot)
+				_, exist := tree.weight(ballot)
+				require.False(t, exist)
+			}
+			ballots = ballots[10:]
+			check()
+		}
+	}
+	require.Equal(t, 2*len(ballots), treeSize(tree.root))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/system"
 	"github.com/spacemeshos/go-spacemesh/tortoise/metrics"
 )
@@ -31,6 +32,9 @@ type turtle struct {
 	commonState
 	verifying *verifying
 	full      *full
+
+	// malicious smeshers and the first layer where their ballots have no weight.
+	malicious map[string]types.LayerID
 }
 
 // newTurtle creates a new verifying tortoise algorithm instance.
@@ -48,6 +52,7 @@ func newTurtle(
 		bdp:         bdp,
 		atxdb:       atxdb,
 		beacons:     beacons,
+		malicious:   map[string]types.LayerID{},
 	}
 	t.verifying = newVerifying(config, &t.commonState)
 	t.full = newFullTortoise(config, &t.commonState)
@@ -134,6 +139,7 @@ func (t *turtle) evict(ctx context.Context) {
 	)
 
 	for lid := t.evicted.Add(1); lid.Before(windowStart); lid = lid.Add(1) {
+		t.full.counter.evict(lid)
 		for _, ballot := range t.ballots[lid] {
 			delete(t.ballotLayer, ballot)
 			delete(t.ballotWeight, ballot)
@@ -149,10 +155,10 @@ func (t *turtle) evict(ctx context.Context) {
 			delete(t.blockLayer, block)
 			delete(t.hareOutput, block)
 			delete(t.validity, block)
-			delete(t.full.weights, block)
 		}
 		delete(t.blocks, lid)
 		delete(t.undecided, lid)
+		delete(t.decisions, lid)
 		delete(t.verifying.goodWeight, lid)
 		delete(t.verifying.abstainedWeight, lid)
 		if lid.GetEpoch() < oldestEpoch {
@@ -392,7 +398,7 @@ func (t *turtle) getFullVote(ctx context.Context, lid types.LayerID, bid types.B
 	if !(vote == abstain && reason == reasonValidity) {
 		return vote, reason, nil
 	}
-	sum := t.full.weights[bid]
+	sum := t.full.weight(t.logger, lid, bid)
 	vote = sum.cmp(t.localThreshold)
 	if vote != abstain {
 		return vote, reasonLocalThreshold, nil
@@ -512,10 +518,12 @@ func (t *turtle) processLayer(ctx context.Context, logger log.Log, lid types.Lay
 		if t.mode.isVerifying() {
 			success = t.verifying.verify(logger, target)
 		}
+		verifiedBy := t.mode
 		if !success && (t.canUseFullMode() || t.mode.isFull()) {
 			if t.mode.isVerifying() {
 				t.switchModes(logger)
 			}
+			verifiedBy = t.mode
 
 			// verifying has a large verification window (think 1_000_000) and if it failed to verify layer
 			// the threshold will be computed according to that window.
@@ -526,6 +534,14 @@ func (t *turtle) processLayer(ctx context.Context, logger log.Log, lid types.Lay
 			success = t.catchupToVerifyingInFullMode(logger, target)
 		}
 		if success {
+			if verifiedBy.isVerifying() {
+				t.full.freeze(logger, target)
+			}
+			t.decisions[target] = decision{
+				mode:            verifiedBy,
+				localThreshold:  t.localThreshold.copy(),
+				globalThreshold: t.globalThreshold.copy(),
+			}
 			t.verified = target
 			t.localThreshold, t.globalThreshold = computeThresholds(logger, t.Config, t.mode,
 				t.verified.Add(1), t.last, t.processed,
@@ -659,6 +675,9 @@ func (t *turtle) updateState(ctx context.Context, logger log.Log, lid types.Laye
 	if err := t.updateLocalVotes(ctx, logger, lid); err != nil {
 		return err
 	}
+	if t.full.counter.eager() {
+		t.full.countVotes(logger)
+	}
 	// TODO(dshulyak) it should be possible to count votes from every single ballot separately
 	// but may require changes to t.processed and t.updateLocalVotes
 	t.verifying.countVotes(logger, lid, t.getTortoiseBallots(lid))
@@ -687,7 +706,7 @@ func (t *turtle) onBallot(ballot *types.Ballot) error {
 
 	baselid := t.ballotLayer[ballot.Votes.Base]
 	var ballotWeight weight
-	if !ballot.IsMalicious() {
+	if !ballot.IsMalicious() && !t.isMalicious(ballot) {
 		var err error
 		ballotWeight, err = computeBallotWeight(
 			t.atxdb, t.bdp, t.referenceWeight,
@@ -711,7 +730,7 @@ func (t *turtle) onBallot(ballot *types.Ballot) error {
 	}
 
 	votes := votes{}
-	for lid := baselid; lid.Before(t.processed); lid = lid.Add(1) {
+	for lid := baselid; lid.Before(t.processed) && lid.Before(ballot.LayerIndex); lid = lid.Add(1) {
 		if _, exist := abstainVotes[lid]; exist {
 			continue
 		}
@@ -735,9 +754,67 @@ func (t *turtle) onBallot(ballot *types.Ballot) error {
 	}
 
 	t.full.onBallot(&tballot)
+	// ballots that are received after their layer was counted are counted immediately
+	if t.full.counter.eager() && !ballot.LayerIndex.After(t.full.counted) {
+		t.full.countVotesFromBallots(t.logger, ballot.LayerIndex, []types.BallotID{ballot.ID()})
+	}
+	return nil
+}
+
+func (t *turtle) isMalicious(ballot *types.Ballot) bool {
+	if ballot.SmesherID() == nil {
+		return false
+	}
+	lid, exist := t.malicious[ballot.SmesherID().String()]
+	return exist && !ballot.LayerIndex.Before(lid)
+}
+
+// onMalfeasance removes weight of the ballots from the smesher starting from the layer.
+// Weight that was already counted from these ballots is subtracted, but decisions
+// for verified layers are not reverted.
+func (t *turtle) onMalfeasance(smesher *signing.PublicKey, from types.LayerID) error {
+	if lid, exist := t.malicious[smesher.String()]; exist && !from.Before(lid) {
+		return nil
+	}
+	t.malicious[smesher.String()] = from
+	logger := t.logger.WithFields(log.Stringer("smesher", smesher), log.Stringer("from_layer", from))
+	logger.With().Warning("removing weight of the malicious smesher")
+
+	for lid := range t.ballots {
+		if lid.Before(from) {
+			continue
+		}
+		ballots, err := t.bdp.LayerBallots(lid)
+		if err != nil {
+			return fmt.Errorf("read ballots for layer %s: %w", lid, err)
+		}
+		for _, ballot := range ballots {
+			if ballot.SmesherID().Equals(smesher) {
+				t.removeWeight(logger, lid, ballot.ID())
+			}
+		}
+	}
 	return nil
 }
 
+func (t *turtle) removeWeight(logger log.Log, lid types.LayerID, ballot types.BallotID) {
+	ballotWeight := t.ballotWeight[ballot]
+	if ballotWeight.isNil() {
+		return
+	}
+	delete(t.ballotWeight, ballot)
+	logger.With().Info("removed ballot weight", ballot, lid, log.Stringer("weight", ballotWeight))
+	if !lid.After(t.verified) {
+		return
+	}
+	if !lid.After(t.processed) {
+		t.verifying.removeWeight(lid, ballot, ballotWeight, t.full.abstain[ballot])
+	}
+	if !lid.After(t.full.counted) && !t.full.isDelayed(lid, ballot) {
+		t.full.counter.countBallot(logger, lid, ballot, ballotWeight.copy().neg())
+	}
+}
+
 func (t *turtle) updateLocalVotes(ctx context.Context, logger log.Log, lid types.LayerID) (err error) {
 	for lid := range t.undecided {
 		if err := t.addLocalVotes(ctx, logger.WithFields(log.Bool("undecided", true)), lid); err != nil {
@@ -217,6 +217,13 @@ func (m mode) isFull() bool {
 	return m[0]
 }
 
+func minLayer(i, j types.LayerID) types.LayerID {
+	if i.Before(j) {
+		return i
+	}
+	return j
+}
+
 func maxLayer(i, j types.LayerID) types.LayerID {
 	if i.After(j) {
 		return i
@@ -81,6 +81,24 @@ func (v *verifying) resetWeights() {
 	v.abstainedWeight = map[types.LayerID]weight{}
 }
 
+// removeWeight subtracts weight of the ballot if it was counted as good.
+func (v *verifying) removeWeight(lid types.LayerID, ballot types.BallotID, ballotWeight weight, abstain map[types.LayerID]struct{}) {
+	if rst := v.goodBallots[ballot]; rst != good && rst != abstained {
+		return
+	}
+	layerWeight, exist := v.goodWeight[lid]
+	if !exist {
+		return
+	}
+	layerWeight.sub(ballotWeight)
+	v.totalGoodWeight.sub(ballotWeight)
+	for abstained := range abstain {
+		if w, exist := v.abstainedWeight[abstained]; exist {
+			w.sub(ballotWeight)
+		}
+	}
+}
+
 func (v *verifying) checkCanBeGood(ballot types.BallotID) bool {
 	val := v.goodBallots[ballot]
 	return val == good || val == canBeGood